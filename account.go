@@ -0,0 +1,82 @@
+package gomultistripe
+
+import "time"
+
+// AccountType is the kind of connected account, which decides who onboards and manages it.
+type AccountType string
+
+const (
+	// AccountStandard accounts have their own Stripe dashboard.
+	AccountStandard AccountType = "standard"
+	// AccountExpress accounts are onboarded by Stripe and use the Express dashboard.
+	AccountExpress AccountType = "express"
+	// AccountCustom accounts are fully managed by the platform.
+	AccountCustom AccountType = "custom"
+)
+
+// AccountParams describes a connected account to create.
+type AccountParams struct {
+	Type    AccountType
+	Country string
+	Email   string
+	// BusinessType is "individual", "company", "non_profit" or "government_entity".
+	BusinessType string
+	// Capabilities lists the capabilities to request, e.g. "card_payments" and "transfers".
+	Capabilities []string
+	Metadata     map[string]string
+}
+
+// Account represents a Stripe connected account in a version-agnostic way. Act on it by
+// passing its ID to ContextWithAccount.
+type Account struct {
+	ID              string
+	Type            AccountType
+	Country         string
+	Email           string
+	BusinessType    string
+	DefaultCurrency string
+	// ChargesEnabled and PayoutsEnabled report whether the account can accept payments and
+	// receive payouts; DetailsSubmitted whether onboarding was completed.
+	ChargesEnabled   bool
+	PayoutsEnabled   bool
+	DetailsSubmitted bool
+	// Capabilities maps each requested capability to its status ("active", "inactive" or
+	// "pending").
+	Capabilities map[string]string
+	// RequirementsDue lists the information the account must provide, RequirementsPastDue
+	// the part of it that was due already and now disables capabilities, and
+	// RequirementsDisabledReason why the account is disabled, if it is.
+	RequirementsDue            []string
+	RequirementsPastDue        []string
+	RequirementsDisabledReason string
+	Metadata                   map[string]string
+	CreatedAt                  time.Time
+}
+
+// AccountLinkType is the purpose of an account link.
+type AccountLinkType string
+
+const (
+	// AccountLinkOnboarding collects the information a new account must provide.
+	AccountLinkOnboarding AccountLinkType = "account_onboarding"
+	// AccountLinkUpdate lets an onboarded account update its information.
+	AccountLinkUpdate AccountLinkType = "account_update"
+)
+
+// AccountLinkParams describes an account link to create.
+type AccountLinkParams struct {
+	AccountID string
+	// Type defaults to AccountLinkOnboarding.
+	Type AccountLinkType
+	// RefreshURL is where Stripe sends the user when the link expired or was already
+	// visited; create a new link there. ReturnURL is where the user goes when leaving the
+	// flow, which does not mean onboarding is complete.
+	RefreshURL string
+	ReturnURL  string
+}
+
+// AccountLink is a single-use URL to Stripe-hosted onboarding for a connected account.
+type AccountLink struct {
+	URL       string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,204 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LifecycleSignal is a product analytics signal derived from subscription events.
+type LifecycleSignal string
+
+const (
+	// SignalTrialStarted is emitted when a subscription starts trialing.
+	SignalTrialStarted LifecycleSignal = "trial_started"
+	// SignalTrialConverted is emitted when a trialing subscription becomes active.
+	SignalTrialConverted LifecycleSignal = "trial_converted"
+	// SignalChurned is emitted when a subscription ends, including a trial that ends
+	// without converting.
+	SignalChurned LifecycleSignal = "churned"
+	// SignalReactivated is emitted when a customer with a churned subscription starts, or
+	// resumes, another subscription.
+	SignalReactivated LifecycleSignal = "reactivated"
+)
+
+// LifecycleEvent is one analytics signal, emitted by a LifecycleTracker.
+type LifecycleEvent struct {
+	Signal         LifecycleSignal
+	SubscriptionID string
+	CustomerID     string
+	PriceID        string
+	// At is when Stripe created the event that led to the signal.
+	At time.Time
+	// Event is the webhook event that led to the signal.
+	Event *CallbackEvent
+}
+
+// LifecycleState is what a LifecycleTracker remembers about a subscription or, for
+// Churned, about a customer.
+type LifecycleState struct {
+	Status  string
+	Trialed bool
+	Churned bool
+	// UpdatedAt is the creation time of the last event applied, so that older events
+	// delivered late do not undo newer ones.
+	UpdatedAt time.Time
+}
+
+// LifecycleStore persists the state of a LifecycleTracker, e.g. in the application's
+// database, so that signals survive restarts and are not emitted twice. Keys are
+// "subscription:" or "customer:" followed by the Stripe ID. Implementations must be safe
+// for concurrent use.
+type LifecycleStore interface {
+	// LoadLifecycle returns the state stored under key, and false when there is none.
+	LoadLifecycle(ctx context.Context, key string) (LifecycleState, bool, error)
+	SaveLifecycle(ctx context.Context, key string, state LifecycleState) error
+}
+
+// MemoryLifecycleStore is a LifecycleStore for a single process, e.g. for tests. The zero
+// value is ready to use.
+type MemoryLifecycleStore struct {
+	mu     sync.Mutex
+	states map[string]LifecycleState
+}
+
+func (m *MemoryLifecycleStore) LoadLifecycle(ctx context.Context, key string) (LifecycleState, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[key]
+	return s, ok, nil
+}
+
+func (m *MemoryLifecycleStore) SaveLifecycle(ctx context.Context, key string, state LifecycleState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states == nil {
+		m.states = make(map[string]LifecycleState)
+	}
+	m.states[key] = state
+	return nil
+}
+
+// LifecycleTracker derives LifecycleEvents from subscription events. Its Consume method is
+// an EventConsumer, so it is typically run by a Dispatcher:
+//
+//	tracker := gomultistripe.NewLifecycleTracker(store, func(ctx context.Context, e *gomultistripe.LifecycleEvent) error {
+//		return analytics.Track(e.CustomerID, string(e.Signal), e.At)
+//	})
+//	d := gomultistripe.NewDispatcher(tracker.Consume, gomultistripe.DispatcherConfig{})
+//
+// Events of one subscription must not be consumed concurrently, as the tracker reads and
+// then writes its state; a single worker, or a store that serializes per key, ensures it.
+type LifecycleTracker struct {
+	store    LifecycleStore
+	onSignal func(ctx context.Context, e *LifecycleEvent) error
+}
+
+// NewLifecycleTracker creates a tracker keeping its state in store (a MemoryLifecycleStore
+// when nil) and passing every signal to onSignal. Returning an error from onSignal fails
+// the event, so the dispatcher retries it; the state is then not saved, so the signal is
+// emitted again.
+func NewLifecycleTracker(store LifecycleStore, onSignal func(ctx context.Context, e *LifecycleEvent) error) *LifecycleTracker {
+	if store == nil {
+		store = &MemoryLifecycleStore{}
+	}
+	return &LifecycleTracker{store: store, onSignal: onSignal}
+}
+
+// Consume derives the signals of evt, passes them to onSignal and saves the new state.
+func (t *LifecycleTracker) Consume(ctx context.Context, evt *CallbackEvent) error {
+	signals, commit, err := t.track(ctx, evt)
+	if err != nil {
+		return err
+	}
+	for _, s := range signals {
+		if t.onSignal == nil {
+			continue
+		}
+		if err := t.onSignal(ctx, s); err != nil {
+			return err
+		}
+	}
+	return commit()
+}
+
+// track returns the signals of evt and a function saving the state they lead to. Events
+// other than subscription changes, and those older than the state, have no signals.
+func (t *LifecycleTracker) track(ctx context.Context, evt *CallbackEvent) ([]*LifecycleEvent, func() error, error) {
+	noop := func() error { return nil }
+	switch evt.Type {
+	case EventCustomerSubscriptionCreated,
+		EventCustomerSubscriptionUpdated,
+		EventCustomerSubscriptionDeleted,
+		EventCustomerSubscriptionPaused,
+		EventCustomerSubscriptionResumed:
+	default:
+		return nil, noop, nil
+	}
+	subKey, custKey := "subscription:"+evt.SubscriptionID, "customer:"+evt.CustomerID
+	sub, _, err := t.store.LoadLifecycle(ctx, subKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !sub.UpdatedAt.IsZero() && evt.EventCreatedAt.Before(sub.UpdatedAt) {
+		return nil, noop, nil
+	}
+	var cust LifecycleState
+	if evt.CustomerID != "" {
+		if cust, _, err = t.store.LoadLifecycle(ctx, custKey); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	status := evt.Status
+	if evt.Type == EventCustomerSubscriptionDeleted {
+		status = "canceled"
+	}
+	var signals []LifecycleSignal
+	switch status {
+	case "trialing":
+		if !sub.Trialed {
+			signals = append(signals, SignalTrialStarted)
+			sub.Trialed = true
+		}
+	case "active":
+		if sub.Status == "trialing" {
+			signals = append(signals, SignalTrialConverted)
+		}
+	case "canceled", "incomplete_expired":
+		if !sub.Churned {
+			signals = append(signals, SignalChurned)
+			sub.Churned, cust.Churned = true, true
+		}
+	}
+	// A customer's other subscriptions that stayed live meanwhile do not reactivate them.
+	wasLive := sub.Status == "active" || sub.Status == "trialing" || sub.Status == "past_due"
+	if (status == "active" || status == "trialing") && cust.Churned && !sub.Churned && !wasLive {
+		signals = append(signals, SignalReactivated)
+		cust.Churned = false
+	}
+	sub.Status, sub.UpdatedAt = status, evt.EventCreatedAt
+	cust.UpdatedAt = evt.EventCreatedAt
+
+	out := make([]*LifecycleEvent, len(signals))
+	for i, s := range signals {
+		out[i] = &LifecycleEvent{
+			Signal:         s,
+			SubscriptionID: evt.SubscriptionID,
+			CustomerID:     evt.CustomerID,
+			PriceID:        evt.PriceID,
+			At:             evt.EventCreatedAt,
+			Event:          evt,
+		}
+	}
+	commit := func() error {
+		if err := t.store.SaveLifecycle(ctx, subKey, sub); err != nil {
+			return err
+		}
+		if evt.CustomerID == "" {
+			return nil
+		}
+		return t.store.SaveLifecycle(ctx, custKey, cust)
+	}
+	return out, commit, nil
+}
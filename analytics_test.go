@@ -0,0 +1,59 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLifecycleTracker(t *testing.T) {
+	var got []LifecycleSignal
+	fail := false
+	tracker := NewLifecycleTracker(nil, func(ctx context.Context, e *LifecycleEvent) error {
+		if fail {
+			return errors.New("analytics unavailable")
+		}
+		got = append(got, e.Signal)
+		return nil
+	})
+	at := time.Unix(1700000000, 0)
+	event := func(typ CallbackEventType, sub, status string) *CallbackEvent {
+		at = at.Add(time.Minute)
+		return &CallbackEvent{Type: typ, SubscriptionID: sub, CustomerID: "cus_1", Status: status, EventCreatedAt: at}
+	}
+	ctx := context.Background()
+	late := event(EventCustomerSubscriptionUpdated, "sub_1", "trialing")
+	for _, evt := range []*CallbackEvent{
+		event(EventCustomerSubscriptionCreated, "sub_1", "trialing"),
+		event(EventCustomerSubscriptionUpdated, "sub_1", "trialing"), // redelivery or unrelated change
+		event(EventCustomerSubscriptionCreated, "sub_2", "active"),
+		event(EventCustomerSubscriptionUpdated, "sub_1", "active"),
+		late,
+		event(EventInvoicePaymentSucceeded, "sub_1", ""),
+		event(EventCustomerSubscriptionDeleted, "sub_1", "canceled"),
+		event(EventCustomerSubscriptionUpdated, "sub_2", "active"), // stayed live: no reactivation
+		event(EventCustomerSubscriptionCreated, "sub_3", "active"),
+	} {
+		if err := tracker.Consume(ctx, evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []LifecycleSignal{SignalTrialStarted, SignalTrialConverted, SignalChurned, SignalReactivated}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// A failed signal leaves the state unchanged, so the retry emits it again.
+	got = nil
+	churn := event(EventCustomerSubscriptionDeleted, "sub_3", "canceled")
+	fail = true
+	if err := tracker.Consume(ctx, churn); err == nil {
+		t.Fatal("expected the signal error")
+	}
+	fail = false
+	if err := tracker.Consume(ctx, churn); err != nil || !slices.Equal(got, []LifecycleSignal{SignalChurned}) {
+		t.Fatalf("retry emitted %v (%v)", got, err)
+	}
+}
@@ -0,0 +1,70 @@
+package gomultistripe
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes an idempotency key sent with a mutating Stripe request.
+// Two records with the same key prove that Stripe saw a retry of one logical
+// operation rather than two separate ones.
+type AuditRecord struct {
+	// Operation is the Handler method that issued the request, e.g. "CreatePaymentIntent".
+	Operation string
+	// IdempotencyKey is the Idempotency-Key header sent to Stripe.
+	IdempotencyKey string
+	// EntityIDs holds the Stripe IDs the operation acted on, keyed by object type
+	// (e.g. "customer", "payment_method").
+	EntityIDs map[string]string
+	// Version is the handler version that issued the request.
+	Version string
+	// Account is the connected account the request acted on, from ContextWithAccount.
+	Account string
+	// TraceID is the trace ID from ContextWithTraceID, if any.
+	TraceID string
+	// Time is when the key was assigned.
+	Time time.Time
+}
+
+// AuditSink receives idempotency key audit records. Implementations must be safe for
+// concurrent use and should not block, as they are called inline with Stripe requests.
+type AuditSink interface {
+	RecordIdempotencyKey(ctx context.Context, rec AuditRecord)
+}
+
+var (
+	auditMu   sync.RWMutex
+	auditSink AuditSink
+)
+
+// SetAuditSink installs the sink used by all handlers. Pass nil to disable auditing.
+func SetAuditSink(sink AuditSink) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditSink = sink
+}
+
+// RecordIdempotencyKey forwards rec to the configured audit sink, if any.
+// Handlers call this for every idempotency key they send.
+func RecordIdempotencyKey(ctx context.Context, rec AuditRecord) {
+	auditMu.RLock()
+	sink := auditSink
+	auditMu.RUnlock()
+	if sink == nil {
+		return
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	sink.RecordIdempotencyKey(ctx, rec)
+}
+
+// NewIdempotencyKey returns a random key suitable for the Stripe Idempotency-Key header.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
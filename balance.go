@@ -0,0 +1,134 @@
+package gomultistripe
+
+import "time"
+
+// Balance is the funds in a Stripe account, by lowercase currency.
+type Balance struct {
+	// Available can be paid out or transferred now, and Pending becomes available once the
+	// payments settle.
+	Available map[string]int64
+	Pending   map[string]int64
+	// InstantAvailable can be paid out with instant payouts.
+	InstantAvailable map[string]int64
+	// ConnectReserved is held back to cover the negative balances of connected accounts.
+	ConnectReserved map[string]int64
+}
+
+// Payout represents a Stripe payout to a bank account or debit card in a version-agnostic
+// way.
+type Payout struct {
+	ID       string
+	Amount   int64
+	Currency string
+	// Status is "paid", "pending", "in_transit", "canceled" or "failed".
+	Status string
+	// ArrivalDate is when the funds are expected in the bank account.
+	ArrivalDate time.Time
+	// Automatic is set for payouts created by the account's payout schedule, whose balance
+	// transactions ListBalanceTransactions can filter by PayoutID.
+	Automatic bool
+	// Method is "standard" or "instant", and Type "bank_account" or "card".
+	Method string
+	Type   string
+	// DestinationID is the bank account or card paid out to.
+	DestinationID        string
+	BalanceTransactionID string
+	// FailureCode and FailureMessage explain a failed payout.
+	FailureCode         string
+	FailureMessage      string
+	Description         string
+	StatementDescriptor string
+	Metadata            map[string]string
+	CreatedAt           time.Time
+}
+
+// PayoutQuery filters ListPayouts. Empty fields do not filter.
+type PayoutQuery struct {
+	// Status is one of the Payout statuses.
+	Status        string
+	DestinationID string
+	// ArrivalFrom and ArrivalTo bound the arrival date (both inclusive).
+	ArrivalFrom time.Time
+	ArrivalTo   time.Time
+}
+
+// PayoutPage is one page of payouts.
+type PayoutPage struct {
+	Payouts []*Payout
+	HasMore bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
+
+// BalanceTransaction is a movement of funds in a Stripe balance, such as a charge, a
+// refund, a fee or a payout.
+type BalanceTransaction struct {
+	ID string
+	// Amount is the gross amount, Fee the Stripe fees on it and Net what the balance
+	// changed by, all in Currency, the balance's currency. They are negative for funds
+	// leaving the balance.
+	Amount   int64
+	Fee      int64
+	Net      int64
+	Currency string
+	// ExchangeRate converted the source's currency into Currency, or is 0.
+	ExchangeRate float64
+	// PresentmentAmount and PresentmentCurrency are the amount the customer paid, was
+	// refunded or disputed in their own currency, before conversion into Currency at
+	// ExchangeRate, with the sign of Amount. They equal Amount and Currency when nothing was
+	// converted, and are otherwise only set when listed with ExpandSources.
+	PresentmentAmount   int64
+	PresentmentCurrency string
+	// ReportingCurrency, ReportingRate and the Reporting amounts are set when listed with
+	// BalanceTransactionQuery.ReportingCurrency: Amount, Fee and Net converted into
+	// ReportingCurrency at ReportingRate.
+	ReportingCurrency string
+	ReportingRate     float64
+	ReportingAmount   int64
+	ReportingFee      int64
+	ReportingNet      int64
+	// Type is e.g. "charge", "refund", "payout", "transfer" or "stripe_fee", and
+	// ReportingCategory the category Stripe's financial reports group it by.
+	Type              string
+	ReportingCategory string
+	// Status is "available" or "pending", until AvailableOn.
+	Status      string
+	AvailableOn time.Time
+	// SourceID is the object that caused the transaction, e.g. a charge or payout.
+	SourceID string
+	// ChargeID and PaymentIntentID are the payment a charge, refund or dispute source
+	// belongs to. They are only set when listed with BalanceTransactionQuery.ExpandSources.
+	ChargeID        string
+	PaymentIntentID string
+	Description     string
+	CreatedAt       time.Time
+}
+
+// BalanceTransactionQuery filters ListBalanceTransactions. Empty fields do not filter.
+type BalanceTransactionQuery struct {
+	// PayoutID lists the transactions settled by an automatic payout, to reconcile it.
+	PayoutID string
+	Type     string
+	Currency string
+	SourceID string
+	// CreatedFrom and CreatedTo bound the creation time, [CreatedFrom, CreatedTo).
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// ExpandSources fetches each transaction's source with it, to set ChargeID,
+	// PaymentIntentID and the presentment amount. It makes the responses larger, so leave it
+	// unset when not needed.
+	ExpandSources bool
+	// ReportingCurrency converts every transaction's amounts into this currency, for
+	// consolidated reporting across balances; see ConvertToReportingCurrency. ReportingRates
+	// provides the rates Stripe's own exchange rate does not.
+	ReportingCurrency string
+	ReportingRates    ExchangeRates
+}
+
+// BalanceTransactionPage is one page of balance transactions.
+type BalanceTransactionPage struct {
+	Transactions []*BalanceTransaction
+	HasMore      bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,75 @@
+package gomultistripe
+
+import "errors"
+
+// ErrBankTransferRequiresCustomer is returned when a payment intent funded by bank transfer
+// is created without a customer. Transfers are credited to the customer's cash balance,
+// which Stripe then applies to the customer's open payment intents.
+var ErrBankTransferRequiresCustomer = errors.New("bank transfer requires a customer")
+
+// Bank transfer types, by the region of the virtual account the customer pays into.
+const (
+	BankTransferEU = "eu_bank_transfer"
+	BankTransferGB = "gb_bank_transfer"
+	BankTransferJP = "jp_bank_transfer"
+	BankTransferMX = "mx_bank_transfer"
+	BankTransferUS = "us_bank_transfer"
+)
+
+// BankTransfer pays a payment intent with the customer_balance payment method: the customer
+// sends a bank transfer to a virtual account, and Stripe applies the funds to the intent.
+type BankTransfer struct {
+	// Type is one of the BankTransfer types, e.g. BankTransferEU.
+	Type string
+	// Country is the country of the IBAN for BankTransferEU: "BE", "DE", "ES", "FR", "IE"
+	// or "NL".
+	Country string
+}
+
+// FundingInstructions tell the customer how to fund a payment intent paid by BankTransfer.
+type FundingInstructions struct {
+	// Type is the BankTransfer type of the intent.
+	Type string
+	// AmountRemaining is the amount, in Currency, still to be transferred.
+	AmountRemaining int64
+	Currency        string
+	// Reference must be included with the transfer, so Stripe can match it to the intent.
+	Reference string
+	// HostedInstructionsURL is a Stripe-hosted page with the instructions, to share with
+	// the customer.
+	HostedInstructionsURL string
+	// Addresses are the accounts the customer can transfer to.
+	Addresses []FinancialAddress
+}
+
+// FinancialAddress is a virtual bank account that receives transfers to a customer's cash
+// balance. Which fields are set depends on Type.
+type FinancialAddress struct {
+	// Type is "iban", "sort_code", "aba", "swift", "spei" or "zengin". The details of aba
+	// and swift addresses are only populated with v76 and later, and their
+	// AccountHolderName and AccountType with v81 and later.
+	Type              string
+	AccountHolderName string
+	BankName          string
+	// AccountNumber is set for sort_code, aba, swift and zengin addresses, and AccountType
+	// for aba, swift and zengin ones.
+	AccountNumber string
+	AccountType   string
+	// IBAN, BIC and Country are set for iban addresses.
+	IBAN    string
+	BIC     string
+	Country string
+	// SortCode is set for sort_code addresses.
+	SortCode string
+	// RoutingNumber is set for aba addresses.
+	RoutingNumber string
+	// SwiftCode is set for swift addresses.
+	SwiftCode string
+	// CLABE is set for spei addresses.
+	CLABE string
+	// BankCode is set for spei and zengin addresses, BranchCode and BranchName for zengin
+	// ones.
+	BankCode   string
+	BranchCode string
+	BranchName string
+}
@@ -0,0 +1,180 @@
+package gomultistripe
+
+import (
+	"context"
+	"time"
+)
+
+// BillingNotifier sends the billing communications customers expect, e.g. emails, from
+// the normalized data of the webhook events that call for them. Implementations only
+// render and send the message; NotifyBilling decides when. Embed NopBillingNotifier to
+// implement only some of them.
+//
+// Webhooks are delivered at least once, so a notice may be repeated; its EventID is a
+// stable key to drop repeats by.
+type BillingNotifier interface {
+	// TrialEnding is called for customer.subscription.trial_will_end, usually three days
+	// before the trial ends.
+	TrialEnding(ctx context.Context, n *TrialEndingNotice) error
+	// PaymentFailed is called for invoice.payment_failed, on every failed attempt.
+	PaymentFailed(ctx context.Context, n *PaymentFailedNotice) error
+	// CardExpiring is called for customer.source.expiring, early in the month a saved card
+	// expires.
+	CardExpiring(ctx context.Context, n *CardExpiringNotice) error
+	// SubscriptionCanceled is called for customer.subscription.deleted, once the
+	// subscription has ended.
+	SubscriptionCanceled(ctx context.Context, n *SubscriptionCanceledNotice) error
+}
+
+// TrialEndingNotice tells a customer their trial is about to convert to a paid
+// subscription.
+type TrialEndingNotice struct {
+	EventID        string
+	CustomerID     string
+	SubscriptionID string
+	PriceID        string
+	TrialEnd       time.Time
+	// DaysRemaining is the whole days left in the trial, rounded up.
+	DaysRemaining int
+	Metadata      map[string]string
+}
+
+// PaymentFailedNotice tells a customer a subscription or invoice payment failed.
+type PaymentFailedNotice struct {
+	EventID        string
+	CustomerID     string
+	SubscriptionID string
+	InvoiceID      string
+	Amount         int64
+	Currency       string
+	// AttemptCount is the number of attempts so far. NextPaymentAttempt is when payment is
+	// retried, or zero when no retry is scheduled and the customer must act.
+	AttemptCount       int64
+	NextPaymentAttempt time.Time
+	// ErrorCode, DeclineCode and Message describe the failure, when the invoice's payment
+	// reported one.
+	ErrorCode   string
+	DeclineCode string
+	Message     string
+	Metadata    map[string]string
+}
+
+// FinalAttempt reports whether no further automatic attempt is scheduled.
+func (n *PaymentFailedNotice) FinalAttempt() bool {
+	return n.NextPaymentAttempt.IsZero()
+}
+
+// CardExpiringNotice asks a customer to update a saved card before it expires.
+type CardExpiringNotice struct {
+	EventID    string
+	CustomerID string
+	// PaymentMethodID is the card's ID.
+	PaymentMethodID string
+	Brand           string
+	Last4           string
+	ExpMonth        uint
+	ExpYear         uint
+	Metadata        map[string]string
+}
+
+// SubscriptionCanceledNotice confirms to a customer that their subscription ended.
+type SubscriptionCanceledNotice struct {
+	EventID        string
+	CustomerID     string
+	SubscriptionID string
+	PriceID        string
+	CanceledAt     time.Time
+	Metadata       map[string]string
+}
+
+// NopBillingNotifier is a BillingNotifier that sends nothing.
+type NopBillingNotifier struct{}
+
+func (NopBillingNotifier) TrialEnding(context.Context, *TrialEndingNotice) error { return nil }
+
+func (NopBillingNotifier) PaymentFailed(context.Context, *PaymentFailedNotice) error { return nil }
+
+func (NopBillingNotifier) CardExpiring(context.Context, *CardExpiringNotice) error { return nil }
+
+func (NopBillingNotifier) SubscriptionCanceled(context.Context, *SubscriptionCanceledNotice) error {
+	return nil
+}
+
+// NotifyBilling wraps consumer so that, once it has processed an event, the billing
+// communication the event calls for is passed to notifier. consumer may be nil to only
+// notify:
+//
+//	d := gomultistripe.NewDispatcher(gomultistripe.NotifyBilling(mailer, consume), cfg)
+//
+// Notifier errors are returned, so the dispatcher retries the event, consumer included.
+// Wrap the result in Deduplicate to process each event once.
+func NotifyBilling(notifier BillingNotifier, consumer EventConsumer) EventConsumer {
+	return func(ctx context.Context, evt *CallbackEvent) error {
+		if consumer != nil {
+			if err := consumer(ctx, evt); err != nil {
+				return err
+			}
+		}
+		return notifyBilling(ctx, notifier, evt)
+	}
+}
+
+// notifyBilling calls the notifier method for evt's type, if any.
+func notifyBilling(ctx context.Context, notifier BillingNotifier, evt *CallbackEvent) error {
+	switch evt.Type {
+	case EventCustomerSubscriptionTrialWillEnd:
+		return notifier.TrialEnding(ctx, &TrialEndingNotice{
+			EventID:        evt.EventID,
+			CustomerID:     evt.CustomerID,
+			SubscriptionID: evt.SubscriptionID,
+			PriceID:        evt.PriceID,
+			TrialEnd:       unixTime(evt.TrialEnd),
+			DaysRemaining:  evt.DaysRemaining,
+			Metadata:       evt.Metadata,
+		})
+	case EventInvoicePaymentFailed:
+		return notifier.PaymentFailed(ctx, &PaymentFailedNotice{
+			EventID:            evt.EventID,
+			CustomerID:         evt.CustomerID,
+			SubscriptionID:     evt.SubscriptionID,
+			InvoiceID:          evt.InvoiceID,
+			Amount:             evt.Amount,
+			Currency:           evt.Currency,
+			AttemptCount:       evt.AttemptCount,
+			NextPaymentAttempt: unixTime(evt.NextPaymentAttempt),
+			ErrorCode:          evt.LastPaymentErrorCode,
+			DeclineCode:        evt.LastPaymentErrorDeclineCode,
+			Message:            evt.LastPaymentErrorMsg,
+			Metadata:           evt.Metadata,
+		})
+	case EventCustomerSourceExpiring:
+		return notifier.CardExpiring(ctx, &CardExpiringNotice{
+			EventID:         evt.EventID,
+			CustomerID:      evt.CustomerID,
+			PaymentMethodID: evt.PaymentMethodID,
+			Brand:           evt.CardBrand,
+			Last4:           evt.CardLast4,
+			ExpMonth:        evt.CardExpMonth,
+			ExpYear:         evt.CardExpYear,
+			Metadata:        evt.Metadata,
+		})
+	case EventCustomerSubscriptionDeleted:
+		return notifier.SubscriptionCanceled(ctx, &SubscriptionCanceledNotice{
+			EventID:        evt.EventID,
+			CustomerID:     evt.CustomerID,
+			SubscriptionID: evt.SubscriptionID,
+			PriceID:        evt.PriceID,
+			CanceledAt:     unixTime(evt.CanceledAt),
+			Metadata:       evt.Metadata,
+		})
+	}
+	return nil
+}
+
+// unixTime converts a unix time to a time.Time, keeping 0 as the zero time.
+func unixTime(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(t, 0)
+}
@@ -0,0 +1,59 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type failedPayments struct {
+	NopBillingNotifier
+	notices []*PaymentFailedNotice
+	err     error
+}
+
+func (f *failedPayments) PaymentFailed(ctx context.Context, n *PaymentFailedNotice) error {
+	f.notices = append(f.notices, n)
+	return f.err
+}
+
+func TestNotifyBilling(t *testing.T) {
+	notifier := &failedPayments{}
+	consumerErr := errors.New("consumer failed")
+	var consumed int
+	consume := NotifyBilling(notifier, func(ctx context.Context, evt *CallbackEvent) error {
+		consumed++
+		if evt.EventID == "evt_fail" {
+			return consumerErr
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	final := &CallbackEvent{Type: EventInvoicePaymentFailed, EventID: "evt_1", InvoiceID: "in_1", Amount: 2000, Currency: "usd", AttemptCount: 4}
+	if err := consume(ctx, final); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.notices) != 1 || notifier.notices[0].InvoiceID != "in_1" || !notifier.notices[0].FinalAttempt() {
+		t.Fatalf("notices %+v", notifier.notices)
+	}
+	retried := &CallbackEvent{Type: EventInvoicePaymentFailed, EventID: "evt_2", NextPaymentAttempt: 1700000000}
+	if err := consume(ctx, retried); err != nil || notifier.notices[1].FinalAttempt() {
+		t.Errorf("retried payment: %v, notice %+v", err, notifier.notices[1])
+	}
+
+	if err := consume(ctx, &CallbackEvent{Type: EventCustomerSubscriptionTrialWillEnd, EventID: "evt_3"}); err != nil {
+		t.Errorf("unimplemented notice: %v", err)
+	}
+	if err := consume(ctx, &CallbackEvent{Type: EventInvoicePaymentFailed, EventID: "evt_fail"}); !errors.Is(err, consumerErr) || len(notifier.notices) != 2 {
+		t.Errorf("notified after the consumer failed: %v", err)
+	}
+
+	notifier.err = errors.New("smtp unavailable")
+	if err := consume(ctx, final); !errors.Is(err, notifier.err) {
+		t.Errorf("notifier error %v not returned", err)
+	}
+	if consumed != 5 {
+		t.Errorf("consumer called %d times", consumed)
+	}
+}
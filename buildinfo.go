@@ -0,0 +1,53 @@
+package gomultistripe
+
+import "runtime/debug"
+
+// stripeModulePrefix is the module path of stripe-go without its major version suffix.
+const stripeModulePrefix = "github.com/stripe/stripe-go/"
+
+// HandlerBuildInfo describes the stripe-go module a registered handler was compiled against.
+type HandlerBuildInfo struct {
+	// Version is the handler version, e.g. "v82".
+	Version string
+	// APIVersion is the Stripe API version the SDK is pinned to.
+	APIVersion string
+	// SDKModule is the stripe-go module path, e.g. "github.com/stripe/stripe-go/v82".
+	SDKModule string
+	// SDKVersion is the exact module version linked into the binary, e.g. "v82.0.0". It is
+	// empty when the binary carries no module information.
+	SDKVersion string
+	// SDKSum is the module checksum from go.sum, if recorded.
+	SDKSum string
+	// Replaced is true when the module was replaced by a go.mod replace directive, in which
+	// case SDKVersion and SDKSum describe the replacement.
+	Replaced bool
+}
+
+// BuildInfo reports, for every registered handler, the stripe-go version compiled into the
+// running binary. Useful when investigating incidents to know which SDK patch was deployed.
+func BuildInfo() []HandlerBuildInfo {
+	deps := make(map[string]*debug.Module)
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			deps[dep.Path] = dep
+		}
+	}
+	var out []HandlerBuildInfo
+	for _, h := range Handlers() {
+		info := HandlerBuildInfo{
+			Version:    h.Version(),
+			APIVersion: h.APIVersion(),
+			SDKModule:  stripeModulePrefix + h.Version(),
+		}
+		if dep, ok := deps[info.SDKModule]; ok {
+			if dep.Replace != nil {
+				dep = dep.Replace
+				info.Replaced = true
+			}
+			info.SDKVersion = dep.Version
+			info.SDKSum = dep.Sum
+		}
+		out = append(out, info)
+	}
+	return out
+}
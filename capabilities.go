@@ -0,0 +1,67 @@
+package gomultistripe
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+//go:generate go run ./cmd/generate_capabilities
+
+// Support describes how completely a handler version implements a Handler method.
+type Support string
+
+const (
+	// SupportFull means the method is implemented without unsupported code paths.
+	SupportFull Support = "supported"
+	// SupportPartial means some options or inputs return ErrUnsupported, listed in
+	// MethodCapability.Unsupported.
+	SupportPartial Support = "partial"
+	// SupportNone means the method is a stub: it always returns ErrUnsupported, panics or
+	// returns only nil values.
+	SupportNone Support = "unsupported"
+)
+
+// MethodCapability is the support level of one Handler method in one version.
+type MethodCapability struct {
+	Support Support `json:"support"`
+	// Unsupported lists the features the method rejects, as named in its Unsupported calls.
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// CapabilityMatrix maps handler version (e.g. "v82") to Handler method name to its support.
+type CapabilityMatrix map[string]map[string]MethodCapability
+
+// Supported reports whether the method can be called on the given version, possibly with
+// some options unavailable. Unknown versions and methods are not supported.
+func (m CapabilityMatrix) Supported(version, method string) bool {
+	c, ok := m[version][method]
+	return ok && c.Support != SupportNone
+}
+
+//go:embed capabilities.json
+var capabilitiesJSON []byte
+
+var (
+	capabilitiesOnce sync.Once
+	capabilities     CapabilityMatrix
+)
+
+// Capabilities returns the method support matrix of every version directory in this
+// module, generated from the handler sources by cmd/generate_capabilities. The returned
+// matrix is a copy and may be modified by the caller.
+func Capabilities() CapabilityMatrix {
+	capabilitiesOnce.Do(func() {
+		if err := json.Unmarshal(capabilitiesJSON, &capabilities); err != nil {
+			panic("gomultistripe: invalid capabilities.json: " + err.Error())
+		}
+	})
+	out := make(CapabilityMatrix, len(capabilities))
+	for version, methods := range capabilities {
+		out[version] = make(map[string]MethodCapability, len(methods))
+		for name, c := range methods {
+			out[version][name] = c
+		}
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package gomultistripe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities_CoverHandlerInterface(t *testing.T) {
+	matrix := Capabilities()
+	if len(matrix) == 0 {
+		t.Fatal("capabilities.json has no versions")
+	}
+	iface := reflect.TypeOf((*Handler)(nil)).Elem()
+	for version, methods := range matrix {
+		for i := 0; i < iface.NumMethod(); i++ {
+			name := iface.Method(i).Name
+			if _, ok := methods[name]; !ok {
+				t.Errorf("%s: no capability for %s; run go generate", version, name)
+			}
+		}
+	}
+}
+
+func TestCapabilities_ReturnsCopy(t *testing.T) {
+	Capabilities()["v82"] = nil
+	if Capabilities()["v82"] == nil {
+		t.Fatal("modifying the returned matrix changed the embedded one")
+	}
+}
@@ -0,0 +1,37 @@
+package gomultistripe
+
+import "time"
+
+// Charge represents a Stripe charge in a version-agnostic way. Webhook events and payment
+// intents reference charges by ID (CallbackEvent.ChargeID, PaymentIntent.LatestChargeID);
+// Handler.RetrieveCharge resolves them.
+type Charge struct {
+	ID              string
+	CustomerID      string
+	PaymentIntentID string
+	// InvoiceID is the invoice the charge paid, before the basil API version (v82), which
+	// moved the link to invoice payments.
+	InvoiceID       string
+	PaymentMethodID string
+	Amount          int64
+	AmountCaptured  int64
+	AmountRefunded  int64
+	Currency        string
+	// Status is "succeeded", "pending" or "failed".
+	Status   string
+	Paid     bool
+	Captured bool
+	// Refunded is set once the charge is refunded in full.
+	Refunded bool
+	// FailureCode and FailureMessage explain a failed charge, e.g. "card_declined".
+	FailureCode    string
+	FailureMessage string
+	// ReceiptURL is the page showing the charge's receipt.
+	ReceiptURL string
+	// BalanceTransactionID links the charge to its balance transaction (fees and net amount),
+	// once the funds have reached the balance.
+	BalanceTransactionID string
+	Description          string
+	Metadata             map[string]string
+	CreatedAt            time.Time
+}
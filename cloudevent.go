@@ -0,0 +1,150 @@
+package gomultistripe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CloudEventsSpecVersion is the CloudEvents specification version of the events
+// ToCloudEvent produces and FromCloudEvent accepts.
+const CloudEventsSpecVersion = "1.0"
+
+const (
+	// DefaultCloudEventSource is the source of CloudEvents when none is set.
+	DefaultCloudEventSource = "https://api.stripe.com"
+	// DefaultCloudEventTypePrefix is prepended to the Stripe event type to form the
+	// CloudEvent type, e.g. "com.stripe.invoice.payment_succeeded".
+	DefaultCloudEventTypePrefix = "com.stripe."
+)
+
+// ErrNotCallbackCloudEvent is returned by FromCloudEvent for CloudEvents that were not
+// produced by ToCloudEvent with the same options.
+var ErrNotCallbackCloudEvent = errors.New("CloudEvent does not carry a callback event")
+
+// CloudEvent is a CloudEvents 1.0 event, which marshals to the structured JSON format.
+type CloudEvent struct {
+	SpecVersion string `json:"specversion"`
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	// Subject is the ID of the object the event is about, e.g. the subscription.
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time,omitzero"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	// DataBase64 is only read, from events whose producer encoded the data as binary.
+	DataBase64 string `json:"data_base64,omitempty"`
+}
+
+// CloudEventOptions configures ToCloudEvent and FromCloudEvent.
+type CloudEventOptions struct {
+	Source     string
+	TypePrefix string
+}
+
+// CloudEventOption configures CloudEventOptions.
+type CloudEventOption func(*CloudEventOptions)
+
+// WithCloudEventSource sets the source attribute, e.g. the URL of the service that
+// received the webhook. It defaults to DefaultCloudEventSource.
+func WithCloudEventSource(source string) CloudEventOption {
+	return func(o *CloudEventOptions) { o.Source = source }
+}
+
+// WithCloudEventTypePrefix sets the prefix of the type attribute. It defaults to
+// DefaultCloudEventTypePrefix.
+func WithCloudEventTypePrefix(prefix string) CloudEventOption {
+	return func(o *CloudEventOptions) { o.TypePrefix = prefix }
+}
+
+// NewCloudEventOptions applies opts to the defaults.
+func NewCloudEventOptions(opts ...CloudEventOption) CloudEventOptions {
+	o := CloudEventOptions{Source: DefaultCloudEventSource, TypePrefix: DefaultCloudEventTypePrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// ToCloudEvent converts the event to a CloudEvent whose data is the event as JSON. Its ID
+// is the Stripe event ID, so consumers can deduplicate redeliveries; events without one
+// get a random ID.
+func (evt *CallbackEvent) ToCloudEvent(opts ...CloudEventOption) (*CloudEvent, error) {
+	o := NewCloudEventOptions(opts...)
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+	id := evt.EventID
+	if id == "" {
+		id = NewIdempotencyKey()
+	}
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          o.Source,
+		Type:            o.TypePrefix + string(evt.Type),
+		Subject:         evt.subject(),
+		Time:            evt.EventCreatedAt,
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
+
+// subject returns the ID of the object the event is about.
+func (evt *CallbackEvent) subject() string {
+	switch {
+	case strings.HasPrefix(string(evt.Type), "customer.subscription."):
+		return evt.SubscriptionID
+	case strings.HasPrefix(string(evt.Type), "invoice."):
+		return evt.InvoiceID
+	case strings.HasPrefix(string(evt.Type), "payment_intent."):
+		return evt.PaymentIntentID
+	case strings.HasPrefix(string(evt.Type), "setup_intent."):
+		return evt.SetupIntentID
+	case strings.HasPrefix(string(evt.Type), "refund."):
+		return evt.RefundID
+	case strings.HasPrefix(string(evt.Type), "charge."):
+		return evt.ChargeID
+	}
+	return ""
+}
+
+// FromCloudEvent converts a CloudEvent produced by ToCloudEvent back to a callback event.
+// Pass the options ToCloudEvent was called with. Release the event as one returned by a
+// handler.
+func FromCloudEvent(ce *CloudEvent, opts ...CloudEventOption) (*CallbackEvent, error) {
+	o := NewCloudEventOptions(opts...)
+	if ce.SpecVersion != CloudEventsSpecVersion {
+		return nil, fmt.Errorf("unsupported CloudEvents specversion %q", ce.SpecVersion)
+	}
+	eventType, ok := strings.CutPrefix(ce.Type, o.TypePrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: type %q", ErrNotCallbackCloudEvent, ce.Type)
+	}
+	if ce.DataContentType != "" && ce.DataContentType != "application/json" {
+		return nil, fmt.Errorf("%w: datacontenttype %q", ErrNotCallbackCloudEvent, ce.DataContentType)
+	}
+	data := []byte(ce.Data)
+	if len(data) == 0 && ce.DataBase64 != "" {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(ce.DataBase64); err != nil {
+			return nil, fmt.Errorf("failed to decode CloudEvent data_base64: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: no data", ErrNotCallbackCloudEvent)
+	}
+	var evt CallbackEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil, fmt.Errorf("failed to parse CloudEvent data: %w", err)
+	}
+	if string(evt.Type) != eventType {
+		return nil, fmt.Errorf("%w: type %q carries a %s event", ErrNotCallbackCloudEvent, ce.Type, evt.Type)
+	}
+	return NewCallbackEvent(evt), nil
+}
@@ -0,0 +1,55 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCloudEventRoundTrip(t *testing.T) {
+	evt := &CallbackEvent{
+		Type:           EventInvoicePaymentFailed,
+		EventID:        "evt_1",
+		EventCreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		InvoiceID:      "in_1",
+		SubscriptionID: "sub_1",
+		Amount:         1000,
+		InvoiceLines:   []InvoiceLine{{ID: "il_1", Amount: 1000}},
+		Metadata:       map[string]string{"spid": "42"},
+	}
+	ce, err := evt.ToCloudEvent(WithCloudEventSource("/billing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ce.ID != "evt_1" || ce.Type != "com.stripe.invoice.payment_failed" || ce.Subject != "in_1" || ce.Source != "/billing" {
+		t.Errorf("got %+v", ce)
+	}
+
+	wire, err := json.Marshal(ce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attrs map[string]any
+	json.Unmarshal(wire, &attrs)
+	if attrs["specversion"] != "1.0" || attrs["time"] != "2025-01-02T03:04:05Z" || attrs["data_base64"] != nil {
+		t.Errorf("marshaled %s", wire)
+	}
+
+	var decoded CloudEvent
+	if err := json.Unmarshal(wire, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FromCloudEvent(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.InvoiceID != "in_1" || got.Metadata["spid"] != "42" || len(got.InvoiceLines) != 1 || !got.EventCreatedAt.Equal(evt.EventCreatedAt) {
+		t.Errorf("got %+v", got)
+	}
+
+	decoded.Type = "io.example.order.created"
+	if _, err := FromCloudEvent(&decoded); !errors.Is(err, ErrNotCallbackCloudEvent) {
+		t.Errorf("foreign type: got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "github.com/iqhive/cfggo"
+
+type Config struct {
+	cfggo.Structure
+	Dir       func() string  `cfggo:"dir" default:"" help:"Repository root (defaults to the current directory or ../..)"`
+	Format    func() string  `cfggo:"format" default:"text" help:"Report format: text or json"`
+	FailUnder func() float64 `cfggo:"fail_under" default:"0" help:"Exit non-zero if any version's coverage percentage is below this value"`
+	Missing   func() bool    `cfggo:"missing" default:"false" help:"List unhandled event types in the text report"`
+}
+
+var config Config
+
+func loadConfig() {
+	config.Init(&config)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VersionCoverage reports which Stripe event types a version's callback.go handles.
+type VersionCoverage struct {
+	Version    string `json:"version"`
+	SDKVersion string `json:"sdk_version"`
+	// Known is the number of EventType constants the SDK defines. Zero for SDKs (such as
+	// v74) that model event types as plain strings.
+	Known    int      `json:"known"`
+	Handled  []string `json:"handled"`
+	Missing  []string `json:"missing"`
+	Coverage float64  `json:"coverage_percent"`
+}
+
+// constantValues parses the string constants of the given type from a Go source file,
+// keyed by constant name.
+func constantValues(path, typeName string) (map[string]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			ident, ok := vs.Type.(*ast.Ident)
+			if !ok || ident.Name != typeName {
+				continue
+			}
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				v, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return nil, err
+				}
+				values[name.Name] = v
+			}
+		}
+	}
+	return values, nil
+}
+
+// handledEventTypes returns the event types matched by case clauses in callback.go. Cases
+// may use SDK constants (stripe.EventTypeX), package constants (gomultistripe.EventX,
+// possibly wrapped in string()) or string literals.
+func handledEventTypes(path string, sdk, pkg map[string]string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var resolve func(expr ast.Expr) string
+	resolve = func(expr ast.Expr) string {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if v, err := strconv.Unquote(e.Value); err == nil {
+				return v
+			}
+		case *ast.SelectorExpr:
+			if v, ok := sdk[e.Sel.Name]; ok {
+				return v
+			}
+			return pkg[e.Sel.Name]
+		case *ast.CallExpr:
+			if len(e.Args) == 1 {
+				return resolve(e.Args[0])
+			}
+		}
+		return ""
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		cc, ok := n.(*ast.CaseClause)
+		if !ok {
+			return true
+		}
+		for _, expr := range cc.List {
+			if v := resolve(expr); strings.Contains(v, ".") {
+				seen[v] = true
+			}
+		}
+		return true
+	})
+	return sortedKeys(seen), nil
+}
+
+// sdkDir locates the source of the stripe-go module required for the given major.
+func sdkDir(baseDir, version string) (dir, sdkVersion string, err error) {
+	cmd := exec.Command("go", "list", "-m", "-json", "github.com/stripe/stripe-go/"+version)
+	cmd.Dir = baseDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("go list stripe-go/%s: %v", version, err)
+	}
+	var mod struct {
+		Dir     string
+		Version string
+	}
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return "", "", err
+	}
+	if mod.Dir == "" {
+		return "", "", fmt.Errorf("stripe-go/%s is not downloaded; run go mod download", version)
+	}
+	return mod.Dir, mod.Version, nil
+}
+
+var versionDirRe = regexp.MustCompile(`^v\d+$`)
+
+// checkCoverage computes the event coverage of every version directory under baseDir.
+func checkCoverage(baseDir string) ([]VersionCoverage, error) {
+	pkg, err := constantValues(filepath.Join(baseDir, "handler.go"), "CallbackEventType")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	var out []VersionCoverage
+	for _, entry := range entries {
+		if !entry.IsDir() || !versionDirRe.MatchString(entry.Name()) {
+			continue
+		}
+		version := entry.Name()
+		dir, sdkVersion, err := sdkDir(baseDir, version)
+		if err != nil {
+			return nil, err
+		}
+		sdk, err := constantValues(filepath.Join(dir, "event.go"), "EventType")
+		if err != nil {
+			return nil, err
+		}
+		handled, err := handledEventTypes(filepath.Join(baseDir, version, "callback.go"), sdk, pkg)
+		if err != nil {
+			return nil, err
+		}
+		known := make(map[string]bool, len(sdk))
+		for _, v := range sdk {
+			known[v] = true
+		}
+		vc := VersionCoverage{Version: version, SDKVersion: sdkVersion, Known: len(known), Handled: handled, Missing: []string{}}
+		isHandled := make(map[string]bool, len(handled))
+		for _, h := range handled {
+			isHandled[h] = true
+		}
+		for _, v := range sortedKeys(known) {
+			if !isHandled[v] {
+				vc.Missing = append(vc.Missing, v)
+			}
+		}
+		if vc.Known > 0 {
+			vc.Coverage = 100 * float64(vc.Known-len(vc.Missing)) / float64(vc.Known)
+		}
+		out = append(out, vc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, _ := strconv.Atoi(out[i].Version[1:])
+		b, _ := strconv.Atoi(out[j].Version[1:])
+		return a < b
+	})
+	return out, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeSource(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "src.go")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConstantValues(t *testing.T) {
+	path := writeSource(t, `package stripe
+
+type EventType string
+
+const (
+	EventTypeChargeSucceeded EventType = "charge.succeeded"
+	EventTypeChargeFailed    EventType = "charge.failed"
+	other                              = "ignored"
+)
+
+const Untyped = "also.ignored"
+`)
+	got, err := constantValues(path, "EventType")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"EventTypeChargeSucceeded": "charge.succeeded", "EventTypeChargeFailed": "charge.failed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constants %v, want %v", got, want)
+	}
+}
+
+func TestHandledEventTypes(t *testing.T) {
+	path := writeSource(t, `package v82
+
+func handle(t string) {
+	switch t {
+	case stripe.EventTypeChargeSucceeded, "invoice.paid":
+	case string(gomultistripe.EventSubscriptionDeleted):
+	case "not_an_event", stripe.EventTypeUnknown:
+	}
+}
+`)
+	sdk := map[string]string{"EventTypeChargeSucceeded": "charge.succeeded"}
+	pkg := map[string]string{"EventSubscriptionDeleted": "customer.subscription.deleted"}
+	got, err := handledEventTypes(path, sdk, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"charge.succeeded", "customer.subscription.deleted", "invoice.paid"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	report := []VersionCoverage{
+		{Version: "v74", SDKVersion: "v74.30.0", Handled: []string{"charge.succeeded"}},
+		{Version: "v82", SDKVersion: "v82.1.0", Known: 4, Handled: []string{"a.b", "c.d", "e.f"}, Missing: []string{"g.h"}, Coverage: 75},
+	}
+	var out strings.Builder
+	if err := writeReport(&out, report, "text", true); err != nil {
+		t.Fatal(err)
+	}
+	want := `v74 (stripe-go v74.30.0): 1 handled, SDK defines no event type constants
+v82 (stripe-go v82.1.0): 3/4 event types handled (75.0%)
+  missing g.h
+`
+	if out.String() != want {
+		t.Errorf("text report:\n%s\nwant:\n%s", out.String(), want)
+	}
+	if err := writeReport(&out, report, "xml", false); err == nil {
+		t.Error("accepted an unknown format")
+	}
+}
@@ -0,0 +1,65 @@
+// Command check_event_coverage reports, for each version directory, which event types
+// defined by that version's stripe-go SDK are not handled by its callback.go.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+func main() {
+	loadConfig()
+
+	baseDir := config.Dir()
+	if baseDir == "" {
+		baseDir = "."
+		if _, err := os.Stat("handler.go"); err != nil {
+			baseDir = "../.."
+		}
+	}
+
+	report, err := checkCoverage(baseDir)
+	if err != nil {
+		slog.Error("checking event coverage failed", "error", err)
+		os.Exit(1)
+	}
+	if err := writeReport(os.Stdout, report, config.Format(), config.Missing()); err != nil {
+		slog.Error("writing report failed", "error", err)
+		os.Exit(2)
+	}
+	for _, vc := range report {
+		if vc.Known > 0 && vc.Coverage < config.FailUnder() {
+			slog.Error("event coverage below threshold", "version", vc.Version, "coverage", vc.Coverage, "fail_under", config.FailUnder())
+			os.Exit(1)
+		}
+	}
+}
+
+func writeReport(w io.Writer, report []VersionCoverage, format string, listMissing bool) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case "text":
+		for _, vc := range report {
+			if vc.Known == 0 {
+				fmt.Fprintf(w, "%s (stripe-go %s): %d handled, SDK defines no event type constants\n", vc.Version, vc.SDKVersion, len(vc.Handled))
+				continue
+			}
+			fmt.Fprintf(w, "%s (stripe-go %s): %d/%d event types handled (%.1f%%)\n",
+				vc.Version, vc.SDKVersion, vc.Known-len(vc.Missing), vc.Known, vc.Coverage)
+			if listMissing {
+				for _, m := range vc.Missing {
+					fmt.Fprintf(w, "  missing %s\n", m)
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (want text or json)", format)
+	}
+}
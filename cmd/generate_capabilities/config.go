@@ -0,0 +1,15 @@
+package main
+
+import "github.com/iqhive/cfggo"
+
+type Config struct {
+	cfggo.Structure
+	Dir   func() string `cfggo:"dir" default:"" help:"Repository root (defaults to the current directory or ../..)"`
+	Check func() bool   `cfggo:"check" default:"false" help:"Do not write capabilities.json; fail if it is stale or a method lost support"`
+}
+
+var config Config
+
+func loadConfig() {
+	config.Init(&config)
+}
@@ -0,0 +1,71 @@
+// Command generate_capabilities writes capabilities.json, the matrix of Handler method
+// support per version directory that gomultistripe.Capabilities() exposes at runtime.
+// With --check it leaves the file alone and fails when it is stale or when a method has
+// lost support compared to the committed matrix, so CI can block regressions.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+const matrixFile = "capabilities.json"
+
+func main() {
+	loadConfig()
+
+	baseDir := config.Dir()
+	if baseDir == "" {
+		baseDir = "."
+		if _, err := os.Stat("handler.go"); err != nil {
+			baseDir = "../.."
+		}
+	}
+	path := filepath.Join(baseDir, matrixFile)
+
+	matrix, err := buildMatrix(baseDir)
+	if err != nil {
+		slog.Error("building capability matrix failed", "error", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(matrix, "", "  ")
+	if err != nil {
+		slog.Error("encoding capability matrix failed", "error", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if !config.Check() {
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			slog.Error("writing capability matrix failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	committed, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("reading committed capability matrix failed", "error", err)
+		os.Exit(1)
+	}
+	var old gomultistripe.CapabilityMatrix
+	if err := json.Unmarshal(committed, &old); err != nil {
+		slog.Error("committed capability matrix is invalid", "file", path, "error", err)
+		os.Exit(1)
+	}
+	if lost := regressions(old, matrix); len(lost) > 0 {
+		for _, r := range lost {
+			slog.Error("capability regression", "method", r)
+		}
+		os.Exit(1)
+	}
+	if !bytes.Equal(committed, data) {
+		slog.Error("capability matrix is stale; run go generate", "file", path)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+var versionDirRe = regexp.MustCompile(`^v\d+$`)
+
+// interfaceMethods returns the method names of the Handler interface in handler.go.
+func interfaceMethods(baseDir string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(baseDir, "handler.go"), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	obj := f.Scope.Lookup("Handler")
+	if obj == nil {
+		return nil, fmt.Errorf("no Handler interface in handler.go")
+	}
+	iface, ok := obj.Decl.(*ast.TypeSpec).Type.(*ast.InterfaceType)
+	if !ok {
+		return nil, fmt.Errorf("Handler in handler.go is not an interface")
+	}
+	var methods []string
+	for _, field := range iface.Methods.List {
+		for _, name := range field.Names {
+			methods = append(methods, name.Name)
+		}
+	}
+	return methods, nil
+}
+
+// versionMethods parses the non-test sources of a version directory and returns the
+// methods declared on its HandlerVNN type.
+func versionMethods(dir, typeName string) (map[string]*ast.FuncDecl, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	methods := make(map[string]*ast.FuncDecl)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok && ident.Name == typeName {
+					methods[fn.Name.Name] = fn
+				}
+			}
+		}
+	}
+	return methods, nil
+}
+
+// classify decides the support level of a method from its body. A method whose first
+// statement panics or returns Unsupported, or whose only statement returns nothing but nil,
+// is a stub. Unsupported calls anywhere else make it partial.
+func classify(fn *ast.FuncDecl) gomultistripe.MethodCapability {
+	if fn.Body == nil {
+		return gomultistripe.MethodCapability{Support: gomultistripe.SupportNone}
+	}
+	var features []string
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || !isUnsupportedCall(call) {
+			return true
+		}
+		found = true
+		if len(call.Args) == 2 {
+			features = append(features, featureName(call.Args[1]))
+		}
+		return true
+	})
+	features = dedupe(features)
+
+	if len(fn.Body.List) > 0 && isStubStatement(fn.Body.List[0]) {
+		return gomultistripe.MethodCapability{Support: gomultistripe.SupportNone, Unsupported: features}
+	}
+	if len(fn.Body.List) == 1 && returnsOnlyNil(fn.Body.List[0]) {
+		return gomultistripe.MethodCapability{Support: gomultistripe.SupportNone}
+	}
+	if found {
+		return gomultistripe.MethodCapability{Support: gomultistripe.SupportPartial, Unsupported: features}
+	}
+	return gomultistripe.MethodCapability{Support: gomultistripe.SupportFull}
+}
+
+func isUnsupportedCall(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		return fun.Sel.Name == "Unsupported"
+	case *ast.Ident:
+		return fun.Name == "panic"
+	}
+	return false
+}
+
+// isStubStatement reports whether stmt unconditionally panics or returns Unsupported.
+func isStubStatement(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		return ok && isUnsupportedCall(call)
+	case *ast.ReturnStmt:
+		for _, r := range s.Results {
+			if call, ok := r.(*ast.CallExpr); ok && isUnsupportedCall(call) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func returnsOnlyNil(stmt ast.Stmt) bool {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) == 0 {
+		return false
+	}
+	for _, r := range ret.Results {
+		if ident, ok := r.(*ast.Ident); !ok || ident.Name != "nil" {
+			return false
+		}
+	}
+	return true
+}
+
+// featureName renders the feature argument of an Unsupported call. Literals are unquoted;
+// anything computed (e.g. "component "+string(c)) keeps its literal prefix followed by "*".
+func featureName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if v, err := strconv.Unquote(e.Value); err == nil {
+			return v
+		}
+	case *ast.BinaryExpr:
+		return strings.TrimSpace(featureName(e.X)) + " *"
+	}
+	return "*"
+}
+
+// buildMatrix computes the capability matrix of every version directory under baseDir.
+func buildMatrix(baseDir string) (gomultistripe.CapabilityMatrix, error) {
+	methods, err := interfaceMethods(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	matrix := make(gomultistripe.CapabilityMatrix)
+	for _, entry := range entries {
+		if !entry.IsDir() || !versionDirRe.MatchString(entry.Name()) {
+			continue
+		}
+		version := entry.Name()
+		impl, err := versionMethods(filepath.Join(baseDir, version), "HandlerV"+version[1:])
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]gomultistripe.MethodCapability, len(methods))
+		for _, m := range methods {
+			fn, ok := impl[m]
+			if !ok {
+				row[m] = gomultistripe.MethodCapability{Support: gomultistripe.SupportNone}
+				continue
+			}
+			row[m] = classify(fn)
+		}
+		matrix[version] = row
+	}
+	return matrix, nil
+}
+
+// regressions lists the methods whose support level dropped between old and new.
+func regressions(old, new gomultistripe.CapabilityMatrix) []string {
+	var out []string
+	for version, methods := range old {
+		for name, was := range methods {
+			now, ok := new[version][name]
+			if !ok {
+				if _, versionKept := new[version]; versionKept {
+					out = append(out, fmt.Sprintf("%s.%s: removed (was %s)", version, name, was.Support))
+				}
+				continue
+			}
+			if rank(now.Support) < rank(was.Support) {
+				out = append(out, fmt.Sprintf("%s.%s: %s -> %s", version, name, was.Support, now.Support))
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func rank(s gomultistripe.Support) int {
+	switch s {
+	case gomultistripe.SupportFull:
+		return 2
+	case gomultistripe.SupportPartial:
+		return 1
+	}
+	return 0
+}
+
+func dedupe(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	out := values[:1]
+	for _, v := range values[1:] {
+		if v != out[len(out)-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
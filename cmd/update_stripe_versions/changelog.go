@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxChangelogLines caps the number of bullet points kept per release, so the commit
+// body stays readable for large releases.
+const maxChangelogLines = 10
+
+// release is a stripe-go GitHub release.
+type release struct {
+	TagName string `json:"tag_name"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+var versionTagRe = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)$`)
+
+// parseVersionTag parses a "vMAJOR.MINOR.PATCH" tag.
+func parseVersionTag(tag string) (Version, bool) {
+	m := versionTagRe.FindStringSubmatch(tag)
+	if m == nil {
+		return Version{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return Version{Major: major, Minor: minor, Patch: patch, Tag: tag}, true
+}
+
+// newerThan reports whether v is a later release than o.
+func (v Version) newerThan(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major > o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor > o.Minor
+	}
+	return v.Patch > o.Patch
+}
+
+// fetchReleases returns the most recent stripe-go releases from GitHub.
+func fetchReleases() ([]release, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get("https://api.github.com/repos/stripe/stripe-go/releases?per_page=100")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned non-OK status: %s", resp.Status)
+	}
+	var releases []release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// changelogFor summarises the releases after from, up to and including to, newest first.
+// from may be empty when the version was not in go.mod, in which case only to is included.
+func changelogFor(releases []release, from, to string) string {
+	toV, ok := parseVersionTag(to)
+	if !ok {
+		return ""
+	}
+	fromV, hasFrom := parseVersionTag(from)
+	var b strings.Builder
+	for _, r := range releases {
+		v, ok := parseVersionTag(r.TagName)
+		if !ok || v.Major != toV.Major || v.newerThan(toV) {
+			continue
+		}
+		if (hasFrom && !v.newerThan(fromV)) || (!hasFrom && v != toV) {
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%s)\n", r.TagName, r.HTMLURL)
+		for _, line := range summarizeReleaseBody(r.Body) {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// summarizeReleaseBody keeps the bullet points of a release body, which is where stripe-go
+// lists its changes, dropping headings, prose and anything past maxChangelogLines.
+func summarizeReleaseBody(body string) []string {
+	var lines []string
+	total := 0
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "* ") && !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		total++
+		if len(lines) < maxChangelogLines {
+			lines = append(lines, "- "+strings.TrimSpace(line[2:]))
+		}
+	}
+	if total > len(lines) {
+		lines = append(lines, fmt.Sprintf("- ... and %d more", total-len(lines)))
+	}
+	return lines
+}
+
+// attachChangelogs fetches release notes for every version bump in the report. Failing
+// to fetch them is not fatal: the update itself does not depend on them.
+func attachChangelogs(report *Report) {
+	var releases []release
+	fetched := false
+	for i, a := range report.Actions {
+		if a.Kind != ActionUpdateVersion && a.Kind != ActionAddVersion {
+			continue
+		}
+		if !fetched {
+			fetched = true
+			var err error
+			if releases, err = fetchReleases(); err != nil {
+				logger.Warn("could not fetch stripe-go release notes", "error", err)
+				return
+			}
+		}
+		report.Actions[i].Changelog = changelogFor(releases, a.From, a.To)
+	}
+}
+
+// commitBody describes the version changes and their changelogs for the commit message.
+func commitBody(report *Report) string {
+	var b strings.Builder
+	for _, a := range report.Actions {
+		fmt.Fprintf(&b, "v%d: %s -> %s\n", a.Major, orNone(a.From), a.To)
+	}
+	for _, a := range report.Actions {
+		if a.Changelog != "" {
+			fmt.Fprintf(&b, "\nChanges in v%d:\n%s\n", a.Major, a.Changelog)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
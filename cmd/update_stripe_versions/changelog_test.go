@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestChangelogFor(t *testing.T) {
+	releases := []release{
+		{TagName: "v82.2.0", Body: "## Changes\n* Add `Foo`\n* Fix `Bar`", HTMLURL: "https://example.com/v82.2.0"},
+		{TagName: "v82.1.0", Body: "Prose only.\n- Add `Baz`", HTMLURL: "https://example.com/v82.1.0"},
+		{TagName: "v82.0.0", Body: "* Initial", HTMLURL: "https://example.com/v82.0.0"},
+		{TagName: "v81.4.0", Body: "* Old major", HTMLURL: "https://example.com/v81.4.0"},
+		{TagName: "v82.3.0-beta.1", Body: "* Beta", HTMLURL: "https://example.com/beta"},
+	}
+	want := "v82.2.0 (https://example.com/v82.2.0)\n  - Add `Foo`\n  - Fix `Bar`\nv82.1.0 (https://example.com/v82.1.0)\n  - Add `Baz`"
+	if got := changelogFor(releases, "v82.0.0", "v82.2.0"); got != want {
+		t.Errorf("bump:\n%s\nwant:\n%s", got, want)
+	}
+	// A version new to go.mod only lists its own release.
+	if got := changelogFor(releases, "", "v82.1.0"); got != "v82.1.0 (https://example.com/v82.1.0)\n  - Add `Baz`" {
+		t.Errorf("added version:\n%s", got)
+	}
+	if got := changelogFor(releases, "v82.0.0", "latest"); got != "" {
+		t.Errorf("unparsable target: %q", got)
+	}
+}
+
+func TestSummarizeReleaseBody_CapsTheBullets(t *testing.T) {
+	var body strings.Builder
+	for i := range maxChangelogLines + 3 {
+		fmt.Fprintf(&body, "* Change %d\n", i)
+	}
+	lines := summarizeReleaseBody(body.String())
+	if len(lines) != maxChangelogLines+1 || lines[0] != "- Change 0" || lines[maxChangelogLines] != "- ... and 3 more" {
+		t.Errorf("summarized %q", lines)
+	}
+}
+
+func TestCommitBody(t *testing.T) {
+	report := &Report{Actions: []Action{
+		{Kind: ActionUpdateVersion, Major: 82, From: "v82.0.0", To: "v82.1.0", Changelog: "v82.1.0 (https://example.com)\n  - Add `Baz`"},
+		{Kind: ActionAddMajorVersion, Major: 83, To: "v83.0.0"},
+	}}
+	want := "v82: v82.0.0 -> v82.1.0\nv83: (none) -> v83.0.0\n\nChanges in v82:\nv82.1.0 (https://example.com)\n  - Add `Baz`"
+	if got := commitBody(report); got != want {
+		t.Errorf("commit body:\n%s\nwant:\n%s", got, want)
+	}
+}
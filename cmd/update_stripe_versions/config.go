@@ -4,8 +4,11 @@ import "github.com/iqhive/cfggo"
 
 type Config struct {
 	cfggo.Structure
-	Debug  func() bool `cfggo:"debug" default:"true" help:"Enable debug mode"`
-	DryRun func() bool `cfggo:"dryrun" default:"true" help:"Enable dry run mode"`
+	Debug  func() bool     `cfggo:"debug" default:"true" help:"Enable debug mode"`
+	DryRun func() bool     `cfggo:"dryrun" default:"true" help:"Enable dry run mode"`
+	Format func() string   `cfggo:"format" default:"text" help:"Output format of the run summary: text or json"`
+	Only   func() []string `cfggo:"only" help:"Only update or add these major versions, e.g. v79,v80"`
+	Skip   func() []string `cfggo:"skip" help:"Never update or add these major versions, e.g. v74"`
 }
 
 var config Config
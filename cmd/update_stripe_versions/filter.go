@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// versionFilter restricts which major versions the updater touches, from the --only and
+// --skip flags.
+type versionFilter struct {
+	only map[int]bool
+	skip map[int]bool
+}
+
+// newVersionFilter parses major versions given as "v79" or "79".
+func newVersionFilter(only, skip []string) (versionFilter, error) {
+	var f versionFilter
+	var err error
+	if f.only, err = parseMajors(only); err != nil {
+		return f, fmt.Errorf("invalid --only: %v", err)
+	}
+	if f.skip, err = parseMajors(skip); err != nil {
+		return f, fmt.Errorf("invalid --skip: %v", err)
+	}
+	return f, nil
+}
+
+func parseMajors(values []string) (map[int]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	majors := make(map[int]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		major, err := strconv.Atoi(strings.TrimPrefix(v, "v"))
+		if err != nil || major <= 0 {
+			return nil, fmt.Errorf("%q is not a major version like v80", v)
+		}
+		majors[major] = true
+	}
+	return majors, nil
+}
+
+// targeted reports whether --only was given, which replaces the "5 most recent versions"
+// default for existing versions.
+func (f versionFilter) targeted() bool {
+	return len(f.only) > 0
+}
+
+// allows reports whether the updater may touch the given major version.
+func (f versionFilter) allows(major int) bool {
+	if f.skip[major] {
+		return false
+	}
+	return !f.targeted() || f.only[major]
+}
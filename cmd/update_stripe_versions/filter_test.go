@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestVersionFilter(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		only, skip []string
+		allowed    map[int]bool
+	}{
+		{"no flags", nil, nil, map[int]bool{74: true, 82: true}},
+		{"only", []string{"v81", " 82"}, nil, map[int]bool{74: false, 81: true, 82: true}},
+		{"skip", nil, []string{"v74"}, map[int]bool{74: false, 82: true}},
+		{"skip wins over only", []string{"v81", "v82"}, []string{"82"}, map[int]bool{81: true, 82: false}},
+	} {
+		f, err := newVersionFilter(tc.only, tc.skip)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if f.targeted() != (len(tc.only) > 0) {
+			t.Errorf("%s: targeted %t", tc.name, f.targeted())
+		}
+		for major, want := range tc.allowed {
+			if f.allows(major) != want {
+				t.Errorf("%s: allows(%d) = %t", tc.name, major, !want)
+			}
+		}
+	}
+
+	for _, bad := range []string{"eighty", "v0", "v-1"} {
+		if _, err := newVersionFilter([]string{bad}, nil); err == nil {
+			t.Errorf("--only %s accepted", bad)
+		}
+		if _, err := newVersionFilter(nil, []string{bad}); err == nil {
+			t.Errorf("--skip %s accepted", bad)
+		}
+	}
+}
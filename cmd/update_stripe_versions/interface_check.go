@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// assertFileName is the generated file holding a version's compile-time interface check.
+const assertFileName = "handler_assert.go"
+
+var packageRe = regexp.MustCompile(`(?m)^package (\w+)`)
+
+// writeInterfaceAsserts (re)generates the interface assertion file in every version
+// directory, so a version that no longer satisfies gomultistripe.Handler fails to compile
+// with a message pointing at the missing method.
+func writeInterfaceAsserts(baseDir string) error {
+	versions, err := findExistingVersionsInDir(baseDir)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		src, err := os.ReadFile(filepath.Join(baseDir, v.Tag, "handler.go"))
+		if err != nil {
+			return fmt.Errorf("failed to read handler for %s: %v", v.Tag, err)
+		}
+		m := packageRe.FindSubmatch(src)
+		if m == nil {
+			return fmt.Errorf("no package clause in %s/handler.go", v.Tag)
+		}
+		content := fmt.Sprintf(`// Code generated by update_stripe_versions. DO NOT EDIT.
+
+package %s
+
+import gomultistripe "github.com/iqhive/gomultistripe"
+
+var _ gomultistripe.Handler = (*HandlerV%d)(nil)
+`, m[1], v.Major)
+		path := filepath.Join(baseDir, v.Tag, assertFileName)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+		logger.Debug("wrote interface assertion", "file", path)
+	}
+	return nil
+}
+
+// verifyInterfaces builds every version package and returns an error naming the versions
+// whose handler no longer implements gomultistripe.Handler (or otherwise fails to build).
+func verifyInterfaces(baseDir string) error {
+	versions, err := findExistingVersionsInDir(baseDir)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, v := range versions {
+		var out bytes.Buffer
+		cmd := exec.Command("go", "build", "./"+v.Tag+"/")
+		cmd.Dir = baseDir
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			logger.Error("version does not build", "major", v.Tag, "output", strings.TrimSpace(out.String()))
+			failed = append(failed, v.Tag)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("handlers no longer satisfy gomultistripe.Handler: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
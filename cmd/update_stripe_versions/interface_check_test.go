@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteInterfaceAsserts_MatchesTheCommittedFiles(t *testing.T) {
+	repo, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := findExistingVersionsInDir(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for _, v := range versions {
+		src, err := os.ReadFile(filepath.Join(repo, v.Tag, "handler.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Mkdir(filepath.Join(dir, v.Tag), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, v.Tag, "handler.go"), src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writeInterfaceAsserts(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range versions {
+		got, err := os.ReadFile(filepath.Join(dir, v.Tag, assertFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := os.ReadFile(filepath.Join(repo, v.Tag, assertFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s: generated\n%s\ncommitted\n%s", v.Tag, got, want)
+		}
+	}
+}
+
+func TestWriteInterfaceAsserts_NeedsAPackageClause(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "v90"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "v90", "handler.go"), []byte("// not Go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeInterfaceAsserts(dir); err == nil {
+		t.Error("wrote an assertion for a handler without a package clause")
+	}
+}
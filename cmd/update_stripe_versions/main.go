@@ -1,35 +1,68 @@
 package main
 
 import (
-	"fmt"
+	"log/slog"
 	"os"
 )
 
+// logger is the updater's structured logger, configured in main.
+var logger = slog.Default()
+
 func main() {
 	loadConfig()
 
+	level := slog.LevelInfo
+	if config.Debug() {
+		level = slog.LevelDebug
+	}
+	logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	switch config.Format() {
+	case "text":
+	case "json":
+		commandOutput = os.Stderr
+	default:
+		logger.Error("unknown output format, want text or json", "format", config.Format())
+		os.Exit(2)
+	}
+
 	if config.DryRun() {
-		fmt.Println("Running in DRY RUN mode - no changes will be made")
+		logger.Info("running in dry run mode, no changes will be made")
+	}
+
+	filter, err := newVersionFilter(config.Only(), config.Skip())
+	if err != nil {
+		logger.Error("invalid version selection", "error", err)
+		os.Exit(2)
 	}
 
-	fmt.Println("Checking setup...")
+	report := &Report{DryRun: config.DryRun(), Actions: []Action{}}
+
+	logger.Info("checking setup")
 	if err := checkSetup(); err != nil {
-		fmt.Printf("Setup check failed: %v\n", err)
-		fmt.Println("Please ensure you have internet access and permission to write to the current directory.")
+		logger.Error("setup check failed, ensure you have internet access and permission to write to the current directory", "error", err)
+		report.Error = err.Error()
+		_ = writeReport(os.Stdout, report, config.Format())
 		os.Exit(1)
 	}
 
-	fmt.Println("Updating Stripe Go SDK versions...")
+	logger.Info("updating stripe-go SDK versions")
 
-	err := UpdateStripeVersions(config.Debug(), config.DryRun())
+	err = UpdateStripeVersions(config.Debug(), config.DryRun(), filter, report)
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if werr := writeReport(os.Stdout, report, config.Format()); werr != nil {
+		logger.Error("writing report failed", "error", werr)
+		os.Exit(1)
+	}
 	if err != nil {
-		fmt.Printf("Error updating Stripe versions: %v\n", err)
+		logger.Error("updating stripe versions failed", "error", err)
 		os.Exit(1)
 	}
 
 	if config.DryRun() {
-		fmt.Println("Dry run completed. No changes were made.")
+		logger.Info("dry run completed, no changes were made")
 	} else {
-		fmt.Println("Stripe Go SDK versions successfully updated!")
+		logger.Info("stripe-go SDK versions successfully updated")
 	}
 }
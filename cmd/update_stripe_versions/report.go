@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Action kinds recorded in a Report.
+const (
+	// ActionUpdateVersion bumps an existing major version to a newer minor/patch release.
+	ActionUpdateVersion = "update_version"
+	// ActionAddVersion adds an existing version directory's module to go.mod.
+	ActionAddVersion = "add_version"
+	// ActionAddMajorVersion creates a new version directory from the latest existing one.
+	ActionAddMajorVersion = "add_major_version"
+)
+
+// Action is a single change the updater planned or performed.
+type Action struct {
+	Kind  string `json:"kind"`
+	Major int    `json:"major"`
+	// From is the version currently in go.mod, if any.
+	From string `json:"from,omitempty"`
+	// To is the version being moved to.
+	To string `json:"to"`
+	// Dir is the version directory created, for ActionAddMajorVersion.
+	Dir string `json:"dir,omitempty"`
+	// Performed is false for planned actions in dry-run mode.
+	Performed bool `json:"performed"`
+	// Changelog summarises the stripe-go release notes for the versions this action moves past.
+	Changelog string `json:"changelog,omitempty"`
+}
+
+// Report summarises an updater run for machine consumption (--format=json).
+type Report struct {
+	DryRun      bool     `json:"dry_run"`
+	Actions     []Action `json:"actions"`
+	TestsPassed bool     `json:"tests_passed"`
+	Committed   bool     `json:"committed"`
+	// RolledBack is true when a failed update was undone.
+	RolledBack bool `json:"rolled_back"`
+	// BrokenVersions lists the versions whose tests failed after being updated.
+	BrokenVersions []string `json:"broken_versions,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// record adds an action to the report and returns its index so the caller can mark it
+// performed once it succeeds.
+func (r *Report) record(a Action) int {
+	r.Actions = append(r.Actions, a)
+	return len(r.Actions) - 1
+}
+
+// writeReport writes the report to w in the given format. The text format is a short
+// human-readable summary; detailed progress is already in the log.
+func writeReport(w io.Writer, r *Report, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "text", "":
+		verb := "performed"
+		if r.DryRun {
+			verb = "planned"
+		}
+		fmt.Fprintf(w, "%d action(s) %s\n", len(r.Actions), verb)
+		for _, a := range r.Actions {
+			fmt.Fprintf(w, "  %s v%d: %s -> %s\n", a.Kind, a.Major, orNone(a.From), a.To)
+			for _, line := range strings.Split(a.Changelog, "\n") {
+				if line != "" {
+					fmt.Fprintf(w, "      %s\n", line)
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (want text or json)", format)
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
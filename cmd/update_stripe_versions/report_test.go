@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	report := &Report{DryRun: true}
+	report.record(Action{Kind: ActionUpdateVersion, Major: 82, From: "v82.0.0", To: "v82.1.0", Changelog: "v82.1.0 (https://example.com)\n  - Add support for X"})
+	report.record(Action{Kind: ActionAddMajorVersion, Major: 83, To: "v83.0.0", Dir: "v83"})
+
+	var text strings.Builder
+	if err := writeReport(&text, report, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := `2 action(s) planned
+  update_version v82: v82.0.0 -> v82.1.0
+      v82.1.0 (https://example.com)
+        - Add support for X
+  add_major_version v83: (none) -> v83.0.0
+`
+	if text.String() != want {
+		t.Errorf("text report:\n%s\nwant:\n%s", text.String(), want)
+	}
+
+	var out strings.Builder
+	if err := writeReport(&out, report, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.DryRun || len(decoded.Actions) != 2 || decoded.Actions[1].Dir != "v83" || decoded.Actions[1].From != "" {
+		t.Errorf("json report %s", out.String())
+	}
+	if strings.Contains(out.String(), `"from": ""`) || strings.Contains(out.String(), "broken_versions") {
+		t.Errorf("json report has empty optional fields: %s", out.String())
+	}
+
+	if err := writeReport(&out, report, "yaml"); err == nil {
+		t.Error("accepted an unknown format")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// snapshot records the module files and version directories before the updater changes
+// anything, so a failed update can be rolled back and leave the repository pristine.
+type snapshot struct {
+	baseDir     string
+	files       map[string][]byte
+	versionDirs map[string]bool
+}
+
+// takeSnapshot saves go.mod and go.sum and notes the existing version directories.
+func takeSnapshot(baseDir string) (*snapshot, error) {
+	s := &snapshot{baseDir: baseDir, files: make(map[string][]byte), versionDirs: make(map[string]bool)}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(baseDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %v", name, err)
+		}
+		s.files[name] = data
+	}
+	versions, err := findExistingVersionsInDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		s.versionDirs[v.Tag] = true
+	}
+	return s, nil
+}
+
+// restore puts back go.mod and go.sum and removes version directories created since the
+// snapshot was taken.
+func (s *snapshot) restore() error {
+	var errs []error
+	for name, data := range s.files {
+		if err := os.WriteFile(filepath.Join(s.baseDir, name), data, 0644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	versions, err := findExistingVersionsInDir(s.baseDir)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, v := range versions {
+		if s.versionDirs[v.Tag] {
+			continue
+		}
+		logger.Info("removing version directory created by the failed update", "dir", v.Tag)
+		if err := os.RemoveAll(filepath.Join(s.baseDir, v.Tag)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// brokenVersions re-runs the tests of each version touched by report and returns the
+// versions that fail, to point at the bump that broke the build.
+func brokenVersions(baseDir string, report *Report) []string {
+	var broken []string
+	seen := make(map[int]bool)
+	for _, a := range report.Actions {
+		if seen[a.Major] {
+			continue
+		}
+		seen[a.Major] = true
+		dir := fmt.Sprintf("v%d", a.Major)
+		if _, err := os.Stat(filepath.Join(baseDir, dir)); err != nil {
+			continue
+		}
+		var out bytes.Buffer
+		cmd := exec.Command("go", "test", "./"+dir+"/...")
+		cmd.Dir = baseDir
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			logger.Error("version fails after update", "major", dir, "to", a.To, "output", out.String())
+			broken = append(broken, dir)
+		}
+	}
+	return broken
+}
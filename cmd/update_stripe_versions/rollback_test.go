@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot_RestoreUndoesTheUpdate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "require github.com/stripe/stripe-go/v82 v82.0.0\n")
+	write("go.sum", "github.com/stripe/stripe-go/v82 v82.0.0 h1:old\n")
+	write("v82/handler.go", "package stripe\n")
+
+	s, err := takeSnapshot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The failed update bumped v82, added v83 and edited the existing version.
+	write("go.mod", "require github.com/stripe/stripe-go/v82 v82.1.0\n")
+	write("go.sum", "github.com/stripe/stripe-go/v82 v82.1.0 h1:new\n")
+	write("v83/handler.go", "package stripe\n")
+	if err := s.restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"go.mod":         "require github.com/stripe/stripe-go/v82 v82.0.0\n",
+		"go.sum":         "github.com/stripe/stripe-go/v82 v82.0.0 h1:old\n",
+		"v82/handler.go": "package stripe\n",
+	} {
+		if got, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(got) != want {
+			t.Errorf("%s: %q, %v", name, got, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "v83")); !os.IsNotExist(err) {
+		t.Errorf("v83 was not removed: %v", err)
+	}
+}
+
+func TestTakeSnapshot_NeedsGoSum(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := takeSnapshot(dir); err == nil {
+		t.Error("took a snapshot without go.sum")
+	}
+}
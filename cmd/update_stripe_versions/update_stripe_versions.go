@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
@@ -16,6 +15,10 @@ import (
 	"time"
 )
 
+// commandOutput receives the output of the go and git commands the updater runs. It is
+// switched to stderr when stdout carries the JSON report.
+var commandOutput io.Writer = os.Stdout
+
 // Version represents a semantic version of the Stripe Go SDK
 type Version struct {
 	Major int
@@ -25,8 +28,10 @@ type Version struct {
 }
 
 // UpdateStripeVersions updates existing versions and adds new ones
-// If dryRun is true, it will only print planned actions without making changes
-func UpdateStripeVersions(debug bool, dryRun bool) error {
+// If dryRun is true, it will only record planned actions without making changes.
+// Only major versions allowed by filter are touched. Planned and performed actions are
+// recorded in report.
+func UpdateStripeVersions(debug bool, dryRun bool, filter versionFilter, report *Report) error {
 	// Find versions we already have
 	existingVersions, baseDir, err := findExistingVersions()
 	if err != nil {
@@ -39,48 +44,96 @@ func UpdateStripeVersions(debug bool, dryRun bool) error {
 		return fmt.Errorf("error fetching tags: %v", err)
 	}
 
-	// Update 5 most recent existing versions
-	if err := updateExistingVersions(baseDir, existingVersions, tags, dryRun); err != nil {
-		return fmt.Errorf("error updating existing versions: %v", err)
+	if dryRun {
+		if err := applyUpdates(baseDir, existingVersions, tags, dryRun, filter, report); err != nil {
+			return err
+		}
+		attachChangelogs(report)
+		return nil
 	}
 
-	// Add new major versions (>80)
-	if err := addNewMajorVersions(baseDir, existingVersions, tags, dryRun); err != nil {
-		return fmt.Errorf("error adding new major versions: %v", err)
+	// Snapshot the module so a failed update can be rolled back
+	snap, err := takeSnapshot(baseDir)
+	if err != nil {
+		return err
+	}
+	if err := applyUpdates(baseDir, existingVersions, tags, dryRun, filter, report); err != nil {
+		return rollback(snap, report, err)
 	}
 
-	// If not in dry-run mode, run tests and commit changes
-	if !dryRun {
-		fmt.Println("Running tests to verify changes...")
-		if err := runTests(baseDir); err != nil {
-			return fmt.Errorf("tests failed after updating versions: %v", err)
-		}
-		fmt.Println("Tests passed!")
+	// Fetch release notes so reviewers can assess the bumps
+	attachChangelogs(report)
 
-		// Commit changes to git
-		if err := commitChanges(baseDir); err != nil {
-			return fmt.Errorf("failed to commit changes: %v", err)
-		}
-		fmt.Println("Changes committed to git!")
+	// Verify the handlers, run tests and commit changes
+	logger.Info("verifying handlers implement gomultistripe.Handler")
+	if err := writeInterfaceAsserts(baseDir); err != nil {
+		return rollback(snap, report, fmt.Errorf("failed to generate interface assertions: %v", err))
+	}
+	if err := verifyInterfaces(baseDir); err != nil {
+		return rollback(snap, report, err)
+	}
+
+	logger.Info("running tests to verify changes")
+	if err := runTests(baseDir); err != nil {
+		report.BrokenVersions = brokenVersions(baseDir, report)
+		return rollback(snap, report, fmt.Errorf("tests failed after updating versions: %v", err))
 	}
+	logger.Info("tests passed")
+	report.TestsPassed = true
 
+	// Commit changes to git
+	if err := commitChanges(baseDir, commitBody(report)); err != nil {
+		return fmt.Errorf("failed to commit changes: %v", err)
+	}
+	report.Committed = true
+	logger.Info("changes committed to git")
+
+	return nil
+}
+
+// applyUpdates bumps the existing versions and adds new major versions.
+func applyUpdates(baseDir string, existingVersions []Version, tags []Version, dryRun bool, filter versionFilter, report *Report) error {
+	// Update 5 most recent (or the targeted) existing versions
+	if err := updateExistingVersions(baseDir, existingVersions, tags, dryRun, filter, report); err != nil {
+		return fmt.Errorf("error updating existing versions: %v", err)
+	}
+
+	// Add new major versions (>80)
+	if err := addNewMajorVersions(baseDir, existingVersions, tags, dryRun, filter, report); err != nil {
+		return fmt.Errorf("error adding new major versions: %v", err)
+	}
 	return nil
 }
 
+// rollback restores the snapshot after a failed update and returns cause, annotated with
+// the versions that broke the build when they are known.
+func rollback(snap *snapshot, report *Report, cause error) error {
+	logger.Warn("update failed, rolling back", "error", cause)
+	if err := snap.restore(); err != nil {
+		return fmt.Errorf("%v; rollback failed, repository may be left modified: %v", cause, err)
+	}
+	report.RolledBack = true
+	if len(report.BrokenVersions) > 0 {
+		return fmt.Errorf("%v (broken by %s); changes rolled back", cause, strings.Join(report.BrokenVersions, ", "))
+	}
+	return fmt.Errorf("%v; changes rolled back", cause)
+}
+
 // runTests runs go test for the entire project
 func runTests(baseDir string) error {
 	cmd := exec.Command("go", "test", "./...")
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = commandOutput
 	cmd.Dir = baseDir
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-// commitChanges commits the changes to git
-func commitChanges(baseDir string) error {
+// commitChanges commits the changes to git, with body (the version changes and their
+// changelogs) as the commit message body
+func commitChanges(baseDir string, body string) error {
 	// Check if we're in a git repository
 	if _, err := os.Stat(baseDir + "/.git"); os.IsNotExist(err) {
-		fmt.Println("Not a git repository, skipping commit")
+		logger.Info("not a git repository, skipping commit")
 		return nil
 	}
 
@@ -91,7 +144,7 @@ func commitChanges(baseDir string) error {
 	// Add changes
 	cmd := exec.Command("git", "add", "go.mod", "go.sum")
 	cmd.Dir = baseDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = commandOutput
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git add failed: %v", err)
@@ -100,15 +153,19 @@ func commitChanges(baseDir string) error {
 	// Add any new version directories
 	cmd = exec.Command("git", "add", "v*")
 	cmd.Dir = baseDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = commandOutput
 	cmd.Stderr = os.Stderr
 	// It's okay if this fails (e.g., no new version directories)
 	_ = cmd.Run()
 
 	// Commit changes
-	cmd = exec.Command("git", "commit", "-m", commitMsg)
+	args := []string{"commit", "-m", commitMsg}
+	if body != "" {
+		args = append(args, "-m", body)
+	}
+	cmd = exec.Command("git", args...)
 	cmd.Dir = baseDir
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = commandOutput
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("git commit failed: %v", err)
@@ -139,23 +196,11 @@ func getStripeGoTags() ([]Version, error) {
 		return nil, err
 	}
 
-	// Regular expression for version tags
-	re := regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)$`)
-
 	// Extract versions
 	var versions []Version
 	for _, tag := range tagList {
-		matches := re.FindStringSubmatch(tag.Name)
-		if matches != nil {
-			major, _ := strconv.Atoi(matches[1])
-			minor, _ := strconv.Atoi(matches[2])
-			patch, _ := strconv.Atoi(matches[3])
-			versions = append(versions, Version{
-				Major: major,
-				Minor: minor,
-				Patch: patch,
-				Tag:   tag.Name,
-			})
+		if v, ok := parseVersionTag(tag.Name); ok {
+			versions = append(versions, v)
 		}
 	}
 
@@ -179,18 +224,16 @@ func findExistingVersions() ([]Version, string, error) {
 		baseDir = "../.."
 	}
 	if _, err := os.Stat(baseDir + "/go.mod"); err != nil {
-		log.Fatalf("error finding existing versions in %s: %v", baseDir, err)
+		return nil, baseDir, fmt.Errorf("no go.mod found in %s: %v", baseDir, err)
 	}
 
 	vers, err := findExistingVersionsInDir(baseDir)
-	log.Printf("existing versions in %s: %v", baseDir, vers)
 	if err != nil {
-		log.Printf("error finding existing versions in %s: %v", ".", err)
 		return nil, baseDir, err
 	}
+	logger.Debug("found existing versions", "dir", baseDir, "versions", len(vers))
 
 	if len(vers) == 0 {
-		log.Printf("no existing versions found")
 		return nil, baseDir, fmt.Errorf("no existing versions found")
 	}
 
@@ -230,7 +273,7 @@ func findExistingVersionsInDir(parentDir string) ([]Version, error) {
 	return versions, nil
 }
 
-func updateExistingVersions(baseDir string, existingVersions []Version, allTags []Version, dryRun bool) error {
+func updateExistingVersions(baseDir string, existingVersions []Version, allTags []Version, dryRun bool, filter versionFilter, report *Report) error {
 	// Get latest minor/patch for each major version
 	latestVersions := make(map[int]Version)
 	for _, v := range allTags {
@@ -245,51 +288,45 @@ func updateExistingVersions(baseDir string, existingVersions []Version, allTags
 		return fmt.Errorf("failed to get current versions from go.mod: %v", err)
 	}
 
-	// Limit to 5 most recent versions
+	var candidates []Version
+	for _, v := range existingVersions {
+		if filter.allows(v.Major) {
+			candidates = append(candidates, v)
+		} else {
+			logger.Debug("skipping version excluded by --only/--skip", "major", v.Tag)
+		}
+	}
+
+	// Limit to 5 most recent versions, unless specific versions were targeted with --only
 	limit := 5
-	if len(existingVersions) < limit {
-		limit = len(existingVersions)
+	if filter.targeted() || len(candidates) < limit {
+		limit = len(candidates)
 	}
 
 	// Update go.mod file
 	for i := 0; i < limit; i++ {
-		v := existingVersions[i]
+		v := candidates[i]
 		if latest, ok := latestVersions[v.Major]; ok {
 			// Get current version for this major version
 			current, exists := currentVersions[v.Major]
 
 			// Skip if we don't have this version in go.mod
+			action := Action{Kind: ActionAddVersion, Major: v.Major, To: latest.Tag}
 			if !exists {
-				if dryRun {
-					fmt.Printf("[DRY RUN] Would add v%d.%d.%d (not currently in go.mod)\n",
-						latest.Major, latest.Minor, latest.Patch)
-				} else {
-					fmt.Printf("Adding v%d.%d.%d (not currently in go.mod)\n",
-						latest.Major, latest.Minor, latest.Patch)
-				}
+				logger.Info("adding version not currently in go.mod", "major", v.Tag, "to", latest.Tag, "dry_run", dryRun)
 			} else {
 				// Compare versions - only update if the new version is newer
 				if latest.Minor < current.Minor ||
 					(latest.Minor == current.Minor && latest.Patch <= current.Patch) {
-					if config.Debug() {
-						fmt.Printf("Skipping v%d: current v%d.%d.%d is already at or newer than v%d.%d.%d\n",
-							v.Major, current.Major, current.Minor, current.Patch,
-							latest.Major, latest.Minor, latest.Patch)
-					}
+					logger.Debug("skipping version, already up to date", "major", v.Tag, "current", current.Tag, "latest", latest.Tag)
 					continue
 				}
 
-				if dryRun {
-					fmt.Printf("[DRY RUN] Would update v%d from v%d.%d.%d to v%d.%d.%d\n",
-						v.Major, current.Major, current.Minor, current.Patch,
-						latest.Major, latest.Minor, latest.Patch)
-					continue
-				}
-
-				fmt.Printf("Updating v%d from v%d.%d.%d to v%d.%d.%d\n",
-					v.Major, current.Major, current.Minor, current.Patch,
-					latest.Major, latest.Minor, latest.Patch)
+				logger.Info("updating version", "major", v.Tag, "from", current.Tag, "to", latest.Tag, "dry_run", dryRun)
+				action.Kind = ActionUpdateVersion
+				action.From = current.Tag
 			}
+			idx := report.record(action)
 
 			// Skip actual update in dry-run mode
 			if dryRun {
@@ -298,11 +335,12 @@ func updateExistingVersions(baseDir string, existingVersions []Version, allTags
 
 			// Use go get to update the module
 			cmd := exec.Command("go", "get", fmt.Sprintf("github.com/stripe/stripe-go/v%d@%s", v.Major, latest.Tag))
-			cmd.Stdout = os.Stdout
+			cmd.Stdout = commandOutput
 			cmd.Stderr = os.Stderr
 			if err := cmd.Run(); err != nil {
 				return fmt.Errorf("failed to update v%d: %v", v.Major, err)
 			}
+			report.Actions[idx].Performed = true
 		}
 	}
 
@@ -310,7 +348,7 @@ func updateExistingVersions(baseDir string, existingVersions []Version, allTags
 	if !dryRun {
 		cmd := exec.Command("go", "mod", "tidy")
 		cmd.Dir = baseDir
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = commandOutput
 		cmd.Stderr = os.Stderr
 		return cmd.Run()
 	}
@@ -349,7 +387,7 @@ func getCurrentVersionsFromGoMod(baseDir string) (map[int]Version, error) {
 		patch, _ := strconv.Atoi(match[4])
 
 		if major != vMajor {
-			fmt.Printf("Warning: Inconsistent major versions in go.mod: %d vs %d\n", major, vMajor)
+			logger.Warn("inconsistent major versions in go.mod", "module_major", major, "version_major", vMajor)
 		}
 
 		versions[major] = Version{
@@ -363,7 +401,7 @@ func getCurrentVersionsFromGoMod(baseDir string) (map[int]Version, error) {
 	return versions, nil
 }
 
-func addNewMajorVersions(baseDir string, existingVersions []Version, allTags []Version, dryRun bool) error {
+func addNewMajorVersions(baseDir string, existingVersions []Version, allTags []Version, dryRun bool, filter versionFilter, report *Report) error {
 	// Find the largest existing major version
 	var maxExistingMajor int
 	for _, v := range existingVersions {
@@ -381,6 +419,9 @@ func addNewMajorVersions(baseDir string, existingVersions []Version, allTags []V
 	// Find new major versions not in our existing directories
 	var newMajorVersions []Version
 	for _, v := range allTags {
+		if !filter.allows(v.Major) {
+			continue
+		}
 		found := false
 		for _, existing := range existingVersions {
 			if existing.Major == v.Major {
@@ -427,67 +468,53 @@ func addNewMajorVersions(baseDir string, existingVersions []Version, allTags []V
 		// Check if we already have this version in go.mod but missing directory
 		current, hasCurrent := currentVersions[v.Major]
 
-		if dryRun {
-			if hasCurrent {
-				fmt.Printf("[DRY RUN] Would add new major version directory: %s (v%d.%d.%d) - already in go.mod as v%d.%d.%d\n",
-					destDir, v.Major, v.Minor, v.Patch, current.Major, current.Minor, current.Patch)
-			} else {
-				fmt.Printf("[DRY RUN] Would add new major version: %s (v%d.%d.%d)\n",
-					destDir, v.Major, v.Minor, v.Patch)
-			}
-			continue
-		}
-
+		action := Action{Kind: ActionAddMajorVersion, Major: v.Major, To: v.Tag, Dir: destDir}
 		if hasCurrent {
-			fmt.Printf("Adding new major version directory: %s (v%d.%d.%d) - already in go.mod as v%d.%d.%d\n",
-				destDir, v.Major, v.Minor, v.Patch, current.Major, current.Minor, current.Patch)
+			logger.Info("adding new major version directory", "dir", destDir, "version", v.Tag, "in_go_mod", current.Tag, "dry_run", dryRun)
+			action.From = current.Tag
 		} else {
-			fmt.Printf("Adding new major version: %s (v%d.%d.%d)\n",
-				baseDir+"/"+destDir, v.Major, v.Minor, v.Patch)
+			logger.Info("adding new major version", "dir", destDir, "version", v.Tag, "dry_run", dryRun)
+		}
+		idx := report.record(action)
+		if dryRun {
+			continue
 		}
 
 		// Create directory
-		if config.Debug() {
-			fmt.Printf("Creating directory %s\n", baseDir+"/"+destDir)
-		}
+		logger.Debug("creating directory", "dir", baseDir+"/"+destDir)
 		if err := os.MkdirAll(baseDir+"/"+destDir, 0755); err != nil {
 			return err
 		}
 
 		// Copy files from latest existing version
-		if config.Debug() {
-			fmt.Printf("Copying files from %s to %s\n", baseDir+"/"+sourceDir, baseDir+"/"+destDir)
-		}
+		logger.Debug("copying files", "from", baseDir+"/"+sourceDir, "to", baseDir+"/"+destDir)
 		if err := copyDir(baseDir+"/"+sourceDir, baseDir+"/"+destDir); err != nil {
 			return err
 		}
 
 		// Update imports in new directory
-		if config.Debug() {
-			fmt.Printf("Updating imports in %s\n", baseDir+"/"+destDir)
-		}
+		logger.Debug("updating imports", "dir", baseDir+"/"+destDir)
 		if err := updateImports(baseDir, destDir, maxExistingMajor, v.Major); err != nil {
 			return err
 		}
 
 		// Add new version to go.mod (if not already there)
-		if config.Debug() {
-			fmt.Printf("Adding new version to go.mod: %s\n", v.Tag)
-		}
+		logger.Debug("adding new version to go.mod", "version", v.Tag)
 		if !hasCurrent {
 			cmd := exec.Command("go", "get", fmt.Sprintf("github.com/stripe/stripe-go/v%d@%s", v.Major, v.Tag))
-			cmd.Stdout = os.Stdout
+			cmd.Stdout = commandOutput
 			cmd.Stderr = os.Stderr
 			if err := cmd.Run(); err != nil {
 				return err
 			}
 		}
+		report.Actions[idx].Performed = true
 	}
 
 	// Update go.mod and go.sum
 	if !dryRun {
 		cmd := exec.Command("go", "mod", "tidy")
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = commandOutput
 		cmd.Stderr = os.Stderr
 		return cmd.Run()
 	}
@@ -565,7 +592,7 @@ func updateImports(baseDir string, dir string, oldMajor, newMajor int) error {
 		content = strings.ReplaceAll(content, oldStruct, newStruct)
 
 		// Write updated content
-		log.Printf("Updating %s", path)
+		logger.Debug("rewriting version references", "file", path)
 		return os.WriteFile(path, []byte(content), info.Mode())
 	})
 }
@@ -0,0 +1,63 @@
+package gomultistripe
+
+// MandateData records that the customer accepted a mandate, as SEPA Direct Debit, BACS and
+// other debits require before the first charge.
+type MandateData struct {
+	// IPAddress and UserAgent are those of the customer's browser when they accepted the
+	// mandate online. When both are empty, the mandate is recorded as accepted offline.
+	IPAddress string
+	UserAgent string
+}
+
+// Online reports whether the mandate was accepted online.
+func (m *MandateData) Online() bool {
+	return m.IPAddress != "" || m.UserAgent != ""
+}
+
+// ConfirmOptions holds the optional settings for Handler.ConfirmPaymentIntent and
+// Handler.ConfirmSetupIntent.
+type ConfirmOptions struct {
+	// ReturnURL is where the customer is sent back to after authenticating with a
+	// redirect-based payment method, such as iDEAL, Bancontact or 3D Secure.
+	ReturnURL string
+	// MandateData records the customer's acceptance of a debit mandate.
+	MandateData *MandateData
+	// OffSession marks a payment the customer is not present for, e.g. a charge to a saved
+	// card or a mandate-backed debit. It does not apply to setup intents.
+	OffSession bool
+	// InstallmentPlan pays a card payment in installments, e.g. one of InstallmentPlans.
+	InstallmentPlan *InstallmentPlan
+}
+
+// ConfirmOption configures ConfirmOptions.
+type ConfirmOption func(*ConfirmOptions)
+
+// WithReturnURL sets the URL redirect-based payment methods return the customer to.
+func WithReturnURL(url string) ConfirmOption {
+	return func(o *ConfirmOptions) { o.ReturnURL = url }
+}
+
+// WithMandateData records the customer's acceptance of a mandate.
+func WithMandateData(m MandateData) ConfirmOption {
+	return func(o *ConfirmOptions) { o.MandateData = &m }
+}
+
+// WithOffSession confirms a payment intent without the customer present.
+func WithOffSession() ConfirmOption {
+	return func(o *ConfirmOptions) { o.OffSession = true }
+}
+
+// WithInstallmentPlan pays a card payment in installments.
+func WithInstallmentPlan(plan InstallmentPlan) ConfirmOption {
+	return func(o *ConfirmOptions) { o.InstallmentPlan = &plan }
+}
+
+// NewConfirmOptions applies opts in order. Handlers use it to read the options passed to
+// ConfirmPaymentIntent and ConfirmSetupIntent.
+func NewConfirmOptions(opts ...ConfirmOption) ConfirmOptions {
+	var o ConfirmOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,518 @@
+package conformance_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/iqhive/gomultistripe/fixtures"
+)
+
+func TestRetrieveInvoice_ReturnsAllLines(t *testing.T) {
+	var s fixtures.State
+	s.CustomerID, s.SubscriptionID, s.Currency, s.Now = "cus_fixture", "sub_fixture", "usd", time.Unix(1700000000, 0)
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/invoices/in_fixture_long":
+			inv := fixtures.InvoiceObject(&s, "in_fixture_long", 1750, true)
+			inv["lines"].(map[string]any)["has_more"] = true
+			json.NewEncoder(w).Encode(inv)
+		case "/v1/invoices/in_fixture_long/lines":
+			json.NewEncoder(w).Encode(map[string]any{
+				"object":   "list",
+				"has_more": false,
+				"data":     []any{map[string]any{"id": "il_2", "object": "line_item", "amount": 750, "currency": "usd"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		inv, err := h.RetrieveInvoice(context.Background(), "in_fixture_long")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if inv.Status != gomultistripe.InvoicePaid || inv.CustomerID != "cus_fixture" || inv.SubscriptionID != "sub_fixture" {
+			t.Errorf("got invoice %+v", inv)
+		}
+		if len(inv.Lines) != 2 || inv.LinesHasMore || inv.Lines[1].ID != "il_2" {
+			t.Errorf("got lines %+v, has more %v", inv.Lines, inv.LinesHasMore)
+		}
+	})
+}
+
+func TestReportMeterEvent_UsesV2WhereSupported(t *testing.T) {
+	var path, contentType, body string
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"object":"billing.meter_event"}`))
+	})
+
+	evt := gomultistripe.MeterEvent{EventName: "api_requests", CustomerID: "cus_fixture", Value: 25, Identifier: "req_1"}
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		path, body = "", ""
+		err := h.ReportMeterEvent(context.Background(), evt)
+		if !supported(t, h, "ReportMeterEvent", err) {
+			return
+		}
+		switch h.Version() {
+		case "v76", "v78", "v79":
+			if err != nil || path != "/v1/billing/meter_events" || !strings.Contains(body, "payload[value]=25") {
+				t.Fatalf("got %v, %s %s", err, path, body)
+			}
+		default:
+			var sent struct {
+				EventName  string            `json:"event_name"`
+				Identifier string            `json:"identifier"`
+				Payload    map[string]string `json:"payload"`
+			}
+			if err != nil || path != "/v2/billing/meter_events" || contentType != "application/json" {
+				t.Fatalf("got %v, %s %s", err, path, contentType)
+			}
+			if err := json.Unmarshal([]byte(body), &sent); err != nil || sent.Identifier != "req_1" ||
+				sent.Payload[gomultistripe.DefaultMeterCustomerKey] != "cus_fixture" || sent.Payload[gomultistripe.DefaultMeterValueKey] != "25" {
+				t.Fatalf("sent %s", body)
+			}
+		}
+	})
+}
+
+func TestReportMeterEvents_StreamsBatches(t *testing.T) {
+	var sessions, streamed int
+	var mu sync.Mutex
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v2/billing/meter_event_session":
+			sessions++
+			json.NewEncoder(w).Encode(map[string]any{
+				"authentication_token": fmt.Sprintf("mes_token_%d", sessions),
+				"expires_at":           time.Now().Add(15 * time.Minute).Format(time.RFC3339),
+			})
+		case "/v2/billing/meter_event_stream":
+			if r.Header.Get("Authorization") != fmt.Sprintf("Bearer mes_token_%d", sessions) || sessions == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":{"type":"invalid_request_error","code":"billing_meter_event_session_expired"}}`))
+				return
+			}
+			var body struct {
+				Events []map[string]any `json:"events"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			streamed += len(body.Events)
+			w.Write([]byte(`{}`))
+		case "/v1/billing/meter_events":
+			streamed++
+			w.Write([]byte(`{"object":"billing.meter_event"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	events := make([]gomultistripe.MeterEvent, 150)
+	for i := range events {
+		events[i] = gomultistripe.MeterEvent{EventName: "api_requests", CustomerID: "cus_fixture", Value: 1}
+	}
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		sessions, streamed = 0, 0
+		err := h.ReportMeterEvents(context.Background(), events)
+		if !supported(t, h, "ReportMeterEvents", err) {
+			return
+		}
+		switch h.Version() {
+		case "v76", "v78", "v79":
+		default:
+			// The first session is rejected, which must be refreshed once and then reused.
+			if sessions != 2 {
+				t.Errorf("created %d sessions, want 2", sessions)
+			}
+		}
+		if err != nil || streamed != len(events) {
+			t.Fatalf("streamed %d events: %v", streamed, err)
+		}
+	})
+}
+
+func TestCreatePriceAndListPrices(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		priceObject := map[string]any{
+			"id": "price_fixture", "object": "price", "product": "prod_fixture", "currency": "usd",
+			"unit_amount": 1500, "lookup_key": "pro_monthly", "active": true, "created": 1700000000,
+			"recurring": map[string]any{"interval": "month", "interval_count": 3},
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/prices":
+			r.ParseForm()
+			form = r.PostForm
+			json.NewEncoder(w).Encode(priceObject)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/prices":
+			form = r.URL.Query()
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{priceObject}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		p, err := h.CreatePrice(context.Background(), gomultistripe.PriceParams{
+			ProductID: "prod_fixture", Currency: "usd", UnitAmount: 1500,
+			Interval: gomultistripe.PriceMonthly, IntervalCount: 3, LookupKey: "pro_monthly", TransferLookupKey: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("recurring[interval]") != "month" || form.Get("recurring[interval_count]") != "3" || form.Get("transfer_lookup_key") != "true" {
+			t.Errorf("sent %v", form)
+		}
+		if p.ProductID != "prod_fixture" || p.Interval != gomultistripe.PriceMonthly || p.IntervalCount != 3 || p.LookupKey != "pro_monthly" {
+			t.Errorf("got price %+v", p)
+		}
+
+		page, err := h.ListPrices(context.Background(), gomultistripe.PriceQuery{LookupKeys: []string{"pro_monthly"}}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("lookup_keys[0]") != "pro_monthly" || form.Get("active") != "true" {
+			t.Errorf("sent %v", form)
+		}
+		if len(page.Prices) != 1 || !page.HasMore || page.NextCursor != "price_fixture" {
+			t.Errorf("got page %+v", page)
+		}
+	})
+}
+
+func TestListInvoices_EveryCustomer(t *testing.T) {
+	var query url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{map[string]any{
+			"id": "in_fixture", "object": "invoice", "number": "INV-0001", "customer": "cus_fixture",
+			"customer_name": "Acme Ltd", "customer_email": "ap@acme.test", "status": "paid", "currency": "usd",
+			"total": 5000, "created": 1700000000,
+		}}})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		page, err := h.ListInvoices(context.Background(), "", "", gomultistripe.DateRange{From: time.Unix(1700000000, 0)}, nil)
+		if err != nil || len(page.Invoices) != 1 {
+			t.Fatalf("invoices %+v: %v", page, err)
+		}
+		if inv := page.Invoices[0]; inv.CustomerName != "Acme Ltd" || inv.CustomerEmail != "ap@acme.test" || inv.CustomerID != "cus_fixture" {
+			t.Errorf("invoice %+v", inv)
+		}
+		if query.Has("customer") || query.Get("created[gte]") != "1700000000" {
+			t.Errorf("listed invoices with %v", query)
+		}
+	})
+}
+
+func TestListInvoices_FiltersAndPages(t *testing.T) {
+	var query url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{
+			map[string]any{"id": "in_2", "object": "invoice", "customer": "cus_fixture", "status": "open", "created": 1700000200},
+			map[string]any{"id": "in_1", "object": "invoice", "customer": "cus_fixture", "status": "open", "created": 1700000100},
+		}})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		dateRange := gomultistripe.DateRange{From: time.Unix(1700000000, 0), To: time.Unix(1700086400, 0)}
+		page, err := h.ListInvoices(context.Background(), "cus_fixture", gomultistripe.InvoiceOpen, dateRange,
+			&gomultistripe.ListOptions{Limit: 1000, StartingAfter: "in_3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !page.HasMore || page.NextCursor != "in_1" || len(page.Invoices) != 2 || page.Invoices[0].Status != gomultistripe.InvoiceOpen {
+			t.Errorf("page %+v", page)
+		}
+		want := map[string]string{
+			"customer": "cus_fixture", "status": "open", "created[gte]": "1700000000", "created[lt]": "1700086400",
+			"limit": fmt.Sprint(gomultistripe.MaxExportPageSize), "starting_after": "in_3",
+		}
+		for k, v := range want {
+			if query.Get(k) != v {
+				t.Errorf("%s = %q, want %q", k, query.Get(k), v)
+			}
+		}
+	})
+}
+
+func TestCreateSubscription_WithTrial(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "trialing",
+			"trial_start": 1700000000, "trial_end": 1701209600, "start_date": 1700000000, "created": 1700000000,
+			"metadata": map[string]string{"plan": "pro"},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		sub, err := h.CreateSubscription(context.Background(), "cus_fixture", "price_fixture",
+			gomultistripe.WithTrialPeriodDays(14),
+			gomultistripe.WithDefaultPaymentMethod("pm_fixture"),
+			gomultistripe.WithSubscriptionMetadata("plan", "pro"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub.Status != "trialing" || sub.TrialStart != 1700000000 || sub.TrialEnd != 1701209600 || sub.Metadata["plan"] != "pro" {
+			t.Errorf("subscription %+v", sub)
+		}
+		if form.Get("trial_period_days") != "14" || form.Get("default_payment_method") != "pm_fixture" || form.Get("metadata[plan]") != "pro" {
+			t.Errorf("created subscription with %v", form)
+		}
+
+		_, err = h.CreateSubscription(context.Background(), "cus_fixture", "price_fixture",
+			gomultistripe.WithTrialPeriodDays(14), gomultistripe.WithTrialEnd(time.Unix(1701209600, 0)))
+		if err != nil || form.Get("trial_end") != "1701209600" || form.Has("trial_period_days") {
+			t.Errorf("created subscription with %v: %v", form, err)
+		}
+	})
+}
+
+func TestSubscriptionItems(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "active", "created": 1700000000,
+			"items": map[string]any{"object": "list", "data": []map[string]any{
+				{"id": "si_seats", "object": "subscription_item", "quantity": 12, "price": map[string]any{"id": "price_seat"}},
+				{"id": "si_addon", "object": "subscription_item", "quantity": 1, "price": map[string]any{"id": "price_addon"}},
+			}},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		sub, err := h.CreateSubscription(context.Background(), "cus_fixture", "price_seat",
+			gomultistripe.WithQuantity(12),
+			gomultistripe.WithSubscriptionItems(gomultistripe.SubscriptionItemParams{PriceID: "price_addon"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []gomultistripe.SubscriptionItem{{ID: "si_seats", PriceID: "price_seat", Quantity: 12}, {ID: "si_addon", PriceID: "price_addon", Quantity: 1}}
+		if sub.PriceID != "price_seat" || !slices.Equal(sub.Items, want) {
+			t.Errorf("subscription %+v", sub)
+		}
+		if form.Get("items[0][price]") != "price_seat" || form.Get("items[0][quantity]") != "12" ||
+			form.Get("items[1][price]") != "price_addon" || form.Has("items[1][quantity]") {
+			t.Errorf("created subscription with %v", form)
+		}
+
+		_, err = h.CreateSubscription(context.Background(), "cus_fixture", "",
+			gomultistripe.WithSubscriptionItems(gomultistripe.SubscriptionItemParams{PriceID: "price_addon", Quantity: 2}))
+		if err != nil || form.Get("items[0][price]") != "price_addon" || form.Has("items[1][price]") {
+			t.Errorf("created subscription with %v: %v", form, err)
+		}
+
+		_, err = h.UpdateSubscriptionItems(context.Background(), "sub_fixture", []gomultistripe.SubscriptionItemParams{
+			{ID: "si_seats", Quantity: 15},
+			{ID: "si_addon", Delete: true},
+			{PriceID: "price_support", Quantity: 1},
+		}, "always_invoice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("items[0][id]") != "si_seats" || form.Get("items[0][quantity]") != "15" || form.Has("items[0][price]") ||
+			form.Get("items[1][id]") != "si_addon" || form.Get("items[1][deleted]") != "true" ||
+			form.Get("items[2][price]") != "price_support" || form.Get("proration_behavior") != "always_invoice" {
+			t.Errorf("updated subscription with %v", form)
+		}
+	})
+}
+
+func TestCreateSubscription_BackdatedAndAnchored(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "active",
+			"start_date": 1696118400, "billing_cycle_anchor": 1704067200, "created": 1700000000,
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		sub, err := h.CreateSubscription(context.Background(), "cus_fixture", "price_fixture",
+			gomultistripe.WithBackdateStartDate(time.Unix(1696118400, 0)),
+			gomultistripe.WithBillingCycleAnchor(time.Unix(1704067200, 0)))
+		if err != nil || sub.ID != "sub_fixture" {
+			t.Fatalf("got %+v, %v", sub, err)
+		}
+		if form.Get("backdate_start_date") != "1696118400" || form.Get("billing_cycle_anchor") != "1704067200" ||
+			form.Get("items[0][price]") != "price_fixture" {
+			t.Errorf("created subscription with %v", form)
+		}
+	})
+}
+
+func TestCancelSubscriptionAt(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		sub := map[string]any{"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "active", "cancel_at": nil}
+		if form.Get("cancel_at") != "" {
+			sub["cancel_at"] = 1735689600
+		}
+		json.NewEncoder(w).Encode(sub)
+	})
+	// The period end is on the subscription before the basil API version and on its items since.
+	sub := map[string]any{
+		"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "active", "cancel_at": 1767225600,
+		"current_period_start": 1733011200, "current_period_end": 1735689600, "created": 1700000000,
+		"items": map[string]any{"object": "list", "data": []any{map[string]any{
+			"id": "si_fixture", "object": "subscription_item", "quantity": 1, "price": map[string]any{"id": "price_fixture", "object": "price"},
+			"current_period_start": 1733011200, "current_period_end": 1735689600,
+		}}},
+	}
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		s, err := h.CancelSubscriptionAt(context.Background(), "sub_fixture", 1735689600)
+		if err != nil || s.CancelAt != 1735689600 || form.Get("cancel_at") != "1735689600" {
+			t.Fatalf("got %+v, %v, sent %v", s, err, form)
+		}
+		s, err = h.CancelSubscriptionAt(context.Background(), "sub_fixture", 0)
+		if err != nil || s.CancelAt != 0 || !form.Has("cancel_at") || form.Get("cancel_at") != "" {
+			t.Fatalf("got %+v, %v, sent %v", s, err, form)
+		}
+
+		evt, err := deliver(h, event("customer.subscription.updated", sub))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.CancelAt != 1767225600 || evt.CurrentPeriodEnd != 1735689600 {
+			t.Errorf("cancel at %d, period end %d", evt.CancelAt, evt.CurrentPeriodEnd)
+		}
+	})
+}
+
+func TestReconcileSeats(t *testing.T) {
+	var updates []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/subscriptions/sub_fixture":
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "sub_fixture", "object": "subscription", "customer": "cus_fixture", "status": "active",
+				"items": map[string]any{"object": "list", "data": []any{
+					map[string]any{"id": "si_seats", "object": "subscription_item", "quantity": 10, "price": map[string]any{"id": "price_seat", "object": "price"}},
+					map[string]any{"id": "si_support", "object": "subscription_item", "quantity": 1, "price": map[string]any{"id": "price_support", "object": "price"}},
+				}},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/subscription_items/si_seats":
+			r.ParseForm()
+			updates = append(updates, r.PostForm)
+			json.NewEncoder(w).Encode(map[string]any{"id": "si_seats", "object": "subscription_item"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		for _, tc := range []struct {
+			name    string
+			actual  int64
+			policy  gomultistripe.SeatPolicy
+			drift   int64
+			updated bool
+		}{
+			{"increase", 12, gomultistripe.SeatPolicy{PriceID: "price_seat", ProrationBehavior: "always_invoice"}, 2, true},
+			{"within threshold", 11, gomultistripe.SeatPolicy{PriceID: "price_seat", Threshold: 1}, 1, false},
+			{"decrease not allowed", 8, gomultistripe.SeatPolicy{PriceID: "price_seat"}, -2, false},
+			{"decrease", 8, gomultistripe.SeatPolicy{PriceID: "price_seat", AllowDecrease: true}, -2, true},
+			{"dry run", 12, gomultistripe.SeatPolicy{PriceID: "price_seat", DryRun: true}, 2, false},
+		} {
+			updates = nil
+			rec, err := h.ReconcileSeats(ctx, "sub_fixture", tc.actual, tc.policy)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if rec.SubscriptionItemID != "si_seats" || rec.BilledQuantity != 10 || rec.Drift != tc.drift || rec.Updated != tc.updated || (len(updates) == 1) != tc.updated {
+				t.Errorf("%s: got %+v after updates %v", tc.name, rec, updates)
+			}
+			if tc.name == "increase" && (updates[0].Get("quantity") != "12" || updates[0].Get("proration_behavior") != "always_invoice") {
+				t.Errorf("%s: updated with %v", tc.name, updates[0])
+			}
+		}
+		// With several items the seat price must be named.
+		if _, err := h.ReconcileSeats(ctx, "sub_fixture", 12, gomultistripe.SeatPolicy{}); !errors.Is(err, gomultistripe.ErrSeatItemNotFound) {
+			t.Errorf("without a price: got %v", err)
+		}
+	})
+}
+
+func TestReportRuns(t *testing.T) {
+	var form url.Values
+	polls := 0
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		run := map[string]any{
+			"id": "frr_fixture", "object": "reporting.report_run", "report_type": gomultistripe.ReportRevenueDebitCreditSummary,
+			"status": "pending", "created": 1700000000,
+			"parameters": map[string]any{"interval_start": 1696118400, "interval_end": 1698796800, "currency": "usd"},
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/reporting/report_runs":
+			r.ParseForm()
+			form = r.PostForm
+		case r.URL.Path == "/v1/reporting/report_runs/frr_fixture":
+			if polls++; polls > 2 {
+				run["status"] = "succeeded"
+				run["result"] = map[string]any{"id": "file_fixture", "object": "file", "url": "https://files.stripe.com/v1/files/file_fixture/contents"}
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(run)
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		polls = 0
+		ctx := context.Background()
+		run, err := h.CreateReportRun(ctx, gomultistripe.ReportRunRequest{
+			ReportType:    gomultistripe.ReportRevenueDebitCreditSummary,
+			IntervalStart: time.Unix(1696118400, 0), IntervalEnd: time.Unix(1698796800, 0),
+			Currency: "usd", Columns: []string{"account_category", "debit", "credit"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("report_type") != gomultistripe.ReportRevenueDebitCreditSummary || form.Get("parameters[interval_start]") != "1696118400" ||
+			form.Get("parameters[interval_end]") != "1698796800" || form.Get("parameters[currency]") != "usd" ||
+			form.Get("parameters[columns][2]") != "credit" || form.Has("parameters[timezone]") {
+			t.Errorf("created report run with %v", form)
+		}
+		if run.ID != "frr_fixture" || run.Status != "pending" || run.IntervalEnd.Unix() != 1698796800 {
+			t.Errorf("created %+v", run)
+		}
+
+		// The result of a pending run cannot be downloaded yet.
+		if err := h.DownloadReportRun(ctx, "frr_fixture", io.Discard); !errors.Is(err, gomultistripe.ErrReportNotReady) {
+			t.Errorf("downloading a pending run: %v", err)
+		}
+		run, err = gomultistripe.WaitForReportRun(ctx, h, "frr_fixture", time.Millisecond)
+		if err != nil || run.Status != "succeeded" || run.ResultFileID != "file_fixture" || polls != 3 {
+			t.Errorf("waited for %+v after %d polls: %v", run, polls, err)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+func TestListCustomers_Pages(t *testing.T) {
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/customers" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		customers := []any{map[string]any{"id": "cus_3", "object": "customer"}, map[string]any{"id": "cus_2", "object": "customer"}}
+		hasMore := true
+		if q.Get("starting_after") == "cus_2" {
+			customers, hasMore = []any{map[string]any{"id": "cus_1", "object": "customer"}}, false
+		}
+		if q.Get("limit") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": hasMore, "data": customers})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		var ids []string
+		opts := &gomultistripe.ListOptions{Limit: 2}
+		for {
+			page, err := h.ListCustomers(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range page.Customers {
+				ids = append(ids, c.ID)
+			}
+			if !page.HasMore {
+				break
+			}
+			opts.StartingAfter = page.NextCursor
+		}
+		if !slices.Equal(ids, []string{"cus_3", "cus_2", "cus_1"}) {
+			t.Errorf("listed %v", ids)
+		}
+	})
+}
+
+func TestHandlers_KeepTheirOwnKeyAndEndpoints(t *testing.T) {
+	// tenant answers with a customer named after the server, and only for its own key.
+	tenant := func(name string) *httptest.Server {
+		return fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer sk_test_"+name {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{
+				map[string]any{"id": "cus_" + name, "object": "customer"},
+			}})
+		})
+	}
+	a, b := tenant("a"), tenant("b")
+
+	handlersA, handlersB := versions(), versions()
+	for i := range handlersA {
+		t.Run(handlersA[i].Version(), func(t *testing.T) {
+			handlersA[i].SetSecretKey("sk_test_a")
+			handlersA[i].SetEndpoints(gomultistripe.Endpoints{APIURL: a.URL})
+			handlersB[i].SetSecretKey("sk_test_b")
+			handlersB[i].SetEndpoints(gomultistripe.Endpoints{APIURL: b.URL})
+			var wg sync.WaitGroup
+			for _, c := range []struct {
+				h    gomultistripe.Handler
+				want string
+			}{{handlersA[i], "cus_a"}, {handlersB[i], "cus_b"}, {handlersA[i], "cus_a"}, {handlersB[i], "cus_b"}} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					page, err := c.h.ListCustomers(context.Background(), &gomultistripe.ListOptions{Limit: 1})
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if len(page.Customers) != 1 || page.Customers[0].ID != c.want {
+						t.Errorf("listed %+v, want %s", page.Customers, c.want)
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestContextWithAPIKey_OverridesHandlerKey(t *testing.T) {
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		tenant, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer sk_test_")
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPost {
+			json.NewEncoder(w).Encode(map[string]any{"id": "cus_" + tenant, "object": "customer"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{
+			map[string]any{"id": "cus_" + tenant, "object": "customer"},
+		}})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		h.SetSecretKey("sk_test_handler")
+		for _, tenant := range []string{"a", "b"} {
+			ctx := gomultistripe.ContextWithAPIKey(context.Background(), "sk_test_"+tenant)
+			cust, err := h.CreateCustomer(ctx, &gomultistripe.Customer{Name: tenant})
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, err := h.ListCustomers(ctx, &gomultistripe.ListOptions{Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cust.ID != "cus_"+tenant || len(page.Customers) != 1 || page.Customers[0].ID != "cus_"+tenant {
+				t.Errorf("created %s and listed %+v with the key of %s", cust.ID, page.Customers, tenant)
+			}
+		}
+		page, err := h.ListCustomers(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Customers) != 1 || page.Customers[0].ID != "cus_handler" {
+			t.Errorf("listed %+v without a key in the context", page.Customers)
+		}
+		// The client for the handler's own key follows SetSecretKey.
+		h.SetSecretKey("sk_test_rotated")
+		if page, err = h.ListCustomers(context.Background(), nil); err != nil || len(page.Customers) != 1 || page.Customers[0].ID != "cus_rotated" {
+			t.Errorf("listed %+v after changing the key: %v", page, err)
+		}
+	})
+}
+
+func TestEventDestinations(t *testing.T) {
+	var created map[string]any
+	var paths []string
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		destination := map[string]any{
+			"id": "ed_fixture", "object": "v2.core.event_destination", "name": "billing", "type": "webhook_endpoint",
+			"status": "enabled", "event_payload": "thin", "enabled_events": []string{"v1.billing.meter.error_report_triggered"},
+			"webhook_endpoint": map[string]any{"url": "https://example.com/hook"}, "created": "2025-01-02T03:04:05.000Z",
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/core/event_destinations":
+			json.NewDecoder(r.Body).Decode(&created)
+			destination["webhook_endpoint"] = map[string]any{"url": "https://example.com/hook", "signing_secret": "whsec_fixture"}
+			json.NewEncoder(w).Encode(destination)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/core/event_destinations":
+			if r.URL.Query().Get("page") == "" {
+				json.NewEncoder(w).Encode(map[string]any{"data": []any{destination}, "next_page_url": "/v2/core/event_destinations?page=2"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": []any{destination}, "next_page_url": nil})
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/core/event_destinations/ed_fixture/disable":
+			destination["status"] = "disabled"
+			json.NewEncoder(w).Encode(destination)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		paths = nil
+		dest, err := h.CreateEventDestination(context.Background(), gomultistripe.EventDestinationParams{
+			Name: "billing", Type: gomultistripe.EventDestinationWebhook, WebhookURL: "https://example.com/hook",
+			EnabledEvents: []string{"v1.billing.meter.error_report_triggered"},
+		})
+		if !supported(t, h, "CreateEventDestination", err) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created["event_payload"] != "thin" || created["webhook_endpoint"].(map[string]any)["url"] != "https://example.com/hook" {
+			t.Errorf("sent %v", created)
+		}
+		if dest.ID != "ed_fixture" || dest.WebhookSecret != "whsec_fixture" || dest.CreatedAt.IsZero() {
+			t.Errorf("got destination %+v", dest)
+		}
+
+		all, err := h.ListEventDestinations(context.Background())
+		if err != nil || len(all) != 2 {
+			t.Fatalf("listed %d destinations: %v", len(all), err)
+		}
+		dest, err = h.SetEventDestinationEnabled(context.Background(), "ed_fixture", false)
+		if err != nil || dest.Status != "disabled" {
+			t.Fatalf("got %+v: %v", dest, err)
+		}
+		if len(paths) != 4 {
+			t.Errorf("sent %v", paths)
+		}
+	})
+}
+
+func TestEndpoints_FilesAreServedSeparately(t *testing.T) {
+	var files []string
+	filesSrv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		files = append(files, r.URL.Path)
+		w.Write([]byte("currency,amount\nusd,1000\n"))
+	})
+	api := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/reporting/report_runs/frr_fixture" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "frr_fixture", "object": "reporting.report_run", "status": "succeeded",
+			"result": map[string]any{"id": "file_fixture", "object": "file", "url": "https://files.stripe.com/v1/files/file_fixture/contents"},
+		})
+	})
+
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		files = nil
+		h.SetEndpoints(gomultistripe.Endpoints{APIURL: api.URL, FilesURL: filesSrv.URL})
+		var csv strings.Builder
+		if err := h.DownloadReportRun(context.Background(), "frr_fixture", &csv); err != nil {
+			t.Fatal(err)
+		}
+		if csv.String() != "currency,amount\nusd,1000\n" || !slices.Equal(files, []string{"/v1/files/file_fixture/contents"}) {
+			t.Errorf("downloaded %q from %v", csv.String(), files)
+		}
+	})
+}
+
+// auditLog is an AuditSink keeping the records it receives.
+type auditLog struct {
+	mu      sync.Mutex
+	records []gomultistripe.AuditRecord
+}
+
+func (a *auditLog) RecordIdempotencyKey(_ context.Context, rec gomultistripe.AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.records = append(a.records, rec)
+}
+
+func TestAuditSink_RecordsTheKeySent(t *testing.T) {
+	var keys, accounts []string
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		accounts = append(accounts, r.Header.Get("Stripe-Account"))
+		json.NewEncoder(w).Encode(map[string]any{"id": "pm_fixture", "object": "payment_method", "type": "card", "customer": "cus_fixture"})
+	})
+	audit := &auditLog{}
+	gomultistripe.SetAuditSink(audit)
+	t.Cleanup(func() { gomultistripe.SetAuditSink(nil) })
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		keys, accounts, audit.records = nil, nil, nil
+		ctx := gomultistripe.ContextWithTraceID(gomultistripe.ContextWithAccount(context.Background(), "acct_fixture"), "trace_fixture")
+		if _, err := h.AttachPaymentMethod(ctx, "cus_fixture", "pm_fixture"); err != nil {
+			t.Fatal(err)
+		}
+		if len(audit.records) != 1 || len(keys) != 1 {
+			t.Fatalf("recorded %+v for requests with keys %v", audit.records, keys)
+		}
+		rec := audit.records[0]
+		if rec.IdempotencyKey == "" || rec.IdempotencyKey != keys[0] || rec.Operation != "AttachPaymentMethod" ||
+			rec.Version != h.Version() || rec.Account != "acct_fixture" || accounts[0] != "acct_fixture" || rec.TraceID != "trace_fixture" ||
+			rec.EntityIDs["customer"] != "cus_fixture" || rec.EntityIDs["payment_method"] != "pm_fixture" || rec.Time.IsZero() {
+			t.Errorf("recorded %+v for key %q", rec, keys[0])
+		}
+	})
+}
+
+func TestBuildInfo_ReportsTheLinkedSDK(t *testing.T) {
+	var got []string
+	for _, info := range gomultistripe.BuildInfo() {
+		got = append(got, info.Version)
+		if info.SDKModule != "github.com/stripe/stripe-go/"+info.Version || info.APIVersion != gomultistripe.GetHandler(info.Version).APIVersion() ||
+			!strings.HasPrefix(info.SDKVersion, info.Version+".") || info.SDKSum == "" || info.Replaced {
+			t.Errorf("build info %+v", info)
+		}
+	}
+	if !slices.Equal(got, []string{"v74", "v75", "v76", "v78", "v79", "v80", "v81", "v82"}) {
+		t.Errorf("reported %v", got)
+	}
+}
+
+func TestSetLogger_RecordsCarryTheOperation(t *testing.T) {
+	var buf bytes.Buffer
+	gomultistripe.SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { gomultistripe.SetLogger(nil) })
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"id": "pm_fixture", "object": "payment_method", "type": "card"})
+	})
+	// records decodes the records logged since the last call.
+	records := func() []map[string]any {
+		var out []map[string]any
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var rec map[string]any
+			if err := dec.Decode(&rec); err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, rec)
+		}
+		buf.Reset()
+		return out
+	}
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		records()
+		e := event("refund.created", map[string]any{"id": "re_fixture", "object": "refund"})
+		e["livemode"], e["request"] = true, map[string]any{"id": "req_fixture"}
+		if _, err := deliver(h, e); err != nil {
+			t.Fatal(err)
+		}
+		recs := records()
+		if len(recs) != 1 || recs[0][gomultistripe.LogKeyVersion] != h.Version() ||
+			recs[0][gomultistripe.LogKeyOperation] != "HandleWebhook" || recs[0][gomultistripe.LogKeyEventID] != "evt_refund.created" ||
+			recs[0][gomultistripe.LogKeyEventType] != "refund.created" || recs[0][gomultistripe.LogKeyLivemode] != true ||
+			recs[0][gomultistripe.LogKeyRequestID] != "req_fixture" {
+			t.Errorf("logged %v", recs)
+		}
+
+		if _, err := h.HandleWebhook([]byte(`{}`), "t=1,v1=00"); err == nil {
+			t.Fatal("accepted an unsigned webhook")
+		}
+		if recs := records(); len(recs) != 1 || recs[0]["level"] != "WARN" || recs[0][gomultistripe.LogKeyOperation] != "HandleWebhook" {
+			t.Errorf("logged %v", recs)
+		}
+
+		ctx := gomultistripe.ContextWithTraceID(gomultistripe.ContextWithAccount(context.Background(), "acct_fixture"), "trace_fixture")
+		if _, err := h.AttachPaymentMethod(ctx, "cus_fixture", "pm_fixture"); err != nil {
+			t.Fatal(err)
+		}
+		recs = records()
+		if len(recs) != 1 || recs[0][gomultistripe.LogKeyOperation] != "AttachPaymentMethod" || recs[0][gomultistripe.LogKeyVersion] != h.Version() ||
+			recs[0][gomultistripe.LogKeyAccount] != "acct_fixture" || recs[0][gomultistripe.LogKeyTraceID] != "trace_fixture" {
+			t.Errorf("logged %v", recs)
+		}
+	})
+}
@@ -0,0 +1,461 @@
+package conformance_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+func TestConnectAccounts(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		acct := map[string]any{
+			"id": "acct_fixture", "object": "account", "type": "express", "country": "US",
+			"charges_enabled": false, "capabilities": map[string]any{"card_payments": "pending", "transfers": "active"},
+			"requirements": map[string]any{"currently_due": []string{"external_account"}, "disabled_reason": "requirements.past_due"},
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/accounts":
+			json.NewEncoder(w).Encode(acct)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/account" && r.Header.Get("Stripe-Account") == "acct_fixture":
+			json.NewEncoder(w).Encode(acct)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/account_links":
+			json.NewEncoder(w).Encode(map[string]any{"object": "account_link", "url": "https://connect.stripe.com/setup/e/fixture", "expires_at": 1700000300})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		ctx := context.Background()
+		acct, err := h.CreateAccount(ctx, gomultistripe.AccountParams{
+			Type: gomultistripe.AccountExpress, Country: "US", Capabilities: []string{"card_payments", "transfers"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[0]; got.Get("type") != "express" || got.Get("capabilities[card_payments][requested]") != "true" || got.Get("capabilities[transfers][requested]") != "true" {
+			t.Errorf("sent %v", got)
+		}
+		if acct.ID != "acct_fixture" || acct.Capabilities["transfers"] != "active" || acct.Capabilities["card_payments"] != "pending" ||
+			len(acct.RequirementsDue) != 1 || acct.RequirementsDisabledReason != "requirements.past_due" {
+			t.Errorf("got account %+v", acct)
+		}
+		if _, err := h.RetrieveAccount(gomultistripe.ContextWithAccount(ctx, "acct_fixture"), ""); err != nil {
+			t.Errorf("retrieving the account in the context: %v", err)
+		}
+		link, err := h.CreateAccountLink(ctx, gomultistripe.AccountLinkParams{
+			AccountID: acct.ID, RefreshURL: "https://example.com/refresh", ReturnURL: "https://example.com/return",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[len(forms)-1]; got.Get("type") != "account_onboarding" || got.Get("account") != "acct_fixture" {
+			t.Errorf("sent %v", got)
+		}
+		if link.URL == "" || link.ExpiresAt.Unix() != 1700000300 {
+			t.Errorf("got link %+v", link)
+		}
+	})
+}
+
+func TestTransfersAndDestinationCharges(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.Form)
+		transfer := map[string]any{
+			"id": "tr_1", "object": "transfer", "amount": 800, "currency": "usd", "transfer_group": "order_42",
+			"destination": "acct_seller", "source_transaction": "ch_1", "destination_payment": "py_1",
+		}
+		switch r.URL.Path {
+		case "/v1/payment_intents":
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "pi_1", "object": "payment_intent", "amount": 1000, "currency": "usd", "status": "succeeded",
+				"application_fee_amount": 200, "transfer_data": map[string]any{"destination": "acct_seller"}, "transfer_group": "order_42",
+			})
+		case "/v1/transfers":
+			if r.Method == http.MethodGet {
+				json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{transfer}})
+				return
+			}
+			json.NewEncoder(w).Encode(transfer)
+		case "/v1/transfers/tr_1/reversals":
+			json.NewEncoder(w).Encode(map[string]any{"id": "trr_1", "object": "transfer_reversal", "amount": 300, "currency": "usd"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		ctx := context.Background()
+		pi, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 1000, Currency: "usd", ApplicationFeeAmount: 200, TransferDestination: "acct_seller", TransferGroup: "order_42",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[0]; got.Get("application_fee_amount") != "200" || got.Get("transfer_data[destination]") != "acct_seller" || got.Get("transfer_group") != "order_42" {
+			t.Errorf("sent %v", got)
+		}
+		if pi.ApplicationFeeAmount != 200 || pi.TransferDestination != "acct_seller" || pi.TransferGroup != "order_42" {
+			t.Errorf("got intent %+v", pi)
+		}
+
+		tr, err := h.CreateTransfer(ctx, gomultistripe.TransferParams{
+			Amount: 800, Currency: "usd", DestinationAccountID: "acct_seller", SourceChargeID: "ch_1", TransferGroup: "order_42",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[1]; got.Get("destination") != "acct_seller" || got.Get("source_transaction") != "ch_1" {
+			t.Errorf("sent %v", got)
+		}
+		if tr.DestinationAccountID != "acct_seller" || tr.SourceChargeID != "ch_1" || tr.DestinationPaymentID != "py_1" {
+			t.Errorf("got transfer %+v", tr)
+		}
+		rev, err := h.ReverseTransfer(ctx, "tr_1", 300, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[2]; got.Get("amount") != "300" || got.Get("refund_application_fee") != "true" || rev.TransferID != "tr_1" || rev.Amount != 300 {
+			t.Errorf("sent %v, got reversal %+v", got, rev)
+		}
+		page, err := h.ListTransfers(ctx, gomultistripe.TransferQuery{DestinationAccountID: "acct_seller"}, &gomultistripe.ListOptions{Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := forms[3]; got.Get("destination") != "acct_seller" || !page.HasMore || page.NextCursor != "tr_1" {
+			t.Errorf("sent %v, got page %+v", got, page)
+		}
+	})
+}
+
+func TestDisputes(t *testing.T) {
+	dispute := func(status string) map[string]any {
+		return map[string]any{
+			"id": "dp_fixture", "object": "dispute", "amount": 1000, "currency": "usd", "reason": "product_not_received",
+			"status": status, "charge": "ch_fixture", "payment_intent": "pi_fixture", "created": 1700000000,
+			"evidence_details": map[string]any{"due_by": 1700600000, "has_evidence": status != "needs_response", "past_due": false, "submission_count": 0},
+			"metadata":         map[string]any{"order": "42"},
+		}
+	}
+	var requests []string
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/disputes":
+			if r.URL.Query().Get("payment_intent") != "pi_fixture" {
+				t.Errorf("listed with %v", r.URL.Query())
+			}
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{dispute("needs_response")}})
+		case strings.HasSuffix(r.URL.Path, "/close"):
+			json.NewEncoder(w).Encode(dispute("lost"))
+		case r.Method == http.MethodPost:
+			json.NewEncoder(w).Encode(dispute("under_review"))
+		default:
+			json.NewEncoder(w).Encode(dispute("needs_response"))
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		requests = nil
+
+		page, err := h.ListDisputes(ctx, gomultistripe.DisputeQuery{PaymentIntentID: "pi_fixture"}, nil)
+		if err != nil || len(page.Disputes) != 1 || page.NextCursor != "dp_fixture" {
+			t.Fatalf("page %+v: %v", page, err)
+		}
+		d := page.Disputes[0]
+		if d.ChargeID != "ch_fixture" || d.Reason != "product_not_received" || d.EvidenceDueBy.Unix() != 1700600000 || d.Metadata["order"] != "42" {
+			t.Errorf("dispute %+v", d)
+		}
+		if d, err = h.RetrieveDispute(ctx, "dp_fixture"); err != nil || d.Status != "needs_response" {
+			t.Fatalf("retrieved %+v: %v", d, err)
+		}
+		d, err = h.UpdateDispute(ctx, "dp_fixture", gomultistripe.DisputeUpdate{
+			Evidence: &gomultistripe.DisputeEvidence{ShippingTrackingNumber: "1Z999", Receipt: "file_receipt"},
+			Submit:   true,
+		})
+		if err != nil || d.Status != "under_review" {
+			t.Fatalf("updated %+v: %v", d, err)
+		}
+		if form.Get("evidence[shipping_tracking_number]") != "1Z999" || form.Get("evidence[receipt]") != "file_receipt" ||
+			form.Get("submit") != "true" || form.Has("evidence[customer_name]") {
+			t.Errorf("updated with %v", form)
+		}
+		if d, err = h.CloseDispute(ctx, "dp_fixture"); err != nil || d.Status != "lost" {
+			t.Fatalf("closed %+v: %v", d, err)
+		}
+		if requests[3] != "POST /v1/disputes/dp_fixture/close" {
+			t.Errorf("requests %v", requests)
+		}
+
+		evt, err := deliver(h, event("charge.dispute.closed", dispute("won")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.Dispute == nil || evt.Dispute.Status != "won" || evt.Dispute.Amount != 1000 || evt.ChargeID != "ch_fixture" ||
+			evt.PaymentIntentID != "pi_fixture" || evt.Metadata["order"] != "42" {
+			t.Errorf("event %+v, dispute %+v", evt, evt.Dispute)
+		}
+	})
+}
+
+func TestBalanceAndPayouts(t *testing.T) {
+	var queries []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		switch r.URL.Path {
+		case "/v1/balance":
+			json.NewEncoder(w).Encode(map[string]any{
+				"object":    "balance",
+				"available": []any{map[string]any{"amount": 5000, "currency": "usd"}, map[string]any{"amount": 700, "currency": "eur"}},
+				"pending":   []any{map[string]any{"amount": 1200, "currency": "usd"}},
+			})
+		case "/v1/payouts":
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{map[string]any{
+				"id": "po_fixture", "object": "payout", "amount": 4000, "currency": "usd", "status": "paid", "automatic": true,
+				"arrival_date": 1700100000, "method": "standard", "type": "bank_account", "destination": "ba_fixture",
+				"balance_transaction": "txn_payout", "created": 1700000000,
+			}}})
+		case "/v1/balance_transactions":
+			var source any = "ch_fixture"
+			if r.URL.Query().Get("expand[0]") == "data.source" {
+				source = map[string]any{
+					"id": "ch_fixture", "object": "charge", "payment_intent": "pi_fixture",
+					"amount": 800, "amount_captured": 800, "currency": "eur",
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{map[string]any{
+				"id": "txn_charge", "object": "balance_transaction", "amount": 1000, "fee": 59, "net": 941, "currency": "usd", "exchange_rate": 1.25,
+				"type": "charge", "reporting_category": "charge", "status": "available", "available_on": 1700050000,
+				"source": source, "created": 1700000000,
+			}}})
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		queries = nil
+
+		b, err := h.RetrieveBalance(ctx)
+		if err != nil || b.Available["usd"] != 5000 || b.Available["eur"] != 700 || b.Pending["usd"] != 1200 {
+			t.Fatalf("balance %+v: %v", b, err)
+		}
+		payouts, err := h.ListPayouts(ctx, gomultistripe.PayoutQuery{Status: "paid", ArrivalFrom: time.Unix(1700000000, 0)}, nil)
+		if err != nil || len(payouts.Payouts) != 1 {
+			t.Fatalf("payouts %+v: %v", payouts, err)
+		}
+		po := payouts.Payouts[0]
+		if po.DestinationID != "ba_fixture" || po.BalanceTransactionID != "txn_payout" || !po.Automatic || po.ArrivalDate.Unix() != 1700100000 {
+			t.Errorf("payout %+v", po)
+		}
+		if queries[1].Get("status") != "paid" || queries[1].Get("arrival_date[gte]") != "1700000000" {
+			t.Errorf("listed payouts with %v", queries[1])
+		}
+		txns, err := h.ListBalanceTransactions(ctx, gomultistripe.BalanceTransactionQuery{PayoutID: po.ID}, &gomultistripe.ListOptions{Limit: 10})
+		if err != nil || len(txns.Transactions) != 1 || txns.NextCursor != "txn_charge" {
+			t.Fatalf("transactions %+v: %v", txns, err)
+		}
+		if txn := txns.Transactions[0]; txn.Net != 941 || txn.Fee != 59 || txn.SourceID != "ch_fixture" || txn.Status != "available" ||
+			txn.ExchangeRate != 1.25 || txn.PresentmentCurrency != "" {
+			t.Errorf("transaction %+v", txn)
+		}
+		if queries[2].Get("payout") != "po_fixture" || queries[2].Get("limit") != "10" {
+			t.Errorf("listed transactions with %v", queries[2])
+		}
+		txns, err = h.ListBalanceTransactions(ctx, gomultistripe.BalanceTransactionQuery{CreatedFrom: time.Unix(1700000000, 0), ExpandSources: true}, nil)
+		if err != nil || txns.Transactions[0].ChargeID != "ch_fixture" || txns.Transactions[0].PaymentIntentID != "pi_fixture" {
+			t.Errorf("expanded transactions %+v: %v", txns.Transactions, err)
+		}
+		if txn := txns.Transactions[0]; txn.PresentmentAmount != 800 || txn.PresentmentCurrency != "eur" {
+			t.Errorf("presentment amount %d %s", txn.PresentmentAmount, txn.PresentmentCurrency)
+		}
+		if queries[3].Get("created[gte]") != "1700000000" {
+			t.Errorf("listed transactions with %v", queries[3])
+		}
+
+		txns, err = h.ListBalanceTransactions(ctx, gomultistripe.BalanceTransactionQuery{ExpandSources: true, ReportingCurrency: "EUR"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if txn := txns.Transactions[0]; txn.ReportingCurrency != "eur" || txn.ReportingAmount != 800 || txn.ReportingFee != 47 || txn.ReportingNet != 753 {
+			t.Errorf("reporting amounts %+v", txn)
+		}
+		_, err = h.ListBalanceTransactions(ctx, gomultistripe.BalanceTransactionQuery{ReportingCurrency: "gbp"}, nil)
+		if !errors.Is(err, gomultistripe.ErrNoExchangeRate) {
+			t.Errorf("converted without rates: %v", err)
+		}
+		txns, err = h.ListBalanceTransactions(ctx, gomultistripe.BalanceTransactionQuery{
+			ReportingCurrency: "gbp",
+			ReportingRates:    gomultistripe.StaticRates{Base: "gbp", Rates: map[string]float64{"usd": 0.8}},
+		}, nil)
+		if err != nil || txns.Transactions[0].ReportingAmount != 800 || txns.Transactions[0].ReportingRate != 0.8 {
+			t.Errorf("reporting amounts %+v: %v", txns, err)
+		}
+	})
+}
+
+func TestPersonsAndExternalAccounts(t *testing.T) {
+	var requests []string
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		forms = append(forms, r.PostForm)
+		switch {
+		case strings.Contains(r.URL.Path, "/persons"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "person_fixture", "object": "person", "account": "acct_fixture", "first_name": "Jenny", "last_name": "Rosen",
+				"dob":          map[string]any{"day": 1, "month": 2, "year": 1990},
+				"address":      map[string]any{"line1": "1 Main St", "city": "Springfield", "postal_code": "12345", "country": "US"},
+				"relationship": map[string]any{"representative": true, "owner": true, "percent_ownership": 51, "title": "CEO"},
+				"requirements": map[string]any{"currently_due": []string{"ssn_last_4"}},
+				"verification": map[string]any{"status": "unverified"},
+				"created":      1700000000, "deleted": r.Method == http.MethodDelete,
+			})
+		case strings.Contains(r.URL.Path, "/external_accounts"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "ba_fixture", "object": "bank_account", "account": "acct_fixture", "country": "US", "currency": "usd",
+				"bank_name": "STRIPE TEST BANK", "last4": "6789", "routing_number": "110000000", "status": "new",
+				"default_for_currency": true, "deleted": r.Method == http.MethodDelete,
+			})
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		requests, forms = nil, nil
+
+		p, err := h.CreatePerson(ctx, "acct_fixture", gomultistripe.PersonParams{
+			FirstName:    "Jenny",
+			LastName:     "Rosen",
+			DOB:          time.Date(1990, 2, 1, 0, 0, 0, 0, time.UTC),
+			Address:      &gomultistripe.Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+			Relationship: &gomultistripe.PersonRelationship{Representative: true, Owner: true, PercentOwnership: 51, Title: "CEO"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.AccountID != "acct_fixture" || p.DOB.Year() != 1990 || p.Address.City != "Springfield" ||
+			!p.Relationship.Representative || p.Relationship.PercentOwnership != 51 || p.RequirementsDue[0] != "ssn_last_4" {
+			t.Errorf("person %+v", p)
+		}
+		if f := forms[0]; f.Get("dob[month]") != "2" || f.Get("relationship[representative]") != "true" || f.Get("address[city]") != "Springfield" {
+			t.Errorf("created person with %v", f)
+		}
+		if _, err := h.UpdatePerson(ctx, "acct_fixture", p.ID, gomultistripe.PersonParams{SSNLast4: "0000"}); err != nil {
+			t.Fatal(err)
+		}
+		if f := forms[1]; f.Get("ssn_last_4") != "0000" || f.Has("first_name") || f.Has("relationship[owner]") {
+			t.Errorf("updated person with %v", f)
+		}
+		if err := h.DeletePerson(ctx, "acct_fixture", p.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		ba, err := h.AddExternalBankAccount(ctx, "acct_fixture", gomultistripe.ExternalBankAccountParams{
+			Country: "US", Currency: "usd", RoutingNumber: "110000000", AccountNumber: "000123456789", DefaultForCurrency: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ba.AccountID != "acct_fixture" || ba.Last4 != "6789" || ba.Status != "new" || !ba.DefaultForCurrency {
+			t.Errorf("bank account %+v", ba)
+		}
+		if f := forms[3]; f.Get("external_account[routing_number]") != "110000000" || f.Get("external_account[account_number]") != "000123456789" {
+			t.Errorf("added bank account with %v", f)
+		}
+		if err := h.DeleteExternalAccount(ctx, "acct_fixture", ba.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{
+			"POST /v1/accounts/acct_fixture/persons",
+			"POST /v1/accounts/acct_fixture/persons/person_fixture",
+			"DELETE /v1/accounts/acct_fixture/persons/person_fixture",
+			"POST /v1/accounts/acct_fixture/external_accounts",
+			"DELETE /v1/accounts/acct_fixture/external_accounts/ba_fixture",
+		}
+		if !slices.Equal(requests, want) {
+			t.Errorf("requests %q", requests)
+		}
+	})
+}
+
+func TestTopups(t *testing.T) {
+	topup := func(status string) map[string]any {
+		return map[string]any{
+			"id": "tu_fixture", "object": "topup", "amount": 50000, "currency": "usd", "status": status,
+			"expected_availability_date": 1700100000, "balance_transaction": "txn_topup", "transfer_group": "payouts_week_46",
+			"metadata": map[string]string{"batch": "46"}, "created": 1700000000,
+		}
+	}
+	var requests []string
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		forms = append(forms, r.Form)
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "has_more": true, "data": []any{topup("succeeded")}})
+			return
+		}
+		json.NewEncoder(w).Encode(topup("pending"))
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		requests, forms = nil, nil
+
+		tu, err := h.CreateTopup(ctx, gomultistripe.TopupParams{
+			Amount: 50000, Currency: "usd", TransferGroup: "payouts_week_46", Metadata: map[string]string{"batch": "46"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tu.Status != "pending" || tu.BalanceTransactionID != "txn_topup" || tu.ExpectedAvailabilityDate.Unix() != 1700100000 {
+			t.Errorf("top-up %+v", tu)
+		}
+		if f := forms[0]; f.Get("amount") != "50000" || f.Get("transfer_group") != "payouts_week_46" || f.Get("metadata[batch]") != "46" {
+			t.Errorf("created top-up with %v", f)
+		}
+		page, err := h.ListTopups(ctx, gomultistripe.TopupQuery{Status: "succeeded"}, &gomultistripe.ListOptions{Limit: 10})
+		if err != nil || len(page.Topups) != 1 || page.NextCursor != "tu_fixture" || page.Topups[0].Status != "succeeded" {
+			t.Fatalf("top-ups %+v: %v", page, err)
+		}
+		if forms[1].Get("status") != "succeeded" || forms[1].Get("limit") != "10" {
+			t.Errorf("listed top-ups with %v", forms[1])
+		}
+		if want := []string{"POST /v1/topups", "GET /v1/topups"}; !slices.Equal(requests, want) {
+			t.Errorf("requests %q", requests)
+		}
+
+		failed := topup("failed")
+		failed["failure_code"], failed["failure_message"] = "insufficient_funds", "The bank account has insufficient funds."
+		evt, err := deliver(h, event("topup.failed", failed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.Type != gomultistripe.EventTopupFailed || evt.Topup == nil || evt.Topup.FailureCode != "insufficient_funds" ||
+			evt.Topup.Amount != 50000 || evt.Metadata["batch"] != "46" {
+			t.Errorf("event %+v, top-up %+v", evt, evt.Topup)
+		}
+	})
+}
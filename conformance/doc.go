@@ -0,0 +1,3 @@
+// Package conformance tests that every handler version implements the Handler interface
+// the same way, against a fake Stripe API. It has no exported API; see its tests.
+package conformance
@@ -0,0 +1,92 @@
+package conformance_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	v74 "github.com/iqhive/gomultistripe/v74"
+	v75 "github.com/iqhive/gomultistripe/v75"
+	v76 "github.com/iqhive/gomultistripe/v76"
+	v78 "github.com/iqhive/gomultistripe/v78"
+	v79 "github.com/iqhive/gomultistripe/v79"
+	v80 "github.com/iqhive/gomultistripe/v80"
+	v81 "github.com/iqhive/gomultistripe/v81"
+	v82 "github.com/iqhive/gomultistripe/v82"
+)
+
+// webhookSecret and secretKey are the credentials eachVersion configures handlers with.
+const (
+	webhookSecret = "whsec_fixture"
+	secretKey     = "sk_test_fixture"
+)
+
+// versions returns a new handler of every version.
+func versions() []gomultistripe.Handler {
+	return []gomultistripe.Handler{
+		v74.NewHandler(), v75.NewHandler(), v76.NewHandler(), v78.NewHandler(),
+		v79.NewHandler(), v80.NewHandler(), v81.NewHandler(), v82.NewHandler(),
+	}
+}
+
+// fakeStripe starts a fake Stripe API answering every request with handler. It is closed
+// when the test ends.
+func fakeStripe(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// eachVersion runs test as a subtest for a new handler of every version, configured with
+// secretKey and webhookSecret and, unless api is nil, calling api instead of Stripe.
+func eachVersion(t *testing.T, api *httptest.Server, test func(t *testing.T, h gomultistripe.Handler)) {
+	t.Helper()
+	for _, h := range versions() {
+		h.SetSecretKey(secretKey)
+		h.SetWebhookSecret(webhookSecret)
+		if api != nil {
+			h.SetEndpoints(gomultistripe.Endpoints{APIURL: api.URL, MeterEventsURL: api.URL})
+		}
+		t.Run(h.Version(), func(t *testing.T) { test(t, h) })
+	}
+}
+
+// supported reports whether the capability matrix lists method as callable on h. When it
+// does not, it checks that err is ErrUnsupported.
+func supported(t *testing.T, h gomultistripe.Handler, method string, err error) bool {
+	t.Helper()
+	if gomultistripe.Capabilities().Supported(h.Version(), method) {
+		return true
+	}
+	if !errors.Is(err, gomultistripe.ErrUnsupported) {
+		t.Errorf("%s: expected ErrUnsupported, got %v", method, err)
+	}
+	return false
+}
+
+// event returns the JSON fields of an event of eventType carrying object, to be changed
+// before it is delivered.
+func event(eventType string, object map[string]any) map[string]any {
+	return map[string]any{
+		"id": "evt_" + eventType, "object": "event", "created": 1700000001,
+		"type": eventType, "data": map[string]any{"object": object},
+	}
+}
+
+// deliver signs evt with webhookSecret, in the API version of h unless evt sets one, and
+// passes it to h.HandleWebhook.
+func deliver(h gomultistripe.Handler, evt map[string]any) (*gomultistripe.CallbackEvent, error) {
+	if _, ok := evt["api_version"]; !ok {
+		evt["api_version"] = h.APIVersion()
+	}
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+	return h.HandleWebhook(payload, gomultistripe.SignPayload(payload, webhookSecret, time.Now()))
+}
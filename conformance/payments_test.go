@@ -0,0 +1,953 @@
+package conformance_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+func TestPaymentIntent_ManualCaptureFlow(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		intent := map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "usd", "capture_method": "manual"}
+		switch r.URL.Path {
+		case "/v1/payment_intents":
+			intent["status"] = "requires_confirmation"
+		case "/v1/payment_intents/pi_fixture/confirm":
+			intent["status"], intent["amount_capturable"] = "requires_capture", 5000
+		case "/v1/payment_intents/pi_fixture/cancel":
+			intent["status"], intent["cancellation_reason"] = "canceled", "abandoned"
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(intent)
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		ctx := context.Background()
+		pi, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "usd", CustomerID: "cus_fixture", CaptureMethod: "manual", ConfirmLater: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if forms[0].Get("confirm") != "false" || forms[0].Get("capture_method") != "manual" {
+			t.Errorf("created with %v", forms[0])
+		}
+		if pi, err = h.ConfirmPaymentIntent(ctx, pi.ID, "pm_fixture"); err != nil || pi.AmountCapturable != 5000 {
+			t.Fatalf("confirmed %+v: %v", pi, err)
+		}
+		if forms[1].Get("payment_method") != "pm_fixture" {
+			t.Errorf("confirmed with %v", forms[1])
+		}
+		if pi, err = h.CancelPaymentIntent(ctx, pi.ID, "abandoned"); err != nil || pi.Status != "canceled" || pi.CancellationReason != "abandoned" {
+			t.Fatalf("canceled %+v: %v", pi, err)
+		}
+		if forms[2].Get("cancellation_reason") != "abandoned" {
+			t.Errorf("canceled with %v", forms[2])
+		}
+	})
+}
+
+func TestRetrievePaymentIntent_CardChecks(t *testing.T) {
+	var query url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "usd", "status": "succeeded",
+			"latest_charge": map[string]any{
+				"id": "ch_fixture", "object": "charge", "payment_method_details": map[string]any{"type": "card", "card": map[string]any{
+					"brand": "visa", "last4": "4242", "network_transaction_id": "ntid_fixture",
+					"checks": map[string]any{"cvc_check": "pass", "address_line1_check": "fail", "address_postal_code_check": "unavailable"},
+				}},
+			},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		pi, err := h.RetrievePaymentIntent(context.Background(), "pi_fixture")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains([]string{query.Get("expand[0]"), query.Get("expand[1]")}, "latest_charge") {
+			t.Errorf("retrieved with %v", query)
+		}
+		if pi.LatestChargeID != "ch_fixture" || pi.CVCCheck != "pass" || pi.AddressLine1Check != "fail" || pi.AddressPostalCodeCheck != "unavailable" {
+			t.Errorf("got %+v", pi)
+		}
+		// The network transaction ID is only in the models of stripe-go v81 and later.
+		if want := h.Version() >= "v81"; (pi.NetworkTransactionID == "ntid_fixture") != want {
+			t.Errorf("network transaction ID %q", pi.NetworkTransactionID)
+		}
+	})
+}
+
+func TestRetrieveAndListCharges(t *testing.T) {
+	charge := map[string]any{
+		"id": "ch_fixture", "object": "charge", "customer": "cus_fixture", "payment_intent": "pi_fixture",
+		"invoice": "in_fixture", "payment_method": "pm_fixture", "balance_transaction": "txn_fixture",
+		"amount": 5000, "amount_captured": 5000, "amount_refunded": 5000, "currency": "usd", "status": "succeeded",
+		"paid": true, "captured": true, "refunded": true, "receipt_url": "https://pay.stripe.com/receipts/fixture",
+		"created": 1700000000,
+	}
+	var paths []string
+	var query url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		query = r.URL.Query()
+		if r.URL.Path == "/v1/charges" {
+			json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{charge}})
+			return
+		}
+		json.NewEncoder(w).Encode(charge)
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		paths = nil
+		ch, err := h.RetrieveCharge(context.Background(), "ch_fixture")
+		if !supported(t, h, "RetrieveCharge", err) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch.ID != "ch_fixture" || ch.CustomerID != "cus_fixture" || ch.PaymentIntentID != "pi_fixture" || ch.PaymentMethodID != "pm_fixture" ||
+			ch.BalanceTransactionID != "txn_fixture" || ch.AmountRefunded != 5000 || !ch.Refunded || ch.ReceiptURL == "" || ch.Metadata == nil {
+			t.Errorf("charge %+v", ch)
+		}
+		// The basil API version (v82) no longer links charges to invoices.
+		if want := h.Version() < "v82"; (ch.InvoiceID == "in_fixture") != want {
+			t.Errorf("invoice ID %q", ch.InvoiceID)
+		}
+
+		charges, err := h.ListCharges(context.Background(), "cus_fixture")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(charges) != 1 || charges[0].ID != "ch_fixture" || query.Get("customer") != "cus_fixture" {
+			t.Errorf("listed %+v with %v", charges, query)
+		}
+		if !slices.Equal(paths, []string{"/v1/charges/ch_fixture", "/v1/charges"}) {
+			t.Errorf("requested %v", paths)
+		}
+	})
+}
+
+func TestListUncapturedPaymentIntents(t *testing.T) {
+	var query url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		charge := map[string]any{"id": "ch_fixture", "object": "charge", "payment_method_details": map[string]any{
+			"type": "card", "card": map[string]any{"brand": "visa", "last4": "4242", "capture_before": 1700604800},
+		}}
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []any{
+			map[string]any{"id": "pi_authorized", "object": "payment_intent", "amount": 5000, "amount_capturable": 5000, "currency": "usd",
+				"status": "requires_capture", "capture_method": "manual", "latest_charge": charge},
+			map[string]any{"id": "pi_captured", "object": "payment_intent", "amount": 5000, "currency": "usd", "status": "succeeded"},
+		}})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		intents, err := h.ListUncapturedPaymentIntents(context.Background(), "cus_fixture")
+		if err != nil {
+			t.Fatal(err)
+		}
+		since, _ := strconv.ParseInt(query.Get("created[gte]"), 10, 64)
+		if query.Get("customer") != "cus_fixture" || time.Since(time.Unix(since, 0)).Round(time.Hour) != gomultistripe.UncapturedAuthorizationWindow {
+			t.Errorf("listed with %v", query)
+		}
+		if len(intents) != 1 || intents[0].ID != "pi_authorized" || intents[0].AmountCapturable != 5000 || intents[0].CaptureMethod != "manual" {
+			t.Fatalf("listed %+v", intents)
+		}
+		// capture_before is only in the models of stripe-go v76 and later.
+		if want := h.Version() >= "v76"; (intents[0].AuthorizationExpiresAt.Unix() == 1700604800) != want {
+			t.Errorf("authorization expires at %v", intents[0].AuthorizationExpiresAt)
+		}
+	})
+}
+
+func TestCapturePaymentIntent_Multicapture(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		intent := map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "usd", "capture_method": "manual"}
+		switch r.URL.Path {
+		case "/v1/payment_intents":
+			intent["status"] = "requires_payment_method"
+		case "/v1/payment_intents/pi_fixture/capture":
+			intent["status"], intent["amount_received"] = "succeeded", 2000
+			if r.PostForm.Get("final_capture") == "false" {
+				intent["status"], intent["amount_capturable"] = "requires_capture", 3000
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(intent)
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		ctx := context.Background()
+		_, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "usd", CaptureMethod: "manual", RequestMulticapture: true, ConfirmLater: true,
+		})
+		// Multicapture can only be requested with stripe-go v75 and later.
+		if h.Version() < "v75" {
+			if !errors.Is(err, gomultistripe.ErrUnsupported) || forms != nil {
+				t.Errorf("got %v, sent %v", err, forms)
+			}
+		} else if err != nil || forms[0].Get("payment_method_options[card][request_multicapture]") != "if_available" {
+			t.Errorf("created with %v: %v", forms, err)
+		}
+
+		forms = nil
+		pi, err := h.CapturePaymentIntent(ctx, "pi_fixture", 2000, false)
+		if h.Version() < "v75" {
+			if !errors.Is(err, gomultistripe.ErrUnsupported) || forms != nil {
+				t.Errorf("partial capture: got %v, sent %v", err, forms)
+			}
+		} else {
+			if err != nil || pi.Status != "requires_capture" || pi.AmountCapturable != 3000 {
+				t.Fatalf("captured %+v: %v", pi, err)
+			}
+			if forms[0].Get("amount_to_capture") != "2000" || forms[0].Get("final_capture") != "false" {
+				t.Errorf("captured with %v", forms[0])
+			}
+		}
+		forms = nil
+		if pi, err = h.CapturePaymentIntent(ctx, "pi_fixture", 0, true); err != nil || pi.Status != "succeeded" {
+			t.Fatalf("captured %+v: %v", pi, err)
+		}
+		if forms[0].Has("amount_to_capture") || (h.Version() >= "v75" && forms[0].Get("final_capture") != "true") {
+			t.Errorf("captured the rest with %v", forms[0])
+		}
+	})
+}
+
+func TestPaymentIntent_Level3(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		json.NewEncoder(w).Encode(map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 2600, "currency": "usd", "status": "requires_payment_method"})
+	})
+	level3 := &gomultistripe.Level3{
+		MerchantReference: "PO-1001", ShippingAmount: 500,
+		LineItems: []gomultistripe.Level3LineItem{{ProductCode: "WIDGET", ProductDescription: "Widget", UnitCost: 1000, Quantity: 2, TaxAmount: 200, DiscountAmount: 100}},
+	}
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		_, err := h.CreatePaymentIntent(context.Background(), &gomultistripe.PaymentIntent{Amount: 2600, Currency: "usd", Level3: level3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		form := forms[0]
+		if form.Get("level3[merchant_reference]") != "PO-1001" || form.Get("level3[shipping_amount]") != "500" ||
+			form.Get("level3[line_items][0][product_code]") != "WIDGET" || form.Get("level3[line_items][0][quantity]") != "2" ||
+			form.Get("level3[line_items][0][discount_amount]") != "100" {
+			t.Errorf("created with %v", form)
+		}
+		// Level 3 data that does not add up to the amount is rejected before it is sent.
+		forms = nil
+		if _, err := h.CreatePaymentIntent(context.Background(), &gomultistripe.PaymentIntent{Amount: 2500, Currency: "usd", Level3: level3}); err == nil || forms != nil {
+			t.Errorf("mismatched total: got %v, sent %v", err, forms)
+		}
+	})
+}
+
+func TestPaymentIntent_CouponAndLevel3(t *testing.T) {
+	var requests []string
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/coupons/half":
+			json.NewEncoder(w).Encode(map[string]any{"id": "half", "object": "coupon", "percent_off": 50, "duration": "once", "valid": true})
+		case "/v1/coupons/free":
+			json.NewEncoder(w).Encode(map[string]any{"id": "free", "object": "coupon", "percent_off": 100, "duration": "once", "valid": true})
+		default:
+			r.ParseForm()
+			form = r.PostForm
+			json.NewEncoder(w).Encode(map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 2600, "currency": "usd", "status": "requires_payment_method"})
+		}
+	})
+	level3 := &gomultistripe.Level3{
+		MerchantReference: "PO-1001", ShippingAmount: 500,
+		LineItems: []gomultistripe.Level3LineItem{{ProductCode: "WIDGET", ProductDescription: "Widget", UnitCost: 1000, Quantity: 2, TaxAmount: 200, DiscountAmount: 100}},
+	}
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		// The Level 3 data adds up to the discounted amount, which is the amount charged.
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 5200, Currency: "usd", CouponID: "half", Level3: level3}); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("amount") != "2600" || form.Get("level3[merchant_reference]") != "PO-1001" {
+			t.Errorf("created with %v", form)
+		}
+
+		// Local checks fail before the coupon is looked up.
+		requests = nil
+		invalid := &gomultistripe.Level3{LineItems: level3.LineItems}
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 5200, Currency: "usd", CouponID: "half", Level3: invalid}); err == nil || requests != nil {
+			t.Errorf("invalid Level 3 data: got %v, requested %v", err, requests)
+		}
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 5200, Currency: "usd", CouponID: "half", StatementDescriptorSuffix: "<ORDER>"}); err == nil || requests != nil {
+			t.Errorf("invalid suffix: got %v, requested %v", err, requests)
+		}
+
+		// A discount leaving nothing to charge is rejected rather than sent as amount=0.
+		requests = nil
+		_, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 5200, Currency: "usd", CouponID: "free"})
+		if !errors.Is(err, gomultistripe.ErrCouponInvalid) || !slices.Equal(requests, []string{"GET /v1/coupons/free"}) {
+			t.Errorf("full discount: got %v, requested %v", err, requests)
+		}
+	})
+}
+
+func TestPaymentIntent_StatementDescriptorSuffix(t *testing.T) {
+	var forms []url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pi_fixture", "object": "payment_intent", "amount": 1000, "currency": "usd", "status": "requires_payment_method",
+			"statement_descriptor_suffix": r.PostForm.Get("statement_descriptor_suffix"),
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		forms = nil
+		pi, err := h.CreatePaymentIntent(context.Background(), &gomultistripe.PaymentIntent{Amount: 1000, Currency: "usd", StatementDescriptorSuffix: "ORDER 42"})
+		if err != nil || pi.StatementDescriptorSuffix != "ORDER 42" || forms[0].Get("statement_descriptor_suffix") != "ORDER 42" {
+			t.Fatalf("got %+v, %v, sent %v", pi, err, forms)
+		}
+		forms = nil
+		_, err = h.CreatePaymentIntent(context.Background(), &gomultistripe.PaymentIntent{Amount: 1000, Currency: "usd", StatementDescriptorSuffix: "42"})
+		if !errors.Is(err, gomultistripe.ErrInvalidStatementDescriptor) || forms != nil {
+			t.Errorf("suffix without a letter: got %v, sent %v", err, forms)
+		}
+	})
+}
+
+func TestPaymentIntent_AutomaticPaymentMethods(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		intent := map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "usd", "status": "requires_payment_method"}
+		if enabled := form.Get("automatic_payment_methods[enabled]"); enabled != "" {
+			intent["automatic_payment_methods"] = map[string]any{
+				"enabled": enabled == "true", "allow_redirects": form.Get("automatic_payment_methods[allow_redirects]"),
+			}
+		}
+		json.NewEncoder(w).Encode(intent)
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		for _, apm := range []*gomultistripe.AutomaticPaymentMethods{
+			nil,
+			{Enabled: false},
+			{Enabled: true, AllowRedirects: "never"},
+		} {
+			pi, err := h.CreatePaymentIntent(context.Background(), &gomultistripe.PaymentIntent{
+				Amount: 5000, Currency: "usd", ConfirmLater: true, AutomaticPaymentMethods: apm,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, sent := form["automatic_payment_methods[enabled]"]
+			if sent != (apm != nil) || (apm != nil && *pi.AutomaticPaymentMethods != *apm) {
+				t.Errorf("for %+v sent %v and got %+v", apm, form, pi.AutomaticPaymentMethods)
+			}
+		}
+	})
+}
+
+func TestConfirm_ReturnURLAndMandateData(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		object, id := "payment_intent", "pi_fixture"
+		if strings.HasPrefix(r.URL.Path, "/v1/setup_intents") {
+			object, id = "setup_intent", "seti_fixture"
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": id, "object": object, "amount": 5000, "currency": "eur", "status": "requires_action",
+			"next_action": map[string]any{"type": "redirect_to_url", "redirect_to_url": map[string]any{"url": "https://hooks.stripe.com/redirect/" + id}},
+		})
+	})
+
+	online := gomultistripe.MandateData{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		pi, err := h.ConfirmPaymentIntent(ctx, "pi_fixture", "pm_ideal",
+			gomultistripe.WithReturnURL("https://example.com/return"), gomultistripe.WithMandateData(online), gomultistripe.WithOffSession())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("return_url") != "https://example.com/return" || form.Get("off_session") != "true" ||
+			form.Get("mandate_data[customer_acceptance][type]") != "online" ||
+			form.Get("mandate_data[customer_acceptance][online][ip_address]") != "203.0.113.7" {
+			t.Errorf("confirmed payment intent with %v", form)
+		}
+		if pi.RedirectURL != "https://hooks.stripe.com/redirect/pi_fixture" {
+			t.Errorf("payment intent redirect URL %q", pi.RedirectURL)
+		}
+
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", PaymentMethod: "pm_sepa", MandateData: &gomultistripe.MandateData{}, OffSession: true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("mandate_data[customer_acceptance][type]") != "offline" || form.Get("off_session") != "true" {
+			t.Errorf("created payment intent with %v", form)
+		}
+
+		si, err := h.CreateSetupIntent(ctx, &gomultistripe.SetupIntent{
+			CustomerID: "cus_fixture", PaymentMethod: "pm_sepa", Confirm: true, ReturnURL: "https://example.com/return", MandateData: &online,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("confirm") != "true" || form.Get("return_url") != "https://example.com/return" ||
+			form.Get("mandate_data[customer_acceptance][online][user_agent]") != "Mozilla/5.0" {
+			t.Errorf("created setup intent with %v", form)
+		}
+		if si, err = h.ConfirmSetupIntent(ctx, si.ID, "", gomultistripe.WithReturnURL("https://example.com/again")); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("return_url") != "https://example.com/again" || form.Has("payment_method") || form.Has("mandate_data[customer_acceptance][type]") {
+			t.Errorf("confirmed setup intent with %v", form)
+		}
+		if si.ID != "seti_fixture" || si.Status != "requires_action" || si.RedirectURL != "https://hooks.stripe.com/redirect/seti_fixture" {
+			t.Errorf("got setup intent %+v", si)
+		}
+	})
+}
+
+func TestPaymentIntent_PaymentMethodOptions(t *testing.T) {
+	var form url.Values
+	requests := 0
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "eur", "status": "requires_payment_method"})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		_, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", ConfirmLater: true,
+			PaymentMethodOptions: gomultistripe.PaymentMethodOptions{
+				"klarna": {"preferred_locale": "de-DE"},
+				"card":   {"installments[enabled]": "true"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("payment_method_options[klarna][preferred_locale]") != "de-DE" ||
+			form.Get("payment_method_options[card][installments][enabled]") != "true" {
+			t.Errorf("sent %v", form)
+		}
+
+		before := requests
+		_, err = h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", ConfirmLater: true,
+			PaymentMethodOptions: gomultistripe.PaymentMethodOptions{"klarna": {"colour": "pink"}},
+		})
+		if !errors.Is(err, gomultistripe.ErrUnsupported) || requests != before {
+			t.Errorf("unknown option: %v after %d requests", err, requests-before)
+		}
+		// Multicapture is only modelled from v75 on.
+		_, err = h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", ConfirmLater: true, CaptureMethod: "manual",
+			PaymentMethodOptions: gomultistripe.PaymentMethodOptions{"card": {"request_multicapture": "if_available"}},
+		})
+		if rejected := errors.Is(err, gomultistripe.ErrUnsupported); rejected != (h.Version() == "v74") {
+			t.Errorf("request_multicapture: %v", err)
+		}
+	})
+}
+
+func TestPaymentIntent_Installments(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/confirm") && form.Get("payment_method_options[card][installments][plan][count]") == "24" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+				"type": "invalid_request_error", "param": "payment_method_options[card][installments][plan]", "message": "Invalid plan",
+			}})
+			return
+		}
+		installments := map[string]any{
+			"enabled": true,
+			"available_plans": []any{
+				map[string]any{"type": "fixed_count", "count": 3, "interval": "month"},
+				map[string]any{"type": "fixed_count", "count": 6, "interval": "month"},
+			},
+		}
+		if form.Get("payment_method_options[card][installments][plan][count]") != "" {
+			installments["plan"] = map[string]any{"type": "fixed_count", "count": 3, "interval": "month"}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pi_fixture", "object": "payment_intent", "amount": 120000, "currency": "mxn", "status": "requires_confirmation",
+			"payment_method_options": map[string]any{"card": map[string]any{"installments": installments}},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "usd", PaymentMethod: "pm_card", InstallmentsEnabled: true,
+		}); !errors.Is(err, gomultistripe.ErrInstallmentsUnavailable) {
+			t.Errorf("installments in usd: %v", err)
+		}
+		pi, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 120000, Currency: "mxn", PaymentMethod: "pm_card", ConfirmLater: true, InstallmentsEnabled: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("payment_method_options[card][installments][enabled]") != "true" {
+			t.Errorf("created with %v", form)
+		}
+		plans, err := gomultistripe.InstallmentPlans(ctx, h, pi.ID)
+		if err != nil || len(plans) != 2 || plans[1] != (gomultistripe.InstallmentPlan{Type: "fixed_count", Count: 6, Interval: "month"}) {
+			t.Fatalf("plans %+v, %v", plans, err)
+		}
+		if pi, err = h.ConfirmPaymentIntent(ctx, pi.ID, "", gomultistripe.WithInstallmentPlan(plans[0])); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("payment_method_options[card][installments][plan][type]") != "fixed_count" ||
+			form.Get("payment_method_options[card][installments][plan][interval]") != "month" {
+			t.Errorf("confirmed with %v", form)
+		}
+		if pi.InstallmentPlan == nil || *pi.InstallmentPlan != plans[0] {
+			t.Errorf("selected plan %+v", pi.InstallmentPlan)
+		}
+		_, err = h.ConfirmPaymentIntent(ctx, pi.ID, "", gomultistripe.WithInstallmentPlan(gomultistripe.InstallmentPlan{Type: "fixed_count", Count: 24, Interval: "month"}))
+		if !errors.Is(err, gomultistripe.ErrInstallmentsUnavailable) {
+			t.Errorf("rejected plan: %v", err)
+		}
+	})
+}
+
+func TestPaymentIntent_BankTransfer(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pi_fixture", "object": "payment_intent", "amount": 2500, "currency": "eur", "status": "requires_action",
+			"customer": "cus_fixture",
+			"next_action": map[string]any{"type": "display_bank_transfer_instructions", "display_bank_transfer_instructions": map[string]any{
+				"type": "eu_bank_transfer", "amount_remaining": 2500, "currency": "eur", "reference": "REF123",
+				"hosted_instructions_url": "https://payments.stripe.com/instructions",
+				"financial_addresses": []any{map[string]any{"type": "iban", "iban": map[string]any{
+					"account_holder_name": "Fixture GmbH", "bic": "FIXTDEFF", "country": "DE", "iban": "DE00123456780000000000",
+				}}},
+			}},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		transfer := &gomultistripe.BankTransfer{Type: gomultistripe.BankTransferEU, Country: "DE"}
+
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 2500, Currency: "eur", BankTransfer: transfer}); !errors.Is(err, gomultistripe.ErrBankTransferRequiresCustomer) {
+			t.Errorf("without a customer: %v", err)
+		}
+		pi, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{Amount: 2500, Currency: "eur", CustomerID: "cus_fixture", BankTransfer: transfer})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("payment_method_types[0]") != "customer_balance" || form.Get("payment_method_data[type]") != "customer_balance" ||
+			form.Get("payment_method_options[customer_balance][funding_type]") != "bank_transfer" ||
+			form.Get("payment_method_options[customer_balance][bank_transfer][type]") != "eu_bank_transfer" ||
+			form.Get("payment_method_options[customer_balance][bank_transfer][eu_bank_transfer][country]") != "DE" || form.Has("payment_method") {
+			t.Errorf("created with %v", form)
+		}
+		fi := pi.FundingInstructions
+		if fi == nil || fi.Type != "eu_bank_transfer" || fi.AmountRemaining != 2500 || fi.Reference != "REF123" || len(fi.Addresses) != 1 {
+			t.Fatalf("funding instructions %+v", fi)
+		}
+		if addr := fi.Addresses[0]; addr.Type != "iban" || addr.IBAN != "DE00123456780000000000" || addr.BIC != "FIXTDEFF" || addr.AccountHolderName != "Fixture GmbH" {
+			t.Errorf("address %+v", addr)
+		}
+	})
+}
+
+func TestCreateCustomerSession_PaymentElementShowsSavedPaymentMethods(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"object": "customer_session", "client_secret": "cuss_secret_fixture", "customer": "cus_fixture",
+			"created": 1700000000, "expires_at": 1700001800,
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		form = nil
+		cs, err := h.CreateCustomerSession(context.Background(), "cus_fixture", []gomultistripe.CustomerSessionComponent{
+			gomultistripe.CustomerSessionPaymentElement,
+		})
+		if h.Version() < "v79" {
+			if !errors.Is(err, gomultistripe.ErrUnsupported) || form != nil {
+				t.Errorf("got %v, sent %v", err, form)
+			}
+			return
+		}
+		if err != nil || cs.ClientSecret != "cuss_secret_fixture" {
+			t.Fatalf("got %+v, %v", cs, err)
+		}
+		if form.Get("components[payment_element][enabled]") != "true" ||
+			form.Get("components[payment_element][features][payment_method_redisplay]") != "enabled" ||
+			form.Get("components[payment_element][features][payment_method_save]") != "enabled" {
+			t.Errorf("created session with %v", form)
+		}
+	})
+}
+
+func TestCreateCustomerSession_PricingTableAndBuyButton(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"object": "customer_session", "client_secret": "cuss_secret_fixture", "customer": "cus_fixture",
+			"created": 1700000000, "expires_at": 1700001800,
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		form = nil
+		cs, err := h.CreateCustomerSession(context.Background(), "cus_fixture", []gomultistripe.CustomerSessionComponent{
+			gomultistripe.CustomerSessionPricingTable, gomultistripe.CustomerSessionBuyButton,
+		})
+		if !supported(t, h, "CreateCustomerSession", err) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cs.CustomerID != "cus_fixture" || cs.ClientSecret != "cuss_secret_fixture" ||
+			cs.CreatedAt.Unix() != 1700000000 || cs.ExpiresAt.Unix() != 1700001800 {
+			t.Errorf("got %+v", cs)
+		}
+		if form.Get("customer") != "cus_fixture" || form.Get("components[pricing_table][enabled]") != "true" ||
+			form.Get("components[buy_button][enabled]") != "true" || form.Get("components[payment_element][enabled]") != "" {
+			t.Errorf("created session with %v", form)
+		}
+
+		form = nil
+		_, err = h.CreateCustomerSession(context.Background(), "cus_fixture", []gomultistripe.CustomerSessionComponent{"customer_sheet"})
+		if !errors.Is(err, gomultistripe.ErrUnsupported) || form != nil {
+			t.Errorf("unknown component: got %v, sent %v", err, form)
+		}
+	})
+}
+
+func TestPreparePaymentSheet(t *testing.T) {
+	var paths []string
+	var keyVersion string
+	var intent url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/customers":
+			json.NewEncoder(w).Encode(map[string]any{"id": "cus_new", "object": "customer"})
+		case "/v1/ephemeral_keys":
+			keyVersion = r.Header.Get("Stripe-Version")
+			json.NewEncoder(w).Encode(map[string]any{"id": "ephkey_fixture", "object": "ephemeral_key", "secret": "ek_test_fixture"})
+		case "/v1/payment_intents":
+			intent = r.PostForm
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "pi_fixture", "object": "payment_intent", "client_secret": "pi_fixture_secret", "status": "requires_payment_method",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		for _, customerID := range []string{"", "cus_fixture"} {
+			paths, keyVersion, intent = nil, "", nil
+			sheet, err := h.PreparePaymentSheet(context.Background(), customerID, 1500, "eur")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, wantPaths := customerID, []string{"/v1/ephemeral_keys", "/v1/payment_intents"}
+			if customerID == "" {
+				want, wantPaths = "cus_new", append([]string{"/v1/customers"}, wantPaths...)
+			}
+			if !slices.Equal(paths, wantPaths) || keyVersion != h.APIVersion() {
+				t.Errorf("sent %v, ephemeral key for API version %q", paths, keyVersion)
+			}
+			if intent.Get("customer") != want || intent.Get("amount") != "1500" || intent.Get("currency") != "eur" ||
+				intent.Get("automatic_payment_methods[enabled]") != "true" || intent.Get("confirm") != "" {
+				t.Errorf("created intent with %v", intent)
+			}
+			if sheet.CustomerID != want || sheet.EphemeralKeySecret != "ek_test_fixture" ||
+				sheet.PaymentIntentID != "pi_fixture" || sheet.PaymentIntentClientSecret != "pi_fixture_secret" {
+				t.Errorf("got %+v", sheet)
+			}
+		}
+	})
+}
+
+func TestAsAPIError(t *testing.T) {
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req_fixture")
+		w.WriteHeader(http.StatusPaymentRequired)
+		json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+			"type": "card_error", "code": "card_declined", "decline_code": "insufficient_funds",
+			"message": "Your card has insufficient funds.", "charge": "ch_fixture",
+			"payment_intent": map[string]any{"id": "pi_fixture", "object": "payment_intent"},
+		}})
+	})
+
+	catalog, err := gomultistripe.NewMessageCatalog("en", gomultistripe.DefaultMessages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		_, err := h.RetrievePaymentIntent(context.Background(), "pi_fixture")
+		apiErr, ok := h.AsAPIError(err)
+		if !ok {
+			t.Fatalf("%v is not an API error", err)
+		}
+		want := gomultistripe.APIError{
+			Type: "card_error", Code: "card_declined", DeclineCode: "insufficient_funds",
+			Message: "Your card has insufficient funds.", ChargeID: "ch_fixture", PaymentIntentID: "pi_fixture",
+			HTTPStatus: http.StatusPaymentRequired, RequestID: "req_fixture",
+		}
+		if *apiErr != want {
+			t.Errorf("API error %+v", apiErr)
+		}
+		if got := catalog.Message("en-GB", apiErr); got != gomultistripe.DefaultMessages["en"]["insufficient_funds"] {
+			t.Errorf("message %q", got)
+		}
+		if _, ok := h.AsAPIError(errors.New("network down")); ok {
+			t.Error("plain error reported as an API error")
+		}
+	})
+}
+
+func TestReceiptLocale_IsSetOnTheCustomer(t *testing.T) {
+	var requests []string
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "cus_fixture", "object": "customer", "name": form.Get("name"),
+			"preferred_locales": []string{form.Get("preferred_locales[0]"), form.Get("preferred_locales[1]")},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		requests = nil
+		ctx := context.Background()
+		_, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", CustomerID: "cus_fixture", ReceiptEmail: "jenny@example.com", ReceiptLocale: "fr-CA",
+		})
+		if !errors.Is(err, gomultistripe.ErrUnsupported) || len(requests) != 0 {
+			t.Errorf("receipt locale on the intent: %v after requests %v", err, requests)
+		}
+
+		locales := gomultistripe.PreferLocale([]string{"en", "fr-CA"}, "fr-ca")
+		cust, err := h.UpdateCustomer(ctx, "cus_fixture", &gomultistripe.Customer{Name: "Jenny", PreferredLocales: locales})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("preferred_locales[0]") != "fr-ca" || form.Get("preferred_locales[1]") != "en" || form.Has("preferred_locales[2]") {
+			t.Errorf("updated customer with %v", form)
+		}
+		if len(cust.PreferredLocales) != 2 || cust.PreferredLocales[0] != "fr-ca" {
+			t.Errorf("customer %+v", cust)
+		}
+	})
+}
+
+func TestPaymentIntent_ReceiptEmailAndLocale(t *testing.T) {
+	var form url.Values
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pi_fixture", "object": "payment_intent", "amount": 5000, "currency": "eur", "status": "succeeded",
+			"receipt_email": "jenny@example.com",
+			"customer":      map[string]any{"id": "cus_fixture", "object": "customer", "preferred_locales": []string{"fr-CA", "en"}},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		ctx := context.Background()
+		if _, err := h.CreatePaymentIntent(ctx, &gomultistripe.PaymentIntent{
+			Amount: 5000, Currency: "eur", CustomerID: "cus_fixture", ReceiptEmail: "jenny@example.com",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if form.Get("receipt_email") != "jenny@example.com" {
+			t.Errorf("created with %v", form)
+		}
+		pi, err := h.RetrievePaymentIntent(ctx, "pi_fixture")
+		if err != nil || pi.ReceiptEmail != "jenny@example.com" || pi.ReceiptLocale != "fr-CA" {
+			t.Errorf("got %+v, %v", pi, err)
+		}
+	})
+}
+
+// storedPaymentMethods starts a fake Stripe API listing methods as the payment methods of
+// any customer.
+func storedPaymentMethods(t *testing.T, methods ...map[string]any) *httptest.Server {
+	return fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payment_methods" || r.URL.Query().Get("customer") != "cus_fixture" {
+			http.NotFound(w, r)
+			return
+		}
+		data := make([]any, len(methods))
+		for i, pm := range methods {
+			data[i] = pm
+		}
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+	})
+}
+
+// paymentMethodsByID lists the payment methods of cus_fixture, keyed by ID.
+func paymentMethodsByID(t *testing.T, h gomultistripe.Handler) map[string]*gomultistripe.PaymentMethod {
+	t.Helper()
+	methods, err := h.GetPaymentMethods(context.Background(), "cus_fixture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := make(map[string]*gomultistripe.PaymentMethod, len(methods))
+	for _, pm := range methods {
+		byID[pm.ID] = pm
+	}
+	return byID
+}
+
+func TestGetPaymentMethods_LinkWalletAndPayPal(t *testing.T) {
+	srv := storedPaymentMethods(t,
+		map[string]any{
+			"id": "pm_apple_pay", "object": "payment_method", "type": "card", "customer": "cus_fixture", "created": 1700000000,
+			"card": map[string]any{"brand": "visa", "last4": "4242", "exp_month": 12, "exp_year": 2030, "wallet": map[string]any{"type": "apple_pay"}},
+		},
+		map[string]any{
+			"id": "pm_link", "object": "payment_method", "type": "link", "customer": "cus_fixture", "created": 1700000001,
+			"link": map[string]any{"email": "jane@example.com"},
+		},
+		map[string]any{
+			"id": "pm_paypal", "object": "payment_method", "type": "paypal", "customer": "cus_fixture", "created": 1700000002,
+			"paypal": map[string]any{"payer_email": "john@example.com"},
+		},
+	)
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		byID := paymentMethodsByID(t, h)
+		if pm := byID["pm_apple_pay"]; pm == nil || pm.Type != "card" || pm.Wallet != "apple_pay" || pm.Brand != "visa" || pm.Last4 != "4242" || pm.Email != "" {
+			t.Errorf("apple pay: got %+v", pm)
+		}
+		if pm := byID["pm_link"]; pm == nil || pm.Type != "link" || pm.Email != "jane@example.com" || pm.Last4 != "" || pm.CustomerID != "cus_fixture" {
+			t.Errorf("link: got %+v", pm)
+		}
+		if pm := byID["pm_paypal"]; pm == nil || pm.Type != "paypal" || pm.Email != "john@example.com" || pm.Metadata == nil {
+			t.Errorf("paypal: got %+v", pm)
+		}
+	})
+}
+
+func TestFindDuplicatePaymentMethods_ByCardFingerprint(t *testing.T) {
+	card := func(id, fingerprint string) map[string]any {
+		return map[string]any{
+			"id": id, "object": "payment_method", "type": "card", "customer": "cus_fixture", "created": 1700000000,
+			"card": map[string]any{"brand": "visa", "last4": "4242", "exp_month": 12, "exp_year": 2030, "fingerprint": fingerprint},
+		}
+	}
+	srv := storedPaymentMethods(t, card("pm_first", "fp_visa"), card("pm_again", "fp_visa"), card("pm_other", "fp_mastercard"),
+		map[string]any{"id": "pm_link", "object": "payment_method", "type": "link", "customer": "cus_fixture", "link": map[string]any{"email": "jane@example.com"}})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		byID := paymentMethodsByID(t, h)
+		if byID["pm_first"].Fingerprint != "fp_visa" || byID["pm_link"].Fingerprint != "" {
+			t.Fatalf("fingerprints %q and %q", byID["pm_first"].Fingerprint, byID["pm_link"].Fingerprint)
+		}
+		methods := []*gomultistripe.PaymentMethod{byID["pm_first"], byID["pm_again"], byID["pm_other"], byID["pm_link"]}
+		dups := gomultistripe.FindDuplicatePaymentMethods(methods, byID["pm_again"])
+		if len(dups) != 1 || dups[0].ID != "pm_first" {
+			t.Errorf("duplicates of pm_again: %+v", dups)
+		}
+		if dups := gomultistripe.FindDuplicatePaymentMethods(methods, byID["pm_other"]); len(dups) != 0 {
+			t.Errorf("duplicates of pm_other: %+v", dups)
+		}
+		if dups := gomultistripe.FindDuplicatePaymentMethods(methods, byID["pm_link"]); len(dups) != 0 {
+			t.Errorf("duplicates of a method without a fingerprint: %+v", dups)
+		}
+	})
+}
+
+func TestGetPaymentMethods_CardFundingCountryAndIssuer(t *testing.T) {
+	srv := storedPaymentMethods(t, map[string]any{
+		"id": "pm_fixture", "object": "payment_method", "type": "card", "customer": "cus_fixture", "created": 1700000000,
+		"card": map[string]any{
+			"brand": "mastercard", "last4": "4444", "exp_month": 1, "exp_year": 2031,
+			"funding": "prepaid", "country": "DE", "issuer": "Fixture Bank",
+		},
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		pm := paymentMethodsByID(t, h)["pm_fixture"]
+		if pm == nil || pm.Funding != "prepaid" || pm.Country != "DE" || pm.Issuer != "Fixture Bank" {
+			t.Errorf("got %+v", pm)
+		}
+	})
+}
+
+func TestRetrievePaymentMethod(t *testing.T) {
+	var path string
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": "pm_fixture", "object": "payment_method", "type": "card", "created": 1700000000,
+			"card": map[string]any{"brand": "visa", "last4": "4242", "country": "DE"},
+		})
+	})
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		pm, err := h.RetrievePaymentMethod(context.Background(), "pm_fixture")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != "/v1/payment_methods/pm_fixture" || pm.ID != "pm_fixture" || pm.Country != "DE" || pm.CustomerID != "" {
+			t.Errorf("retrieved %s: %+v", path, pm)
+		}
+	})
+}
@@ -0,0 +1,336 @@
+package conformance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+func TestHandleWebhook_RefundEvents(t *testing.T) {
+	refund := map[string]any{
+		"id": "re_fixture", "object": "refund", "amount": 400, "currency": "usd", "reason": "requested_by_customer",
+		"status": "succeeded", "charge": "ch_fixture", "payment_intent": "pi_fixture", "created": 1700000000,
+		"metadata": map[string]any{"order": "42"},
+	}
+	charge := map[string]any{
+		"id": "ch_fixture", "object": "charge", "amount": 1000, "amount_refunded": 400, "currency": "usd", "refunded": false,
+		"payment_intent": "pi_fixture", "created": 1690000000, "metadata": map[string]any{"order": "42"},
+		"refunds": map[string]any{"object": "list", "data": []any{refund}},
+	}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		for _, tc := range []struct {
+			eventType string
+			object    map[string]any
+		}{{"refund.created", refund}, {"charge.refunded", charge}} {
+			evt, err := deliver(h, event(tc.eventType, tc.object))
+			if err != nil {
+				t.Fatalf("%s: %v", tc.eventType, err)
+			}
+			if evt.RefundID != "re_fixture" || evt.RefundAmount != 400 || evt.RefundStatus != "succeeded" ||
+				evt.RefundReason != "requested_by_customer" || evt.ChargeID != "ch_fixture" ||
+				evt.PaymentIntentID != "pi_fixture" || evt.Currency != "usd" || evt.Metadata["order"] != "42" {
+				t.Errorf("%s: got %+v", tc.eventType, evt)
+			}
+		}
+	})
+}
+
+func TestHandleWebhook_UsesTheHandlersOwnSecret(t *testing.T) {
+	// The environment only supplies a secret to handlers without one.
+	t.Setenv("STRIPE_WEBHOOK_SECRET", "whsec_env")
+	handlersA, handlersB, handlersEnv := versions(), versions(), versions()
+	for i := range handlersA {
+		t.Run(handlersA[i].Version(), func(t *testing.T) {
+			handlersA[i].SetWebhookSecret("whsec_a")
+			handlersB[i].SetWebhookSecret("whsec_b")
+			payload, _ := json.Marshal(map[string]any{
+				"id": "evt_secret", "object": "event", "api_version": handlersA[i].APIVersion(), "created": 1700000001,
+				"type": "refund.created", "data": map[string]any{"object": map[string]any{"id": "re_secret", "object": "refund"}},
+			})
+			for _, c := range []struct {
+				h      gomultistripe.Handler
+				secret string
+				ok     bool
+			}{
+				{handlersA[i], "whsec_a", true}, {handlersA[i], "whsec_b", false}, {handlersA[i], "whsec_env", false},
+				{handlersB[i], "whsec_b", true}, {handlersB[i], "whsec_a", false},
+				{handlersEnv[i], "whsec_env", true}, {handlersEnv[i], "whsec_a", false},
+			} {
+				_, err := c.h.HandleWebhook(payload, gomultistripe.SignPayload(payload, c.secret, time.Now()))
+				if (err == nil) != c.ok {
+					t.Errorf("signed with %s: got %v, want accepted %t", c.secret, err, c.ok)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleWebhook_CashBalanceEvents(t *testing.T) {
+	balance := map[string]any{"object": "cash_balance", "customer": "cus_fixture", "available": map[string]any{"eur": 2500}}
+	txn := map[string]any{
+		"id": "ccsbtxn_fixture", "object": "customer_cash_balance_transaction", "type": "applied_to_payment", "customer": "cus_fixture",
+		"net_amount": -2500, "ending_balance": 0, "currency": "eur", "created": 1700000000,
+		"applied_to_payment": map[string]any{"payment_intent": "pi_fixture"},
+	}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		evt, err := deliver(h, event("cash_balance.funds_available", balance))
+		if err != nil || evt.CustomerID != "cus_fixture" || evt.CashBalance["eur"] != 2500 {
+			t.Errorf("funds available: got %+v, %v", evt, err)
+		}
+		evt, err = deliver(h, event("customer_cash_balance_transaction.created", txn))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.CashBalanceTransactionID != "ccsbtxn_fixture" || evt.CashBalanceTransactionType != "applied_to_payment" ||
+			evt.CashBalanceNetAmount != -2500 || evt.CashBalanceEndingBalance != 0 || evt.Currency != "eur" ||
+			evt.CustomerID != "cus_fixture" || evt.PaymentIntentID != "pi_fixture" {
+			t.Errorf("transaction: got %+v", evt)
+		}
+	})
+}
+
+func TestHandleWebhook_CarriesTheEventEnvelope(t *testing.T) {
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		e := event("refund.created", map[string]any{
+			"id": "re_fixture", "object": "refund", "amount": 400, "currency": "usd", "destination_details": map[string]any{"type": "card"},
+		})
+		e["id"], e["account"] = "evt_envelope", "acct_fixture"
+		evt, err := deliver(h, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.EventID != "evt_envelope" || evt.EventCreatedAt.Unix() != 1700000001 || evt.AccountID != "acct_fixture" || evt.APIVersion != h.APIVersion() {
+			t.Errorf("got %+v", evt)
+		}
+		// Fields the normalized event does not map are read from the payload.
+		var raw struct {
+			Data struct {
+				Object struct {
+					DestinationDetails struct{ Type string } `json:"destination_details"`
+				}
+			}
+		}
+		if err := json.Unmarshal(evt.Payload, &raw); err != nil || raw.Data.Object.DestinationDetails.Type != "card" {
+			t.Errorf("payload %s: %v", evt.Payload, err)
+		}
+	})
+}
+
+func TestHandleWebhook_PaymentMethodAndChargeEvents(t *testing.T) {
+	card := map[string]any{"brand": "visa", "last4": "4242", "exp_month": 12, "exp_year": 2030}
+	pm := map[string]any{
+		"id": "pm_fixture", "object": "payment_method", "type": "card", "customer": "cus_fixture", "created": 1700000000,
+		"card": card, "metadata": map[string]any{},
+	}
+	detached := map[string]any{"id": "pm_fixture", "object": "payment_method", "type": "card", "customer": nil, "created": 1700000000, "card": card}
+	charge := map[string]any{
+		"id": "ch_fixture", "object": "charge", "amount": 1000, "currency": "usd", "status": "failed", "customer": "cus_fixture",
+		"payment_intent": "pi_fixture", "payment_method": "pm_fixture", "created": 1700000000,
+		"failure_code": "card_declined", "failure_message": "Your card was declined.",
+		"payment_method_details": map[string]any{"type": "card", "card": card},
+	}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		for _, tc := range []struct {
+			eventType string
+			object    map[string]any
+			previous  map[string]any
+		}{
+			{"payment_method.attached", pm, nil},
+			{"payment_method.automatically_updated", pm, nil},
+			{"payment_method.detached", detached, map[string]any{"customer": "cus_fixture"}},
+			{"charge.failed", charge, nil},
+		} {
+			e := event(tc.eventType, tc.object)
+			if tc.previous != nil {
+				e["data"].(map[string]any)["previous_attributes"] = tc.previous
+			}
+			evt, err := deliver(h, e)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.eventType, err)
+			}
+			if evt.PaymentMethodID != "pm_fixture" || evt.CustomerID != "cus_fixture" || evt.CardBrand != "visa" ||
+				evt.CardLast4 != "4242" || evt.CardExpMonth != 12 || evt.CardExpYear != 2030 {
+				t.Errorf("%s: got %+v", tc.eventType, evt)
+			}
+			if tc.eventType == "charge.failed" && (evt.ChargeID != "ch_fixture" || evt.PaymentIntentID != "pi_fixture" ||
+				evt.Amount != 1000 || evt.Status != "failed" || evt.LastPaymentErrorCode != "card_declined" || evt.LastPaymentErrorChargeID != "ch_fixture") {
+				t.Errorf("charge.failed: got %+v", evt)
+			}
+		}
+	})
+}
+
+func TestHandleWebhook_AccountUpdated(t *testing.T) {
+	account := map[string]any{
+		"id": "acct_fixture", "object": "account", "type": "express", "charges_enabled": true, "payouts_enabled": false,
+		"created": 1700000000, "requirements": map[string]any{
+			"currently_due":   []any{"external_account", "individual.verification.document"},
+			"past_due":        []any{"external_account"},
+			"disabled_reason": "requirements.past_due",
+		},
+	}
+	previous := map[string]any{"requirements": map[string]any{"currently_due": []any{"external_account", "tos_acceptance.date"}}}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		e := event("account.updated", account)
+		e["account"] = "acct_fixture"
+		e["data"].(map[string]any)["previous_attributes"] = previous
+		evt, err := deliver(h, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.Account == nil || evt.Account.ID != "acct_fixture" || !evt.Account.ChargesEnabled || evt.Account.PayoutsEnabled ||
+			len(evt.Account.RequirementsPastDue) != 1 || evt.Account.RequirementsDisabledReason != "requirements.past_due" {
+			t.Fatalf("account %+v", evt.Account)
+		}
+		if fmt.Sprint(evt.RequirementsAdded, evt.RequirementsResolved, evt.PastDueAdded) != "[individual.verification.document] [tos_acceptance.date] []" {
+			t.Errorf("added %v, resolved %v, past due added %v", evt.RequirementsAdded, evt.RequirementsResolved, evt.PastDueAdded)
+		}
+	})
+}
+
+func TestHandleWebhook_PaymentIntentPaymentFailed(t *testing.T) {
+	intent := map[string]any{
+		"id": "pi_fixture", "object": "payment_intent", "amount": 1500, "currency": "usd", "status": "requires_payment_method",
+		"customer": "cus_fixture", "created": 1700000000,
+		"last_payment_error": map[string]any{
+			"type": "card_error", "code": "card_declined", "decline_code": "insufficient_funds",
+			"message": "Your card has insufficient funds.", "charge": "ch_fixture",
+			"payment_method": map[string]any{"id": "pm_fixture", "object": "payment_method", "type": "card"},
+		},
+	}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		evt, err := deliver(h, event("payment_intent.payment_failed", intent))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if evt.LastPaymentErrorMsg != "Your card has insufficient funds." || evt.LastPaymentErrorCode != "card_declined" ||
+			evt.LastPaymentErrorDeclineCode != "insufficient_funds" || evt.LastPaymentErrorChargeID != "ch_fixture" ||
+			evt.LastPaymentErrorPaymentMethodID != "pm_fixture" {
+			t.Errorf("got %+v", evt)
+		}
+		if e := evt.PaymentError(); e == nil || e.Message != evt.LastPaymentErrorMsg || e.PaymentIntentID != "pi_fixture" {
+			t.Errorf("payment error %+v", e)
+		}
+	})
+}
+
+func TestHandleWebhook_ReportsSchemaDifferences(t *testing.T) {
+	refund := map[string]any{
+		"id": "re_fixture", "object": "refund", "amount": 400, "currency": "usd", "status": "succeeded",
+		"charge": "ch_fixture", "created": 1700000000, "metadata": map[string]any{},
+	}
+	drifted := map[string]any{
+		"id": "re_fixture", "object": "refund", "amount": 400, "currency": "usd", "status": "succeeded",
+		"created": 1700000000, "metadata": map[string]any{}, "x_future_field": true,
+	}
+	eachVersion(t, nil, func(t *testing.T, h gomultistripe.Handler) {
+		var reports []gomultistripe.SchemaReport
+		h.SetSchemaReporter(func(r gomultistripe.SchemaReport) { reports = append(reports, r) })
+		if _, err := deliver(h, event("refund.created", refund)); err != nil || len(reports) != 0 {
+			t.Fatalf("matching object: reports %+v, %v", reports, err)
+		}
+		// A drifted object is still parsed; strict validation only reports it.
+		evt, err := deliver(h, event("refund.updated", drifted))
+		if err != nil || evt.RefundID != "re_fixture" {
+			t.Fatalf("drifted object: got %+v, %v", evt, err)
+		}
+		if len(reports) != 1 {
+			t.Fatalf("reports %+v", reports)
+		}
+		r := reports[0]
+		if r.EventID != "evt_refund.updated" || r.EventType != "refund.updated" || r.Version != h.Version() || r.APIVersion != h.APIVersion() ||
+			r.Object != "refund" || fmt.Sprint(r.Unknown, r.Missing) != "[x_future_field] [charge]" {
+			t.Errorf("report %+v", r)
+		}
+	})
+}
+
+func TestVersionRouter_PicksTheClosestHandler(t *testing.T) {
+	handlers := versions()
+	for _, h := range handlers {
+		h.SetWebhookSecret(webhookSecret)
+	}
+	r := gomultistripe.NewVersionRouter(handlers...)
+	for _, h := range handlers {
+		if got := r.HandlerFor(h.APIVersion()); got != h {
+			t.Errorf("%s: routed to %s", h.APIVersion(), got.Version())
+		}
+	}
+	for apiVersion, want := range map[string]string{
+		"2025-01-27.acacia": "v80", // newest of the same release train
+		"2025-06-30.basil":  "v82",
+		"2025-09-30.clover": "v82", // unknown train: newest not after the event
+		"2024-01-01":        "v76",
+		"2020-08-27":        "v74", // older than every handler: the oldest
+	} {
+		if got := r.HandlerFor(apiVersion); got.Version() != want {
+			t.Errorf("%s: routed to %s, want %s", apiVersion, got.Version(), want)
+		}
+	}
+
+	e := event("refund.created", map[string]any{"id": "re_fixture", "object": "refund", "amount": 400, "currency": "usd"})
+	e["api_version"] = "2025-01-27.acacia"
+	payload, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evt, err := r.HandleWebhook(payload, gomultistripe.SignPayload(payload, webhookSecret, time.Now()))
+	if err != nil || evt.RefundID != "re_fixture" || evt.APIVersion != "2025-01-27.acacia" {
+		t.Errorf("got %+v, %v", evt, err)
+	}
+	if _, err := r.HandleWebhook(payload, gomultistripe.SignPayload(payload, "whsec_other", time.Now())); err == nil {
+		t.Error("routed an event with an invalid signature")
+	}
+}
+
+func TestWebhookHTTPHandler_StatusByFailure(t *testing.T) {
+	// The remaining lines of the invoice cannot be fetched.
+	srv := fakeStripe(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"type": "invalid_request_error", "message": "No such invoice"}})
+	})
+	gomultistripe.SetFetchAllInvoiceLines(true)
+	t.Cleanup(func() { gomultistripe.SetFetchAllInvoiceLines(false) })
+
+	eachVersion(t, srv, func(t *testing.T, h gomultistripe.Handler) {
+		endpoint := gomultistripe.WebhookHTTPHandler(h, func(ctx context.Context, evt *gomultistripe.CallbackEvent) error { return nil })
+		post := func(payload []byte, secret string) int {
+			req := httptest.NewRequest(http.MethodPost, "/stripe/webhook", bytes.NewReader(payload))
+			req.Header.Set("Stripe-Signature", gomultistripe.SignPayload(payload, secret, time.Now()))
+			rec := httptest.NewRecorder()
+			endpoint.ServeHTTP(rec, req)
+			return rec.Code
+		}
+		marshal := func(evt map[string]any) []byte {
+			evt["api_version"] = h.APIVersion()
+			payload, _ := json.Marshal(evt)
+			return payload
+		}
+		refund := marshal(event("refund.created", map[string]any{"id": "re_fixture", "object": "refund"}))
+		for _, c := range []struct {
+			name    string
+			payload []byte
+			secret  string
+			want    int
+		}{
+			{"delivered", refund, webhookSecret, http.StatusOK},
+			{"badly signed", refund, "whsec_other", http.StatusBadRequest},
+			{"not JSON", []byte("not json"), webhookSecret, http.StatusBadRequest},
+			{"malformed object", marshal(event("refund.created", map[string]any{"id": "re_fixture", "object": "refund", "amount": "ten"})), webhookSecret, http.StatusBadRequest},
+			{"invoice lines unavailable", marshal(event("invoice.created", map[string]any{
+				"id": "in_fixture", "object": "invoice", "lines": map[string]any{"object": "list", "data": []any{}, "has_more": true},
+			})), webhookSecret, http.StatusInternalServerError},
+		} {
+			if got := post(c.payload, c.secret); got != c.want {
+				t.Errorf("%s: status %d, want %d", c.name, got, c.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package gomultistripe
+
+import "context"
+
+// contextKey is the type of the context keys defined by this package, so they cannot
+// collide with keys from other packages.
+type contextKey int
+
+const (
+	accountKey contextKey = iota
+	idempotencyKeyKey
+	traceIDKey
+	apiKeyKey
+)
+
+// ContextWithAccount returns a context that makes every handler call act on the given
+// connected account, by sending it as the Stripe-Account header.
+func ContextWithAccount(ctx context.Context, accountID string) context.Context {
+	return context.WithValue(ctx, accountKey, accountID)
+}
+
+// AccountFromContext returns the connected account set by ContextWithAccount.
+func AccountFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(accountKey).(string)
+	return v, ok && v != ""
+}
+
+// ContextWithIdempotencyKey returns a context carrying a caller-chosen idempotency key, for
+// example one derived from an order ID, so that retrying a handler call after a crash or
+// timeout cannot repeat it on Stripe's side.
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by ContextWithIdempotencyKey.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(idempotencyKeyKey).(string)
+	return v, ok && v != ""
+}
+
+// ContextWithTraceID returns a context carrying a trace or correlation ID. Handlers add it
+// to their log records and audit records.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceIDFromContext returns the ID set by ContextWithTraceID.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(traceIDKey).(string)
+	return v, ok && v != ""
+}
+
+// ContextWithAPIKey returns a context that makes every handler call authenticate with the
+// given secret key instead of the handler's, so one handler can serve many tenants.
+func ContextWithAPIKey(ctx context.Context, secretKey string) context.Context {
+	return context.WithValue(ctx, apiKeyKey, secretKey)
+}
+
+// APIKeyFromContext returns the key set by ContextWithAPIKey.
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(apiKeyKey).(string)
+	return v, ok && v != ""
+}
+
+// RequestIdempotencyKey returns the Idempotency-Key for one request of the given operation.
+// Without a key from ContextWithIdempotencyKey it is random. With one, it is the caller's
+// key followed by the operation, so a handler method that sends several requests (e.g.
+// creating a customer and then a payment intent) uses a distinct, repeatable key for each.
+// With TraceToIdempotencyKey enabled, the active trace ID is appended to random keys only;
+// the caller's keys stay the same across traces, so retries are still recognised.
+func RequestIdempotencyKey(ctx context.Context, operation string) string {
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		return key + ":" + operation
+	}
+	return NewIdempotencyKey() + traceKeySuffix(ctx)
+}
@@ -0,0 +1,21 @@
+package gomultistripe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	if a, b := RequestIdempotencyKey(ctx, "CreateCustomer"), RequestIdempotencyKey(ctx, "CreateCustomer"); a == b {
+		t.Fatalf("keys without a context key should be random, got %q twice", a)
+	}
+
+	ctx = ContextWithIdempotencyKey(ctx, "order-42")
+	if got := RequestIdempotencyKey(ctx, "CreatePaymentIntent"); got != "order-42:CreatePaymentIntent" {
+		t.Fatalf("got %q", got)
+	}
+	if RequestIdempotencyKey(ctx, "CreateCustomer") == RequestIdempotencyKey(ctx, "CreatePaymentIntent") {
+		t.Fatal("operations sharing a context key must get distinct request keys")
+	}
+}
@@ -0,0 +1,156 @@
+package gomultistripe
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCouponInvalid is returned when a coupon can no longer be redeemed, or does not apply
+	// to the currency of the payment.
+	ErrCouponInvalid = errors.New("coupon cannot be applied")
+	// ErrPromotionCodeInvalid is matched by the errors returned for codes that do not exist,
+	// are inactive or expired, or whose restrictions the redemption does not meet.
+	ErrPromotionCodeInvalid = errors.New("promotion code cannot be applied")
+)
+
+// Metadata keys handlers set on payment intents created with a coupon or promotion code.
+const (
+	MetadataCoupon               = "coupon"
+	MetadataPromotionCode        = "promotion_code"
+	MetadataAmountBeforeDiscount = "amount_before_discount"
+)
+
+// CouponDuration is how long a coupon's discount applies to a subscription.
+type CouponDuration string
+
+const (
+	CouponOnce      CouponDuration = "once"
+	CouponRepeating CouponDuration = "repeating"
+	CouponForever   CouponDuration = "forever"
+)
+
+// Coupon is a version-agnostic coupon: a percentage or fixed amount off.
+type Coupon struct {
+	ID   string
+	Name string
+	// PercentOff or AmountOff is set. AmountOff is in the smallest unit of Currency.
+	PercentOff float64
+	AmountOff  int64
+	Currency   string
+	Duration   CouponDuration
+	// DurationInMonths is set for CouponRepeating.
+	DurationInMonths int64
+	// MaxRedemptions is 0 for no limit.
+	MaxRedemptions int64
+	TimesRedeemed  int64
+	// RedeemBy is the unix time after which the coupon cannot be redeemed, or 0.
+	RedeemBy int64
+	// Valid reports whether the coupon can still be redeemed.
+	Valid     bool
+	Metadata  map[string]string
+	CreatedAt time.Time
+}
+
+// CouponParams describes a coupon to create. Set either PercentOff or AmountOff and
+// Currency.
+type CouponParams struct {
+	// ID is the code customers enter, for coupons applied by ID. Empty lets Stripe generate
+	// one; use promotion codes for customer-facing codes.
+	ID               string
+	Name             string
+	PercentOff       float64
+	AmountOff        int64
+	Currency         string
+	Duration         CouponDuration
+	DurationInMonths int64
+	MaxRedemptions   int64
+	RedeemBy         time.Time
+	Metadata         map[string]string
+}
+
+// PromotionCode is a version-agnostic promotion code: a customer-facing code for a coupon.
+type PromotionCode struct {
+	ID     string
+	Code   string
+	Coupon *Coupon
+	Active bool
+	// CustomerID restricts redemption to one customer when set.
+	CustomerID string
+	// ExpiresAt is the unix time the code expires, or 0.
+	ExpiresAt      int64
+	MaxRedemptions int64
+	TimesRedeemed  int64
+	// FirstTimeTransaction restricts the code to customers who have never paid.
+	FirstTimeTransaction bool
+	// MinimumAmount is the smallest order amount, in MinimumAmountCurrency, the code applies to.
+	MinimumAmount         int64
+	MinimumAmountCurrency string
+}
+
+// Check reports, as an error matching ErrPromotionCodeInvalid, why the code cannot be
+// redeemed by the customer at now. An empty customerID skips the customer restriction.
+// FirstTimeTransaction and MinimumAmount depend on the purchase and are not checked.
+func (p *PromotionCode) Check(customerID string, now time.Time) error {
+	switch {
+	case !p.Active:
+		return fmt.Errorf("%w: %s is inactive", ErrPromotionCodeInvalid, p.Code)
+	case p.ExpiresAt != 0 && now.Unix() >= p.ExpiresAt:
+		return fmt.Errorf("%w: %s has expired", ErrPromotionCodeInvalid, p.Code)
+	case p.MaxRedemptions != 0 && p.TimesRedeemed >= p.MaxRedemptions:
+		return fmt.Errorf("%w: %s has been fully redeemed", ErrPromotionCodeInvalid, p.Code)
+	case p.CustomerID != "" && customerID != "" && p.CustomerID != customerID:
+		return fmt.Errorf("%w: %s is restricted to another customer", ErrPromotionCodeInvalid, p.Code)
+	case p.Coupon == nil || !p.Coupon.Valid:
+		return fmt.Errorf("%w: the coupon of %s is no longer valid", ErrPromotionCodeInvalid, p.Code)
+	}
+	return nil
+}
+
+// ApplyCoupon returns amount, in currency, after the coupon's discount, never below 0.
+// It returns ErrCouponInvalid for invalid coupons and for amount-off coupons in another
+// currency.
+func ApplyCoupon(amount int64, currency string, c *Coupon) (int64, error) {
+	if !c.Valid {
+		return 0, fmt.Errorf("%w: %s is no longer valid", ErrCouponInvalid, c.ID)
+	}
+	discount := c.AmountOff
+	if c.PercentOff != 0 {
+		discount = int64(math.Round(float64(amount) * c.PercentOff / 100))
+	} else if !strings.EqualFold(c.Currency, currency) {
+		return 0, fmt.Errorf("%w: %s is for %s, not %s", ErrCouponInvalid, c.ID, c.Currency, currency)
+	}
+	return max(amount-discount, 0), nil
+}
+
+// DiscountPaymentIntent applies a coupon, or the coupon of a promotion code, to a payment
+// intent being created. It returns the amount to charge and the metadata recording the
+// discount, or ErrCouponInvalid if the discount leaves nothing to charge. Payment intents have no native discounts, so this is the whole redemption:
+// Stripe does not count it against the coupon's or code's redemption limits. Handlers use
+// it for PaymentIntent.CouponID and PaymentIntent.PromotionCode.
+func DiscountPaymentIntent(params *PaymentIntent, coupon *Coupon, promo *PromotionCode) (int64, map[string]string, error) {
+	md := map[string]string{MetadataAmountBeforeDiscount: strconv.FormatInt(params.Amount, 10)}
+	if promo != nil {
+		if err := promo.Check(params.CustomerID, time.Now()); err != nil {
+			return 0, nil, err
+		}
+		if promo.MinimumAmount != 0 && (!strings.EqualFold(promo.MinimumAmountCurrency, params.Currency) || params.Amount < promo.MinimumAmount) {
+			return 0, nil, fmt.Errorf("%w: %s requires a minimum of %d %s", ErrPromotionCodeInvalid, promo.Code, promo.MinimumAmount, promo.MinimumAmountCurrency)
+		}
+		coupon = promo.Coupon
+		md[MetadataPromotionCode] = promo.Code
+	}
+	amount, err := ApplyCoupon(params.Amount, params.Currency, coupon)
+	if err != nil {
+		return 0, nil, err
+	}
+	if amount <= 0 {
+		return 0, nil, fmt.Errorf("%w: %s discounts the whole amount of %d", ErrCouponInvalid, coupon.ID, params.Amount)
+	}
+	md[MetadataCoupon] = coupon.ID
+	return amount, md, nil
+}
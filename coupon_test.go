@@ -0,0 +1,75 @@
+package gomultistripe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplyCoupon(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		coupon Coupon
+		want   int64
+		err    error
+	}{
+		{"percent", Coupon{PercentOff: 12.5, Valid: true}, 875, nil},
+		{"amount", Coupon{AmountOff: 300, Currency: "usd", Valid: true}, 700, nil},
+		{"more than the amount", Coupon{AmountOff: 5000, Currency: "usd", Valid: true}, 0, nil},
+		{"other currency", Coupon{AmountOff: 300, Currency: "eur", Valid: true}, 0, ErrCouponInvalid},
+		{"invalid", Coupon{PercentOff: 10}, 0, ErrCouponInvalid},
+	} {
+		got, err := ApplyCoupon(1000, "USD", &tc.coupon)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %d, %v, want %d, %v", tc.name, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestDiscountPaymentIntent_ChecksPromotionCode(t *testing.T) {
+	now := time.Now()
+	promo := &PromotionCode{
+		Code:                  "SPRING",
+		Active:                true,
+		Coupon:                &Coupon{ID: "spring25", PercentOff: 25, Valid: true},
+		ExpiresAt:             now.Add(time.Hour).Unix(),
+		MinimumAmount:         1000,
+		MinimumAmountCurrency: "usd",
+	}
+	params := &PaymentIntent{Amount: 2000, Currency: "usd", CustomerID: "cus_1"}
+	amount, md, err := DiscountPaymentIntent(params, nil, promo)
+	if err != nil || amount != 1500 {
+		t.Fatalf("got %d, %v", amount, err)
+	}
+	if md[MetadataCoupon] != "spring25" || md[MetadataPromotionCode] != "SPRING" || md[MetadataAmountBeforeDiscount] != "2000" {
+		t.Errorf("got metadata %v", md)
+	}
+
+	for name, change := range map[string]func(p *PromotionCode, pi *PaymentIntent){
+		"below minimum":    func(p *PromotionCode, pi *PaymentIntent) { pi.Amount = 999 },
+		"expired":          func(p *PromotionCode, pi *PaymentIntent) { p.ExpiresAt = now.Add(-time.Hour).Unix() },
+		"fully redeemed":   func(p *PromotionCode, pi *PaymentIntent) { p.MaxRedemptions, p.TimesRedeemed = 1, 1 },
+		"other customer":   func(p *PromotionCode, pi *PaymentIntent) { p.CustomerID = "cus_2" },
+		"invalid coupon":   func(p *PromotionCode, pi *PaymentIntent) { p.Coupon = &Coupon{} },
+		"inactive":         func(p *PromotionCode, pi *PaymentIntent) { p.Active = false },
+		"minimum currency": func(p *PromotionCode, pi *PaymentIntent) { pi.Currency = "eur" },
+	} {
+		p, pi := *promo, *params
+		change(&p, &pi)
+		if _, _, err := DiscountPaymentIntent(&pi, nil, &p); !errors.Is(err, ErrPromotionCodeInvalid) {
+			t.Errorf("%s: expected ErrPromotionCodeInvalid, got %v", name, err)
+		}
+	}
+}
+
+func TestDiscountPaymentIntent_RejectsAFullDiscount(t *testing.T) {
+	params := &PaymentIntent{Amount: 2000, Currency: "usd"}
+	for _, c := range []*Coupon{
+		{ID: "free", PercentOff: 100, Valid: true},
+		{ID: "big", AmountOff: 5000, Currency: "usd", Valid: true},
+	} {
+		if amount, _, err := DiscountPaymentIntent(params, c, nil); !errors.Is(err, ErrCouponInvalid) {
+			t.Errorf("%s: expected ErrCouponInvalid, got %d, %v", c.ID, amount, err)
+		}
+	}
+}
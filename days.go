@@ -0,0 +1,15 @@
+package gomultistripe
+
+import (
+	"math"
+	"time"
+)
+
+// DaysUntil returns the whole days from from until to, rounded up, or 0 if to is not after
+// from. Handlers use it for CallbackEvent.DaysRemaining.
+func DaysUntil(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	return int(math.Ceil(to.Sub(from).Hours() / 24))
+}
@@ -0,0 +1,177 @@
+package gomultistripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrParkedEventNotFound is returned by DeadLetter.Requeue when no parked event has the given ID.
+var ErrParkedEventNotFound = errors.New("parked event not found")
+
+// ParkedEvent is an event that repeatedly failed consumer processing.
+type ParkedEvent struct {
+	// ID is what Requeue takes: the Stripe event ID, or "unidentified-<n>" for an event
+	// without one.
+	ID       string
+	Event    *CallbackEvent
+	Error    string
+	ParkedAt time.Time
+}
+
+// DeadLetter stores events that exhausted their consumer attempts so they can be
+// inspected and replayed once the underlying problem is fixed.
+type DeadLetter interface {
+	// Park stores evt together with the error from its last attempt.
+	Park(ctx context.Context, evt *CallbackEvent, cause error) error
+	// List returns all parked events, oldest first.
+	List(ctx context.Context) ([]*ParkedEvent, error)
+	// Requeue removes the parked event with the given ParkedEvent.ID, which is the Stripe
+	// event ID for events that have one, and returns it for reprocessing.
+	Requeue(ctx context.Context, eventID string) (*CallbackEvent, error)
+}
+
+// MemoryDeadLetter is an in-process DeadLetter. Parked events are lost on restart.
+type MemoryDeadLetter struct {
+	mu     sync.Mutex
+	events []*ParkedEvent
+}
+
+// NewMemoryDeadLetter creates an empty in-memory dead-letter queue.
+func NewMemoryDeadLetter() *MemoryDeadLetter {
+	return &MemoryDeadLetter{}
+}
+
+func (m *MemoryDeadLetter) Park(ctx context.Context, evt *CallbackEvent, cause error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, newParkedEvent(evt, cause))
+	return nil
+}
+
+func (m *MemoryDeadLetter) List(ctx context.Context) ([]*ParkedEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]*ParkedEvent, len(m.events))
+	copy(out, m.events)
+	return out, nil
+}
+
+func (m *MemoryDeadLetter) Requeue(ctx context.Context, eventID string) (*CallbackEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, p := range m.events {
+		if p.ID == eventID {
+			m.events = append(m.events[:i], m.events[i+1:]...)
+			return p.Event, nil
+		}
+	}
+	return nil, ErrParkedEventNotFound
+}
+
+// FileDeadLetter is a DeadLetter that stores each parked event as a JSON file in a
+// directory, so parked events survive restarts.
+type FileDeadLetter struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileDeadLetter creates a file-based dead-letter queue in dir, creating it if needed.
+func NewFileDeadLetter(dir string) (*FileDeadLetter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	return &FileDeadLetter{dir: dir}, nil
+}
+
+func (f *FileDeadLetter) path(eventID string) string {
+	return filepath.Join(f.dir, filepath.Base(eventID)+".json")
+}
+
+func (f *FileDeadLetter) Park(ctx context.Context, evt *CallbackEvent, cause error) error {
+	p := newParkedEvent(evt, cause)
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp := f.path(p.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(p.ID))
+}
+
+func (f *FileDeadLetter) List(ctx context.Context) ([]*ParkedEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []*ParkedEvent
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		p, err := f.read(filepath.Join(f.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ParkedAt.Before(out[j].ParkedAt) })
+	return out, nil
+}
+
+func (f *FileDeadLetter) Requeue(ctx context.Context, eventID string) (*CallbackEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := f.path(eventID)
+	p, err := f.read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrParkedEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, err
+	}
+	return p.Event, nil
+}
+
+func (f *FileDeadLetter) read(path string) (*ParkedEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p ParkedEvent
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode parked event %s: %w", path, err)
+	}
+	if p.ID == "" {
+		// Files parked before ParkedEvent.ID existed are named after it.
+		p.ID = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return &p, nil
+}
+
+func newParkedEvent(evt *CallbackEvent, cause error) *ParkedEvent {
+	p := &ParkedEvent{ID: evt.EventID, Event: evt, ParkedAt: time.Now()}
+	if p.ID == "" {
+		p.ID = fmt.Sprintf("unidentified-%d", p.ParkedAt.UnixNano())
+	}
+	if cause != nil {
+		p.Error = cause.Error()
+	}
+	return p
+}
@@ -0,0 +1,136 @@
+package gomultistripe
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultDedupeCapacity is the number of event IDs a MemoryDedupeStore remembers unless
+// configured otherwise.
+const DefaultDedupeCapacity = 10000
+
+// DedupeStore records the IDs of the events that were processed, so that Deduplicate can
+// drop Stripe's redeliveries. Implementations must be safe for concurrent use.
+type DedupeStore interface {
+	// MarkSeen records eventID and reports whether it was new, i.e. not recorded yet.
+	MarkSeen(ctx context.Context, eventID string) (bool, error)
+	// Forget removes eventID, so that the event is processed again when redelivered.
+	Forget(ctx context.Context, eventID string) error
+}
+
+// Deduplicate wraps consumer so that events store has already seen are dropped: Stripe
+// delivers webhooks at least once, and resends events it did not get a 2xx for in time.
+// Events whose processing fails are forgotten again, so a retry processes them. Events
+// without an ID are always passed on.
+//
+// Wrap the consumer of a Dispatcher to drop duplicates once processed, or the dispatch
+// function given to WebhookHTTPHandler to drop them before they are queued:
+//
+//	d := gomultistripe.NewDispatcher(gomultistripe.Deduplicate(store, consume), cfg)
+//
+// A redelivery arriving while the first delivery is still being processed is dropped too.
+func Deduplicate(store DedupeStore, consumer EventConsumer) EventConsumer {
+	return func(ctx context.Context, evt *CallbackEvent) error {
+		if evt.EventID == "" {
+			return consumer(ctx, evt)
+		}
+		// The consumer may release the event, so its ID is read beforehand.
+		eventID := evt.EventID
+		isNew, err := store.MarkSeen(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			Logger().DebugContext(ctx, "dropping duplicate event", LogKeyEventID, eventID, LogKeyEventType, string(evt.Type))
+			return nil
+		}
+		if err := consumer(ctx, evt); err != nil {
+			if forgetErr := store.Forget(context.WithoutCancel(ctx), eventID); forgetErr != nil {
+				return errors.Join(err, forgetErr)
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// MemoryDedupeStore is a DedupeStore for a single process that remembers the most recently
+// seen event IDs. The zero value is ready to use and remembers DefaultDedupeCapacity IDs.
+type MemoryDedupeStore struct {
+	// Capacity is the number of IDs remembered; the least recently seen are evicted first.
+	Capacity int
+
+	mu    sync.Mutex
+	order *list.List
+	ids   map[string]*list.Element
+}
+
+// NewMemoryDedupeStore creates a store remembering up to capacity event IDs.
+func NewMemoryDedupeStore(capacity int) *MemoryDedupeStore {
+	return &MemoryDedupeStore{Capacity: capacity}
+}
+
+func (m *MemoryDedupeStore) MarkSeen(ctx context.Context, eventID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ids == nil {
+		m.order, m.ids = list.New(), make(map[string]*list.Element)
+	}
+	if e, ok := m.ids[eventID]; ok {
+		m.order.MoveToFront(e)
+		return false, nil
+	}
+	m.ids[eventID] = m.order.PushFront(eventID)
+	capacity := m.Capacity
+	if capacity <= 0 {
+		capacity = DefaultDedupeCapacity
+	}
+	for m.order.Len() > capacity {
+		delete(m.ids, m.order.Remove(m.order.Back()).(string))
+	}
+	return true, nil
+}
+
+func (m *MemoryDedupeStore) Forget(ctx context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.ids[eventID]; ok {
+		m.order.Remove(e)
+		delete(m.ids, eventID)
+	}
+	return nil
+}
+
+// SharedDedupeStore is a DedupeStore on a SharedStore, so that a redelivery is dropped
+// whichever replica receives it.
+type SharedDedupeStore struct {
+	Store SharedStore
+	// Prefix is prepended to event IDs. Defaults to "gomultistripe:event:".
+	Prefix string
+	// TTL is how long an event ID is remembered. Defaults to 72 hours, the period Stripe
+	// retries deliveries for.
+	TTL time.Duration
+}
+
+func (s *SharedDedupeStore) key(eventID string) string {
+	if s.Prefix == "" {
+		return "gomultistripe:event:" + eventID
+	}
+	return s.Prefix + eventID
+}
+
+func (s *SharedDedupeStore) MarkSeen(ctx context.Context, eventID string) (bool, error) {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = 72 * time.Hour
+	}
+	return s.Store.SetNX(ctx, s.key(eventID), "seen", ttl)
+}
+
+func (s *SharedDedupeStore) Forget(ctx context.Context, eventID string) error {
+	_, err := s.Store.CompareAndDelete(ctx, s.key(eventID), "seen")
+	return err
+}
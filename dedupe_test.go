@@ -0,0 +1,67 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeduplicate(t *testing.T) {
+	for name, store := range map[string]DedupeStore{
+		"memory": &MemoryDedupeStore{},
+		"shared": &SharedDedupeStore{Store: &MemoryStore{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var processed []string
+			fail := errors.New("consumer failed")
+			failing := true
+			consume := Deduplicate(store, func(ctx context.Context, evt *CallbackEvent) error {
+				if evt.EventID == "evt_flaky" && failing {
+					failing = false
+					return fail
+				}
+				processed = append(processed, evt.EventID)
+				return nil
+			})
+			ctx := context.Background()
+			for _, id := range []string{"evt_1", "evt_1", "evt_2", "", "", "evt_1"} {
+				if err := consume(ctx, &CallbackEvent{EventID: id}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := consume(ctx, &CallbackEvent{EventID: "evt_flaky"}); !errors.Is(err, fail) {
+				t.Fatalf("failing consumer: %v", err)
+			}
+			// The failed event was forgotten, so its redelivery is processed.
+			if err := consume(ctx, &CallbackEvent{EventID: "evt_flaky"}); err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"evt_1", "evt_2", "", "", "evt_flaky"}
+			if len(processed) != len(want) {
+				t.Fatalf("processed %q, want %q", processed, want)
+			}
+			for i := range want {
+				if processed[i] != want[i] {
+					t.Fatalf("processed %q, want %q", processed, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryDedupeStore_EvictsLeastRecentlySeen(t *testing.T) {
+	s := NewMemoryDedupeStore(2)
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "a", "c"} {
+		s.MarkSeen(ctx, id)
+	}
+	// b was seen least recently when c arrived.
+	for _, tc := range []struct {
+		id   string
+		want bool
+	}{{"a", false}, {"c", false}, {"b", true}} {
+		if isNew, _ := s.MarkSeen(ctx, tc.id); isNew != tc.want {
+			t.Errorf("%s: new %t, want %t", tc.id, isNew, tc.want)
+		}
+	}
+}
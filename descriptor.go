@@ -0,0 +1,27 @@
+package gomultistripe
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidStatementDescriptor is returned when a statement descriptor violates Stripe's rules.
+var ErrInvalidStatementDescriptor = errors.New("invalid statement descriptor")
+
+// ValidateStatementDescriptorSuffix checks a per-payment statement descriptor suffix
+// against Stripe's rules: at most 22 characters, at least one letter, and none of
+// < > \ ' " *. Stripe also limits prefix and suffix to 22 characters combined, which
+// can only be checked against the account's prefix.
+func ValidateStatementDescriptorSuffix(suffix string) error {
+	if len(suffix) > 22 {
+		return errors.Join(ErrInvalidStatementDescriptor, errors.New("suffix exceeds 22 characters"))
+	}
+	if strings.ContainsAny(suffix, `<>\'"*`) {
+		return errors.Join(ErrInvalidStatementDescriptor, errors.New(`suffix contains one of < > \ ' " *`))
+	}
+	if strings.IndexFunc(suffix, unicode.IsLetter) < 0 {
+		return errors.Join(ErrInvalidStatementDescriptor, errors.New("suffix must contain at least one letter"))
+	}
+	return nil
+}
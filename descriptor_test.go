@@ -0,0 +1,23 @@
+package gomultistripe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateStatementDescriptorSuffix(t *testing.T) {
+	for suffix, ok := range map[string]bool{
+		"ORDER 42":                true,
+		"22 CHARACTERS SUFFIX AB": false,
+		"22 CHARACTERS SUFFIX A":  true,
+		"2024":                    false,
+		`ORDER "42"`:              false,
+		"ORDER*42":                false,
+		"<ORDER>":                 false,
+	} {
+		err := ValidateStatementDescriptorSuffix(suffix)
+		if (err == nil) != ok || (err != nil && !errors.Is(err, ErrInvalidStatementDescriptor)) {
+			t.Errorf("%q: got %v", suffix, err)
+		}
+	}
+}
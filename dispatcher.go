@@ -0,0 +1,266 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDispatcherNotRunning is returned when events are dispatched before Start or after Shutdown.
+var ErrDispatcherNotRunning = errors.New("dispatcher is not running")
+
+// EventConsumer processes a single normalized webhook event.
+type EventConsumer func(ctx context.Context, evt *CallbackEvent) error
+
+// Flusher is implemented by pipeline components (such as a dedupe store) that buffer
+// state which must be persisted before the process exits.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// DispatcherConfig configures a Dispatcher.
+type DispatcherConfig struct {
+	// Workers is the number of goroutines consuming events. Defaults to 1.
+	Workers int
+	// QueueSize is the number of events that can be buffered before Dispatch blocks. Defaults to 100.
+	QueueSize int
+	// Flushers are flushed, in order, once all in-flight events have been drained on Shutdown.
+	Flushers []Flusher
+	// Poison controls how often a failing event is retried before it is parked.
+	Poison PoisonPolicy
+	// DeadLetter, if set, stores events that exhaust their attempts so they can be requeued later.
+	DeadLetter DeadLetter
+	// OnParked, if set, is called for every event that exhausts its attempts.
+	OnParked func(evt *CallbackEvent, err error)
+	// Metrics receives consumer outcome measurements. Defaults to NopMetrics.
+	Metrics Metrics
+	// ReleaseEvents makes the dispatcher Release each event once the consumer has processed
+	// it, for use with SetCallbackEventPooling; the consumer must then not keep the event.
+	// Parked events are never released, as the dead letter store may keep them.
+	ReleaseEvents bool
+}
+
+// PoisonPolicy describes how the dispatcher treats events the consumer keeps failing on.
+type PoisonPolicy struct {
+	// MaxAttempts is the number of consumer attempts per event, including the first. Defaults to 1.
+	MaxAttempts int
+	// Backoff is the delay between attempts.
+	Backoff time.Duration
+}
+
+// Dispatcher decouples webhook ingestion from event processing. Events returned by
+// Handler.HandleWebhook are queued with Dispatch and handed to the consumer by a pool
+// of workers, so the HTTP handler can acknowledge Stripe quickly.
+type Dispatcher struct {
+	consumer EventConsumer
+	cfg      DispatcherConfig
+
+	mu      sync.RWMutex
+	running bool
+	queue   chan *CallbackEvent
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	statsMu sync.Mutex
+	stats   map[CallbackEventType]*ConsumerStats
+}
+
+// NewDispatcher creates a dispatcher that hands events to consumer. Call Start before dispatching.
+func NewDispatcher(consumer EventConsumer, cfg DispatcherConfig) *Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.Poison.MaxAttempts <= 0 {
+		cfg.Poison.MaxAttempts = 1
+	}
+	if cfg.Metrics == nil {
+		cfg.Metrics = NopMetrics{}
+	}
+	return &Dispatcher{
+		consumer: consumer,
+		cfg:      cfg,
+		stats:    make(map[CallbackEventType]*ConsumerStats),
+	}
+}
+
+// Start launches the worker pool. The context is passed to the consumer for every event;
+// cancelling it aborts in-flight processing without draining the queue.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		return errors.New("dispatcher already started")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.queue = make(chan *CallbackEvent, d.cfg.QueueSize)
+	d.running = true
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.work(runCtx, d.queue)
+	}
+	return nil
+}
+
+func (d *Dispatcher) work(ctx context.Context, queue <-chan *CallbackEvent) {
+	defer d.wg.Done()
+	for evt := range queue {
+		d.process(ctx, evt)
+	}
+}
+
+// process runs the consumer for evt, retrying according to the poison policy and
+// parking the event once its attempts are exhausted.
+func (d *Dispatcher) process(ctx context.Context, evt *CallbackEvent) {
+	log := Logger().With(LogKeyOperation, "Dispatch", LogKeyEventID, evt.EventID, LogKeyEventType, string(evt.Type))
+	var err error
+	for attempt := 1; attempt <= d.cfg.Poison.MaxAttempts; attempt++ {
+		if attempt > 1 && d.cfg.Poison.Backoff > 0 {
+			select {
+			case <-time.After(d.cfg.Poison.Backoff):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil && err != nil {
+			break
+		}
+		if err = d.consumer(ctx, evt); err == nil {
+			lag := eventLag(evt)
+			d.count(evt.Type, func(s *ConsumerStats) {
+				s.Succeeded++
+				s.MaxLag = max(s.MaxLag, lag)
+			})
+			d.cfg.Metrics.ConsumerSucceeded(evt.Type, attempt)
+			if !evt.EventCreatedAt.IsZero() {
+				d.cfg.Metrics.EventProcessed(evt.Type, lag)
+			}
+			if d.cfg.ReleaseEvents {
+				evt.Release()
+			}
+			return
+		}
+		d.count(evt.Type, func(s *ConsumerStats) { s.Failed++ })
+		d.cfg.Metrics.ConsumerFailed(evt.Type, attempt, err)
+		log.WarnContext(ctx, "event consumer failed", "attempt", attempt, "error", err)
+	}
+	d.count(evt.Type, func(s *ConsumerStats) { s.Parked++ })
+	d.cfg.Metrics.EventParked(evt.Type, err)
+	log.ErrorContext(ctx, "parking event", "error", err)
+	if d.cfg.DeadLetter != nil {
+		// Parking must survive a cancelled run context, otherwise shutdown would drop the event.
+		if parkErr := d.cfg.DeadLetter.Park(context.WithoutCancel(ctx), evt, err); parkErr != nil {
+			err = errors.Join(err, parkErr)
+		}
+	}
+	if d.cfg.OnParked != nil {
+		d.cfg.OnParked(evt, err)
+	}
+}
+
+func (d *Dispatcher) count(eventType CallbackEventType, update func(*ConsumerStats)) {
+	d.statsMu.Lock()
+	defer d.statsMu.Unlock()
+	s, ok := d.stats[eventType]
+	if !ok {
+		s = &ConsumerStats{}
+		d.stats[eventType] = s
+	}
+	update(s)
+}
+
+// eventLag returns the time since Stripe created evt, or 0 when the creation time is unknown.
+func eventLag(evt *CallbackEvent) time.Duration {
+	if evt.EventCreatedAt.IsZero() {
+		return 0
+	}
+	return time.Since(evt.EventCreatedAt)
+}
+
+// Stats returns a snapshot of the consumer counters per event type.
+func (d *Dispatcher) Stats() map[CallbackEventType]ConsumerStats {
+	d.statsMu.Lock()
+	defer d.statsMu.Unlock()
+	out := make(map[CallbackEventType]ConsumerStats, len(d.stats))
+	for t, s := range d.stats {
+		out[t] = *s
+	}
+	return out
+}
+
+// Dispatch queues an event for processing. It blocks while the queue is full, until ctx is done.
+func (d *Dispatcher) Dispatch(ctx context.Context, evt *CallbackEvent) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.running {
+		return ErrDispatcherNotRunning
+	}
+	// The event belongs to the workers once queued, and may already be released, so it is
+	// read beforehand.
+	eventType, created, lag := evt.Type, !evt.EventCreatedAt.IsZero(), eventLag(evt)
+	select {
+	case d.queue <- evt:
+		if created {
+			d.cfg.Metrics.EventQueued(eventType, lag)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Requeue moves a parked event from the configured DeadLetter back onto the queue. eventID
+// is the event's ParkedEvent.ID.
+func (d *Dispatcher) Requeue(ctx context.Context, eventID string) error {
+	if d.cfg.DeadLetter == nil {
+		return errors.New("dispatcher has no dead-letter queue configured")
+	}
+	evt, err := d.cfg.DeadLetter.Requeue(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := d.Dispatch(ctx, evt); err != nil {
+		// Put the event back so it is not lost when the dispatcher is not running.
+		return errors.Join(err, d.cfg.DeadLetter.Park(ctx, evt, err))
+	}
+	return nil
+}
+
+// Shutdown stops accepting new events, waits for queued and in-flight events to be
+// processed and then flushes the configured Flushers. If ctx expires first, in-flight
+// processing is cancelled and ctx.Err() is returned.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.running = false
+	close(d.queue)
+	cancel := d.cancel
+	d.mu.Unlock()
+	defer cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+
+	var errs []error
+	for _, f := range d.cfg.Flushers {
+		if err := f.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
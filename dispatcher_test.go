@@ -0,0 +1,184 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcher_ParksAfterMaxAttemptsAndRequeues(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	dlq := NewMemoryDeadLetter()
+	d := NewDispatcher(func(ctx context.Context, evt *CallbackEvent) error {
+		calls.Add(1)
+		if healthy.Load() {
+			return nil
+		}
+		return errors.New("consumer down")
+	}, DispatcherConfig{
+		Poison:     PoisonPolicy{MaxAttempts: 3},
+		DeadLetter: dlq,
+	})
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	evt := &CallbackEvent{Type: EventPaymentIntentSucceeded, EventID: "evt_1"}
+	if err := d.Dispatch(context.Background(), evt); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	waitFor(t, func() bool {
+		parked, _ := dlq.List(context.Background())
+		return len(parked) == 1
+	})
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+
+	healthy.Store(true)
+	if err := d.Requeue(context.Background(), "evt_1"); err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	stats := d.Stats()[EventPaymentIntentSucceeded]
+	if stats.Failed != 3 || stats.Parked != 1 || stats.Succeeded != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if err := d.Dispatch(context.Background(), evt); !errors.Is(err, ErrDispatcherNotRunning) {
+		t.Fatalf("expected ErrDispatcherNotRunning after shutdown, got %v", err)
+	}
+}
+
+func TestFileDeadLetter_RoundTrip(t *testing.T) {
+	dlq, err := NewFileDeadLetter(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileDeadLetter failed: %v", err)
+	}
+	ctx := context.Background()
+	evt := &CallbackEvent{Type: EventInvoicePaymentFailed, EventID: "evt_2", Metadata: map[string]string{"SPID": "1"}}
+	if err := dlq.Park(ctx, evt, errors.New("boom")); err != nil {
+		t.Fatalf("Park failed: %v", err)
+	}
+	parked, err := dlq.List(ctx)
+	if err != nil || len(parked) != 1 || parked[0].Error != "boom" {
+		t.Fatalf("unexpected List result: %v, %v", parked, err)
+	}
+	got, err := dlq.Requeue(ctx, "evt_2")
+	if err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	if got.Metadata["SPID"] != "1" {
+		t.Fatalf("event not round-tripped: %+v", got)
+	}
+	if _, err := dlq.Requeue(ctx, "evt_2"); !errors.Is(err, ErrParkedEventNotFound) {
+		t.Fatalf("expected ErrParkedEventNotFound, got %v", err)
+	}
+}
+
+func TestFileDeadLetter_RequeuesEventsWithoutAnID(t *testing.T) {
+	dir := t.TempDir()
+	dlq, err := NewFileDeadLetter(dir)
+	if err != nil {
+		t.Fatalf("NewFileDeadLetter failed: %v", err)
+	}
+	ctx := context.Background()
+	if err := dlq.Park(ctx, &CallbackEvent{Type: EventInvoicePaymentFailed}, errors.New("boom")); err != nil {
+		t.Fatalf("Park failed: %v", err)
+	}
+	parked, err := dlq.List(ctx)
+	if err != nil || len(parked) != 1 || !strings.HasPrefix(parked[0].ID, "unidentified-") {
+		t.Fatalf("unexpected List result: %v, %v", parked, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, parked[0].ID+".json")); err != nil {
+		t.Fatalf("parked event is not stored under its ID: %v", err)
+	}
+	got, err := dlq.Requeue(ctx, parked[0].ID)
+	if err != nil || got.Type != EventInvoicePaymentFailed {
+		t.Fatalf("Requeue returned %+v, %v", got, err)
+	}
+	if parked, _ := dlq.List(ctx); len(parked) != 0 {
+		t.Fatalf("event still parked after Requeue: %+v", parked)
+	}
+}
+
+type lagMetrics struct {
+	NopMetrics
+	queued, processed atomic.Int64
+}
+
+func (m *lagMetrics) EventQueued(_ CallbackEventType, lag time.Duration) { m.queued.Store(int64(lag)) }
+func (m *lagMetrics) EventProcessed(_ CallbackEventType, lag time.Duration) {
+	m.processed.Store(int64(lag))
+}
+
+func TestDispatcher_ReportsEventLag(t *testing.T) {
+	m := &lagMetrics{}
+	d := NewDispatcher(func(ctx context.Context, evt *CallbackEvent) error { return nil }, DispatcherConfig{Metrics: m})
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	evt := &CallbackEvent{Type: EventInvoicePaymentSucceeded, EventID: "evt_3", EventCreatedAt: time.Now().Add(-time.Minute)}
+	if err := d.Dispatch(context.Background(), evt); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if got := time.Duration(m.queued.Load()); got < time.Minute {
+		t.Fatalf("expected queued lag of at least a minute, got %v", got)
+	}
+	if got := time.Duration(m.processed.Load()); got < time.Minute {
+		t.Fatalf("expected processed lag of at least a minute, got %v", got)
+	}
+	if got := d.Stats()[EventInvoicePaymentSucceeded].MaxLag; got < time.Minute {
+		t.Fatalf("expected MaxLag of at least a minute, got %v", got)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDispatcher_ReleasesPooledEvents(t *testing.T) {
+	SetCallbackEventPooling(true)
+	defer SetCallbackEventPooling(false)
+	done := make(chan struct{})
+	d := NewDispatcher(func(ctx context.Context, evt *CallbackEvent) error {
+		defer close(done)
+		if evt.EventID != "evt_pooled" || len(evt.InvoiceLines) != 1 {
+			t.Errorf("consumer got %+v", evt)
+		}
+		return nil
+	}, DispatcherConfig{ReleaseEvents: true})
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	evt := NewCallbackEvent(CallbackEvent{EventID: "evt_pooled", InvoiceLines: []InvoiceLine{{ID: "il_1"}}})
+	if err := d.Dispatch(context.Background(), evt); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	<-done
+	if err := d.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if evt.EventID != "" || evt.InvoiceLines != nil {
+		t.Fatalf("event was not released: %+v", evt)
+	}
+}
@@ -0,0 +1,131 @@
+package gomultistripe
+
+import "time"
+
+// Dispute represents a Stripe dispute (chargeback) in a version-agnostic way.
+type Dispute struct {
+	ID              string
+	ChargeID        string
+	PaymentIntentID string
+	// Amount is the disputed amount, withdrawn from the balance while the dispute is open.
+	Amount   int64
+	Currency string
+	// Reason is the cardholder's reason, e.g. "fraudulent", "product_not_received" or
+	// "duplicate". NetworkReasonCode is the card network's code for it.
+	Reason            string
+	NetworkReasonCode string
+	// Status is "warning_needs_response", "warning_under_review", "warning_closed",
+	// "needs_response", "under_review", "won" or "lost".
+	Status string
+	// EvidenceDueBy is when evidence must be submitted by. HasEvidence reports whether
+	// evidence was saved, EvidencePastDue whether the deadline passed, and
+	// EvidenceSubmissionCount how many times evidence was submitted.
+	EvidenceDueBy           time.Time
+	HasEvidence             bool
+	EvidencePastDue         bool
+	EvidenceSubmissionCount int64
+	// IsChargeRefundable reports whether the charge can still be refunded, which inquiries
+	// (warning_ statuses) allow.
+	IsChargeRefundable bool
+	Metadata           map[string]string
+	CreatedAt          time.Time
+}
+
+// DisputeEvidence is the evidence challenging a dispute. Text fields take plain text, and
+// the fields documented as files take the ID of a file uploaded with purpose
+// "dispute_evidence". Empty fields leave the saved evidence unchanged.
+type DisputeEvidence struct {
+	ProductDescription           string
+	CustomerName                 string
+	CustomerEmailAddress         string
+	CustomerPurchaseIP           string
+	BillingAddress               string
+	ShippingAddress              string
+	ShippingCarrier              string
+	ShippingTrackingNumber       string
+	ShippingDate                 string
+	ServiceDate                  string
+	AccessActivityLog            string
+	CancellationPolicyDisclosure string
+	CancellationRebuttal         string
+	RefundPolicyDisclosure       string
+	RefundRefusalExplanation     string
+	DuplicateChargeExplanation   string
+	DuplicateChargeID            string
+	UncategorizedText            string
+
+	// Files.
+	Receipt                      string
+	CustomerCommunication        string
+	CustomerSignature            string
+	ShippingDocumentation        string
+	ServiceDocumentation         string
+	CancellationPolicy           string
+	RefundPolicy                 string
+	DuplicateChargeDocumentation string
+	UncategorizedFile            string
+}
+
+// FormParams encodes the non-empty fields as Stripe evidence form parameters, which every
+// stripe-go version accepts, so handlers send them as extra params.
+func (e *DisputeEvidence) FormParams() map[string]string {
+	params := make(map[string]string)
+	for name, value := range map[string]string{
+		"product_description":            e.ProductDescription,
+		"customer_name":                  e.CustomerName,
+		"customer_email_address":         e.CustomerEmailAddress,
+		"customer_purchase_ip":           e.CustomerPurchaseIP,
+		"billing_address":                e.BillingAddress,
+		"shipping_address":               e.ShippingAddress,
+		"shipping_carrier":               e.ShippingCarrier,
+		"shipping_tracking_number":       e.ShippingTrackingNumber,
+		"shipping_date":                  e.ShippingDate,
+		"service_date":                   e.ServiceDate,
+		"access_activity_log":            e.AccessActivityLog,
+		"cancellation_policy_disclosure": e.CancellationPolicyDisclosure,
+		"cancellation_rebuttal":          e.CancellationRebuttal,
+		"refund_policy_disclosure":       e.RefundPolicyDisclosure,
+		"refund_refusal_explanation":     e.RefundRefusalExplanation,
+		"duplicate_charge_explanation":   e.DuplicateChargeExplanation,
+		"duplicate_charge_id":            e.DuplicateChargeID,
+		"uncategorized_text":             e.UncategorizedText,
+		"receipt":                        e.Receipt,
+		"customer_communication":         e.CustomerCommunication,
+		"customer_signature":             e.CustomerSignature,
+		"shipping_documentation":         e.ShippingDocumentation,
+		"service_documentation":          e.ServiceDocumentation,
+		"cancellation_policy":            e.CancellationPolicy,
+		"refund_policy":                  e.RefundPolicy,
+		"duplicate_charge_documentation": e.DuplicateChargeDocumentation,
+		"uncategorized_file":             e.UncategorizedFile,
+	} {
+		if value != "" {
+			params["evidence["+name+"]"] = value
+		}
+	}
+	return params
+}
+
+// DisputeUpdate describes an update to a dispute.
+type DisputeUpdate struct {
+	// Evidence is saved on the dispute, merged with the evidence saved before.
+	Evidence *DisputeEvidence
+	// Submit sends the saved evidence to the card network. Evidence can only be submitted
+	// once, so leave it unset to save evidence gradually.
+	Submit   bool
+	Metadata map[string]string
+}
+
+// DisputeQuery filters ListDisputes. Empty fields do not filter.
+type DisputeQuery struct {
+	ChargeID        string
+	PaymentIntentID string
+}
+
+// DisputePage is one page of disputes.
+type DisputePage struct {
+	Disputes []*Dispute
+	HasMore  bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,17 @@
+package gomultistripe
+
+// Endpoints overrides the base URLs a handler sends Stripe requests to, e.g. to route
+// traffic through an egress proxy. Stripe serves regular API calls, file uploads and
+// Connect OAuth from different hosts, so each can be routed separately.
+// Empty fields keep the Stripe default for that endpoint.
+type Endpoints struct {
+	// APIURL replaces https://api.stripe.com.
+	APIURL string
+	// FilesURL replaces https://files.stripe.com, used for file uploads.
+	FilesURL string
+	// ConnectURL replaces https://connect.stripe.com, used for Connect OAuth.
+	ConnectURL string
+	// MeterEventsURL replaces https://meter-events.stripe.com, used by the v2 meter event
+	// stream of handlers for stripe-go v80 and later.
+	MeterEventsURL string
+}
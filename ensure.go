@@ -0,0 +1,60 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmailRequired is returned by FindOrCreateCustomer for a customer without an email.
+var ErrEmailRequired = errors.New("customer email is required")
+
+// FindOrCreateCustomer returns the customer with params.Email, creating it from params when
+// Stripe has none. The boolean reports whether it was created. With a locker, concurrent
+// calls for the same email are serialized, so they cannot create duplicate customers; a
+// nil locker does not lock.
+func FindOrCreateCustomer(ctx context.Context, h Handler, locker Locker, params *Customer) (*Customer, bool, error) {
+	if params.Email == "" {
+		return nil, false, ErrEmailRequired
+	}
+	unlock, err := lock(ctx, locker, "customer-email:"+params.Email)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+	cust, err := h.FindCustomerByEmail(ctx, params.Email)
+	if err != nil || cust != nil {
+		return cust, false, err
+	}
+	cust, err = h.CreateCustomer(ctx, params)
+	if err != nil {
+		return nil, false, err
+	}
+	return cust, true, nil
+}
+
+// EnsureSubscription returns the customer's subscription to priceID, creating it with opts
+// when the customer has none that is still live (i.e. not canceled or expired while
+// incomplete). The boolean reports whether it was created. With a locker, concurrent calls
+// for the same customer are serialized, so they cannot subscribe it twice; a nil locker
+// does not lock.
+func EnsureSubscription(ctx context.Context, h Handler, locker Locker, customerID, priceID string, opts ...SubscriptionOption) (*Subscription, bool, error) {
+	unlock, err := lock(ctx, locker, "customer:"+customerID)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+	subs, err := h.ListSubscriptions(ctx, customerID)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, sub := range subs {
+		if sub.PriceID == priceID && sub.Status != "canceled" && sub.Status != "incomplete_expired" {
+			return sub, false, nil
+		}
+	}
+	sub, err := h.CreateSubscription(ctx, customerID, priceID, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	return sub, true, nil
+}
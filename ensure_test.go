@@ -0,0 +1,108 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowStore is a Handler whose customers and subscriptions live in memory. Lookups and
+// creations yield to other goroutines, so unserialized callers race into duplicates.
+type slowStore struct {
+	Handler
+	mu        sync.Mutex
+	customers []*Customer
+	subs      []*Subscription
+}
+
+func (s *slowStore) FindCustomerByEmail(ctx context.Context, email string) (*Customer, error) {
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.customers {
+		if c.Email == email {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *slowStore) CreateCustomer(ctx context.Context, params *Customer) (*Customer, error) {
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &Customer{ID: "cus_" + params.Email, Email: params.Email}
+	s.customers = append(s.customers, c)
+	return c, nil
+}
+
+func (s *slowStore) ListSubscriptions(ctx context.Context, customerID string) ([]*Subscription, error) {
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Subscription(nil), s.subs...), nil
+}
+
+func (s *slowStore) CreateSubscription(ctx context.Context, customerID, priceID string, opts ...SubscriptionOption) (*Subscription, error) {
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := &Subscription{ID: "sub_" + priceID, CustomerID: customerID, PriceID: priceID, Status: "active"}
+	s.subs = append(s.subs, sub)
+	return sub, nil
+}
+
+func TestEnsureHelpers_SerializeConcurrentCalls(t *testing.T) {
+	store := &slowStore{}
+	var locker KeyedMutex
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, _, err := FindOrCreateCustomer(ctx, store, &locker, &Customer{Email: "a@example.com"}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, _, err := EnsureSubscription(ctx, store, &locker, "cus_a", "price_pro"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(store.customers) != 1 || len(store.subs) != 1 {
+		t.Fatalf("got %d customers and %d subscriptions, want one of each", len(store.customers), len(store.subs))
+	}
+	if len(locker.keys) != 0 {
+		t.Fatalf("released keys should be dropped, %d left", len(locker.keys))
+	}
+}
+
+func TestKeyedMutex_LockHonorsContext(t *testing.T) {
+	var locker KeyedMutex
+	unlock, err := locker.Lock(context.Background(), "cus_a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := locker.Lock(ctx, "cus_a"); err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to expire while waiting, got %v", err)
+	}
+	if other, err := locker.Lock(context.Background(), "cus_b"); err != nil {
+		t.Fatal(err)
+	} else {
+		other()
+	}
+	unlock()
+	unlock()
+	if again, err := locker.Lock(context.Background(), "cus_a"); err != nil {
+		t.Fatal(err)
+	} else {
+		again()
+	}
+}
@@ -0,0 +1,29 @@
+package gomultistripe
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported is matched (via errors.Is) by errors returned when an operation or
+// option is not available in a handler's Stripe API version.
+var ErrUnsupported = errors.New("not supported by this Stripe API version")
+
+// UnsupportedError reports which feature a handler version lacks.
+type UnsupportedError struct {
+	Version string
+	Feature string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by Stripe handler %s", e.Feature, e.Version)
+}
+
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// Unsupported returns an UnsupportedError for the given handler version and feature.
+func Unsupported(version, feature string) error {
+	return &UnsupportedError{Version: version, Feature: feature}
+}
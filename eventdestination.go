@@ -0,0 +1,85 @@
+package gomultistripe
+
+import "time"
+
+// EventDestinationType is where an event destination delivers events.
+type EventDestinationType string
+
+const (
+	// EventDestinationWebhook delivers events to an HTTPS endpoint.
+	EventDestinationWebhook EventDestinationType = "webhook_endpoint"
+	// EventDestinationEventBridge delivers events to an Amazon EventBridge partner event
+	// source, which must then be associated with an event bus in the AWS console.
+	EventDestinationEventBridge EventDestinationType = "amazon_eventbridge"
+)
+
+// EventPayload is the payload style of the events sent to an event destination.
+type EventPayload string
+
+const (
+	// EventPayloadThin sends thin events, which carry only the ID of the related object;
+	// the consumer fetches the object or the full event when it needs it.
+	EventPayloadThin EventPayload = "thin"
+	// EventPayloadSnapshot sends classic events with a snapshot of the object, rendered in
+	// EventDestinationParams.SnapshotAPIVersion.
+	EventPayloadSnapshot EventPayload = "snapshot"
+)
+
+// EventDestinationParams describes an event destination to create.
+type EventDestinationParams struct {
+	Name        string
+	Description string
+	Type        EventDestinationType
+	// EnabledEvents lists the event types to send, e.g. "v1.billing.meter.error_report_triggered".
+	EnabledEvents []string
+	// Payload defaults to EventPayloadThin.
+	Payload EventPayload
+	// SnapshotAPIVersion is the API version snapshot payloads are rendered in. It defaults to
+	// the handler's API version.
+	SnapshotAPIVersion string
+	// IncludeConnectedAccounts also sends the events of connected accounts, rather than only
+	// those of the account the destination belongs to.
+	IncludeConnectedAccounts bool
+	// WebhookURL is required for EventDestinationWebhook.
+	WebhookURL string
+	// AWSAccountID and AWSRegion are required for EventDestinationEventBridge.
+	AWSAccountID string
+	AWSRegion    string
+	Metadata     map[string]string
+}
+
+// EventDestinationUpdate changes an event destination. Empty fields are left unchanged.
+type EventDestinationUpdate struct {
+	Name          string
+	Description   string
+	EnabledEvents []string
+	WebhookURL    string
+	Metadata      map[string]string
+}
+
+// EventDestination is a v2 event destination.
+type EventDestination struct {
+	ID          string
+	Name        string
+	Description string
+	Type        EventDestinationType
+	// Status is "enabled" or "disabled".
+	Status             string
+	EnabledEvents      []string
+	Payload            EventPayload
+	SnapshotAPIVersion string
+	// EventsFrom is "self", "other_accounts" or both.
+	EventsFrom []string
+	WebhookURL string
+	// WebhookSecret is the signing secret of a webhook destination. Stripe returns it only
+	// when the destination is created, so store it then.
+	WebhookSecret string
+	AWSAccountID  string
+	// AWSEventSourceARN is the partner event source created for an EventBridge destination,
+	// and AWSEventSourceStatus its status ("active", "pending", "deleted" or "unknown").
+	AWSEventSourceARN    string
+	AWSEventSourceStatus string
+	Livemode             bool
+	Metadata             map[string]string
+	CreatedAt            time.Time
+}
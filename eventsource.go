@@ -0,0 +1,81 @@
+package gomultistripe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrThinEvent is returned by UnwrapEvent for thin (v2) events, which carry only the ID
+	// of the related object and so cannot be normalized. Fetch the object instead.
+	ErrThinEvent = errors.New("thin events carry no object to normalize")
+	// ErrUnknownEnvelope is returned by UnwrapEvent for payloads that are neither a Stripe
+	// event nor an envelope around one.
+	ErrUnknownEnvelope = errors.New("payload is not a Stripe event, EventBridge event or CloudEvent")
+)
+
+// EventHandler normalizes Stripe events that need no signature check. Handler and
+// VersionRouter implement it.
+type EventHandler interface {
+	HandleEvent(payload []byte) (*CallbackEvent, error)
+}
+
+// IngestEvent normalizes a Stripe event delivered by Amazon EventBridge, as a CloudEvent,
+// or bare, with the same pipeline HandleWebhook uses, so consumers do not depend on the
+// transport. body is unwrapped with UnwrapEvent.
+//
+// No signature is verified: only pass events from a transport that authenticates its
+// sender, e.g. an EventBridge rule on the Stripe partner event bus feeding SQS or Lambda.
+func IngestEvent(h EventHandler, body []byte) (*CallbackEvent, error) {
+	event, err := UnwrapEvent(body)
+	if err != nil {
+		return nil, err
+	}
+	return h.HandleEvent(event)
+}
+
+// eventEnvelope holds the fields UnwrapEvent uses to tell the formats apart.
+type eventEnvelope struct {
+	// Object is "event" for Stripe events and "v2.core.event" for thin events.
+	Object string `json:"object"`
+	// Detail is the payload of an EventBridge event.
+	Detail json.RawMessage `json:"detail"`
+	// SpecVersion marks a CloudEvent in structured mode, whose payload is Data or, for
+	// binary payloads, DataBase64.
+	SpecVersion string          `json:"specversion"`
+	Data        json.RawMessage `json:"data"`
+	DataBase64  string          `json:"data_base64"`
+}
+
+// UnwrapEvent returns the Stripe event inside an Amazon EventBridge event (in its detail)
+// or a CloudEvent in structured JSON mode (in its data or data_base64). A bare Stripe
+// event, e.g. the body of a CloudEvent in binary mode, is returned as is.
+func UnwrapEvent(body []byte) ([]byte, error) {
+	for depth := 0; depth < 2; depth++ {
+		var envelope eventEnvelope
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return nil, fmt.Errorf("failed to parse event envelope: %w", err)
+		}
+		switch {
+		case envelope.Object == "event":
+			return body, nil
+		case envelope.Object == "v2.core.event":
+			return nil, ErrThinEvent
+		case len(envelope.Detail) > 0:
+			body = envelope.Detail
+		case envelope.SpecVersion != "" && len(envelope.Data) > 0:
+			body = envelope.Data
+		case envelope.SpecVersion != "" && envelope.DataBase64 != "":
+			data, err := base64.StdEncoding.DecodeString(envelope.DataBase64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode CloudEvent data_base64: %w", err)
+			}
+			body = data
+		default:
+			return nil, ErrUnknownEnvelope
+		}
+	}
+	return nil, ErrUnknownEnvelope
+}
@@ -0,0 +1,30 @@
+package gomultistripe
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestUnwrapEvent(t *testing.T) {
+	event := `{"id":"evt_1","object":"event","type":"invoice.paid"}`
+	for name, body := range map[string]string{
+		"bare":        event,
+		"eventbridge": `{"version":"0","detail-type":"invoice.paid","source":"aws.partner/stripe.com/ed_1","detail":` + event + `}`,
+		"cloudevent":  `{"specversion":"1.0","type":"com.stripe.invoice.paid","source":"stripe","id":"evt_1","data":` + event + `}`,
+		"cloudevent base64": `{"specversion":"1.0","type":"com.stripe.invoice.paid","source":"stripe","id":"evt_1","data_base64":"` +
+			base64.StdEncoding.EncodeToString([]byte(event)) + `"}`,
+	} {
+		got, err := UnwrapEvent([]byte(body))
+		if err != nil || string(got) != event {
+			t.Errorf("%s: got %s, %v", name, got, err)
+		}
+	}
+
+	if _, err := UnwrapEvent([]byte(`{"detail":{"id":"evt_1","object":"v2.core.event","type":"v1.billing.meter.no_meter_found"}}`)); !errors.Is(err, ErrThinEvent) {
+		t.Errorf("thin event: got %v", err)
+	}
+	if _, err := UnwrapEvent([]byte(`{"detail":{"detail":{"detail":{}}}}`)); !errors.Is(err, ErrUnknownEnvelope) {
+		t.Errorf("nested envelopes: got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package gomultistripe
+
+import "time"
+
+// ExportObject names a Stripe object type that ListForExport can page through.
+type ExportObject string
+
+const (
+	ExportCharges        ExportObject = "charge"
+	ExportPaymentIntents ExportObject = "payment_intent"
+	ExportInvoices       ExportObject = "invoice"
+)
+
+// MaxExportPageSize is the largest page Stripe returns from a list request.
+const MaxExportPageSize = 100
+
+// ExportQuery selects one page of objects created in [CreatedFrom, CreatedTo). Zero times
+// leave that end of the range open.
+type ExportQuery struct {
+	Object      ExportObject
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Limit is the page size; zero or anything above MaxExportPageSize means the maximum.
+	Limit int64
+	// StartingAfter is the NextCursor of the previous page.
+	StartingAfter string
+}
+
+// ExportRecord is one exported charge, payment intent or invoice. Its fields form the
+// stable column schema of exports, shared by every object type; fields that do not apply to
+// an object type are left empty.
+type ExportRecord struct {
+	Object     ExportObject
+	ID         string
+	Created    time.Time
+	Livemode   bool
+	CustomerID string
+	Currency   string
+	// Amount is the charge amount, the intended payment amount or the invoice total.
+	Amount int64
+	// AmountReceived is the amount captured (charges), received (payment intents) or paid
+	// (invoices).
+	AmountReceived int64
+	// AmountRefunded is set for charges.
+	AmountRefunded int64
+	Status         string
+	// PaymentIntentID is set for charges, and for invoices before the basil API version.
+	PaymentIntentID string
+	// ChargeID is the latest charge of a payment intent, or the charge of an invoice before
+	// the basil API version.
+	ChargeID string
+	// InvoiceID is set for charges and payment intents before the basil API version.
+	InvoiceID            string
+	BalanceTransactionID string
+	Description          string
+	Metadata             map[string]string
+}
+
+// ExportPage is one page of export records, oldest last as returned by Stripe.
+type ExportPage struct {
+	Records []ExportRecord
+	HasMore bool
+	// NextCursor is passed as ExportQuery.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,234 @@
+package export
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// AccountingFormat is the CSV import format of an accounting system.
+type AccountingFormat string
+
+const (
+	// QuickBooksOnline writes invoices for QuickBooks Online's invoice import and payouts as
+	// a three-column bank statement (Date, Description, Amount), with US dates.
+	QuickBooksOnline AccountingFormat = "quickbooks"
+	// Xero writes invoices for Xero's sales invoice import and payouts for its bank statement
+	// import, with day-first dates.
+	Xero AccountingFormat = "xero"
+)
+
+// Column headers of the accounting formats, in the names the imports map automatically.
+var (
+	QuickBooksInvoiceColumns = []string{
+		"InvoiceNo", "Customer", "InvoiceDate", "DueDate", "Memo",
+		"Item(Product/Service)", "ItemDescription", "ItemQuantity", "ItemRate", "ItemAmount", "Currency",
+	}
+	QuickBooksBankColumns = []string{"Date", "Description", "Amount"}
+	XeroInvoiceColumns    = []string{
+		"*ContactName", "EmailAddress", "*InvoiceNumber", "Reference", "*InvoiceDate", "*DueDate",
+		"*Description", "*Quantity", "*UnitAmount", "*AccountCode", "*TaxType", "Currency",
+	}
+	XeroBankColumns = []string{"*Date", "*Amount", "Payee", "Description", "Reference"}
+)
+
+// AccountingExporter writes a period's invoices and payouts as CSV files that QuickBooks
+// Online and Xero import: invoices as sales invoices, one row per line, and paid payouts
+// as deposits on the bank account's statement, to match against the bank feed.
+type AccountingExporter struct {
+	Handler gomultistripe.Handler
+	Format  AccountingFormat
+	// Item is the QuickBooks product or service invoice lines are booked to. Defaults to
+	// "Sales".
+	Item string
+	// AccountCode and TaxType are the Xero account and tax rate of invoice lines. They
+	// default to "200" and "Tax Exempt", Xero's sales account and zero rate.
+	AccountCode string
+	TaxType     string
+	// Interval is the minimum time between page requests. Zero sends requests back to back.
+	Interval time.Duration
+}
+
+// ExportInvoices writes the finalized invoices created in [from, to) to w and returns the
+// number of invoices written. Drafts and voided invoices are skipped.
+func (e *AccountingExporter) ExportInvoices(ctx context.Context, w io.Writer, from, to time.Time) (int, error) {
+	var invoices []*gomultistripe.Invoice
+	err := e.pages(ctx, func(opts *gomultistripe.ListOptions) (bool, string, error) {
+		page, err := e.Handler.ListInvoices(ctx, "", "", gomultistripe.DateRange{From: from, To: to}, opts)
+		if err != nil {
+			return false, "", err
+		}
+		for _, inv := range page.Invoices {
+			if inv.Status == gomultistripe.InvoiceDraft || inv.Status == gomultistripe.InvoiceVoid {
+				continue
+			}
+			if inv.LinesHasMore {
+				if inv, err = e.Handler.RetrieveInvoice(ctx, inv.ID); err != nil {
+					return false, "", err
+				}
+			}
+			invoices = append(invoices, inv)
+		}
+		return page.HasMore, page.NextCursor, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(invoices), e.WriteInvoices(w, invoices)
+}
+
+// ExportPayouts writes the paid payouts that arrived in [from, to) to w and returns the
+// number of payouts written.
+func (e *AccountingExporter) ExportPayouts(ctx context.Context, w io.Writer, from, to time.Time) (int, error) {
+	query := gomultistripe.PayoutQuery{Status: "paid", ArrivalFrom: from}
+	if !to.IsZero() {
+		query.ArrivalTo = to.Add(-time.Second)
+	}
+	var payouts []*gomultistripe.Payout
+	err := e.pages(ctx, func(opts *gomultistripe.ListOptions) (bool, string, error) {
+		page, err := e.Handler.ListPayouts(ctx, query, opts)
+		if err != nil {
+			return false, "", err
+		}
+		payouts = append(payouts, page.Payouts...)
+		return page.HasMore, page.NextCursor, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(payouts), e.WritePayouts(w, payouts)
+}
+
+// WriteInvoices writes invoices in the exporter's format, for invoices loaded otherwise.
+// Each line is a row with quantity 1; invoices without lines get one row for their total.
+func (e *AccountingExporter) WriteInvoices(w io.Writer, invoices []*gomultistripe.Invoice) error {
+	cw := csv.NewWriter(w)
+	columns, err := e.columns(QuickBooksInvoiceColumns, XeroInvoiceColumns)
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, inv := range invoices {
+		number := cmp.Or(inv.Number, inv.ID)
+		lines := inv.Lines
+		if len(lines) == 0 {
+			lines = []gomultistripe.InvoiceLine{{Amount: inv.Total, Currency: inv.Currency, Description: number}}
+		}
+		created := inv.CreatedAt
+		due := created
+		if inv.DueDate != 0 {
+			due = time.Unix(inv.DueDate, 0)
+		}
+		for _, l := range lines {
+			amount := gomultistripe.FormatAmount(l.Amount, inv.Currency)
+			var row []string
+			if e.Format == Xero {
+				row = []string{
+					contactName(inv), inv.CustomerEmail, number, inv.ID, xeroDate(created), xeroDate(due),
+					l.Description, "1", amount, cmp.Or(e.AccountCode, "200"), cmp.Or(e.TaxType, "Tax Exempt"), strings.ToUpper(inv.Currency),
+				}
+			} else {
+				row = []string{
+					number, contactName(inv), quickBooksDate(created), quickBooksDate(due), inv.ID,
+					cmp.Or(e.Item, "Sales"), l.Description, "1", amount, amount, strings.ToUpper(inv.Currency),
+				}
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePayouts writes payouts as bank statement deposits in the exporter's format.
+// Failed and canceled payouts, which never reached the bank, are skipped.
+func (e *AccountingExporter) WritePayouts(w io.Writer, payouts []*gomultistripe.Payout) error {
+	cw := csv.NewWriter(w)
+	columns, err := e.columns(QuickBooksBankColumns, XeroBankColumns)
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, po := range payouts {
+		if po.Status == "failed" || po.Status == "canceled" {
+			continue
+		}
+		description := cmp.Or(po.StatementDescriptor, "Stripe payout")
+		amount := gomultistripe.FormatAmount(po.Amount, po.Currency)
+		var row []string
+		if e.Format == Xero {
+			row = []string{xeroDate(po.ArrivalDate), amount, "Stripe", description, po.ID}
+		} else {
+			row = []string{quickBooksDate(po.ArrivalDate), description + " " + po.ID, amount}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// columns returns the header of the exporter's format.
+func (e *AccountingExporter) columns(quickBooks, xero []string) ([]string, error) {
+	switch e.Format {
+	case QuickBooksOnline:
+		return quickBooks, nil
+	case Xero:
+		return xero, nil
+	}
+	return nil, fmt.Errorf("unknown accounting format %q (want quickbooks or xero)", e.Format)
+}
+
+// pages calls list with successive cursors until it reports no more pages, waiting
+// Interval between requests.
+func (e *AccountingExporter) pages(ctx context.Context, list func(*gomultistripe.ListOptions) (bool, string, error)) error {
+	if _, err := e.columns(nil, nil); err != nil {
+		return err
+	}
+	var tick <-chan time.Time
+	if e.Interval > 0 {
+		ticker := time.NewTicker(e.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	opts := &gomultistripe.ListOptions{Limit: gomultistripe.MaxExportPageSize}
+	for first := true; ; first = false {
+		if tick != nil && !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-tick:
+			}
+		}
+		hasMore, cursor, err := list(opts)
+		if err != nil {
+			return err
+		}
+		if !hasMore || cursor == "" {
+			return nil
+		}
+		opts.StartingAfter = cursor
+	}
+}
+
+// contactName names an invoice's customer by name, else email, else ID.
+func contactName(inv *gomultistripe.Invoice) string {
+	return cmp.Or(inv.CustomerName, cmp.Or(inv.CustomerEmail, inv.CustomerID))
+}
+
+func quickBooksDate(t time.Time) string { return t.UTC().Format("01/02/2006") }
+
+func xeroDate(t time.Time) string { return t.UTC().Format("02/01/2006") }
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"slices"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// accountingHandler serves one page of invoices and payouts.
+type accountingHandler struct {
+	gomultistripe.Handler
+	invoices      []*gomultistripe.Invoice
+	payouts       []*gomultistripe.Payout
+	payoutQueries []gomultistripe.PayoutQuery
+}
+
+func (h *accountingHandler) ListInvoices(ctx context.Context, customerID string, status gomultistripe.InvoiceStatus, dateRange gomultistripe.DateRange, opts *gomultistripe.ListOptions) (*gomultistripe.InvoicePage, error) {
+	return &gomultistripe.InvoicePage{Invoices: h.invoices}, nil
+}
+
+func (h *accountingHandler) RetrieveInvoice(ctx context.Context, invoiceID string) (*gomultistripe.Invoice, error) {
+	inv := *h.invoices[0]
+	inv.Lines = append(inv.Lines, gomultistripe.InvoiceLine{ID: "il_3", Amount: 250, Description: "Overage"})
+	inv.LinesHasMore = false
+	return &inv, nil
+}
+
+func (h *accountingHandler) ListPayouts(ctx context.Context, query gomultistripe.PayoutQuery, opts *gomultistripe.ListOptions) (*gomultistripe.PayoutPage, error) {
+	h.payoutQueries = append(h.payoutQueries, query)
+	return &gomultistripe.PayoutPage{Payouts: h.payouts}, nil
+}
+
+func readCSV(t *testing.T, buf *bytes.Buffer) [][]string {
+	t.Helper()
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestAccountingExporter(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := &accountingHandler{
+		invoices: []*gomultistripe.Invoice{
+			{
+				ID: "in_1", Number: "INV-0001", CustomerName: "Acme Ltd", CustomerEmail: "ap@acme.test", Status: gomultistripe.InvoicePaid,
+				Currency: "usd", Total: 5250, CreatedAt: created, LinesHasMore: true,
+				Lines: []gomultistripe.InvoiceLine{{ID: "il_1", Amount: 4000, Description: "Pro plan"}, {ID: "il_2", Amount: 1000, Description: "Seats"}},
+			},
+			{ID: "in_2", Status: gomultistripe.InvoiceDraft, Currency: "usd", Total: 100, CreatedAt: created},
+			{ID: "in_3", Number: "INV-0003", CustomerID: "cus_3", Status: gomultistripe.InvoiceOpen, Currency: "jpy", Total: 1200, CreatedAt: created, DueDate: created.AddDate(0, 0, 30).Unix()},
+		},
+		payouts: []*gomultistripe.Payout{
+			{ID: "po_1", Amount: 123456, Currency: "usd", Status: "paid", ArrivalDate: created},
+		},
+	}
+
+	var buf bytes.Buffer
+	n, err := (&AccountingExporter{Handler: h, Format: QuickBooksOnline}).ExportInvoices(context.Background(), &buf, time.Time{}, time.Time{})
+	if err != nil || n != 2 {
+		t.Fatalf("wrote %d invoices: %v", n, err)
+	}
+	rows := readCSV(t, &buf)
+	if len(rows) != 1+3+1 || !slices.Equal(rows[0], QuickBooksInvoiceColumns) {
+		t.Fatalf("unexpected CSV %v", rows)
+	}
+	if want := []string{"INV-0001", "Acme Ltd", "01/02/2025", "01/02/2025", "in_1", "Sales", "Overage", "1", "2.50", "2.50", "USD"}; !slices.Equal(rows[3], want) {
+		t.Errorf("QuickBooks row %q, want %q", rows[3], want)
+	}
+	if got := rows[4]; got[1] != "cus_3" || got[3] != "02/01/2025" || got[9] != "1200" {
+		t.Errorf("QuickBooks yen row %q", got)
+	}
+
+	buf.Reset()
+	if _, err := (&AccountingExporter{Handler: h, Format: Xero, AccountCode: "4000"}).ExportInvoices(context.Background(), &buf, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	rows = readCSV(t, &buf)
+	if want := []string{"Acme Ltd", "ap@acme.test", "INV-0001", "in_1", "02/01/2025", "02/01/2025", "Pro plan", "1", "40.00", "4000", "Tax Exempt", "USD"}; !slices.Equal(rows[1], want) {
+		t.Errorf("Xero row %q, want %q", rows[1], want)
+	}
+
+	buf.Reset()
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	n, err = (&AccountingExporter{Handler: h, Format: Xero}).ExportPayouts(context.Background(), &buf, created, to)
+	if err != nil || n != 1 {
+		t.Fatalf("wrote %d payouts: %v", n, err)
+	}
+	if q := h.payoutQueries[0]; q.Status != "paid" || !q.ArrivalTo.Before(to) {
+		t.Errorf("listed payouts with %+v", q)
+	}
+	rows = readCSV(t, &buf)
+	if want := []string{"02/01/2025", "1234.56", "Stripe", "Stripe payout", "po_1"}; !slices.Equal(rows[1], want) {
+		t.Errorf("Xero payout row %q, want %q", rows[1], want)
+	}
+
+	if err := (&AccountingExporter{Format: "sage"}).WritePayouts(&buf, nil); err == nil {
+		t.Error("wrote an unknown format")
+	}
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(r gomultistripe.ExportRecord) error {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		if err := c.w.Write(Columns); err != nil {
+			return err
+		}
+	}
+	metadata, err := metadataJSON(r.Metadata)
+	if err != nil {
+		return err
+	}
+	return c.w.Write([]string{
+		string(r.Object), r.ID, r.Created.UTC().Format(time.RFC3339), strconv.FormatBool(r.Livemode),
+		r.CustomerID, r.Currency,
+		strconv.FormatInt(r.Amount, 10), strconv.FormatInt(r.AmountReceived, 10), strconv.FormatInt(r.AmountRefunded, 10),
+		r.Status, r.PaymentIntentID, r.ChargeID, r.InvoiceID, r.BalanceTransactionID,
+		r.Description, metadata,
+	})
+}
+
+// Close writes the header if no record was written, so empty exports still have a schema.
+func (c *csvWriter) Close() error {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		if err := c.w.Write(Columns); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// metadataJSON encodes metadata as a JSON object, or "" when there is none.
+func metadataJSON(m map[string]string) (string, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
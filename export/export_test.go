@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/parquet-go/parquet-go"
+)
+
+// pagedHandler serves records two per page.
+type pagedHandler struct {
+	gomultistripe.Handler
+	records []gomultistripe.ExportRecord
+	queries []gomultistripe.ExportQuery
+}
+
+func (h *pagedHandler) ListForExport(ctx context.Context, q gomultistripe.ExportQuery) (*gomultistripe.ExportPage, error) {
+	h.queries = append(h.queries, q)
+	start := 0
+	for i, r := range h.records {
+		if r.ID == q.StartingAfter {
+			start = i + 1
+		}
+	}
+	end := min(start+2, len(h.records))
+	page := &gomultistripe.ExportPage{Records: h.records[start:end], HasMore: end < len(h.records)}
+	if page.HasMore {
+		page.NextCursor = h.records[end-1].ID
+	}
+	return page, nil
+}
+
+func testRecords() []gomultistripe.ExportRecord {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []gomultistripe.ExportRecord{
+		{Object: gomultistripe.ExportCharges, ID: "ch_1", Created: created, Amount: 1000, AmountReceived: 1000, Currency: "usd", Metadata: map[string]string{"order": "42"}},
+		{Object: gomultistripe.ExportCharges, ID: "ch_2", Created: created, Amount: 500, AmountRefunded: 500, Currency: "usd"},
+		{Object: gomultistripe.ExportCharges, ID: "ch_3", Created: created, Amount: 250, Currency: "eur"},
+	}
+}
+
+func TestExport_CSVPaginates(t *testing.T) {
+	h := &pagedHandler{records: testRecords()}
+	var buf bytes.Buffer
+	n, err := (&Exporter{Handler: h}).Export(context.Background(), &buf, CSV, gomultistripe.ExportCharges, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(h.queries) != 2 || h.queries[1].StartingAfter != "ch_2" {
+		t.Fatalf("wrote %d records in queries %+v", n, h.queries)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || len(rows[0]) != len(Columns) {
+		t.Fatalf("unexpected CSV %v", rows)
+	}
+	if got := rows[1][2]; got != "2025-01-02T03:04:05Z" {
+		t.Errorf("created = %q", got)
+	}
+	if got := rows[1][len(Columns)-1]; got != `{"order":"42"}` {
+		t.Errorf("metadata = %q", got)
+	}
+}
+
+func TestExport_Parquet(t *testing.T) {
+	h := &pagedHandler{records: testRecords()}
+	var buf bytes.Buffer
+	if _, err := (&Exporter{Handler: h}).Export(context.Background(), &buf, Parquet, gomultistripe.ExportCharges, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := parquet.Read[parquetRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1].AmountRefunded != 500 || !rows[0].Created.Equal(testRecords()[0].Created) {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+	fields := parquet.SchemaOf(parquetRow{}).Fields()
+	for i, f := range fields {
+		if f.Name() != Columns[i] {
+			t.Errorf("parquet column %d is %s, want %s", i, f.Name(), Columns[i])
+		}
+	}
+}
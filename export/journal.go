@@ -0,0 +1,105 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// JournalColumns is the column order of journal CSV files, one row per journal line.
+// Columns are only ever appended.
+var JournalColumns = []string{
+	"entry_id", "date", "currency", "category", "reference", "description",
+	"account", "debit", "credit",
+}
+
+// JournalExporter pages through balance transactions with Handler.ListBalanceTransactions
+// and maps them to journal entries, for ERPs that import Stripe activity as double-entry
+// bookkeeping.
+type JournalExporter struct {
+	Handler gomultistripe.Handler
+	// Mapper maps each transaction. Defaults to
+	// gomultistripe.NewLedgerMapper(gomultistripe.DefaultLedgerAccounts).
+	Mapper gomultistripe.LedgerMapper
+	// Interval is the minimum time between page requests. Zero sends requests back to back.
+	Interval time.Duration
+}
+
+// Stream calls fn with the entries of every balance transaction created in [from, to),
+// newest first as Stripe lists them. A zero from or to leaves that end of the range open.
+// It stops at the first error fn returns.
+func (e *JournalExporter) Stream(ctx context.Context, from, to time.Time, fn func(gomultistripe.JournalEntry) error) error {
+	mapper := e.Mapper
+	if mapper == nil {
+		mapper = gomultistripe.NewLedgerMapper(gomultistripe.DefaultLedgerAccounts)
+	}
+	var tick <-chan time.Time
+	if e.Interval > 0 {
+		ticker := time.NewTicker(e.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	query := gomultistripe.BalanceTransactionQuery{CreatedFrom: from, CreatedTo: to, ExpandSources: true}
+	opts := &gomultistripe.ListOptions{Limit: gomultistripe.MaxExportPageSize}
+	for first := true; ; first = false {
+		if tick != nil && !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-tick:
+			}
+		}
+		page, err := e.Handler.ListBalanceTransactions(ctx, query, opts)
+		if err != nil {
+			return err
+		}
+		for _, txn := range page.Transactions {
+			entries, err := mapper.Map(txn)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if err := fn(entry); err != nil {
+					return err
+				}
+			}
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			return nil
+		}
+		opts.StartingAfter = page.NextCursor
+	}
+}
+
+// Export writes the journal lines of every balance transaction created in [from, to) to w
+// as CSV with JournalColumns, and returns the number of entries written. Amounts are in the
+// currency's smallest unit and dates RFC 3339 UTC.
+func (e *JournalExporter) Export(ctx context.Context, w io.Writer, from, to time.Time) (int, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(JournalColumns); err != nil {
+		return 0, err
+	}
+	n := 0
+	err := e.Stream(ctx, from, to, func(entry gomultistripe.JournalEntry) error {
+		n++
+		for _, l := range entry.Lines {
+			err := cw.Write([]string{
+				entry.ID, entry.Date.UTC().Format(time.RFC3339), entry.Currency, entry.Category, entry.Reference, entry.Description,
+				l.Account, strconv.FormatInt(l.Debit, 10), strconv.FormatInt(l.Credit, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	cw.Flush()
+	if err != nil {
+		return n, err
+	}
+	return n, cw.Error()
+}
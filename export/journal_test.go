@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// balanceHandler serves balance transactions two per page.
+type balanceHandler struct {
+	gomultistripe.Handler
+	txns    []*gomultistripe.BalanceTransaction
+	queries []gomultistripe.BalanceTransactionQuery
+}
+
+func (h *balanceHandler) ListBalanceTransactions(ctx context.Context, query gomultistripe.BalanceTransactionQuery, opts *gomultistripe.ListOptions) (*gomultistripe.BalanceTransactionPage, error) {
+	h.queries = append(h.queries, query)
+	start := 0
+	for i, txn := range h.txns {
+		if txn.ID == opts.StartingAfter {
+			start = i + 1
+		}
+	}
+	end := min(start+2, len(h.txns))
+	page := &gomultistripe.BalanceTransactionPage{Transactions: h.txns[start:end], HasMore: end < len(h.txns)}
+	if page.HasMore {
+		page.NextCursor = h.txns[end-1].ID
+	}
+	return page, nil
+}
+
+func TestJournalExporter_CSV(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := &balanceHandler{txns: []*gomultistripe.BalanceTransaction{
+		{ID: "txn_ch", ReportingCategory: "charge", Amount: 10000, Fee: 320, Net: 9680, Currency: "usd", PaymentIntentID: "pi_1", CreatedAt: created},
+		{ID: "txn_re", ReportingCategory: "refund", Amount: -2000, Net: -2000, Currency: "usd", PaymentIntentID: "pi_1", CreatedAt: created},
+		{ID: "txn_po", ReportingCategory: "payout", Amount: -7680, Net: -7680, Currency: "usd", SourceID: "po_1", CreatedAt: created},
+	}}
+	from, to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	n, err := (&JournalExporter{Handler: h}).Export(context.Background(), &buf, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || len(h.queries) != 2 || !h.queries[0].CreatedFrom.Equal(from) || !h.queries[0].ExpandSources {
+		t.Fatalf("wrote %d entries in queries %+v", n, h.queries)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1+3+2+2 || len(rows[0]) != len(JournalColumns) {
+		t.Fatalf("unexpected CSV %v", rows)
+	}
+	if got := rows[3]; got[0] != "txn_ch" || got[1] != "2025-01-02T03:04:05Z" || got[4] != "pi_1" || got[6] != "revenue" || got[8] != "10000" {
+		t.Errorf("charge credit row %q", got)
+	}
+	if got := rows[7]; got[0] != "txn_po" || got[4] != "po_1" || got[6] != "bank" || got[7] != "7680" {
+		t.Errorf("payout debit row %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRow is the Parquet schema. Its columns match Columns, in the same order.
+type parquetRow struct {
+	Object               string    `parquet:"object"`
+	ID                   string    `parquet:"id"`
+	Created              time.Time `parquet:"created,timestamp(millisecond)"`
+	Livemode             bool      `parquet:"livemode"`
+	CustomerID           string    `parquet:"customer_id"`
+	Currency             string    `parquet:"currency"`
+	Amount               int64     `parquet:"amount"`
+	AmountReceived       int64     `parquet:"amount_received"`
+	AmountRefunded       int64     `parquet:"amount_refunded"`
+	Status               string    `parquet:"status"`
+	PaymentIntentID      string    `parquet:"payment_intent_id"`
+	ChargeID             string    `parquet:"charge_id"`
+	InvoiceID            string    `parquet:"invoice_id"`
+	BalanceTransactionID string    `parquet:"balance_transaction_id"`
+	Description          string    `parquet:"description"`
+	Metadata             string    `parquet:"metadata"`
+}
+
+type parquetWriter struct {
+	w *parquet.GenericWriter[parquetRow]
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{w: parquet.NewGenericWriter[parquetRow](w, parquet.Compression(&parquet.Zstd))}
+}
+
+func (p *parquetWriter) Write(r gomultistripe.ExportRecord) error {
+	metadata, err := metadataJSON(r.Metadata)
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write([]parquetRow{{
+		Object:               string(r.Object),
+		ID:                   r.ID,
+		Created:              r.Created.UTC(),
+		Livemode:             r.Livemode,
+		CustomerID:           r.CustomerID,
+		Currency:             r.Currency,
+		Amount:               r.Amount,
+		AmountReceived:       r.AmountReceived,
+		AmountRefunded:       r.AmountRefunded,
+		Status:               r.Status,
+		PaymentIntentID:      r.PaymentIntentID,
+		ChargeID:             r.ChargeID,
+		InvoiceID:            r.InvoiceID,
+		BalanceTransactionID: r.BalanceTransactionID,
+		Description:          r.Description,
+		Metadata:             metadata,
+	}})
+	return err
+}
+
+func (p *parquetWriter) Close() error {
+	return p.w.Close()
+}
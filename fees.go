@@ -0,0 +1,128 @@
+package gomultistripe
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoPricing is matched by the errors EstimateFees returns for a currency its pricing
+// table has no fixed fee for.
+var ErrNoPricing = errors.New("no pricing for currency")
+
+// PricingTable is the card pricing model EstimateFees applies. Percentages are in percent,
+// e.g. 2.9 for 2.9%. Stripe's published rates differ by country and change over time, and
+// accounts on custom pricing have their own, so configure the table from the account's
+// pricing page.
+type PricingTable struct {
+	// Percent and Fixed are the fee for domestic cards: Percent of the amount plus the
+	// fixed fee in the minor unit of the charge's lowercase currency.
+	Percent float64
+	Fixed   map[string]int64
+	// DomesticCountries lists the card countries charged the domestic rate, e.g. the
+	// account's country or, for European accounts, the EEA. Cards issued elsewhere add
+	// InternationalPercent.
+	DomesticCountries    []string
+	InternationalPercent float64
+	// SettlementCurrency is the account's default currency. Charges in other currencies
+	// add ConversionPercent for converting them. Empty skips the conversion fee.
+	SettlementCurrency string
+	ConversionPercent  float64
+}
+
+// USPricing is Stripe's standard card pricing for US accounts: 2.9% + 30¢, plus 1.5% for
+// international cards and 1% for currency conversion. Copy it and add Fixed fees to
+// estimate charges in other currencies.
+var USPricing = PricingTable{
+	Percent:              2.9,
+	Fixed:                map[string]int64{"usd": 30},
+	DomesticCountries:    []string{"US"},
+	InternationalPercent: 1.5,
+	SettlementCurrency:   "usd",
+	ConversionPercent:    1,
+}
+
+// FeeEstimate is the expected Stripe fee on a card payment, in the charge's currency.
+type FeeEstimate struct {
+	Amount   int64
+	Currency string
+	// Processing is the domestic rate, International the surcharge for a foreign card and
+	// Conversion the currency conversion fee. Total is their sum, and Net what remains of
+	// Amount.
+	Processing    int64
+	International int64
+	Conversion    int64
+	Total         int64
+	Net           int64
+}
+
+// EstimateFees computes the fee Stripe is expected to take from a card payment of amount
+// in currency, paid with a card issued in cardCountry, under pricing. An empty cardCountry
+// is treated as domestic. Each fee component is rounded to the nearest minor unit, as
+// Stripe does, so the estimate matches the balance transaction's fee unless the account
+// has other fees, such as Radar or Connect fees.
+func EstimateFees(amount int64, currency, cardCountry string, pricing *PricingTable) (*FeeEstimate, error) {
+	currency = strings.ToLower(currency)
+	fixed, ok := pricing.Fixed[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoPricing, currency)
+	}
+	percent := func(p float64) int64 {
+		return int64(math.Round(float64(amount) * p / 100))
+	}
+	est := &FeeEstimate{
+		Amount:     amount,
+		Currency:   currency,
+		Processing: percent(pricing.Percent) + fixed,
+	}
+	if cardCountry != "" && !slices.ContainsFunc(pricing.DomesticCountries, func(c string) bool {
+		return strings.EqualFold(c, cardCountry)
+	}) {
+		est.International = percent(pricing.InternationalPercent)
+	}
+	if pricing.SettlementCurrency != "" && !strings.EqualFold(pricing.SettlementCurrency, currency) {
+		est.Conversion = percent(pricing.ConversionPercent)
+	}
+	est.Total = est.Processing + est.International + est.Conversion
+	est.Net = amount - est.Total
+	return est, nil
+}
+
+// zeroDecimalCurrencies and threeDecimalCurrencies are the currencies whose Stripe amounts
+// are not in hundredths.
+var (
+	zeroDecimalCurrencies = []string{
+		"bif", "clp", "djf", "gnf", "jpy", "kmf", "krw", "mga", "pyg", "rwf", "ugx", "vnd", "vuv", "xaf", "xof", "xpf",
+	}
+	threeDecimalCurrencies = []string{"bhd", "jod", "kwd", "omr", "tnd"}
+)
+
+// FormatAmount formats an amount in the currency's smallest unit as a decimal number, e.g.
+// 1234.56 for 123456 in usd and 1200 for 1200 in jpy.
+func FormatAmount(amount int64, currency string) string {
+	decimals := currencyDecimals(currency)
+	if decimals == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
+
+// currencyDecimals returns the number of decimals of the currency's smallest unit.
+func currencyDecimals(currency string) int {
+	currency = strings.ToLower(currency)
+	switch {
+	case slices.Contains(zeroDecimalCurrencies, currency):
+		return 0
+	case slices.Contains(threeDecimalCurrencies, currency):
+		return 3
+	}
+	return 2
+}
@@ -0,0 +1,52 @@
+package gomultistripe
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestEstimateFees(t *testing.T) {
+	est, err := EstimateFees(10000, "USD", "us", &USPricing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if est.Processing != 320 || est.International != 0 || est.Conversion != 0 || est.Total != 320 || est.Net != 9680 {
+		t.Errorf("domestic %+v", est)
+	}
+
+	est, _ = EstimateFees(10000, "usd", "GB", &USPricing)
+	if est.International != 150 || est.Total != 470 {
+		t.Errorf("international %+v", est)
+	}
+
+	// 2.9% of 1999 is 57.971, rounded like Stripe does.
+	eur := USPricing
+	eur.Fixed = maps.Clone(USPricing.Fixed)
+	eur.Fixed["eur"] = 30
+	est, _ = EstimateFees(1999, "eur", "DE", &eur)
+	if est.Processing != 88 || est.International != 30 || est.Conversion != 20 || est.Net != 1999-138 {
+		t.Errorf("converted %+v", est)
+	}
+
+	if _, err := EstimateFees(1000, "gbp", "GB", &USPricing); !errors.Is(err, ErrNoPricing) {
+		t.Errorf("gbp: %v", err)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	for _, tt := range []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{123456, "usd", "1234.56"},
+		{-5, "eur", "-0.05"},
+		{1200, "JPY", "1200"},
+		{1500, "kwd", "1.500"},
+	} {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%d, %s) = %s, want %s", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
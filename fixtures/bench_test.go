@@ -0,0 +1,80 @@
+package fixtures_test
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/iqhive/gomultistripe/fixtures"
+)
+
+// BenchmarkHandleWebhook measures parsing one signed event of each type the lifecycle
+// scenario delivers, per handler version.
+func BenchmarkHandleWebhook(b *testing.B) {
+	for _, h := range allHandlers() {
+		h.SetWebhookSecret("whsec_fixture")
+		events, err := fixtures.SubscriptionLifecycle().Events(fixtures.Options{Secret: "whsec_fixture", APIVersion: h.APIVersion()})
+		if err != nil {
+			b.Fatal(err)
+		}
+		seen := map[string]bool{}
+		for _, evt := range events {
+			if seen[evt.Type] {
+				continue
+			}
+			seen[evt.Type] = true
+			if _, err := h.HandleWebhook(evt.Payload, evt.Signature); errors.Is(err, gomultistripe.ErrUnknownEventType) {
+				continue
+			}
+			b.Run(h.Version()+"/"+evt.Type, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(evt.Payload)))
+				for b.Loop() {
+					if _, err := h.HandleWebhook(evt.Payload, evt.Signature); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkHandleWebhook_LargeInvoice measures parsing an invoice event carrying thousands
+// of lines, the case SplitInvoiceLines streams.
+func BenchmarkHandleWebhook_LargeInvoice(b *testing.B) {
+	largeInvoice := fixtures.Scenario{Name: "large-invoice", Steps: []fixtures.Step{{
+		Type: "invoice.created",
+		Object: func(s *fixtures.State) map[string]any {
+			inv := fixtures.InvoiceObject(s, "in_fixture_large", 0, false)
+			lines := inv["lines"].(map[string]any)
+			line := lines["data"].([]any)[0].(map[string]any)
+			data := make([]any, 5000)
+			for i := range data {
+				l := maps.Clone(line)
+				l["id"] = fmt.Sprintf("il_%d", i)
+				data[i] = l
+			}
+			lines["data"] = data
+			return inv
+		},
+	}}}
+	for _, h := range allHandlers() {
+		h.SetWebhookSecret("whsec_fixture")
+		events, err := largeInvoice.Events(fixtures.Options{Secret: "whsec_fixture", APIVersion: h.APIVersion()})
+		if err != nil {
+			b.Fatal(err)
+		}
+		evt := events[0]
+		b.Run(h.Version(), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(evt.Payload)))
+			for b.Loop() {
+				if _, err := h.HandleWebhook(evt.Payload, evt.Signature); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
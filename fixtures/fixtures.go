@@ -0,0 +1,189 @@
+// Package fixtures generates ordered, signed Stripe webhook events so that consumers and
+// their state machines can be tested end to end, or demoed, without a Stripe account.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// Event is one signed webhook delivery.
+type Event struct {
+	ID   string
+	Type string
+	// Created is the simulated time of the event, also used as its created timestamp.
+	Created time.Time
+	Payload []byte
+	// Signature is the Stripe-Signature header for Payload.
+	Signature string
+}
+
+// Options parameterizes a scenario. Only Secret is required when calling Replay.
+type Options struct {
+	// Secret is the webhook signing secret the receiving handler is configured with.
+	Secret string
+	// APIVersion is the api_version of the events. It must match the receiving handler's
+	// APIVersion(); Replay fills it in.
+	APIVersion string
+	// Start is the simulated time of the first event. Defaults to now.
+	Start time.Time
+	// Now returns the signing time. Signatures must be fresh to pass tolerance checks, so
+	// it defaults to time.Now regardless of the simulated event times.
+	Now func() time.Time
+
+	CustomerID     string
+	SubscriptionID string
+	PriceID        string
+	// Amount is the price per period in the currency's smallest unit. Defaults to 1000.
+	Amount   int64
+	Currency string
+	// TrialDays is the length of the trial. Defaults to 14.
+	TrialDays int
+}
+
+func (o Options) withDefaults() Options {
+	if o.Start.IsZero() {
+		o.Start = time.Now()
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+	if o.CustomerID == "" {
+		o.CustomerID = "cus_fixture"
+	}
+	if o.SubscriptionID == "" {
+		o.SubscriptionID = "sub_fixture"
+	}
+	if o.PriceID == "" {
+		o.PriceID = "price_fixture"
+	}
+	if o.Amount == 0 {
+		o.Amount = 1000
+	}
+	if o.Currency == "" {
+		o.Currency = "usd"
+	}
+	if o.TrialDays == 0 {
+		o.TrialDays = 14
+	}
+	return o
+}
+
+// Step is one event of a scenario.
+type Step struct {
+	// Type is the Stripe event type.
+	Type string
+	// At returns the simulated time of the event. Nil keeps the previous step's time.
+	At func(s *State) time.Time
+	// Object builds the event's data.object.
+	Object func(s *State) map[string]any
+	// Previous optionally builds data.previous_attributes, the changed fields' old values,
+	// for *.updated events. It is called before Object.
+	Previous func(s *State) map[string]any
+}
+
+// State is the simulated account state a scenario's steps read and update.
+type State struct {
+	Options
+	// Now is the simulated time of the step being built.
+	Now time.Time
+	// SubscriptionStatus is the current status of the simulated subscription.
+	SubscriptionStatus string
+	// Quantity is the quantity of the subscription's item, initially 1. Steps may change it,
+	// and PriceID, to simulate seat and plan changes.
+	Quantity int64
+	// TrialEnd is the end of the simulated trial.
+	TrialEnd time.Time
+	// CanceledAt is set once the subscription is canceled.
+	CanceledAt time.Time
+}
+
+// AfterStart returns a Step.At for d after Options.Start.
+func AfterStart(d time.Duration) func(s *State) time.Time {
+	return func(s *State) time.Time { return s.Start.Add(d) }
+}
+
+// AfterTrialEnd returns a Step.At for d after the end of the trial; d may be negative.
+func AfterTrialEnd(d time.Duration) func(s *State) time.Time {
+	return func(s *State) time.Time { return s.TrialEnd.Add(d) }
+}
+
+// Scenario is an ordered sequence of steps.
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Events builds and signs the scenario's events, in order.
+func (sc Scenario) Events(opts Options) ([]Event, error) {
+	if opts.APIVersion == "" {
+		return nil, errors.New("fixtures: Options.APIVersion is required")
+	}
+	opts = opts.withDefaults()
+	state := &State{Options: opts, Now: opts.Start, Quantity: 1, TrialEnd: opts.Start.AddDate(0, 0, opts.TrialDays)}
+	events := make([]Event, 0, len(sc.Steps))
+	for i, step := range sc.Steps {
+		if step.At != nil {
+			state.Now = step.At(state)
+		}
+		evt := Event{
+			ID:      fmt.Sprintf("evt_%s_%02d", sc.Name, i+1),
+			Type:    step.Type,
+			Created: state.Now,
+		}
+		data := map[string]any{}
+		if step.Previous != nil {
+			data["previous_attributes"] = step.Previous(state)
+		}
+		data["object"] = step.Object(state)
+		payload, err := json.Marshal(map[string]any{
+			"id":               evt.ID,
+			"object":           "event",
+			"api_version":      opts.APIVersion,
+			"created":          state.Now.Unix(),
+			"type":             step.Type,
+			"livemode":         false,
+			"pending_webhooks": 1,
+			"request":          map[string]any{"id": nil, "idempotency_key": nil},
+			"data":             data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fixtures: encoding %s: %w", step.Type, err)
+		}
+		evt.Payload = payload
+		evt.Signature = gomultistripe.SignPayload(payload, opts.Secret, opts.Now())
+		events = append(events, evt)
+	}
+	return events, nil
+}
+
+// Replay delivers the scenario's events in order to h.HandleWebhook, as Stripe would, and
+// passes each normalized event to consumer. Events of types the handler does not normalize
+// (such as checkout.session.completed) are skipped. h must be configured with opts.Secret.
+func (sc Scenario) Replay(ctx context.Context, h gomultistripe.Handler, opts Options, consumer gomultistripe.EventConsumer) error {
+	if opts.APIVersion == "" {
+		opts.APIVersion = h.APIVersion()
+	}
+	events, err := sc.Events(opts)
+	if err != nil {
+		return err
+	}
+	for _, evt := range events {
+		cb, err := h.HandleWebhook(evt.Payload, evt.Signature)
+		if errors.Is(err, gomultistripe.ErrUnknownEventType) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("fixtures: %s (%s): %w", evt.ID, evt.Type, err)
+		}
+		if err := consumer(ctx, cb); err != nil {
+			return fmt.Errorf("fixtures: consumer failed on %s (%s): %w", evt.ID, evt.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package fixtures_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/iqhive/gomultistripe/fixtures"
+	v74 "github.com/iqhive/gomultistripe/v74"
+	v75 "github.com/iqhive/gomultistripe/v75"
+	v76 "github.com/iqhive/gomultistripe/v76"
+	v78 "github.com/iqhive/gomultistripe/v78"
+	v79 "github.com/iqhive/gomultistripe/v79"
+	v80 "github.com/iqhive/gomultistripe/v80"
+	v81 "github.com/iqhive/gomultistripe/v81"
+	v82 "github.com/iqhive/gomultistripe/v82"
+)
+
+func allHandlers() []gomultistripe.Handler {
+	return []gomultistripe.Handler{
+		v74.NewHandler(), v75.NewHandler(), v76.NewHandler(), v78.NewHandler(),
+		v79.NewHandler(), v80.NewHandler(), v81.NewHandler(), v82.NewHandler(),
+	}
+}
+
+func TestSubscriptionLifecycle_ReplaysOnEveryVersion(t *testing.T) {
+	want := []gomultistripe.CallbackEventType{
+		gomultistripe.EventCustomerSubscriptionCreated,
+		gomultistripe.EventInvoicePaymentSucceeded,
+		gomultistripe.EventCustomerSubscriptionTrialWillEnd,
+		gomultistripe.EventInvoicePaymentFailed,
+		gomultistripe.EventCustomerSubscriptionUpdated,
+		gomultistripe.EventCustomerSubscriptionDeleted,
+	}
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			var got []*gomultistripe.CallbackEvent
+			err := fixtures.SubscriptionLifecycle().Replay(context.Background(), h, fixtures.Options{Secret: "whsec_fixture"},
+				func(ctx context.Context, evt *gomultistripe.CallbackEvent) error {
+					got = append(got, evt)
+					return nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d events, want %d", len(got), len(want))
+			}
+			for i, evt := range got {
+				if evt.Type != want[i] {
+					t.Errorf("event %d is %s, want %s", i, evt.Type, want[i])
+				}
+			}
+			if last := got[len(got)-1]; last.Status != "canceled" || last.SubscriptionID != "sub_fixture" || last.CanceledAt == 0 {
+				t.Errorf("unexpected final event %+v", last)
+			}
+			if trial := got[2]; trial.DaysRemaining != 3 || trial.TrialEnd == 0 || trial.PriceID != "price_fixture" {
+				t.Errorf("unexpected trial_will_end event %+v", trial)
+			}
+			if failed := got[3]; failed.Amount != 1000 || len(failed.InvoiceLines) != 1 || failed.InvoiceLines[0].SubscriptionID != "sub_fixture" {
+				t.Errorf("unexpected payment_failed event %+v", failed)
+			}
+			if failed := got[3]; failed.AttemptCount != 1 || failed.NextPaymentAttempt == 0 ||
+				failed.LastPaymentErrorDeclineCode != "insufficient_funds" || failed.LastPaymentErrorChargeID != "ch_in_fixture_renewal" {
+				t.Errorf("payment_failed event lacks dunning fields %+v", failed)
+			}
+		})
+	}
+}
+
+func TestSubscriptionUpdated_CarriesItemChanges(t *testing.T) {
+	seatChange := fixtures.Scenario{Name: "seats", Steps: []fixtures.Step{{
+		Type: "customer.subscription.updated",
+		Previous: func(s *fixtures.State) map[string]any {
+			return map[string]any{"items": fixtures.SubscriptionObject(s)["items"]}
+		},
+		Object: func(s *fixtures.State) map[string]any {
+			s.SubscriptionStatus = "active"
+			s.Quantity = 5
+			return fixtures.SubscriptionObject(s)
+		},
+	}}}
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			var evt *gomultistripe.CallbackEvent
+			err := seatChange.Replay(context.Background(), h, fixtures.Options{Secret: "whsec_fixture"},
+				func(ctx context.Context, e *gomultistripe.CallbackEvent) error {
+					evt = e
+					return nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+			changes := evt.ItemChanges()
+			if len(changes) != 1 || !changes[0].QuantityChanged() || changes[0].PriceChanged() ||
+				changes[0].PreviousQuantity != 1 || changes[0].Quantity != 5 || changes[0].PriceID != "price_fixture" {
+				t.Fatalf("unexpected item changes %+v (items %+v, previous %+v)", changes, evt.Items, evt.PreviousItems)
+			}
+		})
+	}
+}
+
+func TestInvoiceWithTruncatedLines_FetchesTheRest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/invoices/in_fixture_long/lines" || r.URL.Query().Get("starting_after") != "in_fixture_long_line" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"object":   "list",
+			"has_more": false,
+			"data": []any{
+				map[string]any{"id": "il_2", "object": "line_item", "amount": 500, "currency": "usd"},
+				map[string]any{"id": "il_3", "object": "line_item", "amount": 250, "currency": "usd"},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	longInvoice := fixtures.Scenario{Name: "long-invoice", Steps: []fixtures.Step{{
+		Type: "invoice.created",
+		Object: func(s *fixtures.State) map[string]any {
+			inv := fixtures.InvoiceObject(s, "in_fixture_long", 1750, false)
+			inv["lines"].(map[string]any)["has_more"] = true
+			return inv
+		},
+	}}}
+	t.Cleanup(func() { gomultistripe.SetFetchAllInvoiceLines(false) })
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			h.SetSecretKey("sk_test_fixture")
+			h.SetEndpoints(gomultistripe.Endpoints{APIURL: srv.URL})
+			defer h.SetEndpoints(gomultistripe.Endpoints{})
+			replay := func() *gomultistripe.CallbackEvent {
+				var evt *gomultistripe.CallbackEvent
+				err := longInvoice.Replay(context.Background(), h, fixtures.Options{Secret: "whsec_fixture"},
+					func(ctx context.Context, e *gomultistripe.CallbackEvent) error {
+						evt = e
+						return nil
+					})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return evt
+			}
+
+			gomultistripe.SetFetchAllInvoiceLines(false)
+			if evt := replay(); len(evt.InvoiceLines) != 1 || !evt.InvoiceLinesHasMore {
+				t.Errorf("without fetching: got %d lines, has more %v", len(evt.InvoiceLines), evt.InvoiceLinesHasMore)
+			}
+			gomultistripe.SetFetchAllInvoiceLines(true)
+			evt := replay()
+			if len(evt.InvoiceLines) != 3 || evt.InvoiceLinesHasMore || evt.InvoiceLines[2].ID != "il_3" {
+				t.Errorf("with fetching: got lines %+v, has more %v", evt.InvoiceLines, evt.InvoiceLinesHasMore)
+			}
+		})
+	}
+}
+
+func TestHandleWebhook_ChecksAPIVersionAndSchema(t *testing.T) {
+	extraField := fixtures.Scenario{Name: "schema", Steps: []fixtures.Step{{
+		Type: "invoice.created",
+		Object: func(s *fixtures.State) map[string]any {
+			inv := fixtures.InvoiceObject(s, "in_fixture_schema", 1000, false)
+			inv["x_future_field"] = true
+			return inv
+		},
+	}}}
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			events, err := extraField.Events(fixtures.Options{Secret: "whsec_fixture", APIVersion: "2017-08-15"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := h.HandleWebhook(events[0].Payload, events[0].Signature); err == nil {
+				t.Fatal("expected events of an incompatible API version to be rejected")
+			}
+
+			var reports []gomultistripe.SchemaReport
+			h.SetSchemaReporter(func(r gomultistripe.SchemaReport) { reports = append(reports, r) })
+			defer h.SetSchemaReporter(nil)
+			err = extraField.Replay(context.Background(), h, fixtures.Options{Secret: "whsec_fixture"},
+				func(ctx context.Context, e *gomultistripe.CallbackEvent) error { return nil })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reports) != 1 || reports[0].Object != "invoice" || !slices.Contains(reports[0].Unknown, "x_future_field") {
+				t.Fatalf("unexpected schema reports %+v", reports)
+			}
+		})
+	}
+}
+
+func TestIngestEvent_MatchesHandleWebhook(t *testing.T) {
+	router := gomultistripe.NewVersionRouter(allHandlers()...)
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			events, err := fixtures.SubscriptionLifecycle().Events(fixtures.Options{Secret: "whsec_fixture", APIVersion: h.APIVersion()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, evt := range events {
+				want, wantErr := h.HandleWebhook(evt.Payload, evt.Signature)
+				envelope := fmt.Sprintf(`{"version":"0","id":"eb_1","detail-type":%q,"source":"aws.partner/stripe.com/ed_fixture","detail":%s}`, evt.Type, evt.Payload)
+				got, err := gomultistripe.IngestEvent(router, []byte(envelope))
+				if (err == nil) != (wantErr == nil) {
+					t.Fatalf("%s: got error %v, want %v", evt.Type, err, wantErr)
+				}
+				if want != nil && (got.Type != want.Type || got.EventID != want.EventID || got.SubscriptionID != want.SubscriptionID) {
+					t.Errorf("%s: got %+v, want %+v", evt.Type, got, want)
+				}
+			}
+		})
+	}
+}
+
+// billingNotices records the notices NotifyBilling passes on.
+type billingNotices struct {
+	trialEnding  []*gomultistripe.TrialEndingNotice
+	failed       []*gomultistripe.PaymentFailedNotice
+	cardExpiring []*gomultistripe.CardExpiringNotice
+	canceled     []*gomultistripe.SubscriptionCanceledNotice
+}
+
+func (b *billingNotices) TrialEnding(ctx context.Context, n *gomultistripe.TrialEndingNotice) error {
+	b.trialEnding = append(b.trialEnding, n)
+	return nil
+}
+
+func (b *billingNotices) PaymentFailed(ctx context.Context, n *gomultistripe.PaymentFailedNotice) error {
+	b.failed = append(b.failed, n)
+	return nil
+}
+
+func (b *billingNotices) CardExpiring(ctx context.Context, n *gomultistripe.CardExpiringNotice) error {
+	b.cardExpiring = append(b.cardExpiring, n)
+	return nil
+}
+
+func (b *billingNotices) SubscriptionCanceled(ctx context.Context, n *gomultistripe.SubscriptionCanceledNotice) error {
+	b.canceled = append(b.canceled, n)
+	return nil
+}
+
+func TestNotifyBilling(t *testing.T) {
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			notices := &billingNotices{}
+			consume := gomultistripe.NotifyBilling(notices, nil)
+			err := fixtures.SubscriptionLifecycle().Replay(context.Background(), h, fixtures.Options{Secret: "whsec_fixture"}, consume)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(notices.trialEnding) != 1 || len(notices.failed) != 1 || len(notices.canceled) != 1 {
+				t.Fatalf("notices %+v", notices)
+			}
+			if n := notices.trialEnding[0]; n.SubscriptionID != "sub_fixture" || n.DaysRemaining != 3 || n.TrialEnd.IsZero() || n.EventID == "" {
+				t.Errorf("trial ending notice %+v", n)
+			}
+			if n := notices.failed[0]; n.InvoiceID == "" || n.Amount == 0 || n.AttemptCount == 0 {
+				t.Errorf("payment failed notice %+v", n)
+			}
+			if n := notices.canceled[0]; n.SubscriptionID != "sub_fixture" || n.CanceledAt.IsZero() {
+				t.Errorf("subscription canceled notice %+v", n)
+			}
+
+			payload, _ := json.Marshal(map[string]any{
+				"id": "evt_expiring", "object": "event", "api_version": h.APIVersion(), "created": 1700000000,
+				"type": "customer.source.expiring", "data": map[string]any{"object": map[string]any{
+					"id": "card_fixture", "object": "card", "brand": "Visa", "last4": "4242",
+					"exp_month": 11, "exp_year": 2023, "customer": "cus_fixture",
+				}},
+			})
+			evt, err := h.HandleWebhook(payload, gomultistripe.SignPayload(payload, "whsec_fixture", time.Now()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := consume(context.Background(), evt); err != nil {
+				t.Fatal(err)
+			}
+			want := gomultistripe.CardExpiringNotice{
+				EventID: "evt_expiring", CustomerID: "cus_fixture", PaymentMethodID: "card_fixture",
+				Brand: "Visa", Last4: "4242", ExpMonth: 11, ExpYear: 2023, Metadata: map[string]string{},
+			}
+			if len(notices.cardExpiring) != 1 || !reflect.DeepEqual(*notices.cardExpiring[0], want) {
+				t.Errorf("card expiring notices %+v", notices.cardExpiring)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/iqhive/gomultistripe/fixtures"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestGolden_SubscriptionLifecycle checks that every version normalizes the lifecycle's
+// events to exactly the same CallbackEvents, recorded in testdata/lifecycle.golden.json.
+// Run with -update after an intended change.
+func TestGolden_SubscriptionLifecycle(t *testing.T) {
+	golden := filepath.Join("testdata", "lifecycle.golden.json")
+	opts := fixtures.Options{Secret: "whsec_fixture", Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	written := false
+	for _, h := range allHandlers() {
+		t.Run(h.Version(), func(t *testing.T) {
+			h.SetWebhookSecret("whsec_fixture")
+			var got []*gomultistripe.CallbackEvent
+			err := fixtures.SubscriptionLifecycle().Replay(context.Background(), h, opts,
+				func(ctx context.Context, evt *gomultistripe.CallbackEvent) error {
+					// Handlers return times in the local time zone.
+					evt.EventCreatedAt, evt.CreatedAt = evt.EventCreatedAt.UTC(), evt.CreatedAt.UTC()
+					// The API version, and with it the payload, is the handler's own.
+					evt.APIVersion, evt.Payload = "", nil
+					got = append(got, evt)
+					return nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+			if *update && !written {
+				if err := os.MkdirAll("testdata", 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				written = true
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("events differ from %s:\n%s", golden, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package fixtures
+
+import "time"
+
+const day = 24 * time.Hour
+
+// SubscriptionLifecycle is a trial subscription that is never paid for: checkout completes,
+// the subscription starts trialing, the $0 trial invoice is paid, the trial-ending notice
+// goes out three days before the end, the first real invoice fails, the subscription goes
+// past due and is finally canceled after the retries run out.
+func SubscriptionLifecycle() Scenario {
+	return Scenario{
+		Name: "lifecycle",
+		Steps: []Step{
+			{Type: "checkout.session.completed", At: AfterStart(0), Object: CheckoutSessionObject},
+			{Type: "customer.subscription.created", At: AfterStart(time.Second), Object: func(s *State) map[string]any {
+				s.SubscriptionStatus = "trialing"
+				return SubscriptionObject(s)
+			}},
+			// Stripe sends both events for a paid invoice.
+			{Type: "invoice.paid", At: AfterStart(2 * time.Second), Object: trialInvoice},
+			{Type: "invoice.payment_succeeded", Object: trialInvoice},
+			{Type: "customer.subscription.trial_will_end", At: AfterTrialEnd(-3 * day), Object: SubscriptionObject},
+			{Type: "invoice.payment_failed", At: AfterTrialEnd(time.Hour), Object: func(s *State) map[string]any {
+				return FailedInvoiceObject(s, "in_fixture_renewal", s.Amount, "insufficient_funds")
+			}},
+			{Type: "customer.subscription.updated", Object: func(s *State) map[string]any {
+				s.SubscriptionStatus = "past_due"
+				return SubscriptionObject(s)
+			}},
+			{Type: "customer.subscription.deleted", At: AfterTrialEnd(7 * day), Object: func(s *State) map[string]any {
+				s.SubscriptionStatus = "canceled"
+				s.CanceledAt = s.Now
+				return SubscriptionObject(s)
+			}},
+		},
+	}
+}
+
+// trialInvoice is the $0 invoice issued when the trial starts.
+func trialInvoice(s *State) map[string]any {
+	return InvoiceObject(s, "in_fixture_trial", 0, true)
+}
@@ -0,0 +1,156 @@
+package fixtures
+
+// The object builders below emit the fields consumers typically read, in a shape every
+// supported API version decodes: fields that moved between versions (such as the
+// subscription's current period, moved onto items in basil) are written in both places.
+// They are exported for building custom scenarios.
+
+// CheckoutSessionObject is a completed subscription-mode checkout session.
+func CheckoutSessionObject(s *State) map[string]any {
+	return map[string]any{
+		"id":             "cs_fixture",
+		"object":         "checkout.session",
+		"mode":           "subscription",
+		"status":         "complete",
+		"payment_status": "no_payment_required",
+		"customer":       s.CustomerID,
+		"subscription":   s.SubscriptionID,
+		"currency":       s.Currency,
+		"amount_total":   0,
+		"created":        s.Now.Unix(),
+		"livemode":       false,
+		"metadata":       map[string]string{},
+	}
+}
+
+// SubscriptionObject is the subscription in its current state, with one item.
+func SubscriptionObject(s *State) map[string]any {
+	periodStart, periodEnd := s.Start, s.TrialEnd
+	if !s.Now.Before(s.TrialEnd) {
+		periodStart, periodEnd = s.TrialEnd, s.TrialEnd.AddDate(0, 1, 0)
+	}
+	sub := map[string]any{
+		"id":                   s.SubscriptionID,
+		"object":               "subscription",
+		"customer":             s.CustomerID,
+		"status":               s.SubscriptionStatus,
+		"created":              s.Start.Unix(),
+		"start_date":           s.Start.Unix(),
+		"billing_cycle_anchor": s.TrialEnd.Unix(),
+		"current_period_start": periodStart.Unix(),
+		"current_period_end":   periodEnd.Unix(),
+		"trial_start":          s.Start.Unix(),
+		"trial_end":            s.TrialEnd.Unix(),
+		"cancel_at_period_end": false,
+		"cancel_at":            nil,
+		"canceled_at":          nil,
+		"ended_at":             nil,
+		"livemode":             false,
+		"metadata":             map[string]string{},
+		"items": map[string]any{
+			"object":   "list",
+			"has_more": false,
+			"data": []any{map[string]any{
+				"id":                   "si_fixture",
+				"object":               "subscription_item",
+				"subscription":         s.SubscriptionID,
+				"quantity":             s.Quantity,
+				"current_period_start": periodStart.Unix(),
+				"current_period_end":   periodEnd.Unix(),
+				"price": map[string]any{
+					"id":          s.PriceID,
+					"object":      "price",
+					"currency":    s.Currency,
+					"unit_amount": s.Amount,
+					"recurring":   map[string]any{"interval": "month", "interval_count": 1},
+				},
+			}},
+		},
+	}
+	if !s.CanceledAt.IsZero() {
+		sub["canceled_at"] = s.CanceledAt.Unix()
+		sub["ended_at"] = s.CanceledAt.Unix()
+	}
+	return sub
+}
+
+// InvoiceObject is a subscription invoice with one line, paid or awaiting payment.
+func InvoiceObject(s *State, id string, amount int64, paid bool) map[string]any {
+	status, amountPaid, amountRemaining := "open", int64(0), amount
+	if paid {
+		status, amountPaid, amountRemaining = "paid", amount, 0
+	}
+	periodEnd := s.Now.AddDate(0, 1, 0)
+	return map[string]any{
+		"id":               id,
+		"object":           "invoice",
+		"customer":         s.CustomerID,
+		"subscription":     s.SubscriptionID,
+		"status":           status,
+		"currency":         s.Currency,
+		"amount_due":       amount,
+		"amount_paid":      amountPaid,
+		"amount_remaining": amountRemaining,
+		"total":            amount,
+		"attempt_count":    1,
+		"billing_reason":   "subscription_cycle",
+		"created":          s.Now.Unix(),
+		"livemode":         false,
+		"metadata":         map[string]string{},
+		"parent": map[string]any{
+			"type":                 "subscription_details",
+			"subscription_details": map[string]any{"subscription": s.SubscriptionID},
+		},
+		"lines": map[string]any{
+			"object":   "list",
+			"has_more": false,
+			"data": []any{map[string]any{
+				"id":           id + "_line",
+				"object":       "line_item",
+				"amount":       amount,
+				"currency":     s.Currency,
+				"description":  "1 × fixture subscription",
+				"subscription": s.SubscriptionID,
+				"period":       map[string]any{"start": s.Now.Unix(), "end": periodEnd.Unix()},
+			}},
+		},
+	}
+}
+
+// FailedInvoiceObject is an open invoice whose first automatic payment attempt was declined
+// with declineCode; Smart Retries try again three days later. The failed payment intent is
+// included both as the expanded payment_intent of earlier API versions and as the expanded
+// payments of the basil version, so handlers need no API call to report the decline.
+func FailedInvoiceObject(s *State, id string, amount int64, declineCode string) map[string]any {
+	inv := InvoiceObject(s, id, amount, false)
+	inv["next_payment_attempt"] = s.Now.Add(3 * day).Unix()
+	intent := map[string]any{
+		"id":       "pi_" + id,
+		"object":   "payment_intent",
+		"amount":   amount,
+		"currency": s.Currency,
+		"customer": s.CustomerID,
+		"status":   "requires_payment_method",
+		"last_payment_error": map[string]any{
+			"type":           "card_error",
+			"code":           "card_declined",
+			"decline_code":   declineCode,
+			"message":        "Your card was declined.",
+			"charge":         "ch_" + id,
+			"payment_method": map[string]any{"id": "pm_fixture", "object": "payment_method", "type": "card"},
+		},
+	}
+	inv["payment_intent"] = intent
+	inv["payments"] = map[string]any{
+		"object":   "list",
+		"has_more": false,
+		"data": []any{map[string]any{
+			"id":      "inpay_" + id,
+			"object":  "invoice_payment",
+			"invoice": id,
+			"status":  "open",
+			"payment": map[string]any{"type": "payment_intent", "payment_intent": intent},
+		}},
+	}
+	return inv
+}
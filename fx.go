@@ -0,0 +1,92 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNoExchangeRate is matched by the errors returned for a currency pair no rate is
+// known for.
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// ExchangeRates provides the rates ConvertToReportingCurrency converts with. Stripe does
+// not publish its rates, so they come from the consumer, e.g. the rates of its accounting
+// system.
+type ExchangeRates interface {
+	// Rate returns the units of to one unit of from was worth at t, e.g. 1.08 from "eur"
+	// to "usd". Currencies are lowercase.
+	Rate(ctx context.Context, from, to string, at time.Time) (float64, error)
+}
+
+// StaticRates are fixed rates into a base currency, e.g. an accounting period's closing
+// rates. Rates maps a lowercase currency to the units of Base one unit of it is worth, so
+// {Base: "usd", Rates: {"eur": 1.08, "gbp": 1.27}} converts between all three.
+type StaticRates struct {
+	Base  string
+	Rates map[string]float64
+}
+
+func (r StaticRates) Rate(ctx context.Context, from, to string, at time.Time) (float64, error) {
+	toBase := func(currency string) (float64, bool) {
+		if strings.EqualFold(currency, r.Base) {
+			return 1, true
+		}
+		rate, ok := r.Rates[strings.ToLower(currency)]
+		return rate, ok && rate > 0
+	}
+	fromRate, ok := toBase(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrNoExchangeRate, from, to)
+	}
+	toRate, ok := toBase(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrNoExchangeRate, from, to)
+	}
+	return fromRate / toRate, nil
+}
+
+// ConvertAmount converts amount, in the smallest unit of from, into the smallest unit of
+// to at rate, the units of to one unit of from is worth. It accounts for currencies with
+// zero or three decimals and rounds to the nearest unit.
+func ConvertAmount(amount int64, from, to string, rate float64) int64 {
+	scale := math.Pow10(currencyDecimals(to) - currencyDecimals(from))
+	return int64(math.Round(float64(amount) * rate * scale))
+}
+
+// ConvertToReportingCurrency sets the Reporting fields of txns, converting their amounts
+// into currency. Transactions already in currency keep their amounts. Transactions Stripe
+// converted from a presentment amount in currency use Stripe's exchange rate, so their
+// ReportingAmount is exactly what the customer paid. The others are converted at the rate
+// rates returns for their creation time; without rates they fail with ErrNoExchangeRate.
+func ConvertToReportingCurrency(ctx context.Context, txns []*BalanceTransaction, currency string, rates ExchangeRates) error {
+	currency = strings.ToLower(currency)
+	for _, txn := range txns {
+		txn.ReportingCurrency = currency
+		switch {
+		case strings.EqualFold(txn.Currency, currency):
+			txn.ReportingRate = 1
+			txn.ReportingAmount, txn.ReportingFee, txn.ReportingNet = txn.Amount, txn.Fee, txn.Net
+			continue
+		case txn.ExchangeRate != 0 && strings.EqualFold(txn.PresentmentCurrency, currency):
+			txn.ReportingRate = 1 / txn.ExchangeRate
+			txn.ReportingAmount = txn.PresentmentAmount
+		case rates == nil:
+			return fmt.Errorf("%w: %s to %s for balance transaction %s", ErrNoExchangeRate, txn.Currency, currency, txn.ID)
+		default:
+			rate, err := rates.Rate(ctx, strings.ToLower(txn.Currency), currency, txn.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("balance transaction %s: %w", txn.ID, err)
+			}
+			txn.ReportingRate = rate
+			txn.ReportingAmount = ConvertAmount(txn.Amount, txn.Currency, currency, rate)
+		}
+		txn.ReportingFee = ConvertAmount(txn.Fee, txn.Currency, currency, txn.ReportingRate)
+		// Net is derived rather than converted, so that it stays Amount minus Fee.
+		txn.ReportingNet = txn.ReportingAmount - txn.ReportingFee
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConvertAmount(t *testing.T) {
+	for _, tt := range []struct {
+		amount   int64
+		from, to string
+		rate     float64
+		want     int64
+	}{
+		{1000, "eur", "usd", 1.234, 1234},
+		{1000, "jpy", "usd", 0.0067, 670},
+		{670, "usd", "jpy", 150, 1005},
+		{1000, "usd", "kwd", 0.3, 3000},
+		{-1000, "eur", "usd", 1.08, -1080},
+	} {
+		if got := ConvertAmount(tt.amount, tt.from, tt.to, tt.rate); got != tt.want {
+			t.Errorf("ConvertAmount(%d, %s, %s, %v) = %d, want %d", tt.amount, tt.from, tt.to, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestStaticRates(t *testing.T) {
+	rates := StaticRates{Base: "usd", Rates: map[string]float64{"eur": 1.25, "gbp": 1.5}}
+	ctx := context.Background()
+	for _, tt := range []struct {
+		from, to string
+		want     float64
+	}{
+		{"eur", "usd", 1.25},
+		{"USD", "gbp", 1 / 1.5},
+		{"gbp", "eur", 1.2},
+		{"usd", "usd", 1},
+	} {
+		if got, err := rates.Rate(ctx, tt.from, tt.to, time.Time{}); err != nil || got != tt.want {
+			t.Errorf("Rate(%s, %s) = %v, %v, want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+	if _, err := rates.Rate(ctx, "chf", "usd", time.Time{}); !errors.Is(err, ErrNoExchangeRate) {
+		t.Errorf("unknown currency: %v", err)
+	}
+}
+
+func TestConvertToReportingCurrency(t *testing.T) {
+	txns := []*BalanceTransaction{
+		{ID: "txn_usd", Amount: 1000, Fee: 59, Net: 941, Currency: "usd"},
+		{ID: "txn_fx", Amount: 1250, Fee: 66, Net: 1184, Currency: "usd", ExchangeRate: 1.25, PresentmentAmount: 1000, PresentmentCurrency: "eur"},
+		{ID: "txn_gbp", Amount: -500, Net: -500, Currency: "gbp"},
+	}
+	rates := StaticRates{Base: "eur", Rates: map[string]float64{"usd": 0.8, "gbp": 1.2}}
+	if err := ConvertToReportingCurrency(context.Background(), txns, "EUR", rates); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range [][3]int64{{800, 47, 753}, {1000, 53, 947}, {-600, 0, -600}} {
+		txn := txns[i]
+		if got := [3]int64{txn.ReportingAmount, txn.ReportingFee, txn.ReportingNet}; got != want || txn.ReportingCurrency != "eur" {
+			t.Errorf("%s: reporting %v %s, want %v", txn.ID, got, txn.ReportingCurrency, want)
+		}
+	}
+	if txns[1].ReportingRate != 0.8 {
+		t.Errorf("Stripe's rate not used: %v", txns[1].ReportingRate)
+	}
+
+	same := []*BalanceTransaction{{ID: "txn_eur", Amount: 100, Net: 100, Currency: "eur"}}
+	if err := ConvertToReportingCurrency(context.Background(), same, "eur", nil); err != nil || same[0].ReportingAmount != 100 || same[0].ReportingRate != 1 {
+		t.Errorf("same currency: %+v, %v", same[0], err)
+	}
+	if err := ConvertToReportingCurrency(context.Background(), txns[:1], "eur", nil); !errors.Is(err, ErrNoExchangeRate) {
+		t.Errorf("converted without rates: %v", err)
+	}
+}
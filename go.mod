@@ -14,4 +14,15 @@ require (
 
 require github.com/stripe/stripe-go/v82 v82.0.0
 
-require github.com/iqhive/cfggo v1.0.21
+require (
+	github.com/iqhive/cfggo v1.0.21
+	github.com/parquet-go/parquet-go v0.25.1
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
@@ -0,0 +1,215 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AccessOutcome is the high-level entitlement decision for a subscription.
+type AccessOutcome string
+
+const (
+	// AccessRetained means the customer keeps access, possibly within a grace period.
+	AccessRetained AccessOutcome = "access_retained"
+	// AccessSuspended means access should be withheld until payment is resolved.
+	AccessSuspended AccessOutcome = "access_suspended"
+	// FinalCancellation means the subscription has ended and will not recover.
+	FinalCancellation AccessOutcome = "final_cancellation"
+)
+
+// AccessDecision is emitted by an AccessPolicy when an event affects a subscription's access.
+type AccessDecision struct {
+	Outcome        AccessOutcome
+	SubscriptionID string
+	CustomerID     string
+	// GraceEndsAt is set while a failed payment is within its grace period.
+	GraceEndsAt time.Time
+	// Event is the webhook event that led to the decision, or nil for decisions made by Sweep.
+	Event *CallbackEvent
+}
+
+// GracePolicy configures an AccessPolicy.
+type GracePolicy struct {
+	// GracePeriod is how long a subscription keeps access after its first failed payment.
+	// Zero suspends access as soon as a payment fails.
+	GracePeriod time.Duration
+	// OnDecision receives every decision. Returning an error fails the event so the
+	// dispatcher retries it.
+	OnDecision func(ctx context.Context, d *AccessDecision) error
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// AccessPolicy turns subscription status changes and invoice payment events into
+// AccessDecisions. Its Consume method is an EventConsumer, so it is typically run by a
+// Dispatcher:
+//
+//	policy := gomultistripe.NewAccessPolicy(gomultistripe.GracePolicy{GracePeriod: 7 * 24 * time.Hour, OnDecision: apply})
+//	d := gomultistripe.NewDispatcher(policy.Consume, gomultistripe.DispatcherConfig{})
+//
+// Grace periods are tracked in memory; call Sweep periodically to suspend subscriptions
+// whose grace period ended without a further event. A suspended subscription stays
+// suspended until it is paid, active again or canceled.
+type AccessPolicy struct {
+	cfg GracePolicy
+
+	mu    sync.Mutex
+	grace map[string]graceState
+}
+
+type graceState struct {
+	customerID string
+	endsAt     time.Time
+	// suspended is set once AccessSuspended has been decided, so further failures do not
+	// start a new grace period.
+	suspended bool
+}
+
+// NewAccessPolicy creates an AccessPolicy.
+func NewAccessPolicy(cfg GracePolicy) *AccessPolicy {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &AccessPolicy{cfg: cfg, grace: make(map[string]graceState)}
+}
+
+// Consume evaluates evt and passes the resulting decisions to OnDecision.
+func (p *AccessPolicy) Consume(ctx context.Context, evt *CallbackEvent) error {
+	for _, d := range p.Evaluate(evt) {
+		if p.cfg.OnDecision == nil {
+			continue
+		}
+		if err := p.cfg.OnDecision(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the decisions for evt without emitting them. Events that do not affect
+// access return nil.
+func (p *AccessPolicy) Evaluate(evt *CallbackEvent) []*AccessDecision {
+	switch evt.Type {
+	case EventCustomerSubscriptionDeleted:
+		p.clear(evt.SubscriptionID)
+		return []*AccessDecision{p.decide(FinalCancellation, evt.SubscriptionID, evt.CustomerID, evt)}
+	case EventCustomerSubscriptionCreated,
+		EventCustomerSubscriptionUpdated,
+		EventCustomerSubscriptionPaused,
+		EventCustomerSubscriptionResumed:
+		return []*AccessDecision{p.fromStatus(evt)}
+	case EventInvoicePaymentFailed:
+		var out []*AccessDecision
+		for _, id := range invoiceSubscriptionIDs(evt) {
+			out = append(out, p.paymentFailed(id, evt.CustomerID, evt))
+		}
+		return out
+	case EventInvoicePaymentSucceeded:
+		var out []*AccessDecision
+		for _, id := range invoiceSubscriptionIDs(evt) {
+			p.clear(id)
+			out = append(out, p.decide(AccessRetained, id, evt.CustomerID, evt))
+		}
+		return out
+	}
+	return nil
+}
+
+// Sweep emits AccessSuspended for every subscription whose grace period has ended.
+func (p *AccessPolicy) Sweep(ctx context.Context) error {
+	now := p.cfg.Now()
+	var expired []*AccessDecision
+	p.mu.Lock()
+	for id, g := range p.grace {
+		if !g.suspended && !now.Before(g.endsAt) {
+			expired = append(expired, &AccessDecision{Outcome: AccessSuspended, SubscriptionID: id, CustomerID: g.customerID})
+			g.suspended = true
+			p.grace[id] = g
+		}
+	}
+	p.mu.Unlock()
+	for _, d := range expired {
+		if p.cfg.OnDecision == nil {
+			continue
+		}
+		if err := p.cfg.OnDecision(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *AccessPolicy) fromStatus(evt *CallbackEvent) *AccessDecision {
+	switch evt.Status {
+	case "active", "trialing":
+		p.clear(evt.SubscriptionID)
+		return p.decide(AccessRetained, evt.SubscriptionID, evt.CustomerID, evt)
+	case "past_due":
+		return p.paymentFailed(evt.SubscriptionID, evt.CustomerID, evt)
+	case "canceled", "incomplete_expired":
+		p.clear(evt.SubscriptionID)
+		return p.decide(FinalCancellation, evt.SubscriptionID, evt.CustomerID, evt)
+	default:
+		// unpaid, paused and incomplete subscriptions have no paid-up period to honour.
+		p.suspend(evt.SubscriptionID, evt.CustomerID)
+		return p.decide(AccessSuspended, evt.SubscriptionID, evt.CustomerID, evt)
+	}
+}
+
+// paymentFailed starts the grace period on the first failure and keeps it running on
+// subsequent failures, so retries do not extend it and do not restore the access of a
+// suspended subscription.
+func (p *AccessPolicy) paymentFailed(subscriptionID, customerID string, evt *CallbackEvent) *AccessDecision {
+	now := p.cfg.Now()
+	p.mu.Lock()
+	g, ok := p.grace[subscriptionID]
+	if !ok {
+		g = graceState{customerID: customerID, endsAt: now.Add(p.cfg.GracePeriod)}
+	}
+	if !now.Before(g.endsAt) {
+		g.suspended = true
+	}
+	p.grace[subscriptionID] = g
+	p.mu.Unlock()
+	if g.suspended {
+		return p.decide(AccessSuspended, subscriptionID, customerID, evt)
+	}
+	d := p.decide(AccessRetained, subscriptionID, customerID, evt)
+	d.GraceEndsAt = g.endsAt
+	return d
+}
+
+// suspend records that subscriptionID has lost access until it is paid, active or canceled.
+func (p *AccessPolicy) suspend(subscriptionID, customerID string) {
+	p.mu.Lock()
+	p.grace[subscriptionID] = graceState{customerID: customerID, suspended: true}
+	p.mu.Unlock()
+}
+
+func (p *AccessPolicy) clear(subscriptionID string) {
+	p.mu.Lock()
+	delete(p.grace, subscriptionID)
+	p.mu.Unlock()
+}
+
+func (p *AccessPolicy) decide(outcome AccessOutcome, subscriptionID, customerID string, evt *CallbackEvent) *AccessDecision {
+	return &AccessDecision{Outcome: outcome, SubscriptionID: subscriptionID, CustomerID: customerID, Event: evt}
+}
+
+// invoiceSubscriptionIDs returns the distinct subscriptions billed on an invoice event.
+func invoiceSubscriptionIDs(evt *CallbackEvent) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	if evt.SubscriptionID != "" {
+		ids = append(ids, evt.SubscriptionID)
+		seen[evt.SubscriptionID] = true
+	}
+	for _, line := range evt.InvoiceLines {
+		if line.SubscriptionID != "" && !seen[line.SubscriptionID] {
+			ids = append(ids, line.SubscriptionID)
+			seen[line.SubscriptionID] = true
+		}
+	}
+	return ids
+}
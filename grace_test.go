@@ -0,0 +1,112 @@
+package gomultistripe
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAccessPolicy_GracePeriod(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var decisions []*AccessDecision
+	p := NewAccessPolicy(GracePolicy{
+		GracePeriod: 72 * time.Hour,
+		Now:         func() time.Time { return now },
+		OnDecision: func(ctx context.Context, d *AccessDecision) error {
+			decisions = append(decisions, d)
+			return nil
+		},
+	})
+	ctx := context.Background()
+	failed := &CallbackEvent{
+		Type:         EventInvoicePaymentFailed,
+		CustomerID:   "cus_1",
+		InvoiceLines: []InvoiceLine{{SubscriptionID: "sub_1"}, {SubscriptionID: "sub_1"}},
+	}
+
+	if err := p.Consume(ctx, failed); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if len(decisions) != 1 || decisions[0].Outcome != AccessRetained || !decisions[0].GraceEndsAt.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("expected retained access within grace, got %+v", decisions)
+	}
+
+	// A retry failing later must not extend the grace period.
+	now = now.Add(48 * time.Hour)
+	if err := p.Consume(ctx, failed); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if got := decisions[1]; got.Outcome != AccessRetained || !got.GraceEndsAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("grace period was extended: %+v", got)
+	}
+
+	now = now.Add(24 * time.Hour)
+	if err := p.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if got := decisions[2]; got.Outcome != AccessSuspended || got.SubscriptionID != "sub_1" || got.CustomerID != "cus_1" {
+		t.Fatalf("expected suspension after grace, got %+v", got)
+	}
+
+	if err := p.Consume(ctx, &CallbackEvent{Type: EventCustomerSubscriptionDeleted, SubscriptionID: "sub_1", Status: "canceled"}); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if got := decisions[3]; got.Outcome != FinalCancellation {
+		t.Fatalf("expected final cancellation, got %+v", got)
+	}
+}
+
+func TestAccessPolicy_FailureAfterSuspensionStaysSuspended(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var decisions []*AccessDecision
+	p := NewAccessPolicy(GracePolicy{
+		GracePeriod: 72 * time.Hour,
+		Now:         func() time.Time { return now },
+		OnDecision: func(ctx context.Context, d *AccessDecision) error {
+			decisions = append(decisions, d)
+			return nil
+		},
+	})
+	ctx := context.Background()
+	failed := &CallbackEvent{Type: EventInvoicePaymentFailed, CustomerID: "cus_1", SubscriptionID: "sub_1"}
+	pastDue := &CallbackEvent{Type: EventCustomerSubscriptionUpdated, CustomerID: "cus_1", SubscriptionID: "sub_1", Status: "past_due"}
+
+	if err := p.Consume(ctx, failed); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	now = now.Add(72 * time.Hour)
+	if err := p.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if got := decisions[1]; got.Outcome != AccessSuspended {
+		t.Fatalf("expected suspension after grace, got %+v", got)
+	}
+
+	// Stripe's retries and the past_due update that follows must not restore access.
+	now = now.Add(24 * time.Hour)
+	for _, evt := range []*CallbackEvent{failed, pastDue} {
+		if err := p.Consume(ctx, evt); err != nil {
+			t.Fatalf("Consume failed: %v", err)
+		}
+		if got := decisions[len(decisions)-1]; got.Outcome != AccessSuspended {
+			t.Fatalf("%s after suspension: got %+v", evt.Type, got)
+		}
+	}
+	if err := p.Sweep(ctx); err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if len(decisions) != 4 {
+		t.Fatalf("Sweep suspended the subscription again: %+v", decisions[4:])
+	}
+
+	// Once paid, a later failure starts a new grace period.
+	if err := p.Consume(ctx, &CallbackEvent{Type: EventInvoicePaymentSucceeded, CustomerID: "cus_1", SubscriptionID: "sub_1"}); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if err := p.Consume(ctx, failed); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if got := decisions[5]; got.Outcome != AccessRetained || !got.GraceEndsAt.Equal(now.Add(72*time.Hour)) {
+		t.Fatalf("expected a new grace period after payment, got %+v", got)
+	}
+}
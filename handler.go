@@ -7,6 +7,11 @@ package gomultistripe
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,20 +24,74 @@ type Customer struct {
 	Postcode  string
 	Metadata  map[string]string
 	CreatedAt time.Time
+
+	// PreferredLocales lists the customer's languages in order of preference, e.g. "fr-CA".
+	// Stripe sends receipts and invoices in the first supported locale.
+	PreferredLocales []string
+}
+
+// CustomerPage is one page of customers, newest first.
+type CustomerPage struct {
+	Customers []*Customer
+	HasMore   bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
+
+// CustomerSessionComponent names a Stripe.js component a customer session can be enabled for.
+type CustomerSessionComponent string
+
+const (
+	// CustomerSessionPaymentElement lets the Payment Element display the customer's saved payment
+	// methods and offer to save new ones.
+	CustomerSessionPaymentElement CustomerSessionComponent = "payment_element"
+	CustomerSessionPricingTable   CustomerSessionComponent = "pricing_table"
+	CustomerSessionBuyButton      CustomerSessionComponent = "buy_button"
+)
+
+// CustomerSession holds the client secret Stripe.js needs to act on behalf of a customer.
+type CustomerSession struct {
+	CustomerID   string
+	ClientSecret string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// PaymentSheet holds the secrets the mobile PaymentSheet needs to present a payment.
+type PaymentSheet struct {
+	CustomerID                string
+	EphemeralKeySecret        string
+	PaymentIntentID           string
+	PaymentIntentClientSecret string
 }
 
 // PaymentMethod represents a Stripe payment method in a version-agnostic way.
 type PaymentMethod struct {
 	ID         string
 	CustomerID string
-	Type       string
-	Last4      string
-	Brand      string
-	ExpMonth   uint
-	ExpYear    uint
-	IsDefault  bool
-	Metadata   map[string]string
-	CreatedAt  time.Time
+	// Type is the Stripe payment method type, e.g. "card", "link" or "paypal".
+	Type     string
+	Last4    string
+	Brand    string
+	ExpMonth uint
+	ExpYear  uint
+	// Fingerprint uniquely identifies the card number; the same card added twice has the same fingerprint.
+	Fingerprint string
+	// Funding is the card funding type: "credit", "debit", "prepaid" or "unknown".
+	Funding string
+	// Country is the two-letter ISO code of the card's issuing country.
+	Country string
+	// Issuer is the card issuer name, where Stripe makes it available to the account.
+	Issuer string
+	// Wallet is set for cards added through a wallet, e.g. "apple_pay" or "google_pay".
+	Wallet string
+	// Email is the account email for Link and PayPal payment methods.
+	Email string
+	// IsDefault marks the customer's default payment method. It is only resolved when
+	// requested with WithDefaultFlag.
+	IsDefault bool
+	Metadata  map[string]string
+	CreatedAt time.Time
 }
 
 // PaymentIntent represents a Stripe payment intent in a version-agnostic way.
@@ -46,19 +105,145 @@ type PaymentIntent struct {
 	PaymentMethod string
 	Metadata      map[string]string
 	CreatedAt     time.Time
+
+	// CaptureMethod is "automatic", "automatic_async" or "manual". On creation, "manual" only
+	// authorizes the payment, for capture with CapturePaymentIntent; empty leaves Stripe's
+	// default.
+	CaptureMethod string
+	// AutomaticPaymentMethods, when set on creation, enables or disables the payment methods
+	// configured in the dashboard for this intent, instead of relying on the account's
+	// default. Populated when reading an intent.
+	AutomaticPaymentMethods *AutomaticPaymentMethods
+	// ConfirmLater creates the intent without confirming it, for confirmation with
+	// ConfirmPaymentIntent or on the client with ClientSecret. Only used on creation.
+	ConfirmLater bool
+	// ReturnURL, MandateData and OffSession apply when the intent is confirmed on creation;
+	// see ConfirmOptions. Not populated when reading an intent.
+	ReturnURL   string
+	MandateData *MandateData
+	OffSession  bool
+	// RedirectURL is where to send the customer to authenticate the payment, when Stripe
+	// requires a redirect (status requires_action).
+	RedirectURL string
+	// CancellationReason is why a canceled intent was canceled.
+	CancellationReason string
+	// AmountCapturable is the authorized amount that can still be captured.
+	AmountCapturable int64
+	// AmountReceived is the amount captured so far.
+	AmountReceived int64
+	// StatementDescriptorSuffix is appended to the account's statement descriptor prefix
+	// on the customer's card statement, without changing the account default.
+	StatementDescriptorSuffix string
+	// ReceiptEmail is where Stripe sends the receipt once the payment succeeds.
+	ReceiptEmail string
+	// ReceiptLocale is the language of the receipt, populated from the customer when reading
+	// an intent. Stripe takes it from the customer's preferred locales and has no per-payment
+	// language, so setting it on creation returns ErrUnsupported; change the customer's
+	// PreferredLocales with UpdateCustomer instead.
+	ReceiptLocale string
+	// Level3 optionally carries B2B line item, tax and shipping data. Only used on creation.
+	Level3 *Level3
+	// InstallmentsEnabled, on creation with a card PaymentMethod, asks Stripe for the
+	// installment plans the card offers, listed in AvailableInstallmentPlans; see
+	// InstallmentPlans. InstallmentPlan pays in installments, and implies
+	// InstallmentsEnabled. Both require one of InstallmentCurrencies. Populated when reading
+	// an intent.
+	InstallmentsEnabled       bool
+	InstallmentPlan           *InstallmentPlan
+	AvailableInstallmentPlans []InstallmentPlan
+	// BankTransfer, on creation, pays the intent from the customer's cash balance, funded by
+	// bank transfer, instead of with a PaymentMethod. It requires a CustomerID. Unless the
+	// balance already covers the amount, the confirmed intent's status is requires_action
+	// until the transfer arrives. Only used on creation.
+	BankTransfer *BankTransfer
+	// FundingInstructions tell the customer where to transfer the funds for an intent paid
+	// by BankTransfer, while it is in status requires_action.
+	FundingInstructions *FundingInstructions
+	// PaymentMethodOptions forwards method-specific options, e.g. Klarna's preferred
+	// locale. Options the handler's SDK does not model are rejected with an error matching
+	// ErrUnsupported. Only used on creation.
+	PaymentMethodOptions PaymentMethodOptions
+	// RequestMulticapture asks Stripe to allow several partial captures of a manual-capture
+	// card payment, where the account is enabled for it. Only used on creation (v75 and later).
+	RequestMulticapture bool
+	// AuthorizationExpiresAt is when an uncaptured card authorization lapses (v76 and later).
+	AuthorizationExpiresAt time.Time
+	// CouponID or PromotionCode (the customer-facing code) discounts Amount: the handler
+	// charges the discounted amount and records the discount in the metadata. See
+	// DiscountPaymentIntent. Only used on creation.
+	CouponID      string
+	PromotionCode string
+
+	// ApplicationFeeAmount is the platform's fee on a destination charge, and
+	// TransferDestination the connected account that receives the rest of the payment.
+	// TransferGroup groups the intent with the transfers of the same order.
+	ApplicationFeeAmount int64
+	TransferDestination  string
+	TransferGroup        string
+
+	// LatestChargeID is the most recent charge created by this intent.
+	LatestChargeID string
+	// Card verification results of the latest charge: "pass", "fail", "unavailable" or "unchecked".
+	CVCCheck               string
+	AddressLine1Check      string
+	AddressPostalCodeCheck string
+	// NetworkTransactionID is the card network's ID for the latest charge, useful as dispute evidence.
+	NetworkTransactionID string
+}
+
+// UncapturedAuthorizationWindow is how far back ListUncapturedPaymentIntents looks. Card
+// authorizations lapse after 7 days, extended authorizations after at most 30, so older
+// intents can no longer be captured.
+const UncapturedAuthorizationWindow = 30 * 24 * time.Hour
+
+// SetupIntent represents a Stripe setup intent in a version-agnostic way.
+type SetupIntent struct {
+	ID            string
+	Status        string
+	ClientSecret  string
+	CustomerID    string
+	PaymentMethod string
+	// Usage is "off_session" (Stripe's default) or "on_session", for payment methods only
+	// used while the customer is present.
+	Usage     string
+	Metadata  map[string]string
+	CreatedAt time.Time
+
+	// Confirm confirms the intent on creation, with PaymentMethod, ReturnURL and
+	// MandateData. Intents created without it are confirmed with ConfirmSetupIntent or on
+	// the client with ClientSecret. Only used on creation.
+	Confirm     bool
+	ReturnURL   string
+	MandateData *MandateData
+	// RedirectURL is where to send the customer to authenticate the payment method, when
+	// Stripe requires a redirect (status requires_action).
+	RedirectURL string
 }
 
 // Subscription represents a Stripe subscription in a version-agnostic way.
 type Subscription struct {
-	ID                string
-	CustomerID        string
-	Status            string
+	ID         string
+	CustomerID string
+	Status     string
+	// PriceID is the price of the first item; Items lists every item.
 	PriceID           string
+	Items             []SubscriptionItem
 	CurrentPeriodEnd  int64
 	CancelAtPeriodEnd bool
 	CanceledAt        int64
-	Metadata          map[string]string
-	CreatedAt         time.Time
+	// CancelAt is the unix time a scheduled cancellation takes effect, or 0.
+	CancelAt  int64
+	Metadata  map[string]string
+	CreatedAt time.Time
+
+	// StartDate is when the subscription started; earlier than CreatedAt when backdated.
+	StartDate int64
+	// BillingCycleAnchor is the reference time the billing periods are aligned to.
+	BillingCycleAnchor int64
+	// TrialStart and TrialEnd are the unix times the trial started and ends (or ended), or 0
+	// without a trial.
+	TrialStart int64
+	TrialEnd   int64
 }
 
 // CallbackEventType represents the type of Stripe event received.
@@ -90,11 +275,48 @@ const (
 	EventRefundUpdated  CallbackEventType = "refund.updated"
 	EventRefundFailed   CallbackEventType = "refund.failed"
 	EventChargeRefunded CallbackEventType = "charge.refunded"
+
+	// Payment method events
+	EventPaymentMethodAttached             CallbackEventType = "payment_method.attached"
+	EventPaymentMethodDetached             CallbackEventType = "payment_method.detached"
+	EventPaymentMethodAutomaticallyUpdated CallbackEventType = "payment_method.automatically_updated"
+	EventCustomerSourceExpiring            CallbackEventType = "customer.source.expiring"
+
+	// Charge events
+	EventChargeSucceeded CallbackEventType = "charge.succeeded"
+	EventChargeFailed    CallbackEventType = "charge.failed"
+
+	// Dispute events
+	EventChargeDisputeCreated CallbackEventType = "charge.dispute.created"
+	EventChargeDisputeUpdated CallbackEventType = "charge.dispute.updated"
+	EventChargeDisputeClosed  CallbackEventType = "charge.dispute.closed"
+
+	// Connect account events
+	EventAccountUpdated CallbackEventType = "account.updated"
+
+	// Cash balance events
+	EventCashBalanceFundsAvailable             CallbackEventType = "cash_balance.funds_available"
+	EventCustomerCashBalanceTransactionCreated CallbackEventType = "customer_cash_balance_transaction.created"
+
+	// Top-up events
+	EventTopupSucceeded CallbackEventType = "topup.succeeded"
+	EventTopupFailed    CallbackEventType = "topup.failed"
 )
 
 // CallbackEvent is a version-agnostic representation of a Stripe webhook event.
 type CallbackEvent struct {
 	Type CallbackEventType
+	// EventID is the ID of the Stripe event (evt_...) this was parsed from.
+	EventID string
+	// EventCreatedAt is when Stripe created the event, used to measure webhook delivery lag.
+	EventCreatedAt time.Time
+	// AccountID is the connected account the event occurred on, empty for the platform's
+	// own account. APIVersion is the Stripe API version the event was rendered in.
+	AccountID  string
+	APIVersion string
+	// Payload is the event as Stripe delivered it, for the fields the normalized event does
+	// not map. It shares memory with the payload passed to HandleWebhook or HandleEvent.
+	Payload json.RawMessage
 
 	// Common metadata fields
 	Metadata     map[string]string
@@ -109,13 +331,26 @@ type CallbackEvent struct {
 	CardExpYear     uint
 	CardLast4       string
 
+	// Payment method fields. payment_method events also set PaymentMethodID, CustomerID and
+	// the Card fields: for payment_method.detached, CustomerID is the customer the method was
+	// detached from, and for payment_method.automatically_updated the Card fields hold the
+	// card's new details, e.g. after the issuer reissued it. customer.source.expiring sets
+	// them for a customer's card that expires at the end of the month.
+	PaymentMethodType string
+
 	// PaymentIntent fields
 	PaymentIntentID  string
 	Amount           int64
 	AmountCapturable int64
 	Status           string
 
-	// Payment error fields
+	// Payment error fields, set for payment_intent.payment_failed, for charge.failed (without
+	// a decline code) and, from the invoice's payment intent, for invoice.payment_failed.
+	// LastPaymentErrorCode is Stripe's error code, e.g. "card_declined".
+	// LastPaymentErrorMsg is Stripe's explanation of the error, in English.
+	// LastPaymentErrorDeclineCode is the card issuer's reason for the decline.
+	// LastPaymentErrorPaymentMethodID is the payment method that failed.
+	// LastPaymentErrorChargeID is the ID of the failed charge.
 	LastPaymentErrorCode            string
 	LastPaymentErrorMsg             string
 	LastPaymentErrorDeclineCode     string
@@ -128,19 +363,73 @@ type CallbackEvent struct {
 	CurrentPeriodEnd  int64
 	CancelAtPeriodEnd bool
 	CanceledAt        int64
+	CancelAt          int64
 	CreatedAt         time.Time
+	// TrialEnd is the unix time the subscription's trial ends, or 0. PriceID is the price of
+	// its first item.
+	TrialEnd int64
+	PriceID  string
+	// DaysRemaining is set for customer.subscription.trial_will_end: the whole days left in
+	// the trial when Stripe sent the event, rounded up (usually 3).
+	DaysRemaining int
+	// Items are the subscription's items. PreviousItems holds the items before a
+	// customer.subscription.updated event when the update changed them, and is nil otherwise.
+	Items         []SubscriptionItem
+	PreviousItems []SubscriptionItem
 
 	// Invoice fields
 	InvoiceID    string
 	InvoiceLines []InvoiceLine
+	// InvoiceLinesHasMore is set when the payload truncated the invoice's lines and they were
+	// not fetched, because SetFetchAllInvoiceLines is off.
+	InvoiceLinesHasMore bool
+	// AttemptCount is how many times payment of the invoice has been attempted.
+	// NextPaymentAttempt is the unix time of the next automatic attempt, or 0 when Smart
+	// Retries have given up or the invoice is not collected automatically.
+	AttemptCount       int64
+	NextPaymentAttempt int64
 
-	// Refund fields
+	// Refund fields. For charge.refunded, RefundAmount is the total refunded on the charge,
+	// and the other refund fields describe its latest refund where the payload includes it.
+	// charge.succeeded and charge.failed set ChargeID and Currency, as well as Amount,
+	// Status, CustomerID, PaymentIntentID, PaymentMethodID and, for cards, the Card fields.
 	RefundID     string
 	RefundAmount int64
 	RefundReason string
 	RefundStatus string
 	ChargeID     string
 	Currency     string
+
+	// Account fields, for account.updated. Account is the updated account. The
+	// requirements are compared with those before the update: RequirementsAdded lists the
+	// newly currently due requirements and RequirementsResolved those no longer due, and
+	// PastDueAdded and PastDueResolved do the same for past due ones. They are empty when
+	// the update did not change the list.
+	Account              *Account
+	RequirementsAdded    []string
+	RequirementsResolved []string
+	PastDueAdded         []string
+	PastDueResolved      []string
+
+	// Dispute fields. charge.dispute events set Dispute, as well as ChargeID and
+	// PaymentIntentID; the dispute's Status tells whether a closed dispute was won or lost.
+	Dispute *Dispute
+
+	// Cash balance fields. For cash_balance.funds_available, CashBalance is the customer's
+	// available balance by currency, e.g. after a bank transfer that no payment intent was
+	// waiting for. For customer_cash_balance_transaction.created, CashBalanceTransactionType
+	// is e.g. "funded" or "applied_to_payment", CashBalanceNetAmount is the change to the
+	// balance in Currency (negative when funds leave it), CashBalanceEndingBalance the
+	// balance afterwards, and PaymentIntentID the payment the funds were applied to.
+	CashBalance                map[string]int64
+	CashBalanceTransactionID   string
+	CashBalanceTransactionType string
+	CashBalanceNetAmount       int64
+	CashBalanceEndingBalance   int64
+
+	// Top-up fields. topup events set Topup; on topup.failed its FailureCode and
+	// FailureMessage explain why.
+	Topup *Topup
 }
 
 type InvoiceLine struct {
@@ -151,40 +440,238 @@ type InvoiceLine struct {
 	SubscriptionID string
 }
 
+// AutomaticPaymentMethods controls whether Stripe offers the payment methods enabled in the
+// dashboard for a payment intent. Disabled, the intent only accepts cards.
+type AutomaticPaymentMethods struct {
+	Enabled bool
+	// AllowRedirects is "always" (Stripe's default) or "never", which leaves out payment
+	// methods that redirect the customer, so an intent confirmed on the server needs no
+	// return URL.
+	AllowRedirects string
+}
+
 // Handler abstracts Stripe API interactions and versioning.
 type Handler interface {
 	// Version returns the Stripe API version this handler implements.
 	Version() string
-	// SetSecretKey sets the Stripe secret key for this handler.
+	// APIVersion returns the Stripe API version (e.g. "2025-03-31.basil") the handler's SDK is pinned to.
+	APIVersion() string
+	// SetSecretKey sets the Stripe secret key for this handler. Other handlers, including
+	// those of the same SDK major, are unaffected.
 	SetSecretKey(secretKey string)
-	// SetWebhookSecret sets the Stripe webhook secret for this handler.
+	// SetWebhookSecret sets the Stripe webhook secret HandleWebhook verifies signatures
+	// with. Handlers without one use the STRIPE_WEBHOOK_SECRET environment variable.
 	SetWebhookSecret(webhookSecret string)
+	// SetEndpoints overrides the API, files and Connect base URLs used by this handler.
+	SetEndpoints(endpoints Endpoints)
+	// SetSchemaReporter enables strict validation of webhook payloads against the fields
+	// known to this handler's SDK version. Pass nil to disable.
+	SetSchemaReporter(reporter SchemaReporter)
+	// AsAPIError reports whether err, as returned by the handler's API calls, is an error
+	// from Stripe, and normalizes it, e.g. to look up a MessageCatalog message.
+	AsAPIError(err error) (*APIError, bool)
+	// CreateAccount creates a Connect account for the platform the handler's key belongs to.
+	CreateAccount(ctx context.Context, params AccountParams) (*Account, error)
+	// RetrieveAccount retrieves a connected account, or the platform's own account when
+	// accountID is empty.
+	RetrieveAccount(ctx context.Context, accountID string) (*Account, error)
+	// CreateAccountLink creates a link to Stripe-hosted onboarding for a connected account.
+	CreateAccountLink(ctx context.Context, params AccountLinkParams) (*AccountLink, error)
+	// CreatePerson adds a person, such as the representative or an owner, to a Custom
+	// connected account, and UpdatePerson updates one, e.g. with the details its
+	// RequirementsDue asks for.
+	CreatePerson(ctx context.Context, accountID string, params PersonParams) (*Person, error)
+	UpdatePerson(ctx context.Context, accountID, personID string, params PersonParams) (*Person, error)
+	// DeletePerson removes a person from a Custom connected account.
+	DeletePerson(ctx context.Context, accountID, personID string) error
+	// AddExternalBankAccount adds a bank account that a Custom connected account's payouts
+	// are sent to.
+	AddExternalBankAccount(ctx context.Context, accountID string, params ExternalBankAccountParams) (*ExternalBankAccount, error)
+	// DeleteExternalAccount removes a bank account or card from a connected account. The
+	// default account for a currency cannot be removed.
+	DeleteExternalAccount(ctx context.Context, accountID, externalAccountID string) error
+	// CreateTransfer sends funds from the platform's balance to a connected account.
+	CreateTransfer(ctx context.Context, params TransferParams) (*Transfer, error)
+	// ReverseTransfer moves amount (0 for everything not yet reversed) of a transfer back to
+	// the platform. refundApplicationFee also refunds the application fee of the payment
+	// the transfer was created from, in proportion.
+	ReverseTransfer(ctx context.Context, transferID string, amount int64, refundApplicationFee bool) (*TransferReversal, error)
+	// ListTransfers returns one page of the platform's transfers matching query, newest first.
+	ListTransfers(ctx context.Context, query TransferQuery, opts *ListOptions) (*TransferPage, error)
+	// RetrieveBalance returns the account's balance.
+	RetrieveBalance(ctx context.Context) (*Balance, error)
+	// ListPayouts returns one page of the account's payouts matching query, newest first.
+	ListPayouts(ctx context.Context, query PayoutQuery, opts *ListOptions) (*PayoutPage, error)
+	// ListBalanceTransactions returns one page of the account's balance transactions matching
+	// query, newest first.
+	ListBalanceTransactions(ctx context.Context, query BalanceTransactionQuery, opts *ListOptions) (*BalanceTransactionPage, error)
+	// CreateTopup moves funds from the account's bank account into its Stripe balance. The
+	// top-up is pending until a topup.succeeded or topup.failed webhook.
+	CreateTopup(ctx context.Context, params TopupParams) (*Topup, error)
+	// ListTopups returns one page of the account's top-ups matching query, newest first.
+	ListTopups(ctx context.Context, query TopupQuery, opts *ListOptions) (*TopupPage, error)
+	// ListDisputes returns one page of the account's disputes matching query, newest first.
+	ListDisputes(ctx context.Context, query DisputeQuery, opts *ListOptions) (*DisputePage, error)
+	// RetrieveDispute retrieves a dispute by ID.
+	RetrieveDispute(ctx context.Context, disputeID string) (*Dispute, error)
+	// UpdateDispute saves evidence and metadata on a dispute and, with Submit, submits the
+	// evidence to the card network.
+	UpdateDispute(ctx context.Context, disputeID string, params DisputeUpdate) (*Dispute, error)
+	// CloseDispute accepts a dispute as lost, which cannot be undone.
+	CloseDispute(ctx context.Context, disputeID string) (*Dispute, error)
 	// CreateCustomer creates a customer in Stripe for this version.
 	CreateCustomer(ctx context.Context, params *Customer) (*Customer, error)
 	// UpdateCustomer updates a customer in Stripe for this version.
 	UpdateCustomer(ctx context.Context, customerID string, params *Customer) (*Customer, error)
+	// FindCustomerByEmail returns the most recently created customer with the given email
+	// (matched case-sensitively by Stripe), or nil when there is none.
+	FindCustomerByEmail(ctx context.Context, email string) (*Customer, error)
+	// ListCustomers returns one page of the account's customers, newest first. Pass each
+	// page's NextCursor as opts.StartingAfter to iterate over all of them.
+	ListCustomers(ctx context.Context, opts *ListOptions) (*CustomerPage, error)
+	// CreateCustomerSession creates a customer session enabling the given Stripe.js components.
+	// Versions or components the SDK does not support return an error matching ErrUnsupported.
+	CreateCustomerSession(ctx context.Context, customerID string, components []CustomerSessionComponent) (*CustomerSession, error)
 	// GetPaymentMethods retrieves payment methods for a customer in Stripe for this version.
-	GetPaymentMethods(ctx context.Context, customerID string) ([]*PaymentMethod, error)
+	// Options such as WithOrder(PaymentMethodsDefaultFirst) flag and sort the results.
+	GetPaymentMethods(ctx context.Context, customerID string, opts ...PaymentMethodListOption) ([]*PaymentMethod, error)
+	// RetrievePaymentMethod retrieves a payment method by ID, whether or not it is attached
+	// to a customer.
+	RetrievePaymentMethod(ctx context.Context, paymentMethodID string) (*PaymentMethod, error)
 	// AttachPaymentMethod attaches a payment method to a customer (required for Elements flow).
 	AttachPaymentMethod(ctx context.Context, customerID string, paymentMethodID string) (*PaymentMethod, error)
 	// DetachPaymentMethod detaches a payment method from a customer (for secure removal).
 	DetachPaymentMethod(ctx context.Context, paymentMethodID string) error
 	// CreatePaymentIntent creates a PaymentIntent for secure payment confirmation.
 	CreatePaymentIntent(ctx context.Context, params *PaymentIntent) (*PaymentIntent, error)
+	// PreparePaymentSheet bootstraps the mobile PaymentSheet: it creates a customer when
+	// customerID is empty, an ephemeral key for the customer and an unconfirmed PaymentIntent.
+	PreparePaymentSheet(ctx context.Context, customerID string, amount int64, currency string) (*PaymentSheet, error)
 	// RetrievePaymentIntent retrieves a PaymentIntent by ID.
 	RetrievePaymentIntent(ctx context.Context, paymentIntentID string) (*PaymentIntent, error)
-	// CreateSubscription creates a subscription for a customer.
-	CreateSubscription(ctx context.Context, customerID string, priceID string) (*Subscription, error)
+	// CapturePaymentIntent captures amount (0 for everything capturable) of an authorized intent.
+	// With multicapture, pass final=false to keep the remainder capturable for later captures.
+	CapturePaymentIntent(ctx context.Context, paymentIntentID string, amount int64, final bool) (*PaymentIntent, error)
+	// CancelPaymentIntent cancels an intent that has not succeeded, releasing an uncaptured
+	// authorization. reason is "duplicate", "fraudulent", "requested_by_customer",
+	// "abandoned" or empty.
+	CancelPaymentIntent(ctx context.Context, paymentIntentID string, reason string) (*PaymentIntent, error)
+	// ConfirmPaymentIntent confirms an intent created with ConfirmLater, with the given
+	// payment method or, when paymentMethodID is empty, the one already attached to it.
+	// Options such as WithReturnURL and WithMandateData support redirect-based and
+	// mandate-backed payment methods.
+	ConfirmPaymentIntent(ctx context.Context, paymentIntentID string, paymentMethodID string, opts ...ConfirmOption) (*PaymentIntent, error)
+	// CreateSetupIntent creates a SetupIntent to save a payment method for later payments.
+	CreateSetupIntent(ctx context.Context, params *SetupIntent) (*SetupIntent, error)
+	// RetrieveSetupIntent retrieves a SetupIntent by ID, e.g. when the customer returns from
+	// a redirect.
+	RetrieveSetupIntent(ctx context.Context, setupIntentID string) (*SetupIntent, error)
+	// ConfirmSetupIntent confirms a SetupIntent with the given payment method or, when
+	// paymentMethodID is empty, the one already attached to it.
+	ConfirmSetupIntent(ctx context.Context, setupIntentID string, paymentMethodID string, opts ...ConfirmOption) (*SetupIntent, error)
+	// ListUncapturedPaymentIntents lists intents awaiting capture (status requires_capture)
+	// created within UncapturedAuthorizationWindow, optionally restricted to a customer, so
+	// they can be captured or cancelled before the authorization expires.
+	ListUncapturedPaymentIntents(ctx context.Context, customerID string) ([]*PaymentIntent, error)
+	// RetrieveCharge retrieves a charge by ID.
+	RetrieveCharge(ctx context.Context, chargeID string) (*Charge, error)
+	// ListCharges lists a customer's charges, newest first.
+	ListCharges(ctx context.Context, customerID string) ([]*Charge, error)
+	// CreateCoupon creates a coupon.
+	CreateCoupon(ctx context.Context, params CouponParams) (*Coupon, error)
+	// GetCoupon retrieves a coupon by ID.
+	GetCoupon(ctx context.Context, couponID string) (*Coupon, error)
+	// ValidatePromotionCode looks up an active promotion code by the code customers enter and
+	// checks that the customer can redeem it. It returns an error matching
+	// ErrPromotionCodeInvalid when it cannot be applied. Pass the returned ID to
+	// WithPromotionCode, or the code to PaymentIntent.PromotionCode.
+	ValidatePromotionCode(ctx context.Context, code string, customerID string) (*PromotionCode, error)
+	// CreateSubscription creates a subscription for a customer. Options such as
+	// WithBillingCycleAnchor tailor the subscription, e.g. when migrating customers, and
+	// WithQuantity and WithSubscriptionItems bill seats and several prices.
+	CreateSubscription(ctx context.Context, customerID string, priceID string, opts ...SubscriptionOption) (*Subscription, error)
 	// ListSubscriptions lists subscriptions for a customer.
 	ListSubscriptions(ctx context.Context, customerID string) ([]*Subscription, error)
 	// UpdateSubscription updates a subscription (e.g., change price, cancel at period end).
 	UpdateSubscription(ctx context.Context, subscriptionID string, cancelAtPeriodEnd bool, newPriceID string) (*Subscription, error)
+	// UpdateSubscriptionItems adds, changes and removes items of a subscription in one
+	// update, e.g. to change seat counts or add an add-on price. prorationBehavior is
+	// "create_prorations" (Stripe's default when empty), "always_invoice" or "none".
+	UpdateSubscriptionItems(ctx context.Context, subscriptionID string, items []SubscriptionItemParams, prorationBehavior string) (*Subscription, error)
 	// CancelSubscription cancels a subscription immediately or at period end.
 	CancelSubscription(ctx context.Context, subscriptionID string, atPeriodEnd bool) (*Subscription, error)
+	// CancelSubscriptionAt schedules a subscription to cancel at the given unix time, e.g.
+	// the end date of a fixed-term contract. A cancelAt of 0 removes a scheduled cancellation.
+	CancelSubscriptionAt(ctx context.Context, subscriptionID string, cancelAt int64) (*Subscription, error)
+	// ReconcileSeats sets a per-seat subscription item's quantity to actualSeatCount when the
+	// drift exceeds the policy's threshold. Use policy.DryRun to only report the drift.
+	ReconcileSeats(ctx context.Context, subscriptionID string, actualSeatCount int64, policy SeatPolicy) (*SeatReconciliation, error)
+	// CreateReportRun starts an asynchronous Stripe report run, e.g. a Revenue Recognition report.
+	CreateReportRun(ctx context.Context, req ReportRunRequest) (*ReportRun, error)
+	// GetReportRun retrieves a report run to check its status.
+	GetReportRun(ctx context.Context, reportRunID string) (*ReportRun, error)
+	// DownloadReportRun writes the CSV result of a succeeded report run to w.
+	DownloadReportRun(ctx context.Context, reportRunID string, w io.Writer) error
+	// ListInvoices returns one page of a customer's invoices created within dateRange, newest
+	// first, for billing history pages. An empty status lists invoices of every status, and
+	// an empty customerID the invoices of every customer.
+	ListInvoices(ctx context.Context, customerID string, status InvoiceStatus, dateRange DateRange, opts *ListOptions) (*InvoicePage, error)
+	// RetrieveInvoice retrieves an invoice with all its lines.
+	RetrieveInvoice(ctx context.Context, invoiceID string) (*Invoice, error)
+	// PayInvoice attempts payment of an open invoice now, with the given payment method, or
+	// with the customer's default one when paymentMethodID is empty.
+	PayInvoice(ctx context.Context, invoiceID string, paymentMethodID string) (*Invoice, error)
+	// VoidInvoice voids a finalized invoice that will not be paid.
+	VoidInvoice(ctx context.Context, invoiceID string) (*Invoice, error)
+	// FinalizeInvoice finalizes a draft invoice, which makes it open for payment and gives it
+	// a number, hosted invoice URL and PDF.
+	FinalizeInvoice(ctx context.Context, invoiceID string) (*Invoice, error)
+	// CreateProduct creates a product to sell through prices.
+	CreateProduct(ctx context.Context, params ProductParams) (*Product, error)
+	// CreatePrice creates a one-time or recurring price for a product.
+	CreatePrice(ctx context.Context, params PriceParams) (*Price, error)
+	// GetPrice retrieves a price by ID.
+	GetPrice(ctx context.Context, priceID string) (*Price, error)
+	// ListPrices returns one page of the prices matching query, newest first. Filter by
+	// lookup keys to resolve the current prices of a plan without hardcoding their IDs.
+	ListPrices(ctx context.Context, query PriceQuery, opts *ListOptions) (*PricePage, error)
+	// CreateMeter creates a billing meter for usage-based prices. It is not supported before
+	// stripe-go v76.
+	CreateMeter(ctx context.Context, params MeterParams) (*Meter, error)
+	// ReportMeterEvent records usage for a billing meter. Handlers for stripe-go v80 and later
+	// send it to the v2 meter events endpoint, earlier ones to the v1 endpoint.
+	ReportMeterEvent(ctx context.Context, evt MeterEvent) error
+	// ReportMeterEvents records many usage events, in batches where the version has a batch
+	// endpoint, retrying transient failures. It returns a *MeterEventsError listing the
+	// events that failed. Handlers for stripe-go v80 and later use the v2 meter event stream.
+	ReportMeterEvents(ctx context.Context, events []MeterEvent, opts ...MeterBatchOption) error
+	// CreateEventDestination creates a v2 event destination, which delivers thin or snapshot
+	// events to a webhook endpoint or Amazon EventBridge. Event destinations are not
+	// supported before stripe-go v80.
+	CreateEventDestination(ctx context.Context, params EventDestinationParams) (*EventDestination, error)
+	// GetEventDestination retrieves an event destination by ID.
+	GetEventDestination(ctx context.Context, destinationID string) (*EventDestination, error)
+	// ListEventDestinations returns every event destination of the account.
+	ListEventDestinations(ctx context.Context) ([]*EventDestination, error)
+	// UpdateEventDestination changes the name, description, events, URL or metadata of an
+	// event destination.
+	UpdateEventDestination(ctx context.Context, destinationID string, update EventDestinationUpdate) (*EventDestination, error)
+	// SetEventDestinationEnabled enables or disables an event destination. A disabled
+	// destination receives no events until it is enabled again.
+	SetEventDestinationEnabled(ctx context.Context, destinationID string, enabled bool) (*EventDestination, error)
+	// DeleteEventDestination deletes an event destination.
+	DeleteEventDestination(ctx context.Context, destinationID string) error
+	// ListForExport returns one page of charges, payment intents or invoices created in a
+	// date range, mapped to the common export schema. See the export package.
+	ListForExport(ctx context.Context, q ExportQuery) (*ExportPage, error)
 	// Example: CreateCustomer, Charge, etc. Add more as needed.
 
 	// HandleWebhook processes a Stripe webhook payload and sends events to the channel.
 	HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, error)
+	// HandleEvent normalizes a Stripe event like HandleWebhook, without a signature to
+	// verify, for transports that authenticate events themselves (e.g. Amazon EventBridge).
+	// See IngestEvent.
+	HandleEvent(payload []byte) (*CallbackEvent, error)
 }
 
 // registry holds all registered Stripe handlers by version.
@@ -202,3 +689,21 @@ func RegisterHandler(h Handler) {
 func GetHandler(version string) Handler {
 	return registry[version]
 }
+
+// Handlers returns all registered handlers, ordered by version.
+func Handlers() []Handler {
+	handlers := make([]Handler, 0, len(registry))
+	for _, h := range registry {
+		handlers = append(handlers, h)
+	}
+	sort.Slice(handlers, func(i, j int) bool {
+		return versionNumber(handlers[i].Version()) < versionNumber(handlers[j].Version())
+	})
+	return handlers
+}
+
+// versionNumber converts a handler version such as "v82" into its SDK major.
+func versionNumber(version string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	return n
+}
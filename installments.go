@@ -0,0 +1,58 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInstallmentsUnavailable is matched by the errors returned when installments are
+// requested for a currency, account or card that does not offer them.
+var ErrInstallmentsUnavailable = errors.New("installments not available")
+
+// InstallmentCurrencies lists the lowercase currencies Stripe offers card installments
+// in: Mexican pesos and Japanese yen. Handlers reject installments in other currencies
+// before calling Stripe. Extend it when Stripe adds a market.
+var InstallmentCurrencies = []string{"mxn", "jpy"}
+
+// InstallmentPlan is a card installment plan.
+type InstallmentPlan struct {
+	// Type is "fixed_count", or "bonus" or "revolving" for Japanese cards.
+	Type string
+	// Count is the number of installments of a fixed_count plan, and Interval their
+	// frequency, "month".
+	Count    int64
+	Interval string
+}
+
+// CheckInstallmentCurrency returns an error matching ErrInstallmentsUnavailable when
+// currency is not one of InstallmentCurrencies.
+func CheckInstallmentCurrency(currency string) error {
+	if !slices.Contains(InstallmentCurrencies, strings.ToLower(currency)) {
+		return fmt.Errorf("%w: not offered in %s", ErrInstallmentsUnavailable, currency)
+	}
+	return nil
+}
+
+// InstallmentPlans returns the plans the card of a payment intent created with
+// InstallmentsEnabled and a PaymentMethod can be paid with. Pass the customer's choice to
+// ConfirmPaymentIntent with WithInstallmentPlan. When installments are not enabled on the
+// intent, or the card offers no plan, the error matches ErrInstallmentsUnavailable.
+func InstallmentPlans(ctx context.Context, h Handler, paymentIntentID string) ([]InstallmentPlan, error) {
+	pi, err := h.RetrievePaymentIntent(ctx, paymentIntentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckInstallmentCurrency(pi.Currency); err != nil {
+		return nil, err
+	}
+	switch {
+	case !pi.InstallmentsEnabled:
+		return nil, fmt.Errorf("%w: not enabled on %s", ErrInstallmentsUnavailable, pi.ID)
+	case len(pi.AvailableInstallmentPlans) == 0:
+		return nil, fmt.Errorf("%w: no plans for the card of %s", ErrInstallmentsUnavailable, pi.ID)
+	}
+	return pi.AvailableInstallmentPlans, nil
+}
@@ -0,0 +1,90 @@
+package gomultistripe
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// InvoiceStatus is the status of an invoice.
+type InvoiceStatus string
+
+const (
+	InvoiceDraft         InvoiceStatus = "draft"
+	InvoiceOpen          InvoiceStatus = "open"
+	InvoicePaid          InvoiceStatus = "paid"
+	InvoiceUncollectible InvoiceStatus = "uncollectible"
+	InvoiceVoid          InvoiceStatus = "void"
+)
+
+// DateRange selects times in [From, To). Zero times leave that end of the range open.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ListOptions pages through a list request.
+type ListOptions struct {
+	// Limit is the page size; zero means Stripe's default of 10, anything above
+	// MaxExportPageSize means the maximum.
+	Limit int64
+	// StartingAfter is the NextCursor of the previous page.
+	StartingAfter string
+}
+
+// Invoice is a version-agnostic invoice, as shown on a billing history page.
+type Invoice struct {
+	ID         string
+	Number     string
+	CustomerID string
+	// CustomerName and CustomerEmail are the customer's details as of the invoice's
+	// finalization, or their current details on drafts.
+	CustomerName   string
+	CustomerEmail  string
+	SubscriptionID string
+	Status         InvoiceStatus
+	Currency       string
+	Total          int64
+	AmountDue      int64
+	AmountPaid     int64
+	// AmountRemaining is what is still owed on an open invoice.
+	AmountRemaining int64
+	CreatedAt       time.Time
+	// DueDate is the unix time payment is due for invoices sent for manual payment, or 0.
+	DueDate int64
+	// PaidAt is the unix time the invoice was paid, or 0.
+	PaidAt      int64
+	PeriodStart int64
+	PeriodEnd   int64
+	// HostedInvoiceURL and InvoicePDF are set once the invoice is finalized.
+	HostedInvoiceURL string
+	InvoicePDF       string
+	// Lines are all the invoice's lines, except for invoices from ListInvoices, which include
+	// only the first ten; LinesHasMore is then set if there are more, which RetrieveInvoice
+	// returns.
+	Lines        []InvoiceLine
+	LinesHasMore bool
+	Metadata     map[string]string
+}
+
+// InvoicePage is one page of invoices, newest first.
+type InvoicePage struct {
+	Invoices []*Invoice
+	HasMore  bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
+
+var fetchAllInvoiceLines atomic.Bool
+
+// SetFetchAllInvoiceLines makes handlers retrieve the remaining lines of invoices whose
+// webhook payload was truncated (lines.has_more), so CallbackEvent.InvoiceLines is always
+// complete. It is off by default, as it costs an API request per 100 further lines and fails
+// the webhook, for Stripe to retry, when that request fails.
+func SetFetchAllInvoiceLines(enabled bool) {
+	fetchAllInvoiceLines.Store(enabled)
+}
+
+// FetchAllInvoiceLines reports whether SetFetchAllInvoiceLines is enabled.
+func FetchAllInvoiceLines() bool {
+	return fetchAllInvoiceLines.Load()
+}
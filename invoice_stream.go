@@ -0,0 +1,155 @@
+package gomultistripe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SplitInvoiceLines walks the JSON of an invoice object with a streaming decoder. It
+// decodes the entries of the invoice's lines list one at a time into InvoiceLines, and
+// returns the rest of the invoice, without its lines, for the caller to unmarshal into its
+// SDK struct. hasMore reports that Stripe truncated the list. Invoices with thousands of
+// lines are thus never materialized as SDK line item structs, which hold every field of
+// every line. Handlers use it to parse invoice events.
+func SplitInvoiceLines(object []byte) (invoice []byte, lines []InvoiceLine, hasMore bool, err error) {
+	dec := json.NewDecoder(bytes.NewReader(object))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, false, err
+	}
+	var rest bytes.Buffer
+	rest.Grow(len(object))
+	rest.WriteByte('{')
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if key == "lines" {
+			if lines, hasMore, err = decodeInvoiceLineList(dec); err != nil {
+				return nil, nil, false, err
+			}
+			continue
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, false, err
+		}
+		if rest.Len() > 1 {
+			rest.WriteByte(',')
+		}
+		quoted, _ := json.Marshal(key)
+		rest.Write(quoted)
+		rest.WriteByte(':')
+		rest.Write(value)
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, false, err
+	}
+	rest.WriteByte('}')
+	return rest.Bytes(), lines, hasMore, nil
+}
+
+// invoiceLineJSON holds the fields of an invoice line item that InvoiceLine keeps.
+type invoiceLineJSON struct {
+	ID          string `json:"id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Description string `json:"description"`
+	// Subscription is an ID, or an object when expanded.
+	Subscription json.RawMessage `json:"subscription"`
+}
+
+func decodeInvoiceLineList(dec *json.Decoder) ([]InvoiceLine, bool, error) {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return nil, false, err
+	}
+	if tok != json.Delim('{') {
+		return nil, false, fmt.Errorf("invoice lines: expected an object, got %v", tok)
+	}
+	var lines []InvoiceLine
+	var hasMore bool
+	for dec.More() {
+		key, err := objectKey(dec)
+		if err != nil {
+			return nil, false, err
+		}
+		switch key {
+		case "data":
+			if tok, err := dec.Token(); err != nil || tok == nil {
+				if err != nil {
+					return nil, false, err
+				}
+				continue
+			} else if tok != json.Delim('[') {
+				return nil, false, fmt.Errorf("invoice lines: expected an array, got %v", tok)
+			}
+			for dec.More() {
+				var line invoiceLineJSON
+				if err := dec.Decode(&line); err != nil {
+					return nil, false, fmt.Errorf("invoice lines: %w", err)
+				}
+				lines = append(lines, InvoiceLine{
+					ID:             line.ID,
+					Amount:         line.Amount,
+					Currency:       line.Currency,
+					Description:    line.Description,
+					SubscriptionID: expandableID(line.Subscription),
+				})
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return nil, false, err
+			}
+		case "has_more":
+			if err := dec.Decode(&hasMore); err != nil {
+				return nil, false, err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return lines, hasMore, expectDelim(dec, '}')
+}
+
+// expandableID returns the ID of a field Stripe renders as an ID or, when expanded, as an
+// object with an id.
+func expandableID(raw json.RawMessage) string {
+	var id string
+	if json.Unmarshal(raw, &id) == nil {
+		return id
+	}
+	var object struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(raw, &object) == nil {
+		return object.ID
+	}
+	return ""
+}
+
+func objectKey(dec *json.Decoder) (string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: expected an object key, got %v", ErrInvalidWebhook, tok)
+	}
+	return key, nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("%w: expected %v, got %v", ErrInvalidWebhook, want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSplitInvoiceLines(t *testing.T) {
+	object := []byte(`{"id":"in_1","lines":{"object":"list","data":[
+		{"id":"il_1","amount":500,"currency":"usd","description":"Seat","subscription":"sub_1","period":{"start":1}},
+		{"id":"il_2","amount":250,"currency":"usd","subscription":{"id":"sub_2","object":"subscription"}},
+		{"id":"il_3","amount":-100,"currency":"usd","subscription":null}
+	],"has_more":true,"url":"/v1/invoices/in_1/lines"},"metadata":{"order":"42"},"total":650}`)
+
+	rest, lines, hasMore, err := SplitInvoiceLines(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(rest, &got); err != nil {
+		t.Fatalf("invoice without lines is not valid JSON: %v: %s", err, rest)
+	}
+	want := map[string]any{"id": "in_1", "metadata": map[string]any{"order": "42"}, "total": 650.0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("invoice = %v, want %v", got, want)
+	}
+	wantLines := []InvoiceLine{
+		{ID: "il_1", Amount: 500, Currency: "usd", Description: "Seat", SubscriptionID: "sub_1"},
+		{ID: "il_2", Amount: 250, Currency: "usd", SubscriptionID: "sub_2"},
+		{ID: "il_3", Amount: -100, Currency: "usd"},
+	}
+	if !reflect.DeepEqual(lines, wantLines) || !hasMore {
+		t.Errorf("lines = %+v, has more %v", lines, hasMore)
+	}
+
+	if _, lines, _, err := SplitInvoiceLines([]byte(`{"id":"in_2","lines":null}`)); err != nil || lines != nil {
+		t.Errorf("null lines: got %v, %v", lines, err)
+	}
+	if _, _, _, err := SplitInvoiceLines([]byte(`{"id":"in_3","lines":{"data":[{"id":`)); err == nil {
+		t.Error("truncated payload: expected an error")
+	}
+}
@@ -0,0 +1,148 @@
+package gomultistripe
+
+import (
+	"fmt"
+	"time"
+)
+
+// JournalLine debits or credits one ledger account. Exactly one of Debit and Credit is
+// non-zero, in the minor unit of the entry's currency.
+type JournalLine struct {
+	Account string
+	Debit   int64
+	Credit  int64
+}
+
+// JournalEntry is a balanced double-entry journal entry for one balance transaction.
+type JournalEntry struct {
+	// ID is the balance transaction's ID, so importing an entry twice can be detected.
+	ID       string
+	Date     time.Time
+	Currency string
+	// Category is the transaction's reporting category, e.g. "charge", "refund" or "payout".
+	Category string
+	// Reference is the payment intent, charge or other source the entry is for.
+	Reference   string
+	Description string
+	Lines       []JournalLine
+}
+
+// Balanced reports whether the entry's debits equal its credits.
+func (e *JournalEntry) Balanced() bool {
+	var debits, credits int64
+	for _, l := range e.Lines {
+		debits += l.Debit
+		credits += l.Credit
+	}
+	return debits == credits
+}
+
+// LedgerMapper converts balance transactions, which record every payment, refund, fee,
+// dispute and payout in a Stripe balance, into journal entries. Implement it to post to a
+// chart of accounts NewLedgerMapper cannot express.
+type LedgerMapper interface {
+	// Map returns the entries for txn, or none to skip it.
+	Map(txn *BalanceTransaction) ([]JournalEntry, error)
+}
+
+// LedgerAccounts holds the account codes NewLedgerMapper posts to.
+type LedgerAccounts struct {
+	// StripeBalance is the asset account of the funds held by Stripe, and Bank the account
+	// payouts arrive in and top-ups come from.
+	StripeBalance string
+	Bank          string
+	Revenue       string
+	Refunds       string
+	Fees          string
+	Disputes      string
+	// Transfers is debited for funds sent to connected accounts.
+	Transfers string
+	// Suspense receives the transactions of any other category, for manual review.
+	Suspense string
+	// Categories overrides the account of a reporting category, e.g.
+	// {"partial_capture_reversal": "4000"}.
+	Categories map[string]string
+}
+
+// DefaultLedgerAccounts are descriptive account names, to be replaced by the codes of the
+// chart of accounts the entries are imported into.
+var DefaultLedgerAccounts = LedgerAccounts{
+	StripeBalance: "stripe_balance",
+	Bank:          "bank",
+	Revenue:       "revenue",
+	Refunds:       "refunds",
+	Fees:          "stripe_fees",
+	Disputes:      "disputes",
+	Transfers:     "transfers",
+	Suspense:      "suspense",
+}
+
+// NewLedgerMapper returns the standard double-entry mapping: each transaction's Net is
+// debited (or, when negative, credited) to StripeBalance, its Fee debited to Fees, and its
+// Amount credited (or, when negative, debited) to the account of its reporting category. A
+// charge of 100.00 with a 3.20 fee thus debits StripeBalance 96.80 and Fees 3.20, and credits
+// Revenue 100.00.
+func NewLedgerMapper(accounts LedgerAccounts) LedgerMapper {
+	return &doubleEntryMapper{accounts: accounts}
+}
+
+type doubleEntryMapper struct {
+	accounts LedgerAccounts
+}
+
+func (m *doubleEntryMapper) Map(txn *BalanceTransaction) ([]JournalEntry, error) {
+	entry := JournalEntry{
+		ID:          txn.ID,
+		Date:        txn.CreatedAt,
+		Currency:    txn.Currency,
+		Category:    txn.ReportingCategory,
+		Reference:   txn.SourceID,
+		Description: txn.Description,
+	}
+	if txn.PaymentIntentID != "" {
+		entry.Reference = txn.PaymentIntentID
+	} else if txn.ChargeID != "" {
+		entry.Reference = txn.ChargeID
+	}
+	entry.Lines = appendJournalLine(entry.Lines, m.accounts.StripeBalance, txn.Net)
+	entry.Lines = appendJournalLine(entry.Lines, m.accounts.Fees, txn.Fee)
+	entry.Lines = appendJournalLine(entry.Lines, m.account(txn.ReportingCategory), -txn.Amount)
+	if !entry.Balanced() {
+		return nil, fmt.Errorf("balance transaction %s does not balance: amount %d, fee %d, net %d", txn.ID, txn.Amount, txn.Fee, txn.Net)
+	}
+	return []JournalEntry{entry}, nil
+}
+
+// account returns the account of a reporting category.
+func (m *doubleEntryMapper) account(category string) string {
+	if account, ok := m.accounts.Categories[category]; ok {
+		return account
+	}
+	switch category {
+	case "charge":
+		return m.accounts.Revenue
+	case "refund", "refund_failure":
+		return m.accounts.Refunds
+	case "dispute", "dispute_reversal":
+		return m.accounts.Disputes
+	case "fee":
+		return m.accounts.Fees
+	case "payout", "payout_reversal", "topup", "topup_reversal":
+		return m.accounts.Bank
+	case "transfer", "transfer_reversal":
+		return m.accounts.Transfers
+	}
+	return m.accounts.Suspense
+}
+
+// appendJournalLine appends a debit of amount, or a credit when it is negative. Zero
+// amounts add no line.
+func appendJournalLine(lines []JournalLine, account string, amount int64) []JournalLine {
+	switch {
+	case amount > 0:
+		return append(lines, JournalLine{Account: account, Debit: amount})
+	case amount < 0:
+		return append(lines, JournalLine{Account: account, Credit: -amount})
+	}
+	return lines
+}
@@ -0,0 +1,61 @@
+package gomultistripe
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLedgerMapper(t *testing.T) {
+	accounts := DefaultLedgerAccounts
+	accounts.Categories = map[string]string{"partial_capture_reversal": "revenue"}
+	mapper := NewLedgerMapper(accounts)
+	for _, tt := range []struct {
+		txn  BalanceTransaction
+		want []JournalLine
+	}{
+		{
+			BalanceTransaction{ID: "txn_ch", ReportingCategory: "charge", Amount: 10000, Fee: 320, Net: 9680, PaymentIntentID: "pi_1"},
+			[]JournalLine{{Account: "stripe_balance", Debit: 9680}, {Account: "stripe_fees", Debit: 320}, {Account: "revenue", Credit: 10000}},
+		},
+		{
+			BalanceTransaction{ID: "txn_re", ReportingCategory: "refund", Amount: -2000, Net: -2000},
+			[]JournalLine{{Account: "stripe_balance", Credit: 2000}, {Account: "refunds", Debit: 2000}},
+		},
+		{
+			BalanceTransaction{ID: "txn_dp", ReportingCategory: "dispute", Amount: -5000, Fee: 1500, Net: -6500},
+			[]JournalLine{{Account: "stripe_balance", Credit: 6500}, {Account: "stripe_fees", Debit: 1500}, {Account: "disputes", Debit: 5000}},
+		},
+		{
+			BalanceTransaction{ID: "txn_fee", ReportingCategory: "fee", Amount: -881, Net: -881},
+			[]JournalLine{{Account: "stripe_balance", Credit: 881}, {Account: "stripe_fees", Debit: 881}},
+		},
+		{
+			BalanceTransaction{ID: "txn_po", ReportingCategory: "payout", Amount: -12079, Net: -12079},
+			[]JournalLine{{Account: "stripe_balance", Credit: 12079}, {Account: "bank", Debit: 12079}},
+		},
+		{
+			BalanceTransaction{ID: "txn_pcr", ReportingCategory: "partial_capture_reversal", Amount: -300, Net: -300},
+			[]JournalLine{{Account: "stripe_balance", Credit: 300}, {Account: "revenue", Debit: 300}},
+		},
+		{
+			BalanceTransaction{ID: "txn_adj", ReportingCategory: "other_adjustment", Amount: 50, Net: 50},
+			[]JournalLine{{Account: "stripe_balance", Debit: 50}, {Account: "suspense", Credit: 50}},
+		},
+	} {
+		entries, err := mapper.Map(&tt.txn)
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("%s: %+v, %v", tt.txn.ID, entries, err)
+		}
+		if e := entries[0]; !slices.Equal(e.Lines, tt.want) || !e.Balanced() {
+			t.Errorf("%s: lines %+v, want %+v", tt.txn.ID, e.Lines, tt.want)
+		}
+	}
+
+	entries, _ := mapper.Map(&BalanceTransaction{ID: "txn_ch", ReportingCategory: "charge", Amount: 100, Net: 100, ChargeID: "ch_1", PaymentIntentID: "pi_1"})
+	if entries[0].Reference != "pi_1" || entries[0].Category != "charge" {
+		t.Errorf("entry %+v", entries[0])
+	}
+	if _, err := mapper.Map(&BalanceTransaction{ID: "txn_bad", ReportingCategory: "charge", Amount: 100, Fee: 3, Net: 100}); err == nil {
+		t.Error("mapped an unbalanced transaction")
+	}
+}
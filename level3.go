@@ -0,0 +1,103 @@
+package gomultistripe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Level3 carries Level 2/Level 3 card data (line items, tax and shipping) that B2B
+// merchants can send with a PaymentIntent to qualify for lower interchange rates.
+// The feature is gated by Stripe; accounts without access get an API error.
+type Level3 struct {
+	// MerchantReference is the merchant's order or invoice number. Required.
+	MerchantReference  string
+	CustomerReference  string
+	ShippingAddressZip string
+	ShippingFromZip    string
+	ShippingAmount     int64
+	LineItems          []Level3LineItem
+}
+
+// Level3LineItem is one line of Level 3 data. Amounts are in the smallest currency unit.
+type Level3LineItem struct {
+	// ProductCode is at most 12 characters.
+	ProductCode string
+	// ProductDescription is at most 26 characters.
+	ProductDescription string
+	UnitCost           int64
+	Quantity           int64
+	TaxAmount          int64
+	DiscountAmount     int64
+}
+
+// Validate checks the constraints Stripe applies to Level 3 data, including that the
+// line items and shipping add up to the payment amount.
+func (l *Level3) Validate(amount int64) error {
+	if err := l.ValidateLineItems(); err != nil {
+		return err
+	}
+	if total := l.Total(); total != amount {
+		return fmt.Errorf("level3: line items and shipping total %d, payment amount is %d", total, amount)
+	}
+	return nil
+}
+
+// ValidateLineItems checks the constraints Validate does except for the total, for use
+// before the payment amount is known.
+func (l *Level3) ValidateLineItems() error {
+	if l.MerchantReference == "" {
+		return errors.New("level3: merchant reference is required")
+	}
+	if len(l.LineItems) == 0 {
+		return errors.New("level3: at least one line item is required")
+	}
+	for i, item := range l.LineItems {
+		if len(item.ProductCode) > 12 {
+			return fmt.Errorf("level3: line item %d product code exceeds 12 characters", i)
+		}
+		if len(item.ProductDescription) > 26 {
+			return fmt.Errorf("level3: line item %d product description exceeds 26 characters", i)
+		}
+	}
+	return nil
+}
+
+// Total returns the sum of the line items, net of tax and discounts, and shipping.
+func (l *Level3) Total() int64 {
+	total := l.ShippingAmount
+	for _, item := range l.LineItems {
+		total += item.UnitCost*item.Quantity + item.TaxAmount - item.DiscountAmount
+	}
+	return total
+}
+
+// FormParams encodes the data as Stripe level3 form parameters. No stripe-go version
+// has typed Level 3 params for PaymentIntents, so handlers send these as extra params.
+func (l *Level3) FormParams() map[string]string {
+	params := map[string]string{
+		"level3[merchant_reference]": l.MerchantReference,
+	}
+	if l.CustomerReference != "" {
+		params["level3[customer_reference]"] = l.CustomerReference
+	}
+	if l.ShippingAddressZip != "" {
+		params["level3[shipping_address_zip]"] = l.ShippingAddressZip
+	}
+	if l.ShippingFromZip != "" {
+		params["level3[shipping_from_zip]"] = l.ShippingFromZip
+	}
+	if l.ShippingAmount != 0 {
+		params["level3[shipping_amount]"] = strconv.FormatInt(l.ShippingAmount, 10)
+	}
+	for i, item := range l.LineItems {
+		prefix := "level3[line_items][" + strconv.Itoa(i) + "]"
+		params[prefix+"[product_code]"] = item.ProductCode
+		params[prefix+"[product_description]"] = item.ProductDescription
+		params[prefix+"[unit_cost]"] = strconv.FormatInt(item.UnitCost, 10)
+		params[prefix+"[quantity]"] = strconv.FormatInt(item.Quantity, 10)
+		params[prefix+"[tax_amount]"] = strconv.FormatInt(item.TaxAmount, 10)
+		params[prefix+"[discount_amount]"] = strconv.FormatInt(item.DiscountAmount, 10)
+	}
+	return params
+}
@@ -0,0 +1,25 @@
+package gomultistripe
+
+import "testing"
+
+func TestLevel3_Validate(t *testing.T) {
+	item := Level3LineItem{ProductCode: "WIDGET", ProductDescription: "Widget", UnitCost: 1000, Quantity: 2, TaxAmount: 200, DiscountAmount: 100}
+	for _, tc := range []struct {
+		name   string
+		level3 Level3
+		amount int64
+		ok     bool
+	}{
+		{"valid", Level3{MerchantReference: "PO-1", ShippingAmount: 500, LineItems: []Level3LineItem{item}}, 2600, true},
+		{"no merchant reference", Level3{LineItems: []Level3LineItem{item}}, 2100, false},
+		{"no line items", Level3{MerchantReference: "PO-1", ShippingAmount: 500}, 500, false},
+		{"wrong total", Level3{MerchantReference: "PO-1", LineItems: []Level3LineItem{item}}, 2600, false},
+		{"long product code", Level3{MerchantReference: "PO-1", LineItems: []Level3LineItem{{ProductCode: "WIDGET-DELUXE", Quantity: 1}}}, 0, false},
+		{"26 character description", Level3{MerchantReference: "PO-1", LineItems: []Level3LineItem{{ProductDescription: "A widget with every option", Quantity: 1, UnitCost: 1}}}, 1, true},
+		{"long description", Level3{MerchantReference: "PO-1", LineItems: []Level3LineItem{{ProductDescription: "A widget with every option!", Quantity: 1}}}, 0, false},
+	} {
+		if err := tc.level3.Validate(tc.amount); (err == nil) != tc.ok {
+			t.Errorf("%s: got %v", tc.name, err)
+		}
+	}
+}
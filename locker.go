@@ -0,0 +1,71 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+)
+
+// Locker serializes operations on the same key, e.g. a customer ID or email, so that
+// concurrent requests cannot both decide that an object is missing and create it twice.
+// KeyedMutex locks within one process; StoreLocker across the replicas of a deployment.
+type Locker interface {
+	// Lock blocks until key is free or ctx is done, and returns the function releasing it.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// KeyedMutex is a Locker for a single process. The zero value is ready to use. Keys no
+// goroutine holds or waits for take no memory.
+type KeyedMutex struct {
+	mu   sync.Mutex
+	keys map[string]*keyLock
+}
+
+type keyLock struct {
+	held chan struct{}
+	refs int
+}
+
+func (m *KeyedMutex) Lock(ctx context.Context, key string) (func(), error) {
+	m.mu.Lock()
+	if m.keys == nil {
+		m.keys = make(map[string]*keyLock)
+	}
+	l, ok := m.keys[key]
+	if !ok {
+		l = &keyLock{held: make(chan struct{}, 1)}
+		m.keys[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	select {
+	case l.held <- struct{}{}:
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				<-l.held
+				m.release(key, l)
+			})
+		}, nil
+	case <-ctx.Done():
+		m.release(key, l)
+		return nil, ctx.Err()
+	}
+}
+
+func (m *KeyedMutex) release(key string, l *keyLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(m.keys, key)
+	}
+}
+
+// lock locks key with locker, which may be nil to not lock at all.
+func lock(ctx context.Context, locker Locker, key string) (func(), error) {
+	if locker == nil {
+		return func() {}, nil
+	}
+	return locker.Lock(ctx, key)
+}
@@ -0,0 +1,45 @@
+package gomultistripe
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Attribute keys used in every log record emitted by this package, so records from
+// different components can be filtered and correlated consistently.
+const (
+	// LogKeyVersion is the handler version, e.g. "v82".
+	LogKeyVersion = "stripe.version"
+	// LogKeyOperation is the operation being performed, e.g. "CreatePaymentIntent" or "HandleWebhook".
+	LogKeyOperation = "operation"
+	// LogKeyRequestID is the Stripe request ID (req_...) associated with the record.
+	LogKeyRequestID = "request_id"
+	// LogKeyLivemode reports whether the object belongs to live or test mode.
+	LogKeyLivemode = "livemode"
+	// LogKeyEventID is the Stripe event ID (evt_...).
+	LogKeyEventID = "event_id"
+	// LogKeyEventType is the Stripe event type.
+	LogKeyEventType = "event_type"
+	// LogKeyAccount is the connected account (acct_...) a request acts on.
+	LogKeyAccount = "stripe.account"
+	// LogKeyTraceID is the trace ID set with ContextWithTraceID.
+	LogKeyTraceID = "trace_id"
+)
+
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger installs the logger used by all handlers and pipeline components. By default
+// nothing is logged; pass nil to restore that.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// Logger returns the configured logger, or one that discards all records.
+func Logger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+var discardLogger = slog.New(slog.DiscardHandler)
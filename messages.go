@@ -0,0 +1,161 @@
+package gomultistripe
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// APIError is a Stripe error in a version-agnostic way, as returned by
+// Handler.AsAPIError for the errors of API calls and by CallbackEvent.PaymentError for
+// failed payments.
+type APIError struct {
+	// Type is e.g. "card_error", "invalid_request_error" or "api_error".
+	Type string
+	// Code is the error code, e.g. "card_declined" or "expired_card", and DeclineCode the
+	// card issuer's reason for a decline, e.g. "insufficient_funds".
+	Code        string
+	DeclineCode string
+	// Message is Stripe's explanation, in English. Card errors' messages can be shown to
+	// customers; others are meant for developers.
+	Message         string
+	Param           string
+	ChargeID        string
+	PaymentIntentID string
+	HTTPStatus      int
+	RequestID       string
+}
+
+func (e *APIError) Error() string {
+	if e.DeclineCode != "" {
+		return fmt.Sprintf("%s (%s: %s)", e.Message, e.Code, e.DeclineCode)
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// PaymentError returns the error of a failed payment event, or nil for events without
+// one.
+func (e *CallbackEvent) PaymentError() *APIError {
+	if e.LastPaymentErrorCode == "" && e.LastPaymentErrorDeclineCode == "" && e.LastPaymentErrorMsg == "" {
+		return nil
+	}
+	return &APIError{
+		Code:            e.LastPaymentErrorCode,
+		DeclineCode:     e.LastPaymentErrorDeclineCode,
+		Message:         e.LastPaymentErrorMsg,
+		ChargeID:        e.LastPaymentErrorChargeID,
+		PaymentIntentID: e.PaymentIntentID,
+	}
+}
+
+// GenericMessageKey is the key of a locale's message for errors it has no message for.
+const GenericMessageKey = "generic"
+
+// DefaultMessages are English messages for the common card errors. Codes that would tell
+// a fraudster why a card was refused, such as lost_card, stolen_card and fraudulent, get
+// the generic decline message, as Stripe recommends.
+var DefaultMessages = map[string]map[string]string{
+	"en": {
+		GenericMessageKey:                       "Something went wrong with your payment. Please try again or use a different payment method.",
+		"card_declined":                         "Your card was declined. Please use a different card or contact your bank.",
+		"generic_decline":                       "Your card was declined. Please use a different card or contact your bank.",
+		"do_not_honor":                          "Your card was declined. Please use a different card or contact your bank.",
+		"lost_card":                             "Your card was declined. Please use a different card or contact your bank.",
+		"stolen_card":                           "Your card was declined. Please use a different card or contact your bank.",
+		"fraudulent":                            "Your card was declined. Please use a different card or contact your bank.",
+		"insufficient_funds":                    "Your card has insufficient funds. Please use a different card.",
+		"card_velocity_exceeded":                "Your card has exceeded its limit. Please use a different card or contact your bank.",
+		"expired_card":                          "Your card has expired. Please use a different card.",
+		"incorrect_cvc":                         "Your card's security code is incorrect.",
+		"invalid_cvc":                           "Your card's security code is invalid.",
+		"incorrect_number":                      "Your card number is incorrect.",
+		"invalid_number":                        "Your card number is invalid.",
+		"invalid_expiry_month":                  "Your card's expiration month is invalid.",
+		"invalid_expiry_year":                   "Your card's expiration year is invalid.",
+		"incorrect_zip":                         "Your card's postal code is incorrect.",
+		"processing_error":                      "An error occurred while processing your card. Please try again.",
+		"authentication_required":               "Your bank requires you to authenticate this payment. Please try again.",
+		"card_not_supported":                    "Your card does not support this type of purchase.",
+		"currency_not_supported":                "Your card does not support this currency.",
+		"payment_intent_authentication_failure": "We could not authenticate your payment method. Please choose a different payment method and try again.",
+	},
+}
+
+// MessageCatalog turns payment errors into customer-facing messages in the customer's
+// language, so that every frontend shows the same wording. Messages are text/templates
+// executed with the *APIError.
+type MessageCatalog struct {
+	defaultLocale string
+	templates     map[string]map[string]*template.Template
+}
+
+// NewMessageCatalog creates a catalog from messages, which maps a locale, e.g. "en" or
+// "pt-BR", to the messages of decline codes and error codes, and of GenericMessageKey for
+// all other errors. Locales are matched case-insensitively. It returns an error if a
+// message does not parse.
+//
+//	catalog, err := gomultistripe.NewMessageCatalog("en", gomultistripe.DefaultMessages, map[string]map[string]string{
+//		"de": {
+//			gomultistripe.GenericMessageKey: "Bei Ihrer Zahlung ist ein Fehler aufgetreten.",
+//			"insufficient_funds":            "Ihre Karte ist nicht ausreichend gedeckt.",
+//		},
+//	})
+//
+// Later maps add to and override the messages of earlier ones.
+func NewMessageCatalog(defaultLocale string, messages ...map[string]map[string]string) (*MessageCatalog, error) {
+	c := &MessageCatalog{
+		defaultLocale: strings.ToLower(defaultLocale),
+		templates:     make(map[string]map[string]*template.Template),
+	}
+	for _, m := range messages {
+		for locale, byCode := range m {
+			locale = strings.ToLower(locale)
+			if c.templates[locale] == nil {
+				c.templates[locale] = make(map[string]*template.Template)
+			}
+			for code, text := range byCode {
+				tmpl, err := template.New(locale + "/" + code).Parse(text)
+				if err != nil {
+					return nil, fmt.Errorf("message %s for %s: %w", code, locale, err)
+				}
+				c.templates[locale][code] = tmpl
+			}
+		}
+	}
+	return c, nil
+}
+
+// Message returns the message for e in locale. It looks up the decline code, then the
+// error code, then the generic message, first in locale, then in its base language ("pt"
+// for "pt-BR") and then in the default locale: a generic message in the customer's
+// language serves them better than a specific one in another. It returns an empty string
+// when no locale has a message for e.
+func (c *MessageCatalog) Message(locale string, e *APIError) string {
+	for _, l := range c.locales(locale) {
+		for _, key := range []string{e.DeclineCode, e.Code, GenericMessageKey} {
+			tmpl, ok := c.templates[l][key]
+			if key == "" || !ok {
+				continue
+			}
+			var buf strings.Builder
+			if err := tmpl.Execute(&buf, e); err != nil {
+				continue
+			}
+			return buf.String()
+		}
+	}
+	return ""
+}
+
+// locales returns the locales to look messages up in, most specific first.
+func (c *MessageCatalog) locales(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	var out []string
+	if locale != "" {
+		out = append(out, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			out = append(out, base)
+		}
+	}
+	return append(out, c.defaultLocale)
+}
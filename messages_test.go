@@ -0,0 +1,51 @@
+package gomultistripe
+
+import "testing"
+
+func TestMessageCatalog(t *testing.T) {
+	catalog, err := NewMessageCatalog("en", DefaultMessages, map[string]map[string]string{
+		"pt": {
+			GenericMessageKey:    "Ocorreu um erro no seu pagamento.",
+			"insufficient_funds": "Seu cartão não tem saldo suficiente.",
+		},
+		"pt-BR": {"expired_card": "Seu cartão expirou."},
+		"en":    {"incorrect_zip": "Postal code {{.Param}} mismatch."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	declined := &APIError{Code: "card_declined", DeclineCode: "insufficient_funds"}
+	for _, tt := range []struct {
+		locale string
+		err    *APIError
+		want   string
+	}{
+		{"en", declined, DefaultMessages["en"]["insufficient_funds"]},
+		{"pt_BR", declined, "Seu cartão não tem saldo suficiente."},
+		{"PT-br", &APIError{Code: "expired_card"}, "Seu cartão expirou."},
+		{"pt-BR", &APIError{Code: "incorrect_cvc"}, "Ocorreu um erro no seu pagamento."},
+		{"fr", &APIError{Code: "card_declined", DeclineCode: "stolen_card"}, DefaultMessages["en"]["card_declined"]},
+		{"", &APIError{Code: "rate_limit"}, DefaultMessages["en"][GenericMessageKey]},
+		{"en", &APIError{Code: "incorrect_zip", Param: "postal_code"}, "Postal code postal_code mismatch."},
+	} {
+		if got := catalog.Message(tt.locale, tt.err); got != tt.want {
+			t.Errorf("Message(%q, %+v) = %q, want %q", tt.locale, tt.err, got, tt.want)
+		}
+	}
+
+	if _, err := NewMessageCatalog("en", map[string]map[string]string{"en": {"x": "{{"}}); err == nil {
+		t.Error("invalid template accepted")
+	}
+	empty, _ := NewMessageCatalog("en")
+	if got := empty.Message("en", declined); got != "" {
+		t.Errorf("empty catalog returned %q", got)
+	}
+
+	evt := &CallbackEvent{PaymentIntentID: "pi_1", LastPaymentErrorCode: "card_declined", LastPaymentErrorDeclineCode: "expired_card"}
+	if got := catalog.Message("pt-BR", evt.PaymentError()); got != "Seu cartão expirou." {
+		t.Errorf("event message %q", got)
+	}
+	if (&CallbackEvent{}).PaymentError() != nil {
+		t.Error("payment error of an event without one")
+	}
+}
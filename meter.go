@@ -0,0 +1,80 @@
+package gomultistripe
+
+import (
+	"cmp"
+	"maps"
+	"strconv"
+	"time"
+)
+
+// MeterAggregation is how a billing meter aggregates the values of its events over a
+// billing period.
+type MeterAggregation string
+
+const (
+	MeterSum   MeterAggregation = "sum"
+	MeterCount MeterAggregation = "count"
+	// MeterLast keeps the value of the period's last event. Stripe accepts it from the
+	// acacia API version.
+	MeterLast MeterAggregation = "last"
+)
+
+// Default event payload keys of a billing meter, used when MeterParams or MeterEvent leave
+// them empty.
+const (
+	DefaultMeterCustomerKey = "stripe_customer_id"
+	DefaultMeterValueKey    = "value"
+)
+
+// MeterParams describes a billing meter to create. Usage-based prices reference the meter,
+// and meter events reported under its EventName are billed through them.
+type MeterParams struct {
+	DisplayName string
+	EventName   string
+	// Aggregation defaults to MeterSum.
+	Aggregation MeterAggregation
+	// CustomerKey and ValueKey are the event payload keys holding the customer ID and the
+	// usage value. They default to DefaultMeterCustomerKey and DefaultMeterValueKey.
+	CustomerKey string
+	ValueKey    string
+}
+
+// Meter is a version-agnostic billing meter.
+type Meter struct {
+	ID          string
+	DisplayName string
+	EventName   string
+	// Status is "active" or "inactive".
+	Status      string
+	Aggregation MeterAggregation
+	CustomerKey string
+	ValueKey    string
+	CreatedAt   time.Time
+}
+
+// MeterEvent is one usage record for a billing meter.
+type MeterEvent struct {
+	EventName  string
+	CustomerID string
+	Value      int64
+	// Timestamp is when the usage occurred. Zero means now.
+	Timestamp time.Time
+	// Identifier makes reporting the event idempotent: Stripe ignores events whose identifier
+	// it has seen within the past day. Empty means Stripe assigns one.
+	Identifier string
+	// CustomerKey and ValueKey must match those of the meter; they default to
+	// DefaultMeterCustomerKey and DefaultMeterValueKey.
+	CustomerKey string
+	ValueKey    string
+	// Payload holds further payload fields, e.g. for meters with dimension filters.
+	Payload map[string]string
+}
+
+// MeterEventPayload returns the payload handlers send for evt.
+func MeterEventPayload(evt MeterEvent) map[string]string {
+	payload := make(map[string]string, len(evt.Payload)+2)
+	maps.Copy(payload, evt.Payload)
+	payload[cmp.Or(evt.CustomerKey, DefaultMeterCustomerKey)] = evt.CustomerID
+	payload[cmp.Or(evt.ValueKey, DefaultMeterValueKey)] = strconv.FormatInt(evt.Value, 10)
+	return payload
+}
@@ -0,0 +1,127 @@
+package gomultistripe
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxMeterEventBatch is the most events Stripe's v2 meter event stream accepts per request.
+const MaxMeterEventBatch = 100
+
+// MeterBatchOptions holds the settings of Handler.ReportMeterEvents.
+type MeterBatchOptions struct {
+	// BatchSize is the number of events sent per request, at most MaxMeterEventBatch, which
+	// is the default. Handlers without a batch endpoint send one event per request.
+	BatchSize int
+	// MaxAttempts is the number of attempts per batch, including the first. Only transient
+	// failures (network errors, rate limiting and server errors) are retried. Defaults to 3.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each further one. Defaults to
+	// 500 milliseconds.
+	Backoff time.Duration
+}
+
+// MeterBatchOption configures MeterBatchOptions.
+type MeterBatchOption func(*MeterBatchOptions)
+
+// WithMeterBatchSize sets the number of events sent per request.
+func WithMeterBatchSize(n int) MeterBatchOption {
+	return func(o *MeterBatchOptions) { o.BatchSize = n }
+}
+
+// WithMeterRetries sets the attempts per batch and the delay before the first retry.
+func WithMeterRetries(maxAttempts int, backoff time.Duration) MeterBatchOption {
+	return func(o *MeterBatchOptions) { o.MaxAttempts, o.Backoff = maxAttempts, backoff }
+}
+
+// NewMeterBatchOptions applies opts in order over the defaults. Handlers use it to read the
+// options passed to ReportMeterEvents.
+func NewMeterBatchOptions(opts ...MeterBatchOption) MeterBatchOptions {
+	o := MeterBatchOptions{BatchSize: MaxMeterEventBatch, MaxAttempts: 3, Backoff: 500 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.BatchSize <= 0 || o.BatchSize > MaxMeterEventBatch {
+		o.BatchSize = MaxMeterEventBatch
+	}
+	o.MaxAttempts = max(o.MaxAttempts, 1)
+	return o
+}
+
+// MeterEventsError is returned by ReportMeterEvents when some events were not recorded.
+type MeterEventsError struct {
+	// Errors has one entry per reported event, in order: nil for the events recorded, and the
+	// error of the last attempt for the others.
+	Errors []error
+}
+
+func (e *MeterEventsError) Error() string {
+	failed, first := 0, error(nil)
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d meter events failed: %v", failed, len(e.Errors), first)
+}
+
+func (e *MeterEventsError) Unwrap() []error {
+	return e.Errors
+}
+
+// SendMeterEventBatches is the batching and retry loop behind ReportMeterEvents, which
+// handlers implement by providing send and retryable for their SDK. It gives events without
+// an Identifier a random one, so a retried batch cannot count usage twice, and sends them
+// in batches of opts.BatchSize. A batch is retried while retryable reports its error as
+// transient. It returns nil or a *MeterEventsError.
+func SendMeterEventBatches(ctx context.Context, events []MeterEvent, opts MeterBatchOptions, send func(ctx context.Context, batch []MeterEvent) error, retryable func(error) bool) error {
+	events = append([]MeterEvent(nil), events...)
+	for i := range events {
+		if events[i].Identifier == "" {
+			events[i].Identifier = NewIdempotencyKey()
+		}
+	}
+	errs := make([]error, len(events))
+	failed := false
+	for start := 0; start < len(events); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(events))
+		err := sendMeterEventBatch(ctx, events[start:end], opts, send, retryable)
+		if err != nil {
+			failed = true
+			for i := start; i < end; i++ {
+				errs[i] = err
+			}
+		}
+	}
+	if !failed {
+		return nil
+	}
+	return &MeterEventsError{Errors: errs}
+}
+
+func sendMeterEventBatch(ctx context.Context, batch []MeterEvent, opts MeterBatchOptions, send func(ctx context.Context, batch []MeterEvent) error, retryable func(error) bool) error {
+	backoff := opts.Backoff
+	var err error
+	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return err
+			}
+			backoff *= 2
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if err = send(ctx, batch); err == nil || !retryable(err) {
+			return err
+		}
+		Logger().WarnContext(ctx, "meter event batch failed", LogKeyOperation, "ReportMeterEvents", "attempt", attempt, "events", len(batch), "error", err)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSendMeterEventBatches(t *testing.T) {
+	transient, permanent := errors.New("transient"), errors.New("permanent")
+	events := make([]MeterEvent, 5)
+	for i := range events {
+		events[i] = MeterEvent{EventName: "api_requests", CustomerID: "cus_1", Value: int64(i)}
+	}
+	events[4].Identifier = "caller-chosen"
+
+	var batches [][]MeterEvent
+	attempts := map[int64]int{}
+	send := func(ctx context.Context, batch []MeterEvent) error {
+		batches = append(batches, batch)
+		first := batch[0].Value
+		attempts[first]++
+		switch {
+		case first == 0 && attempts[first] == 1:
+			return transient
+		case first == 2:
+			return permanent
+		}
+		return nil
+	}
+	opts := NewMeterBatchOptions(WithMeterBatchSize(2), WithMeterRetries(3, 0))
+	err := SendMeterEventBatches(context.Background(), events, opts, send, func(err error) bool { return err == transient })
+
+	var batchErr *MeterEventsError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 5 {
+		t.Fatalf("expected a MeterEventsError for 5 events, got %v", err)
+	}
+	for i, want := range []error{nil, nil, permanent, permanent, nil} {
+		if batchErr.Errors[i] != want {
+			t.Errorf("event %d: got error %v, want %v", i, batchErr.Errors[i], want)
+		}
+	}
+	if !errors.Is(err, permanent) {
+		t.Error("the error should match the per-event errors")
+	}
+	if len(batches) != 4 || attempts[0] != 2 || attempts[2] != 1 {
+		t.Fatalf("got %d requests, attempts %v", len(batches), attempts)
+	}
+	if batches[0][0].Identifier == "" || batches[0][0].Identifier != batches[1][0].Identifier {
+		t.Error("retries must resend the same generated identifiers")
+	}
+	if batches[3][0].Identifier != "caller-chosen" || events[0].Identifier != "" {
+		t.Error("identifiers must be kept, and the caller's events left unchanged")
+	}
+}
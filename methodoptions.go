@@ -0,0 +1,103 @@
+package gomultistripe
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// PaymentMethodOptions holds payment-method-specific options of a payment intent, by
+// payment method type and option name as in Stripe's API, e.g.
+//
+//	gomultistripe.PaymentMethodOptions{
+//		"klarna": {"preferred_locale": "de-DE"},
+//		"card":   {"installments[enabled]": "true"},
+//	}
+//
+// Nested options are named in form notation, as "installments[enabled]" above.
+type PaymentMethodOptions map[string]map[string]string
+
+// FormParams encodes the options as Stripe payment_method_options form parameters.
+func (o PaymentMethodOptions) FormParams() map[string]string {
+	params := make(map[string]string)
+	for method, options := range o {
+		for name, value := range options {
+			params["payment_method_options["+method+"]"+formKey(name)] = value
+		}
+	}
+	return params
+}
+
+// Unknown returns the first option, as "method.option", that sdkParams has no field for,
+// and false when it models all of them. Handlers pass their SDK's payment method options
+// params, e.g. stripe.PaymentIntentPaymentMethodOptionsParams{}, so that an option the
+// handler's API version does not know is rejected instead of failing at Stripe.
+func (o PaymentMethodOptions) Unknown(sdkParams any) (string, bool) {
+	methods := make([]string, 0, len(o))
+	for method := range o {
+		methods = append(methods, method)
+	}
+	slices.Sort(methods)
+	for _, method := range methods {
+		names := make([]string, 0, len(o[method]))
+		for name := range o[method] {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if !hasFormField(reflect.TypeOf(sdkParams), append([]string{method}, formPath(name)...)) {
+				return method + "." + name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// formPath splits a form key such as "installments[plan][count]" into its segments.
+func formPath(name string) []string {
+	head, rest, _ := strings.Cut(name, "[")
+	path := []string{head}
+	for _, segment := range strings.Split(rest, "[") {
+		if segment = strings.TrimSuffix(segment, "]"); segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return path
+}
+
+// formKey renders name in bracket notation, so "installments[enabled]" becomes
+// "[installments][enabled]".
+func formKey(name string) string {
+	return "[" + strings.Join(formPath(name), "][") + "]"
+}
+
+// hasFormField reports whether the stripe-go params type t has a value field at the form
+// path. Maps and slices accept any remaining path.
+func hasFormField(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return t.Kind() != reflect.Struct
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Slice:
+		return true
+	case reflect.Struct:
+	default:
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		switch {
+		case tag == "*" && f.Anonymous:
+			if hasFormField(f.Type, path) {
+				return true
+			}
+		case tag == path[0]:
+			return hasFormField(f.Type, path[1:])
+		}
+	}
+	return false
+}
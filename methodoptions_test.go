@@ -0,0 +1,55 @@
+package gomultistripe
+
+import (
+	"maps"
+	"testing"
+)
+
+type fakeMethodOptionsParams struct {
+	Card *struct {
+		Installments *struct {
+			Enabled *bool `form:"enabled"`
+			Plan    *struct {
+				Count *int64 `form:"count"`
+			} `form:"plan"`
+		} `form:"installments"`
+	} `form:"card"`
+	Klarna *struct {
+		PreferredLocale *string `form:"preferred_locale"`
+	} `form:"klarna"`
+	Extra map[string]string `form:"extra"`
+}
+
+func TestPaymentMethodOptions(t *testing.T) {
+	opts := PaymentMethodOptions{
+		"card":   {"installments[enabled]": "true", "installments[plan][count]": "3"},
+		"klarna": {"preferred_locale": "de-DE"},
+	}
+	want := map[string]string{
+		"payment_method_options[card][installments][enabled]":     "true",
+		"payment_method_options[card][installments][plan][count]": "3",
+		"payment_method_options[klarna][preferred_locale]":        "de-DE",
+	}
+	if got := opts.FormParams(); !maps.Equal(got, want) {
+		t.Errorf("FormParams() = %v", got)
+	}
+	if name, ok := opts.Unknown(fakeMethodOptionsParams{}); ok {
+		t.Errorf("%s reported unknown", name)
+	}
+	for _, tc := range []struct {
+		opts PaymentMethodOptions
+		want string
+	}{
+		{PaymentMethodOptions{"afterpay_clearpay": {"reference": "x"}}, "afterpay_clearpay.reference"},
+		{PaymentMethodOptions{"klarna": {"preferred_locale": "de-DE", "colour": "pink"}}, "klarna.colour"},
+		{PaymentMethodOptions{"card": {"installments": "true"}}, "card.installments"},
+		{PaymentMethodOptions{"card": {"installments[plan][count][x]": "3"}}, "card.installments[plan][count][x]"},
+	} {
+		if name, ok := tc.opts.Unknown(&fakeMethodOptionsParams{}); !ok || name != tc.want {
+			t.Errorf("Unknown() = %q, %t, want %q", name, ok, tc.want)
+		}
+	}
+	if _, ok := (PaymentMethodOptions{"extra": {"anything[goes]": "1"}}).Unknown(fakeMethodOptionsParams{}); ok {
+		t.Error("map field rejected")
+	}
+}
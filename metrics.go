@@ -0,0 +1,38 @@
+package gomultistripe
+
+import "time"
+
+// Metrics receives measurements from the webhook pipeline. Implementations must be safe
+// for concurrent use. Embed NopMetrics to only implement the hooks you care about.
+type Metrics interface {
+	// ConsumerSucceeded is called when the consumer processes an event without error.
+	ConsumerSucceeded(eventType CallbackEventType, attempt int)
+	// ConsumerFailed is called for every failed consumer attempt.
+	ConsumerFailed(eventType CallbackEventType, attempt int, err error)
+	// EventParked is called when an event exhausts its attempts and is parked.
+	EventParked(eventType CallbackEventType, err error)
+	// EventQueued is called when an event is dispatched, with the time since Stripe created
+	// it. Record it in a histogram to alert when webhook delivery falls behind.
+	EventQueued(eventType CallbackEventType, lag time.Duration)
+	// EventProcessed is called when the consumer succeeds, with the time since Stripe created
+	// the event, covering delivery, queueing and retries.
+	EventProcessed(eventType CallbackEventType, lag time.Duration)
+}
+
+// NopMetrics is a Metrics implementation that discards all measurements.
+type NopMetrics struct{}
+
+func (NopMetrics) ConsumerSucceeded(CallbackEventType, int)        {}
+func (NopMetrics) ConsumerFailed(CallbackEventType, int, error)    {}
+func (NopMetrics) EventParked(CallbackEventType, error)            {}
+func (NopMetrics) EventQueued(CallbackEventType, time.Duration)    {}
+func (NopMetrics) EventProcessed(CallbackEventType, time.Duration) {}
+
+// ConsumerStats holds per-event-type consumer counters.
+type ConsumerStats struct {
+	Succeeded uint64
+	Failed    uint64
+	Parked    uint64
+	// MaxLag is the longest time from Stripe creating an event to it being processed.
+	MaxLag time.Duration
+}
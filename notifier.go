@@ -0,0 +1,232 @@
+package gomultistripe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// DefaultNotifyTemplate is the message of rules without a Template, e.g.
+// "payment_intent.payment_failed pi_123: 1500.00 USD for cus_123 (Your card was declined.)"
+// or "charge.dispute.created dp_123: 50.00 USD disputed as fraudulent".
+const DefaultNotifyTemplate = `{{.Type}}` +
+	`{{with .Dispute}} {{.ID}}: {{money .Amount .Currency}} disputed as {{.Reason}}` +
+	`{{else}}{{with .PaymentIntentID}} {{.}}{{end}}: {{money .Amount .Currency}}{{end}}` +
+	`{{with .CustomerID}} for {{.}}{{end}}{{with .LastPaymentErrorMsg}} ({{.}}){{end}}`
+
+// NotifyRule selects the events a Notifier posts, and how their messages read.
+type NotifyRule struct {
+	// Types are the event types the rule matches.
+	Types []CallbackEventType
+	// MinAmount only matches events of at least MinAmount, in the smallest unit of Currency;
+	// with Currency set, events in other currencies do not match. A dispute's amount is the
+	// disputed amount. Zero matches every amount.
+	MinAmount int64
+	Currency  string
+	// Template is a text/template executed with the *CallbackEvent. Besides the builtins it
+	// can call money, which formats an amount and currency as "12.34 USD". Empty uses
+	// DefaultNotifyTemplate.
+	Template string
+}
+
+// NotifierConfig configures a Notifier.
+type NotifierConfig struct {
+	// URL is a Slack incoming webhook, or any HTTP endpoint accepting a JSON POST.
+	URL string
+	// Rules are matched in order; an event is posted with the first rule it matches, and
+	// dropped when it matches none.
+	Rules []NotifyRule
+	// RateLimit is the number of messages posted per RateInterval (a minute by default).
+	// Messages beyond it are dropped, and the next message posted says how many were. Zero
+	// posts every message.
+	RateLimit    int
+	RateInterval time.Duration
+	// Body encodes the request body of a message. It defaults to {"text": text}, the format
+	// of Slack, Mattermost and Google Chat webhooks.
+	Body func(text string, evt *CallbackEvent) ([]byte, error)
+	// Client posts the messages. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notifier posts a message to a chat or HTTP webhook for the events its rules match, e.g.
+// failed payments above an amount and new disputes. Its Consume method is an
+// EventConsumer, so it is typically run by a Dispatcher:
+//
+//	n, err := gomultistripe.NewNotifier(gomultistripe.NotifierConfig{
+//		URL: slackWebhookURL,
+//		Rules: []gomultistripe.NotifyRule{
+//			{Types: []gomultistripe.CallbackEventType{gomultistripe.EventPaymentIntentPaymentFailed}, MinAmount: 50000, Currency: "usd"},
+//			{Types: []gomultistripe.CallbackEventType{gomultistripe.EventChargeDisputeCreated}},
+//		},
+//		RateLimit: 20,
+//	})
+//	d := gomultistripe.NewDispatcher(n.Wrap(consume), gomultistripe.DispatcherConfig{})
+type Notifier struct {
+	cfg       NotifierConfig
+	templates []*template.Template
+
+	mu          sync.Mutex
+	windowStart time.Time
+	sent        int
+	suppressed  int
+	now         func() time.Time
+}
+
+// NewNotifier creates a notifier posting to cfg.URL. It returns an error if a rule's
+// template does not parse.
+func NewNotifier(cfg NotifierConfig) (*Notifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("notifier: URL is required")
+	}
+	if cfg.RateInterval <= 0 {
+		cfg.RateInterval = time.Minute
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	n := &Notifier{cfg: cfg, now: time.Now}
+	funcs := template.FuncMap{"money": formatMoney}
+	for i, rule := range cfg.Rules {
+		text := rule.Template
+		if text == "" {
+			text = DefaultNotifyTemplate
+		}
+		tmpl, err := template.New(fmt.Sprintf("rule %d", i)).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("notifier: %w", err)
+		}
+		n.templates = append(n.templates, tmpl)
+	}
+	return n, nil
+}
+
+// Consume posts evt if it matches a rule and the rate limit allows it. A failed post is
+// returned, so a dispatcher retries the event.
+func (n *Notifier) Consume(ctx context.Context, evt *CallbackEvent) error {
+	text, ok, err := n.Message(evt)
+	if err != nil || !ok {
+		return err
+	}
+	suppressed, ok := n.allow()
+	if !ok {
+		Logger().DebugContext(ctx, "notification rate limited", LogKeyEventID, evt.EventID, LogKeyEventType, string(evt.Type))
+		return nil
+	}
+	if suppressed > 0 {
+		text += fmt.Sprintf("\n(%d more notifications were dropped by the rate limit)", suppressed)
+	}
+	return n.post(ctx, text, evt)
+}
+
+// Wrap returns a consumer calling consumer, then notifying of the event once it succeeded.
+// Notification failures are logged rather than returned, so that they do not make the
+// dispatcher process the event again.
+func (n *Notifier) Wrap(consumer EventConsumer) EventConsumer {
+	return func(ctx context.Context, evt *CallbackEvent) error {
+		if err := consumer(ctx, evt); err != nil {
+			return err
+		}
+		if err := n.Consume(ctx, evt); err != nil {
+			Logger().WarnContext(ctx, "notification failed", LogKeyEventID, evt.EventID, LogKeyEventType, string(evt.Type), "error", err)
+		}
+		return nil
+	}
+}
+
+// Message renders the message of evt with the first rule it matches, and reports whether
+// it matched one.
+func (n *Notifier) Message(evt *CallbackEvent) (string, bool, error) {
+	for i, rule := range n.cfg.Rules {
+		if !rule.matches(evt) {
+			continue
+		}
+		var buf strings.Builder
+		if err := n.templates[i].Execute(&buf, evt); err != nil {
+			return "", false, fmt.Errorf("notifier: %w", err)
+		}
+		return buf.String(), true, nil
+	}
+	return "", false, nil
+}
+
+// matches reports whether evt is of one of the rule's types and at least its amount.
+func (r *NotifyRule) matches(evt *CallbackEvent) bool {
+	if !slices.Contains(r.Types, evt.Type) {
+		return false
+	}
+	amount, currency := evt.Amount, evt.Currency
+	if evt.Dispute != nil {
+		amount, currency = evt.Dispute.Amount, evt.Dispute.Currency
+	}
+	if r.Currency != "" && !strings.EqualFold(r.Currency, currency) {
+		return false
+	}
+	return amount >= r.MinAmount
+}
+
+// allow counts a message against the rate limit, and returns the number of messages
+// dropped since the last one allowed.
+func (n *Notifier) allow() (int, bool) {
+	if n.cfg.RateLimit <= 0 {
+		return 0, true
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if now := n.now(); now.Sub(n.windowStart) >= n.cfg.RateInterval {
+		n.windowStart, n.sent = now, 0
+	}
+	if n.sent >= n.cfg.RateLimit {
+		n.suppressed++
+		return 0, false
+	}
+	n.sent++
+	suppressed := n.suppressed
+	n.suppressed = 0
+	return suppressed, true
+}
+
+// post sends text to the webhook.
+func (n *Notifier) post(ctx context.Context, text string, evt *CallbackEvent) error {
+	encode := n.cfg.Body
+	if encode == nil {
+		encode = func(text string, _ *CallbackEvent) ([]byte, error) {
+			return json.Marshal(map[string]string{"text": text})
+		}
+	}
+	body, err := encode(text, evt)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notifier: posting event %s: %s", evt.EventID, resp.Status)
+	}
+	return nil
+}
+
+// formatMoney formats an amount in the currency's smallest unit with the currency code.
+func formatMoney(amount int64, currency string) string {
+	if currency == "" {
+		return FormatAmount(amount, currency)
+	}
+	return FormatAmount(amount, currency) + " " + strings.ToUpper(currency)
+}
@@ -0,0 +1,113 @@
+package gomultistripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	var posted []string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Text string }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		posted = append(posted, body.Text)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(NotifierConfig{
+		URL: srv.URL,
+		Rules: []NotifyRule{
+			{Types: []CallbackEventType{EventPaymentIntentPaymentFailed}, MinAmount: 10000, Currency: "usd"},
+			{Types: []CallbackEventType{EventChargeDisputeCreated}, Template: `Dispute {{.Dispute.ID}} for {{money .Dispute.Amount .Dispute.Currency}}`},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	events := []*CallbackEvent{
+		{Type: EventPaymentIntentPaymentFailed, PaymentIntentID: "pi_small", Amount: 9999, Currency: "usd"},
+		{Type: EventPaymentIntentPaymentFailed, PaymentIntentID: "pi_eur", Amount: 50000, Currency: "eur"},
+		{Type: EventPaymentIntentSucceeded, PaymentIntentID: "pi_ok", Amount: 50000, Currency: "usd"},
+		{Type: EventPaymentIntentPaymentFailed, PaymentIntentID: "pi_big", Amount: 150000, Currency: "usd", CustomerID: "cus_1", LastPaymentErrorMsg: "Your card was declined."},
+		{Type: EventChargeDisputeCreated, Dispute: &Dispute{ID: "dp_1", Amount: 5000, Currency: "jpy"}},
+	}
+	for _, evt := range events {
+		if err := n.Consume(ctx, evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"payment_intent.payment_failed pi_big: 1500.00 USD for cus_1 (Your card was declined.)",
+		"Dispute dp_1 for 5000 JPY",
+	}
+	if strings.Join(posted, "|") != strings.Join(want, "|") {
+		t.Errorf("posted %q, want %q", posted, want)
+	}
+
+	status = http.StatusInternalServerError
+	if err := n.Consume(ctx, events[3]); err == nil {
+		t.Error("failed post not returned")
+	}
+	consumed := 0
+	wrapped := n.Wrap(func(ctx context.Context, evt *CallbackEvent) error {
+		consumed++
+		return nil
+	})
+	if err := wrapped(ctx, events[3]); err != nil || consumed != 1 {
+		t.Errorf("wrapped consumer: %v, consumed %d", err, consumed)
+	}
+	failing := n.Wrap(func(ctx context.Context, evt *CallbackEvent) error { return errors.New("boom") })
+	posts := len(posted)
+	if err := failing(ctx, events[3]); err == nil || len(posted) != posts {
+		t.Errorf("failed event notified: %v", err)
+	}
+
+	if _, err := NewNotifier(NotifierConfig{URL: srv.URL, Rules: []NotifyRule{{Template: "{{"}}}); err == nil {
+		t.Error("invalid template accepted")
+	}
+}
+
+func TestNotifier_RateLimit(t *testing.T) {
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Text string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		posted = append(posted, body.Text)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(NotifierConfig{
+		URL:       srv.URL,
+		Rules:     []NotifyRule{{Types: []CallbackEventType{EventChargeDisputeCreated}, Template: "{{.Dispute.ID}}"}},
+		RateLimit: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+	for _, id := range []string{"dp_1", "dp_2", "dp_3", "dp_4"} {
+		if err := n.Consume(context.Background(), &CallbackEvent{Type: EventChargeDisputeCreated, Dispute: &Dispute{ID: id}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if err := n.Consume(context.Background(), &CallbackEvent{Type: EventChargeDisputeCreated, Dispute: &Dispute{ID: "dp_5"}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"dp_1", "dp_2", "dp_5\n(2 more notifications were dropped by the rate limit)"}
+	if strings.Join(posted, "|") != strings.Join(want, "|") {
+		t.Errorf("posted %q, want %q", posted, want)
+	}
+}
@@ -0,0 +1,123 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultOnboardingPollInterval is how often WaitForAccountOnboarded retrieves the account
+// unless configured otherwise.
+const DefaultOnboardingPollInterval = 5 * time.Second
+
+var (
+	// ErrAccountNotOnboarded is returned when a connected account was not onboarded before
+	// the timeout.
+	ErrAccountNotOnboarded = errors.New("account not onboarded")
+	// ErrAccountRejected is returned when Stripe or the platform rejected a connected
+	// account, which therefore never becomes onboarded.
+	ErrAccountRejected = errors.New("account rejected")
+)
+
+// Onboarded reports whether the account can both accept payments and receive payouts.
+func (a *Account) Onboarded() bool {
+	return a.ChargesEnabled && a.PayoutsEnabled
+}
+
+// OnboardingWaitOptions holds the optional settings of WaitForAccountOnboarded.
+type OnboardingWaitOptions struct {
+	// PollInterval is how often the account is retrieved. Defaults to
+	// DefaultOnboardingPollInterval.
+	PollInterval time.Duration
+	// Progress, when set, is called with the account first and whenever its capabilities,
+	// requirements or enabled flags changed since the previous call.
+	Progress func(*Account)
+}
+
+// OnboardingWaitOption configures OnboardingWaitOptions.
+type OnboardingWaitOption func(*OnboardingWaitOptions)
+
+// WithOnboardingPollInterval sets how often the account is retrieved.
+func WithOnboardingPollInterval(interval time.Duration) OnboardingWaitOption {
+	return func(o *OnboardingWaitOptions) { o.PollInterval = interval }
+}
+
+// WithOnboardingProgress calls progress with the account whenever its state changes, e.g.
+// to show the remaining requirements.
+func WithOnboardingProgress(progress func(*Account)) OnboardingWaitOption {
+	return func(o *OnboardingWaitOptions) { o.Progress = progress }
+}
+
+// NewOnboardingWaitOptions applies opts in order.
+func NewOnboardingWaitOptions(opts ...OnboardingWaitOption) OnboardingWaitOptions {
+	o := OnboardingWaitOptions{PollInterval: DefaultOnboardingPollInterval}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WaitForAccountOnboarded polls a connected account, typically after the user returned from
+// the link of CreateAccountLink, until it has charges and payouts enabled. It returns the
+// account with an error matching ErrAccountNotOnboarded when timeout elapses first, and with
+// one matching ErrAccountRejected when the account was rejected. A timeout of 0 waits until
+// ctx is done.
+func WaitForAccountOnboarded(ctx context.Context, h Handler, accountID string, timeout time.Duration, opts ...OnboardingWaitOption) (*Account, error) {
+	o := NewOnboardingWaitOptions(opts...)
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultOnboardingPollInterval
+	}
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+	var last *Account
+	for {
+		acct, err := h.RetrieveAccount(ctx, accountID)
+		if err != nil {
+			return last, err
+		}
+		if o.Progress != nil && (last == nil || onboardingChanged(last, acct)) {
+			o.Progress(acct)
+		}
+		last = acct
+		switch {
+		case acct.Onboarded():
+			return acct, nil
+		case strings.HasPrefix(acct.RequirementsDisabledReason, "rejected."):
+			return acct, fmt.Errorf("%w: %s (%s)", ErrAccountRejected, accountID, acct.RequirementsDisabledReason)
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return acct, fmt.Errorf("%w: %s after %s", ErrAccountNotOnboarded, accountID, timeout)
+		case <-ctx.Done():
+			return acct, ctx.Err()
+		}
+	}
+}
+
+// onboardingChanged reports whether the onboarding state differs between two retrievals of
+// an account.
+func onboardingChanged(a, b *Account) bool {
+	if a.ChargesEnabled != b.ChargesEnabled || a.PayoutsEnabled != b.PayoutsEnabled ||
+		a.DetailsSubmitted != b.DetailsSubmitted || a.RequirementsDisabledReason != b.RequirementsDisabledReason {
+		return true
+	}
+	if !slices.Equal(a.RequirementsDue, b.RequirementsDue) || len(a.Capabilities) != len(b.Capabilities) {
+		return true
+	}
+	for name, status := range a.Capabilities {
+		if b.Capabilities[name] != status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubAccountHandler returns the accounts in order, repeating the last one.
+type stubAccountHandler struct {
+	Handler
+	accounts []Account
+	calls    int
+}
+
+func (h *stubAccountHandler) RetrieveAccount(ctx context.Context, accountID string) (*Account, error) {
+	acct := h.accounts[min(h.calls, len(h.accounts)-1)]
+	h.calls++
+	return &acct, nil
+}
+
+func TestWaitForAccountOnboarded(t *testing.T) {
+	ctx := context.Background()
+	pending := Account{ID: "acct_1", RequirementsDue: []string{"external_account", "tos_acceptance.date"}}
+	submitted := Account{ID: "acct_1", DetailsSubmitted: true, ChargesEnabled: true}
+	onboarded := Account{ID: "acct_1", DetailsSubmitted: true, ChargesEnabled: true, PayoutsEnabled: true}
+
+	stub := &stubAccountHandler{accounts: []Account{pending, pending, submitted, onboarded}}
+	var progress []*Account
+	acct, err := WaitForAccountOnboarded(ctx, stub, "acct_1", time.Second,
+		WithOnboardingPollInterval(time.Millisecond),
+		WithOnboardingProgress(func(a *Account) { progress = append(progress, a) }))
+	if err != nil || !acct.Onboarded() {
+		t.Fatalf("got %+v, %v", acct, err)
+	}
+	if stub.calls != 4 || len(progress) != 3 || !progress[1].ChargesEnabled {
+		t.Errorf("%d calls, progress %+v", stub.calls, progress)
+	}
+
+	stub = &stubAccountHandler{accounts: []Account{pending}}
+	acct, err = WaitForAccountOnboarded(ctx, stub, "acct_1", 20*time.Millisecond, WithOnboardingPollInterval(time.Millisecond))
+	if !errors.Is(err, ErrAccountNotOnboarded) || acct == nil || len(acct.RequirementsDue) != 2 {
+		t.Errorf("timed out with %+v, %v", acct, err)
+	}
+
+	stub = &stubAccountHandler{accounts: []Account{{ID: "acct_1", RequirementsDisabledReason: "rejected.fraud"}}}
+	if _, err := WaitForAccountOnboarded(ctx, stub, "acct_1", time.Second); !errors.Is(err, ErrAccountRejected) {
+		t.Errorf("rejected: %v", err)
+	}
+}
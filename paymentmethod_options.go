@@ -0,0 +1,66 @@
+package gomultistripe
+
+import "sort"
+
+// PaymentMethodOrder is the order Handler.GetPaymentMethods returns payment methods in.
+type PaymentMethodOrder int
+
+const (
+	// PaymentMethodsUnsorted keeps the order of Stripe's list.
+	PaymentMethodsUnsorted PaymentMethodOrder = iota
+	// PaymentMethodsNewestFirst sorts by creation time, most recent first.
+	PaymentMethodsNewestFirst
+	// PaymentMethodsDefaultFirst puts the customer's default payment method first and the
+	// others after it, newest first.
+	PaymentMethodsDefaultFirst
+)
+
+// PaymentMethodListOptions holds the optional settings for Handler.GetPaymentMethods.
+type PaymentMethodListOptions struct {
+	// ResolveDefault sets PaymentMethod.IsDefault on the customer's default payment method
+	// for invoices and subscriptions (invoice_settings.default_payment_method). It costs one
+	// extra request to retrieve the customer.
+	ResolveDefault bool
+	Order          PaymentMethodOrder
+}
+
+// PaymentMethodListOption configures PaymentMethodListOptions.
+type PaymentMethodListOption func(*PaymentMethodListOptions)
+
+// WithDefaultFlag resolves which payment method is the customer's default.
+func WithDefaultFlag() PaymentMethodListOption {
+	return func(o *PaymentMethodListOptions) { o.ResolveDefault = true }
+}
+
+// WithOrder sorts the payment methods. PaymentMethodsDefaultFirst implies WithDefaultFlag.
+func WithOrder(order PaymentMethodOrder) PaymentMethodListOption {
+	return func(o *PaymentMethodListOptions) { o.Order = order }
+}
+
+// NewPaymentMethodListOptions applies opts in order. Handlers use it to read the options
+// passed to GetPaymentMethods.
+func NewPaymentMethodListOptions(opts ...PaymentMethodListOption) PaymentMethodListOptions {
+	var o PaymentMethodListOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Order == PaymentMethodsDefaultFirst {
+		o.ResolveDefault = true
+	}
+	return o
+}
+
+// SortPaymentMethods sorts methods in place. Payment methods created at the same time keep
+// their relative order.
+func SortPaymentMethods(methods []*PaymentMethod, order PaymentMethodOrder) {
+	if order == PaymentMethodsUnsorted {
+		return
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		a, b := methods[i], methods[j]
+		if order == PaymentMethodsDefaultFirst && a.IsDefault != b.IsDefault {
+			return a.IsDefault
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+}
@@ -0,0 +1,47 @@
+package gomultistripe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortPaymentMethods(t *testing.T) {
+	now := time.Now()
+	methods := func() []*PaymentMethod {
+		return []*PaymentMethod{
+			{ID: "pm_old", CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "pm_default", CreatedAt: now.Add(-time.Hour), IsDefault: true},
+			{ID: "pm_new", CreatedAt: now},
+		}
+	}
+	ids := func(ms []*PaymentMethod) []string {
+		var out []string
+		for _, pm := range ms {
+			out = append(out, pm.ID)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		order PaymentMethodOrder
+		want  []string
+	}{
+		{PaymentMethodsUnsorted, []string{"pm_old", "pm_default", "pm_new"}},
+		{PaymentMethodsNewestFirst, []string{"pm_new", "pm_default", "pm_old"}},
+		{PaymentMethodsDefaultFirst, []string{"pm_default", "pm_new", "pm_old"}},
+	} {
+		ms := methods()
+		SortPaymentMethods(ms, tc.order)
+		if got := ids(ms); len(got) != 3 || got[0] != tc.want[0] || got[1] != tc.want[1] || got[2] != tc.want[2] {
+			t.Errorf("order %d: got %v, want %v", tc.order, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultFirstImpliesDefaultFlag(t *testing.T) {
+	if o := NewPaymentMethodListOptions(WithOrder(PaymentMethodsDefaultFirst)); !o.ResolveDefault {
+		t.Fatal("PaymentMethodsDefaultFirst must resolve the default payment method")
+	}
+	if o := NewPaymentMethodListOptions(WithOrder(PaymentMethodsNewestFirst)); o.ResolveDefault {
+		t.Fatal("PaymentMethodsNewestFirst must not cost a customer request")
+	}
+}
@@ -0,0 +1,18 @@
+package gomultistripe
+
+// FindDuplicatePaymentMethods returns the payment methods in methods that are the same
+// card as candidate, based on the card fingerprint. The candidate itself is excluded, so
+// an empty result means the card is not stored yet. Payment methods without a
+// fingerprint never match.
+func FindDuplicatePaymentMethods(methods []*PaymentMethod, candidate *PaymentMethod) []*PaymentMethod {
+	if candidate == nil || candidate.Fingerprint == "" {
+		return nil
+	}
+	var dups []*PaymentMethod
+	for _, pm := range methods {
+		if pm.ID != candidate.ID && pm.Fingerprint == candidate.Fingerprint {
+			dups = append(dups, pm)
+		}
+	}
+	return dups
+}
@@ -0,0 +1,107 @@
+package gomultistripe
+
+import "time"
+
+// Address is a postal address. Country is a two-letter ISO code.
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// PersonRelationship is a person's roles in a connected account's business.
+type PersonRelationship struct {
+	// Representative is the person authorized to act for the business, who accepts Stripe's
+	// terms. Every Custom account needs exactly one.
+	Representative bool
+	Owner          bool
+	Director       bool
+	Executive      bool
+	// Title is the person's job title, e.g. "CEO".
+	Title string
+	// PercentOwnership is the share of the business an owner holds, from 0 to 100.
+	PercentOwnership float64
+}
+
+// PersonParams describes a person of a Custom connected account to create or update.
+// Empty fields are left unset, or unchanged on update.
+type PersonParams struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	// DOB is the date of birth; its time of day is ignored.
+	DOB     time.Time
+	Address *Address
+	// IDNumber is a full government ID number, such as a US SSN, and SSNLast4 the last four
+	// digits of a US SSN where those suffice. Stripe never returns them.
+	IDNumber string
+	SSNLast4 string
+	// Relationship, when set, replaces the person's roles.
+	Relationship *PersonRelationship
+	Metadata     map[string]string
+}
+
+// Person represents a person of a connected account in a version-agnostic way.
+type Person struct {
+	ID        string
+	AccountID string
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+	// DOB is the date of birth, or zero when not provided.
+	DOB          time.Time
+	Address      *Address
+	Relationship PersonRelationship
+	// IDNumberProvided and SSNLast4Provided report whether the ID numbers were provided.
+	IDNumberProvided bool
+	SSNLast4Provided bool
+	// RequirementsDue lists the information still needed about the person, and
+	// VerificationStatus is "unverified", "pending" or "verified".
+	RequirementsDue    []string
+	VerificationStatus string
+	Metadata           map[string]string
+	CreatedAt          time.Time
+}
+
+// ExternalBankAccountParams describes a bank account to receive a connected account's
+// payouts.
+type ExternalBankAccountParams struct {
+	// Token is a bank account token created with Stripe.js. When set, the account details
+	// are taken from it and the other fields but DefaultForCurrency and Metadata are ignored.
+	Token    string
+	Country  string
+	Currency string
+	// AccountHolderType is "individual" or "company".
+	AccountHolderName string
+	AccountHolderType string
+	// RoutingNumber is the routing or sort code, where the country uses one. AccountNumber
+	// is the account number or, in IBAN countries, the IBAN.
+	RoutingNumber string
+	AccountNumber string
+	// DefaultForCurrency makes it the account's default for payouts in its currency.
+	DefaultForCurrency bool
+	Metadata           map[string]string
+}
+
+// ExternalBankAccount represents a bank account connected accounts are paid out to.
+type ExternalBankAccount struct {
+	ID                string
+	AccountID         string
+	Country           string
+	Currency          string
+	BankName          string
+	Last4             string
+	RoutingNumber     string
+	AccountHolderName string
+	AccountHolderType string
+	// Status is "new", "validated", "verified", "verification_failed" or "errored", after
+	// which payouts to it fail.
+	Status             string
+	DefaultForCurrency bool
+	Metadata           map[string]string
+}
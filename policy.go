@@ -0,0 +1,226 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrPolicyViolation is matched (via errors.Is) by every error a PolicyHandler returns
+	// for an operation its policy rejects.
+	ErrPolicyViolation = errors.New("rejected by policy")
+	// ErrAmountAboveLimit is returned for amounts above Policy.MaxAmount.
+	ErrAmountAboveLimit = fmt.Errorf("%w: amount above limit", ErrPolicyViolation)
+	// ErrCurrencyNotAllowed is returned for currencies missing from Policy.AllowedCurrencies.
+	ErrCurrencyNotAllowed = fmt.Errorf("%w: currency not allowed", ErrPolicyViolation)
+	// ErrCountryBlocked is returned for cards issued in one of Policy.BlockedCountries.
+	ErrCountryBlocked = fmt.Errorf("%w: country blocked", ErrPolicyViolation)
+	// ErrApprovalDenied is returned when Policy.ApprovalGate does not approve an operation.
+	ErrApprovalDenied = fmt.Errorf("%w: approval denied", ErrPolicyViolation)
+)
+
+// PolicyError reports which rule of a policy rejected an operation.
+type PolicyError struct {
+	Operation string
+	// Rule is ErrAmountAboveLimit, ErrCurrencyNotAllowed, ErrCountryBlocked or
+	// ErrApprovalDenied.
+	Rule   error
+	Detail string
+	// Cause is the error returned by the approval gate, for ErrApprovalDenied.
+	Cause error
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s %v: %s", e.Operation, e.Rule, e.Detail)
+}
+
+func (e *PolicyError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Rule}
+	}
+	return []error{e.Rule, e.Cause}
+}
+
+// ApprovalRequest describes an operation awaiting approval.
+type ApprovalRequest struct {
+	Operation string
+	Amount    int64
+	Currency  string
+	// CustomerID is the customer the operation concerns, when there is one.
+	CustomerID string
+	// Account is the connected account set with ContextWithAccount, if any.
+	Account string
+	// TraceID is the ID set with ContextWithTraceID, if any.
+	TraceID string
+}
+
+// ApprovalGate decides whether a high-value operation may proceed, e.g. by asking a
+// second person through the consumer's ticketing or chat workflow. Approve blocks until a
+// decision is made or ctx is done, and returns nil to approve.
+type ApprovalGate interface {
+	Approve(ctx context.Context, req ApprovalRequest) error
+}
+
+// ApprovalFunc adapts a function to ApprovalGate.
+type ApprovalFunc func(ctx context.Context, req ApprovalRequest) error
+
+func (f ApprovalFunc) Approve(ctx context.Context, req ApprovalRequest) error {
+	return f(ctx, req)
+}
+
+// Policy holds the rules a PolicyHandler enforces. Zero fields impose no restriction.
+type Policy struct {
+	// MaxAmount caps the amount, in the currency's smallest unit, of payment intents,
+	// payment sheets, prices and transfers. MaxAmountByCurrency overrides it per lowercase
+	// currency code, since the same number means very different sums in different
+	// currencies.
+	MaxAmount           int64
+	MaxAmountByCurrency map[string]int64
+	// AllowedCurrencies lists the currencies payment intents, payment sheets, prices and
+	// transfers may use, as ISO codes in either case.
+	AllowedCurrencies []string
+	// BlockedCountries lists ISO country codes whose cards may not be charged. The payment
+	// method a payment intent is created or confirmed with is retrieved to look up its
+	// issuing country; payment methods whose country is unknown, including every method
+	// other than a card, are rejected too.
+	BlockedCountries []string
+	// ApprovalGate, when set, must approve payment intents, payment sheets and transfers above
+	// ApprovalThreshold (or ApprovalThresholdByCurrency, per lowercase currency code) once
+	// they pass the other rules. A zero threshold sends every such operation for approval.
+	ApprovalGate                ApprovalGate
+	ApprovalThreshold           int64
+	ApprovalThresholdByCurrency map[string]int64
+}
+
+// PolicyHandler is a Handler that checks operations against a policy before they reach
+// Stripe, as a guardrail for internal tools. Operations without amounts, currencies or
+// payment methods are passed through unchanged.
+type PolicyHandler struct {
+	Handler
+	Policy Policy
+}
+
+// WithPolicy wraps a handler so that operations violating policy are rejected with a
+// *PolicyError.
+func WithPolicy(h Handler, policy Policy) *PolicyHandler {
+	return &PolicyHandler{Handler: h, Policy: policy}
+}
+
+// checkAmount enforces the currency and amount rules.
+func (p *PolicyHandler) checkAmount(operation string, amount int64, currency string) error {
+	currency = strings.ToLower(currency)
+	if len(p.Policy.AllowedCurrencies) > 0 && !slices.ContainsFunc(p.Policy.AllowedCurrencies, func(c string) bool {
+		return strings.EqualFold(c, currency)
+	}) {
+		return &PolicyError{Operation: operation, Rule: ErrCurrencyNotAllowed, Detail: currency}
+	}
+	limit, ok := p.Policy.MaxAmountByCurrency[currency]
+	if !ok {
+		limit = p.Policy.MaxAmount
+	}
+	if limit > 0 && amount > limit {
+		return &PolicyError{Operation: operation, Rule: ErrAmountAboveLimit, Detail: fmt.Sprintf("%d %s exceeds %d", amount, currency, limit)}
+	}
+	return nil
+}
+
+// approve consults the approval gate for operations above the approval threshold.
+func (p *PolicyHandler) approve(ctx context.Context, req ApprovalRequest) error {
+	if p.Policy.ApprovalGate == nil {
+		return nil
+	}
+	req.Currency = strings.ToLower(req.Currency)
+	threshold, ok := p.Policy.ApprovalThresholdByCurrency[req.Currency]
+	if !ok {
+		threshold = p.Policy.ApprovalThreshold
+	}
+	if req.Amount <= threshold {
+		return nil
+	}
+	req.Account, _ = AccountFromContext(ctx)
+	req.TraceID, _ = TraceIDFromContext(ctx)
+	if err := p.Policy.ApprovalGate.Approve(ctx, req); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s awaiting approval: %w", req.Operation, ctx.Err())
+		}
+		return &PolicyError{Operation: req.Operation, Rule: ErrApprovalDenied, Detail: err.Error(), Cause: err}
+	}
+	return nil
+}
+
+// checkPaymentMethod enforces the country rule for a payment method.
+func (p *PolicyHandler) checkPaymentMethod(ctx context.Context, operation, paymentMethodID string) error {
+	if len(p.Policy.BlockedCountries) == 0 || paymentMethodID == "" {
+		return nil
+	}
+	pm, err := p.Handler.RetrievePaymentMethod(ctx, paymentMethodID)
+	if err != nil {
+		return fmt.Errorf("policy check of payment method %s: %w", paymentMethodID, err)
+	}
+	if pm.Country == "" {
+		return &PolicyError{Operation: operation, Rule: ErrCountryBlocked, Detail: fmt.Sprintf("%s payment method %s has no known country", pm.Type, pm.ID)}
+	}
+	if slices.ContainsFunc(p.Policy.BlockedCountries, func(c string) bool {
+		return strings.EqualFold(c, pm.Country)
+	}) {
+		return &PolicyError{Operation: operation, Rule: ErrCountryBlocked, Detail: fmt.Sprintf("card %s issued in %s", pm.ID, pm.Country)}
+	}
+	return nil
+}
+
+func (p *PolicyHandler) CreatePaymentIntent(ctx context.Context, params *PaymentIntent) (*PaymentIntent, error) {
+	if err := p.checkAmount("CreatePaymentIntent", params.Amount, params.Currency); err != nil {
+		return nil, err
+	}
+	if err := p.checkPaymentMethod(ctx, "CreatePaymentIntent", params.PaymentMethod); err != nil {
+		return nil, err
+	}
+	req := ApprovalRequest{Operation: "CreatePaymentIntent", Amount: params.Amount, Currency: params.Currency, CustomerID: params.CustomerID}
+	if err := p.approve(ctx, req); err != nil {
+		return nil, err
+	}
+	return p.Handler.CreatePaymentIntent(ctx, params)
+}
+
+// ConfirmPaymentIntent checks the country of the payment method an intent created with
+// ConfirmLater is confirmed with.
+func (p *PolicyHandler) ConfirmPaymentIntent(ctx context.Context, paymentIntentID string, paymentMethodID string, opts ...ConfirmOption) (*PaymentIntent, error) {
+	if err := p.checkPaymentMethod(ctx, "ConfirmPaymentIntent", paymentMethodID); err != nil {
+		return nil, err
+	}
+	return p.Handler.ConfirmPaymentIntent(ctx, paymentIntentID, paymentMethodID, opts...)
+}
+
+func (p *PolicyHandler) PreparePaymentSheet(ctx context.Context, customerID string, amount int64, currency string) (*PaymentSheet, error) {
+	if err := p.checkAmount("PreparePaymentSheet", amount, currency); err != nil {
+		return nil, err
+	}
+	req := ApprovalRequest{Operation: "PreparePaymentSheet", Amount: amount, Currency: currency, CustomerID: customerID}
+	if err := p.approve(ctx, req); err != nil {
+		return nil, err
+	}
+	return p.Handler.PreparePaymentSheet(ctx, customerID, amount, currency)
+}
+
+func (p *PolicyHandler) CreatePrice(ctx context.Context, params PriceParams) (*Price, error) {
+	if err := p.checkAmount("CreatePrice", params.UnitAmount, params.Currency); err != nil {
+		return nil, err
+	}
+	return p.Handler.CreatePrice(ctx, params)
+}
+
+// CreateTransfer applies the currency, amount and approval rules to transfers to connected
+// accounts.
+func (p *PolicyHandler) CreateTransfer(ctx context.Context, params TransferParams) (*Transfer, error) {
+	if err := p.checkAmount("CreateTransfer", params.Amount, params.Currency); err != nil {
+		return nil, err
+	}
+	req := ApprovalRequest{Operation: "CreateTransfer", Amount: params.Amount, Currency: params.Currency}
+	if err := p.approve(ctx, req); err != nil {
+		return nil, err
+	}
+	return p.Handler.CreateTransfer(ctx, params)
+}
@@ -0,0 +1,127 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type stubPaymentHandler struct {
+	Handler
+	created int
+}
+
+func (h *stubPaymentHandler) RetrievePaymentMethod(ctx context.Context, paymentMethodID string) (*PaymentMethod, error) {
+	switch paymentMethodID {
+	case "pm_us":
+		return &PaymentMethod{ID: paymentMethodID, Type: "card", Country: "US"}, nil
+	case "pm_xx":
+		return &PaymentMethod{ID: paymentMethodID, Type: "card", Country: "XX"}, nil
+	case "pm_sepa":
+		return &PaymentMethod{ID: paymentMethodID, Type: "sepa_debit"}, nil
+	}
+	return nil, errors.New("no such payment method")
+}
+
+func (h *stubPaymentHandler) PreparePaymentSheet(ctx context.Context, customerID string, amount int64, currency string) (*PaymentSheet, error) {
+	h.created++
+	return &PaymentSheet{}, nil
+}
+
+func (h *stubPaymentHandler) CreatePaymentIntent(ctx context.Context, params *PaymentIntent) (*PaymentIntent, error) {
+	h.created++
+	return params, nil
+}
+
+func TestWithPolicy(t *testing.T) {
+	stub := &stubPaymentHandler{}
+	h := WithPolicy(stub, Policy{
+		MaxAmount:           10000,
+		MaxAmountByCurrency: map[string]int64{"jpy": 1000000},
+		AllowedCurrencies:   []string{"USD", "eur", "jpy"},
+		BlockedCountries:    []string{"xx"},
+	})
+	ctx := context.Background()
+	for _, tc := range []struct {
+		params *PaymentIntent
+		want   error
+	}{
+		{&PaymentIntent{Amount: 5000, Currency: "usd"}, nil},
+		{&PaymentIntent{Amount: 500000, Currency: "jpy"}, nil},
+		{&PaymentIntent{Amount: 10001, Currency: "eur"}, ErrAmountAboveLimit},
+		{&PaymentIntent{Amount: 100, Currency: "gbp"}, ErrCurrencyNotAllowed},
+		{&PaymentIntent{Amount: 100, Currency: "usd", CustomerID: "cus_1", PaymentMethod: "pm_us"}, nil},
+		{&PaymentIntent{Amount: 100, Currency: "usd", CustomerID: "cus_1", PaymentMethod: "pm_xx"}, ErrCountryBlocked},
+		// Without a customer, or for a method whose country is unknown, the rule still applies.
+		{&PaymentIntent{Amount: 100, Currency: "usd", PaymentMethod: "pm_xx"}, ErrCountryBlocked},
+		{&PaymentIntent{Amount: 100, Currency: "usd", PaymentMethod: "pm_sepa"}, ErrCountryBlocked},
+	} {
+		_, err := h.CreatePaymentIntent(ctx, tc.params)
+		if !errors.Is(err, tc.want) || (tc.want != nil && !errors.Is(err, ErrPolicyViolation)) {
+			t.Errorf("%+v: got %v, want %v", tc.params, err, tc.want)
+		}
+	}
+	if stub.created != 3 {
+		t.Errorf("%d intents reached the handler, want 3", stub.created)
+	}
+	if _, err := h.CreatePaymentIntent(ctx, &PaymentIntent{Amount: 100, Currency: "usd", PaymentMethod: "pm_missing"}); err == nil || stub.created != 3 {
+		t.Errorf("payment method lookup failure: got %v", err)
+	}
+	var pe *PolicyError
+	if _, err := h.PreparePaymentSheet(ctx, "", 20000, "usd"); !errors.As(err, &pe) || pe.Operation != "PreparePaymentSheet" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestWithPolicy_ApprovalGate(t *testing.T) {
+	stub := &stubPaymentHandler{}
+	var asked []ApprovalRequest
+	h := WithPolicy(stub, Policy{
+		ApprovalThreshold: 100000,
+		ApprovalGate: ApprovalFunc(func(ctx context.Context, req ApprovalRequest) error {
+			asked = append(asked, req)
+			if req.Amount > 500000 {
+				return errors.New("denied by finance")
+			}
+			return nil
+		}),
+	})
+	ctx := ContextWithAccount(context.Background(), "acct_1")
+	for _, amount := range []int64{50000, 200000, 600000} {
+		h.CreatePaymentIntent(ctx, &PaymentIntent{Amount: amount, Currency: "USD", CustomerID: "cus_1"})
+	}
+	if len(asked) != 2 || asked[0].Account != "acct_1" || asked[0].Currency != "usd" || asked[0].CustomerID != "cus_1" {
+		t.Errorf("asked for %+v", asked)
+	}
+	if stub.created != 2 {
+		t.Errorf("%d intents reached the handler, want 2", stub.created)
+	}
+
+	_, err := h.CreatePaymentIntent(ctx, &PaymentIntent{Amount: 700000, Currency: "usd"})
+	var pe *PolicyError
+	if !errors.Is(err, ErrApprovalDenied) || !errors.As(err, &pe) || pe.Cause == nil || pe.Cause.Error() != "denied by finance" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestWithPolicy_ApprovalGateCoversPaymentSheets(t *testing.T) {
+	stub := &stubPaymentHandler{}
+	var asked []ApprovalRequest
+	h := WithPolicy(stub, Policy{
+		ApprovalThreshold: 100000,
+		ApprovalGate: ApprovalFunc(func(ctx context.Context, req ApprovalRequest) error {
+			asked = append(asked, req)
+			return errors.New("denied by finance")
+		}),
+	})
+	ctx := context.Background()
+	if _, err := h.PreparePaymentSheet(ctx, "cus_1", 50000, "usd"); err != nil {
+		t.Fatalf("below the threshold: %v", err)
+	}
+	if _, err := h.PreparePaymentSheet(ctx, "cus_1", 200000, "usd"); !errors.Is(err, ErrApprovalDenied) {
+		t.Errorf("above the threshold: got %v", err)
+	}
+	if stub.created != 1 || len(asked) != 1 || asked[0].Operation != "PreparePaymentSheet" || asked[0].CustomerID != "cus_1" {
+		t.Errorf("%d sheets prepared after asking for %+v", stub.created, asked)
+	}
+}
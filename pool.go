@@ -0,0 +1,45 @@
+package gomultistripe
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	callbackEventPooling atomic.Bool
+	callbackEventPool    = sync.Pool{New: func() any { return new(CallbackEvent) }}
+)
+
+// SetCallbackEventPooling makes handlers take the events they return from a sync.Pool,
+// which reduces GC pressure for consumers processing tens of thousands of events per
+// minute. Events must then be given back with Release once processed. It is off by default.
+func SetCallbackEventPooling(enabled bool) {
+	callbackEventPooling.Store(enabled)
+}
+
+// CallbackEventPooling reports whether SetCallbackEventPooling is enabled.
+func CallbackEventPooling() bool {
+	return callbackEventPooling.Load()
+}
+
+// NewCallbackEvent returns a copy of evt, in storage taken from the pool when pooling is
+// enabled. Handlers use it to return the events they parse.
+func NewCallbackEvent(evt CallbackEvent) *CallbackEvent {
+	if !callbackEventPooling.Load() {
+		return &evt
+	}
+	pooled := callbackEventPool.Get().(*CallbackEvent)
+	*pooled = evt
+	return pooled
+}
+
+// Release returns the event to the pool when pooling is enabled, and does nothing
+// otherwise. The event, including its slices and maps, must not be used afterwards: copy
+// out anything that outlives processing first.
+func (evt *CallbackEvent) Release() {
+	if evt == nil || !callbackEventPooling.Load() {
+		return
+	}
+	*evt = CallbackEvent{}
+	callbackEventPool.Put(evt)
+}
@@ -0,0 +1,82 @@
+package gomultistripe
+
+import "time"
+
+// PriceInterval is the billing frequency of a recurring price.
+type PriceInterval string
+
+const (
+	PriceDaily   PriceInterval = "day"
+	PriceWeekly  PriceInterval = "week"
+	PriceMonthly PriceInterval = "month"
+	PriceYearly  PriceInterval = "year"
+)
+
+// Product is a version-agnostic product: the goods or service that prices are for.
+type Product struct {
+	ID          string
+	Name        string
+	Description string
+	Active      bool
+	Metadata    map[string]string
+	CreatedAt   time.Time
+}
+
+// ProductParams describes a product to create.
+type ProductParams struct {
+	Name        string
+	Description string
+	Metadata    map[string]string
+}
+
+// Price is a version-agnostic price of a product.
+type Price struct {
+	ID        string
+	ProductID string
+	Currency  string
+	// UnitAmount is in the currency's smallest unit.
+	UnitAmount int64
+	// Interval is empty for one-time prices. IntervalCount is the number of intervals
+	// between billings, e.g. 3 with PriceMonthly for quarterly billing.
+	Interval      PriceInterval
+	IntervalCount int64
+	// LookupKey identifies the price in code, so it can be replaced without deploying a
+	// new price ID.
+	LookupKey string
+	Nickname  string
+	Active    bool
+	Metadata  map[string]string
+	CreatedAt time.Time
+}
+
+// PriceParams describes a price to create.
+type PriceParams struct {
+	ProductID  string
+	Currency   string
+	UnitAmount int64
+	// Interval makes the price recurring; leave it empty for a one-time price.
+	// IntervalCount defaults to 1.
+	Interval      PriceInterval
+	IntervalCount int64
+	LookupKey     string
+	// TransferLookupKey moves LookupKey from the price that has it to the new one.
+	TransferLookupKey bool
+	Nickname          string
+	Metadata          map[string]string
+}
+
+// PriceQuery filters ListPrices. The zero value lists every active price.
+type PriceQuery struct {
+	ProductID  string
+	LookupKeys []string
+	// IncludeInactive also lists archived prices.
+	IncludeInactive bool
+}
+
+// PricePage is one page of prices, newest first.
+type PricePage struct {
+	Prices  []*Price
+	HasMore bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,17 @@
+package gomultistripe
+
+import "strings"
+
+// PreferLocale returns locales with locale moved to the front, which is the locale Stripe
+// uses for the customer's receipts and invoices. Matching is case-insensitive and the
+// remaining locales keep their order. Pass the result to UpdateCustomer to change the
+// language of a customer's receipts.
+func PreferLocale(locales []string, locale string) []string {
+	out := []string{locale}
+	for _, l := range locales {
+		if !strings.EqualFold(l, locale) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,208 @@
+// Package reconcile compares the customers and subscriptions recorded in a local database
+// with Stripe and reports the differences, for finance audits and for repairing missed
+// webhooks.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// LocalStore is implemented by the application to load its copy of Stripe data, mapped to
+// the version-agnostic types. Only the fields being compared need to be set.
+type LocalStore interface {
+	LoadLocalCustomers(ctx context.Context) ([]*gomultistripe.Customer, error)
+	LoadLocalSubscriptions(ctx context.Context) ([]*gomultistripe.Subscription, error)
+}
+
+// Kind classifies a difference between the local store and Stripe.
+type Kind string
+
+const (
+	// Missing objects are recorded locally but do not exist in Stripe.
+	Missing Kind = "missing"
+	// Stale objects exist on both sides with differing fields.
+	Stale Kind = "stale"
+	// Orphaned objects exist in Stripe but are not recorded locally.
+	Orphaned Kind = "orphaned"
+)
+
+// Finding is one difference. Stale objects produce one finding per differing field.
+type Finding struct {
+	Kind Kind `json:"kind"`
+	// Object is "customer" or "subscription".
+	Object     string `json:"object"`
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Local      string `json:"local,omitempty"`
+	Stripe     string `json:"stripe,omitempty"`
+}
+
+// Job compares a LocalStore with Stripe. Subscriptions are listed per local customer
+// through the handler; customers are only compared when ListStripeCustomers is set.
+type Job struct {
+	Local  LocalStore
+	Stripe gomultistripe.Handler
+
+	// ListStripeCustomers lists every customer in the Stripe account. Without it, missing,
+	// stale and orphaned customers are not reported.
+	ListStripeCustomers func(ctx context.Context) ([]*gomultistripe.Customer, error)
+
+	// Interval is the minimum time between Stripe requests, to stay within rate limits on
+	// large accounts. Zero sends requests back to back.
+	Interval time.Duration
+	// Progress, if set, is called after each customer's subscriptions have been compared.
+	Progress func(done, total int)
+}
+
+// Run loads both sides and returns the differences. It stops early, returning the error,
+// if ctx is cancelled or a Stripe request fails.
+func (j *Job) Run(ctx context.Context) (*Report, error) {
+	report := &Report{StartedAt: time.Now(), Findings: []Finding{}}
+
+	localCustomers, err := j.Local.LoadLocalCustomers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading local customers: %w", err)
+	}
+	localSubs, err := j.Local.LoadLocalSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading local subscriptions: %w", err)
+	}
+
+	if j.ListStripeCustomers != nil {
+		stripeCustomers, err := j.ListStripeCustomers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing Stripe customers: %w", err)
+		}
+		report.add(compareCustomers(localCustomers, stripeCustomers)...)
+		report.Customers = len(localCustomers)
+	}
+
+	// Subscriptions are listed for every customer known locally, including those only
+	// referenced by a local subscription.
+	customerIDs := make(map[string]bool)
+	for _, c := range localCustomers {
+		customerIDs[c.ID] = true
+	}
+	localByCustomer := make(map[string][]*gomultistripe.Subscription)
+	for _, s := range localSubs {
+		customerIDs[s.CustomerID] = true
+		localByCustomer[s.CustomerID] = append(localByCustomer[s.CustomerID], s)
+	}
+	ids := make([]string, 0, len(customerIDs))
+	for id := range customerIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var tick <-chan time.Time
+	if j.Interval > 0 {
+		ticker := time.NewTicker(j.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for i, id := range ids {
+		if tick != nil && i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-tick:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		stripeSubs, err := j.Stripe.ListSubscriptions(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("listing Stripe subscriptions of %s: %w", id, err)
+		}
+		report.add(compareSubscriptions(localByCustomer[id], stripeSubs)...)
+		if j.Progress != nil {
+			j.Progress(i+1, len(ids))
+		}
+	}
+	report.Subscriptions = len(localSubs)
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func compareCustomers(local, remote []*gomultistripe.Customer) []Finding {
+	var findings []Finding
+	remoteByID := make(map[string]*gomultistripe.Customer, len(remote))
+	for _, c := range remote {
+		remoteByID[c.ID] = c
+	}
+	seen := make(map[string]bool, len(local))
+	for _, l := range local {
+		seen[l.ID] = true
+		r, ok := remoteByID[l.ID]
+		if !ok {
+			findings = append(findings, Finding{Kind: Missing, Object: "customer", ID: l.ID, CustomerID: l.ID})
+			continue
+		}
+		for _, d := range []struct{ field, local, stripe string }{
+			{"email", l.Email, r.Email},
+			{"name", l.Name, r.Name},
+		} {
+			if d.local != d.stripe {
+				findings = append(findings, Finding{Kind: Stale, Object: "customer", ID: l.ID, CustomerID: l.ID, Field: d.field, Local: d.local, Stripe: d.stripe})
+			}
+		}
+	}
+	for _, r := range remote {
+		if !seen[r.ID] {
+			findings = append(findings, Finding{Kind: Orphaned, Object: "customer", ID: r.ID, CustomerID: r.ID})
+		}
+	}
+	return findings
+}
+
+// compareSubscriptions compares one customer's subscriptions. Stripe omits canceled
+// subscriptions from lists, so a local subscription that is canceled on both sides is not
+// reported as missing.
+func compareSubscriptions(local, remote []*gomultistripe.Subscription) []Finding {
+	var findings []Finding
+	remoteByID := make(map[string]*gomultistripe.Subscription, len(remote))
+	for _, s := range remote {
+		remoteByID[s.ID] = s
+	}
+	seen := make(map[string]bool, len(local))
+	for _, l := range local {
+		seen[l.ID] = true
+		r, ok := remoteByID[l.ID]
+		if !ok {
+			if l.Status != "canceled" {
+				findings = append(findings, Finding{Kind: Missing, Object: "subscription", ID: l.ID, CustomerID: l.CustomerID})
+			}
+			continue
+		}
+		for _, d := range []struct{ field, local, stripe string }{
+			{"status", l.Status, r.Status},
+			{"price", l.PriceID, r.PriceID},
+			{"current_period_end", formatUnix(l.CurrentPeriodEnd), formatUnix(r.CurrentPeriodEnd)},
+			{"cancel_at_period_end", strconv.FormatBool(l.CancelAtPeriodEnd), strconv.FormatBool(r.CancelAtPeriodEnd)},
+		} {
+			if d.local != d.stripe {
+				findings = append(findings, Finding{Kind: Stale, Object: "subscription", ID: l.ID, CustomerID: l.CustomerID, Field: d.field, Local: d.local, Stripe: d.stripe})
+			}
+		}
+	}
+	for _, r := range remote {
+		if !seen[r.ID] {
+			findings = append(findings, Finding{Kind: Orphaned, Object: "subscription", ID: r.ID, CustomerID: r.CustomerID})
+		}
+	}
+	return findings
+}
+
+func formatUnix(t int64) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,82 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+type fakeLocal struct {
+	customers []*gomultistripe.Customer
+	subs      []*gomultistripe.Subscription
+}
+
+func (f fakeLocal) LoadLocalCustomers(ctx context.Context) ([]*gomultistripe.Customer, error) {
+	return f.customers, nil
+}
+
+func (f fakeLocal) LoadLocalSubscriptions(ctx context.Context) ([]*gomultistripe.Subscription, error) {
+	return f.subs, nil
+}
+
+type fakeStripe struct {
+	gomultistripe.Handler
+	subs map[string][]*gomultistripe.Subscription
+}
+
+func (f fakeStripe) ListSubscriptions(ctx context.Context, customerID string) ([]*gomultistripe.Subscription, error) {
+	return f.subs[customerID], nil
+}
+
+func TestJobRun(t *testing.T) {
+	job := &Job{
+		Local: fakeLocal{
+			customers: []*gomultistripe.Customer{
+				{ID: "cus_1", Email: "a@example.com"},
+				{ID: "cus_gone"},
+			},
+			subs: []*gomultistripe.Subscription{
+				{ID: "sub_1", CustomerID: "cus_1", Status: "active", PriceID: "price_a"},
+				{ID: "sub_missing", CustomerID: "cus_1", Status: "active"},
+				{ID: "sub_canceled", CustomerID: "cus_1", Status: "canceled"},
+			},
+		},
+		Stripe: fakeStripe{subs: map[string][]*gomultistripe.Subscription{
+			"cus_1": {
+				{ID: "sub_1", CustomerID: "cus_1", Status: "past_due", PriceID: "price_a"},
+				{ID: "sub_extra", CustomerID: "cus_1", Status: "active"},
+			},
+		}},
+		ListStripeCustomers: func(ctx context.Context) ([]*gomultistripe.Customer, error) {
+			return []*gomultistripe.Customer{
+				{ID: "cus_1", Email: "b@example.com"},
+				{ID: "cus_new"},
+			}, nil
+		},
+	}
+
+	report, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Count(Missing); got != 2 {
+		t.Errorf("missing = %d, want 2 (cus_gone, sub_missing): %+v", got, report.Findings)
+	}
+	if got := report.Count(Stale); got != 2 {
+		t.Errorf("stale = %d, want 2 (customer email, subscription status): %+v", got, report.Findings)
+	}
+	if got := report.Count(Orphaned); got != 2 {
+		t.Errorf("orphaned = %d, want 2 (cus_new, sub_extra): %+v", got, report.Findings)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(report.Findings)+1 {
+		t.Errorf("CSV has %d lines, want header plus %d findings", lines, len(report.Findings))
+	}
+}
@@ -0,0 +1,58 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Report is the outcome of a Job run.
+type Report struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Customers and Subscriptions are the number of local objects compared.
+	Customers     int       `json:"customers"`
+	Subscriptions int       `json:"subscriptions"`
+	Findings      []Finding `json:"findings"`
+}
+
+func (r *Report) add(findings ...Finding) {
+	r.Findings = append(r.Findings, findings...)
+}
+
+// Count returns the number of findings of the given kind.
+func (r *Report) Count(kind Kind) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// csvHeader is the stable column order of WriteCSV.
+var csvHeader = []string{"kind", "object", "id", "customer_id", "field", "local", "stripe"}
+
+// WriteCSV writes one row per finding, with a header row.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, f := range r.Findings {
+		if err := cw.Write([]string{string(f.Kind), f.Object, f.ID, f.CustomerID, f.Field, f.Local, f.Stripe}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report, including its summary counts, as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,453 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// ErrInternal is matched (via errors.Is) by errors returned by WithRecovery when a handler
+// panics, e.g. on a nil dereference while mapping an unexpected Stripe response.
+var ErrInternal = errors.New("internal error in Stripe handler")
+
+// InternalError carries the value and stack of a recovered panic.
+type InternalError struct {
+	Version   string
+	Operation string
+	// Panic is the value passed to panic.
+	Panic any
+	// Stack is the goroutine stack at the time of the panic.
+	Stack []byte
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("%v in %s (handler %s): %v", ErrInternal, e.Operation, e.Version, e.Panic)
+}
+
+func (e *InternalError) Is(target error) bool {
+	return target == ErrInternal
+}
+
+// WithRecovery wraps a handler so that a panic in any of its calls, including
+// HandleWebhook, is logged with its stack and returned as an *InternalError instead of
+// crashing the process. Use it for long-running servers, where one malformed payload
+// should fail one request rather than every request in flight.
+func WithRecovery(h Handler) Handler {
+	return &recoveringHandler{Handler: h}
+}
+
+// RecoverConsumer wraps an EventConsumer so that a panic while processing an event is
+// returned as an *InternalError, which a Dispatcher retries and eventually parks like any
+// other failure.
+func RecoverConsumer(consumer EventConsumer) EventConsumer {
+	return func(ctx context.Context, evt *CallbackEvent) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = internalError(ctx, "", "ConsumeEvent", v)
+			}
+		}()
+		return consumer(ctx, evt)
+	}
+}
+
+// internalError logs a recovered panic with its stack and wraps it in an InternalError.
+func internalError(ctx context.Context, version, operation string, v any) error {
+	stack := debug.Stack()
+	Logger().ErrorContext(ctx, "recovered panic",
+		LogKeyVersion, version,
+		LogKeyOperation, operation,
+		"panic", fmt.Sprint(v),
+		"stack", string(stack),
+	)
+	return &InternalError{Version: version, Operation: operation, Panic: v, Stack: stack}
+}
+
+// recoveringHandler is the Handler returned by WithRecovery. Methods without a context
+// (setters and version accessors) are passed through unchanged.
+type recoveringHandler struct {
+	Handler
+}
+
+// recover must be deferred directly by each method so that the builtin recover sees the panic.
+func (r *recoveringHandler) recover(ctx context.Context, operation string, err *error) {
+	if v := recover(); v != nil {
+		*err = internalError(ctx, r.Handler.Version(), operation, v)
+	}
+}
+
+func (r *recoveringHandler) CreateAccount(ctx context.Context, params AccountParams) (out *Account, err error) {
+	defer r.recover(ctx, "CreateAccount", &err)
+	return r.Handler.CreateAccount(ctx, params)
+}
+
+func (r *recoveringHandler) RetrieveAccount(ctx context.Context, accountID string) (out *Account, err error) {
+	defer r.recover(ctx, "RetrieveAccount", &err)
+	return r.Handler.RetrieveAccount(ctx, accountID)
+}
+
+func (r *recoveringHandler) CreateAccountLink(ctx context.Context, params AccountLinkParams) (out *AccountLink, err error) {
+	defer r.recover(ctx, "CreateAccountLink", &err)
+	return r.Handler.CreateAccountLink(ctx, params)
+}
+
+func (r *recoveringHandler) CreatePerson(ctx context.Context, accountID string, params PersonParams) (out *Person, err error) {
+	defer r.recover(ctx, "CreatePerson", &err)
+	return r.Handler.CreatePerson(ctx, accountID, params)
+}
+
+func (r *recoveringHandler) UpdatePerson(ctx context.Context, accountID, personID string, params PersonParams) (out *Person, err error) {
+	defer r.recover(ctx, "UpdatePerson", &err)
+	return r.Handler.UpdatePerson(ctx, accountID, personID, params)
+}
+
+func (r *recoveringHandler) DeletePerson(ctx context.Context, accountID, personID string) (err error) {
+	defer r.recover(ctx, "DeletePerson", &err)
+	return r.Handler.DeletePerson(ctx, accountID, personID)
+}
+
+func (r *recoveringHandler) AddExternalBankAccount(ctx context.Context, accountID string, params ExternalBankAccountParams) (out *ExternalBankAccount, err error) {
+	defer r.recover(ctx, "AddExternalBankAccount", &err)
+	return r.Handler.AddExternalBankAccount(ctx, accountID, params)
+}
+
+func (r *recoveringHandler) DeleteExternalAccount(ctx context.Context, accountID, externalAccountID string) (err error) {
+	defer r.recover(ctx, "DeleteExternalAccount", &err)
+	return r.Handler.DeleteExternalAccount(ctx, accountID, externalAccountID)
+}
+
+func (r *recoveringHandler) CreateTransfer(ctx context.Context, params TransferParams) (out *Transfer, err error) {
+	defer r.recover(ctx, "CreateTransfer", &err)
+	return r.Handler.CreateTransfer(ctx, params)
+}
+
+func (r *recoveringHandler) ReverseTransfer(ctx context.Context, transferID string, amount int64, refundApplicationFee bool) (out *TransferReversal, err error) {
+	defer r.recover(ctx, "ReverseTransfer", &err)
+	return r.Handler.ReverseTransfer(ctx, transferID, amount, refundApplicationFee)
+}
+
+func (r *recoveringHandler) ListTransfers(ctx context.Context, query TransferQuery, opts *ListOptions) (out *TransferPage, err error) {
+	defer r.recover(ctx, "ListTransfers", &err)
+	return r.Handler.ListTransfers(ctx, query, opts)
+}
+
+func (r *recoveringHandler) RetrieveBalance(ctx context.Context) (out *Balance, err error) {
+	defer r.recover(ctx, "RetrieveBalance", &err)
+	return r.Handler.RetrieveBalance(ctx)
+}
+
+func (r *recoveringHandler) ListPayouts(ctx context.Context, query PayoutQuery, opts *ListOptions) (out *PayoutPage, err error) {
+	defer r.recover(ctx, "ListPayouts", &err)
+	return r.Handler.ListPayouts(ctx, query, opts)
+}
+
+func (r *recoveringHandler) ListBalanceTransactions(ctx context.Context, query BalanceTransactionQuery, opts *ListOptions) (out *BalanceTransactionPage, err error) {
+	defer r.recover(ctx, "ListBalanceTransactions", &err)
+	return r.Handler.ListBalanceTransactions(ctx, query, opts)
+}
+
+func (r *recoveringHandler) CreateTopup(ctx context.Context, params TopupParams) (out *Topup, err error) {
+	defer r.recover(ctx, "CreateTopup", &err)
+	return r.Handler.CreateTopup(ctx, params)
+}
+
+func (r *recoveringHandler) ListTopups(ctx context.Context, query TopupQuery, opts *ListOptions) (out *TopupPage, err error) {
+	defer r.recover(ctx, "ListTopups", &err)
+	return r.Handler.ListTopups(ctx, query, opts)
+}
+
+func (r *recoveringHandler) ListDisputes(ctx context.Context, query DisputeQuery, opts *ListOptions) (out *DisputePage, err error) {
+	defer r.recover(ctx, "ListDisputes", &err)
+	return r.Handler.ListDisputes(ctx, query, opts)
+}
+
+func (r *recoveringHandler) RetrieveDispute(ctx context.Context, disputeID string) (out *Dispute, err error) {
+	defer r.recover(ctx, "RetrieveDispute", &err)
+	return r.Handler.RetrieveDispute(ctx, disputeID)
+}
+
+func (r *recoveringHandler) UpdateDispute(ctx context.Context, disputeID string, params DisputeUpdate) (out *Dispute, err error) {
+	defer r.recover(ctx, "UpdateDispute", &err)
+	return r.Handler.UpdateDispute(ctx, disputeID, params)
+}
+
+func (r *recoveringHandler) CloseDispute(ctx context.Context, disputeID string) (out *Dispute, err error) {
+	defer r.recover(ctx, "CloseDispute", &err)
+	return r.Handler.CloseDispute(ctx, disputeID)
+}
+
+func (r *recoveringHandler) CreateCustomer(ctx context.Context, params *Customer) (out *Customer, err error) {
+	defer r.recover(ctx, "CreateCustomer", &err)
+	return r.Handler.CreateCustomer(ctx, params)
+}
+
+func (r *recoveringHandler) UpdateCustomer(ctx context.Context, customerID string, params *Customer) (out *Customer, err error) {
+	defer r.recover(ctx, "UpdateCustomer", &err)
+	return r.Handler.UpdateCustomer(ctx, customerID, params)
+}
+
+func (r *recoveringHandler) FindCustomerByEmail(ctx context.Context, email string) (out *Customer, err error) {
+	defer r.recover(ctx, "FindCustomerByEmail", &err)
+	return r.Handler.FindCustomerByEmail(ctx, email)
+}
+
+func (r *recoveringHandler) ListCustomers(ctx context.Context, opts *ListOptions) (out *CustomerPage, err error) {
+	defer r.recover(ctx, "ListCustomers", &err)
+	return r.Handler.ListCustomers(ctx, opts)
+}
+
+func (r *recoveringHandler) CreateCustomerSession(ctx context.Context, customerID string, components []CustomerSessionComponent) (out *CustomerSession, err error) {
+	defer r.recover(ctx, "CreateCustomerSession", &err)
+	return r.Handler.CreateCustomerSession(ctx, customerID, components)
+}
+
+func (r *recoveringHandler) GetPaymentMethods(ctx context.Context, customerID string, opts ...PaymentMethodListOption) (out []*PaymentMethod, err error) {
+	defer r.recover(ctx, "GetPaymentMethods", &err)
+	return r.Handler.GetPaymentMethods(ctx, customerID, opts...)
+}
+
+func (r *recoveringHandler) RetrievePaymentMethod(ctx context.Context, paymentMethodID string) (out *PaymentMethod, err error) {
+	defer r.recover(ctx, "RetrievePaymentMethod", &err)
+	return r.Handler.RetrievePaymentMethod(ctx, paymentMethodID)
+}
+
+func (r *recoveringHandler) AttachPaymentMethod(ctx context.Context, customerID string, paymentMethodID string) (out *PaymentMethod, err error) {
+	defer r.recover(ctx, "AttachPaymentMethod", &err)
+	return r.Handler.AttachPaymentMethod(ctx, customerID, paymentMethodID)
+}
+
+func (r *recoveringHandler) DetachPaymentMethod(ctx context.Context, paymentMethodID string) (err error) {
+	defer r.recover(ctx, "DetachPaymentMethod", &err)
+	return r.Handler.DetachPaymentMethod(ctx, paymentMethodID)
+}
+
+func (r *recoveringHandler) CreatePaymentIntent(ctx context.Context, params *PaymentIntent) (out *PaymentIntent, err error) {
+	defer r.recover(ctx, "CreatePaymentIntent", &err)
+	return r.Handler.CreatePaymentIntent(ctx, params)
+}
+
+func (r *recoveringHandler) PreparePaymentSheet(ctx context.Context, customerID string, amount int64, currency string) (out *PaymentSheet, err error) {
+	defer r.recover(ctx, "PreparePaymentSheet", &err)
+	return r.Handler.PreparePaymentSheet(ctx, customerID, amount, currency)
+}
+
+func (r *recoveringHandler) RetrievePaymentIntent(ctx context.Context, paymentIntentID string) (out *PaymentIntent, err error) {
+	defer r.recover(ctx, "RetrievePaymentIntent", &err)
+	return r.Handler.RetrievePaymentIntent(ctx, paymentIntentID)
+}
+
+func (r *recoveringHandler) CapturePaymentIntent(ctx context.Context, paymentIntentID string, amount int64, final bool) (out *PaymentIntent, err error) {
+	defer r.recover(ctx, "CapturePaymentIntent", &err)
+	return r.Handler.CapturePaymentIntent(ctx, paymentIntentID, amount, final)
+}
+
+func (r *recoveringHandler) CancelPaymentIntent(ctx context.Context, paymentIntentID string, reason string) (out *PaymentIntent, err error) {
+	defer r.recover(ctx, "CancelPaymentIntent", &err)
+	return r.Handler.CancelPaymentIntent(ctx, paymentIntentID, reason)
+}
+
+func (r *recoveringHandler) ConfirmPaymentIntent(ctx context.Context, paymentIntentID string, paymentMethodID string, opts ...ConfirmOption) (out *PaymentIntent, err error) {
+	defer r.recover(ctx, "ConfirmPaymentIntent", &err)
+	return r.Handler.ConfirmPaymentIntent(ctx, paymentIntentID, paymentMethodID, opts...)
+}
+
+func (r *recoveringHandler) CreateSetupIntent(ctx context.Context, params *SetupIntent) (out *SetupIntent, err error) {
+	defer r.recover(ctx, "CreateSetupIntent", &err)
+	return r.Handler.CreateSetupIntent(ctx, params)
+}
+
+func (r *recoveringHandler) RetrieveSetupIntent(ctx context.Context, setupIntentID string) (out *SetupIntent, err error) {
+	defer r.recover(ctx, "RetrieveSetupIntent", &err)
+	return r.Handler.RetrieveSetupIntent(ctx, setupIntentID)
+}
+
+func (r *recoveringHandler) ConfirmSetupIntent(ctx context.Context, setupIntentID string, paymentMethodID string, opts ...ConfirmOption) (out *SetupIntent, err error) {
+	defer r.recover(ctx, "ConfirmSetupIntent", &err)
+	return r.Handler.ConfirmSetupIntent(ctx, setupIntentID, paymentMethodID, opts...)
+}
+
+func (r *recoveringHandler) ListUncapturedPaymentIntents(ctx context.Context, customerID string) (out []*PaymentIntent, err error) {
+	defer r.recover(ctx, "ListUncapturedPaymentIntents", &err)
+	return r.Handler.ListUncapturedPaymentIntents(ctx, customerID)
+}
+
+func (r *recoveringHandler) RetrieveCharge(ctx context.Context, chargeID string) (out *Charge, err error) {
+	defer r.recover(ctx, "RetrieveCharge", &err)
+	return r.Handler.RetrieveCharge(ctx, chargeID)
+}
+
+func (r *recoveringHandler) ListCharges(ctx context.Context, customerID string) (out []*Charge, err error) {
+	defer r.recover(ctx, "ListCharges", &err)
+	return r.Handler.ListCharges(ctx, customerID)
+}
+
+func (r *recoveringHandler) CreateSubscription(ctx context.Context, customerID string, priceID string, opts ...SubscriptionOption) (out *Subscription, err error) {
+	defer r.recover(ctx, "CreateSubscription", &err)
+	return r.Handler.CreateSubscription(ctx, customerID, priceID, opts...)
+}
+
+func (r *recoveringHandler) ListSubscriptions(ctx context.Context, customerID string) (out []*Subscription, err error) {
+	defer r.recover(ctx, "ListSubscriptions", &err)
+	return r.Handler.ListSubscriptions(ctx, customerID)
+}
+
+func (r *recoveringHandler) UpdateSubscription(ctx context.Context, subscriptionID string, cancelAtPeriodEnd bool, newPriceID string) (out *Subscription, err error) {
+	defer r.recover(ctx, "UpdateSubscription", &err)
+	return r.Handler.UpdateSubscription(ctx, subscriptionID, cancelAtPeriodEnd, newPriceID)
+}
+
+func (r *recoveringHandler) CancelSubscription(ctx context.Context, subscriptionID string, atPeriodEnd bool) (out *Subscription, err error) {
+	defer r.recover(ctx, "CancelSubscription", &err)
+	return r.Handler.CancelSubscription(ctx, subscriptionID, atPeriodEnd)
+}
+
+func (r *recoveringHandler) CancelSubscriptionAt(ctx context.Context, subscriptionID string, cancelAt int64) (out *Subscription, err error) {
+	defer r.recover(ctx, "CancelSubscriptionAt", &err)
+	return r.Handler.CancelSubscriptionAt(ctx, subscriptionID, cancelAt)
+}
+
+func (r *recoveringHandler) UpdateSubscriptionItems(ctx context.Context, subscriptionID string, items []SubscriptionItemParams, prorationBehavior string) (out *Subscription, err error) {
+	defer r.recover(ctx, "UpdateSubscriptionItems", &err)
+	return r.Handler.UpdateSubscriptionItems(ctx, subscriptionID, items, prorationBehavior)
+}
+
+func (r *recoveringHandler) ReconcileSeats(ctx context.Context, subscriptionID string, actualSeatCount int64, policy SeatPolicy) (out *SeatReconciliation, err error) {
+	defer r.recover(ctx, "ReconcileSeats", &err)
+	return r.Handler.ReconcileSeats(ctx, subscriptionID, actualSeatCount, policy)
+}
+
+func (r *recoveringHandler) CreateReportRun(ctx context.Context, req ReportRunRequest) (out *ReportRun, err error) {
+	defer r.recover(ctx, "CreateReportRun", &err)
+	return r.Handler.CreateReportRun(ctx, req)
+}
+
+func (r *recoveringHandler) GetReportRun(ctx context.Context, reportRunID string) (out *ReportRun, err error) {
+	defer r.recover(ctx, "GetReportRun", &err)
+	return r.Handler.GetReportRun(ctx, reportRunID)
+}
+
+func (r *recoveringHandler) DownloadReportRun(ctx context.Context, reportRunID string, w io.Writer) (err error) {
+	defer r.recover(ctx, "DownloadReportRun", &err)
+	return r.Handler.DownloadReportRun(ctx, reportRunID, w)
+}
+
+func (r *recoveringHandler) ListInvoices(ctx context.Context, customerID string, status InvoiceStatus, dateRange DateRange, opts *ListOptions) (out *InvoicePage, err error) {
+	defer r.recover(ctx, "ListInvoices", &err)
+	return r.Handler.ListInvoices(ctx, customerID, status, dateRange, opts)
+}
+
+func (r *recoveringHandler) RetrieveInvoice(ctx context.Context, invoiceID string) (out *Invoice, err error) {
+	defer r.recover(ctx, "RetrieveInvoice", &err)
+	return r.Handler.RetrieveInvoice(ctx, invoiceID)
+}
+
+func (r *recoveringHandler) PayInvoice(ctx context.Context, invoiceID string, paymentMethodID string) (out *Invoice, err error) {
+	defer r.recover(ctx, "PayInvoice", &err)
+	return r.Handler.PayInvoice(ctx, invoiceID, paymentMethodID)
+}
+
+func (r *recoveringHandler) VoidInvoice(ctx context.Context, invoiceID string) (out *Invoice, err error) {
+	defer r.recover(ctx, "VoidInvoice", &err)
+	return r.Handler.VoidInvoice(ctx, invoiceID)
+}
+
+func (r *recoveringHandler) FinalizeInvoice(ctx context.Context, invoiceID string) (out *Invoice, err error) {
+	defer r.recover(ctx, "FinalizeInvoice", &err)
+	return r.Handler.FinalizeInvoice(ctx, invoiceID)
+}
+
+func (r *recoveringHandler) CreateProduct(ctx context.Context, params ProductParams) (out *Product, err error) {
+	defer r.recover(ctx, "CreateProduct", &err)
+	return r.Handler.CreateProduct(ctx, params)
+}
+
+func (r *recoveringHandler) CreatePrice(ctx context.Context, params PriceParams) (out *Price, err error) {
+	defer r.recover(ctx, "CreatePrice", &err)
+	return r.Handler.CreatePrice(ctx, params)
+}
+
+func (r *recoveringHandler) GetPrice(ctx context.Context, priceID string) (out *Price, err error) {
+	defer r.recover(ctx, "GetPrice", &err)
+	return r.Handler.GetPrice(ctx, priceID)
+}
+
+func (r *recoveringHandler) ListPrices(ctx context.Context, query PriceQuery, opts *ListOptions) (out *PricePage, err error) {
+	defer r.recover(ctx, "ListPrices", &err)
+	return r.Handler.ListPrices(ctx, query, opts)
+}
+
+func (r *recoveringHandler) CreateCoupon(ctx context.Context, params CouponParams) (out *Coupon, err error) {
+	defer r.recover(ctx, "CreateCoupon", &err)
+	return r.Handler.CreateCoupon(ctx, params)
+}
+
+func (r *recoveringHandler) GetCoupon(ctx context.Context, couponID string) (out *Coupon, err error) {
+	defer r.recover(ctx, "GetCoupon", &err)
+	return r.Handler.GetCoupon(ctx, couponID)
+}
+
+func (r *recoveringHandler) ValidatePromotionCode(ctx context.Context, code string, customerID string) (out *PromotionCode, err error) {
+	defer r.recover(ctx, "ValidatePromotionCode", &err)
+	return r.Handler.ValidatePromotionCode(ctx, code, customerID)
+}
+
+func (r *recoveringHandler) CreateMeter(ctx context.Context, params MeterParams) (out *Meter, err error) {
+	defer r.recover(ctx, "CreateMeter", &err)
+	return r.Handler.CreateMeter(ctx, params)
+}
+
+func (r *recoveringHandler) ReportMeterEvent(ctx context.Context, evt MeterEvent) (err error) {
+	defer r.recover(ctx, "ReportMeterEvent", &err)
+	return r.Handler.ReportMeterEvent(ctx, evt)
+}
+
+func (r *recoveringHandler) ReportMeterEvents(ctx context.Context, events []MeterEvent, opts ...MeterBatchOption) (err error) {
+	defer r.recover(ctx, "ReportMeterEvents", &err)
+	return r.Handler.ReportMeterEvents(ctx, events, opts...)
+}
+
+func (r *recoveringHandler) CreateEventDestination(ctx context.Context, params EventDestinationParams) (out *EventDestination, err error) {
+	defer r.recover(ctx, "CreateEventDestination", &err)
+	return r.Handler.CreateEventDestination(ctx, params)
+}
+
+func (r *recoveringHandler) GetEventDestination(ctx context.Context, destinationID string) (out *EventDestination, err error) {
+	defer r.recover(ctx, "GetEventDestination", &err)
+	return r.Handler.GetEventDestination(ctx, destinationID)
+}
+
+func (r *recoveringHandler) ListEventDestinations(ctx context.Context) (out []*EventDestination, err error) {
+	defer r.recover(ctx, "ListEventDestinations", &err)
+	return r.Handler.ListEventDestinations(ctx)
+}
+
+func (r *recoveringHandler) UpdateEventDestination(ctx context.Context, destinationID string, update EventDestinationUpdate) (out *EventDestination, err error) {
+	defer r.recover(ctx, "UpdateEventDestination", &err)
+	return r.Handler.UpdateEventDestination(ctx, destinationID, update)
+}
+
+func (r *recoveringHandler) SetEventDestinationEnabled(ctx context.Context, destinationID string, enabled bool) (out *EventDestination, err error) {
+	defer r.recover(ctx, "SetEventDestinationEnabled", &err)
+	return r.Handler.SetEventDestinationEnabled(ctx, destinationID, enabled)
+}
+
+func (r *recoveringHandler) DeleteEventDestination(ctx context.Context, destinationID string) (err error) {
+	defer r.recover(ctx, "DeleteEventDestination", &err)
+	return r.Handler.DeleteEventDestination(ctx, destinationID)
+}
+
+func (r *recoveringHandler) ListForExport(ctx context.Context, q ExportQuery) (out *ExportPage, err error) {
+	defer r.recover(ctx, "ListForExport", &err)
+	return r.Handler.ListForExport(ctx, q)
+}
+
+func (r *recoveringHandler) HandleWebhook(payload []byte, sigHeader string) (out *CallbackEvent, err error) {
+	defer r.recover(context.Background(), "HandleWebhook", &err)
+	return r.Handler.HandleWebhook(payload, sigHeader)
+}
+
+func (r *recoveringHandler) HandleEvent(payload []byte) (out *CallbackEvent, err error) {
+	defer r.recover(context.Background(), "HandleEvent", &err)
+	return r.Handler.HandleEvent(payload)
+}
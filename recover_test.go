@@ -0,0 +1,33 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type panickingHandler struct {
+	Handler
+}
+
+func (panickingHandler) Version() string { return "v0" }
+
+func (panickingHandler) CreateCustomer(ctx context.Context, params *Customer) (*Customer, error) {
+	var card *PaymentMethod
+	return &Customer{Name: card.Brand}, nil
+}
+
+func TestWithRecovery(t *testing.T) {
+	h := WithRecovery(panickingHandler{})
+	cust, err := h.CreateCustomer(context.Background(), &Customer{})
+	if cust != nil {
+		t.Fatalf("expected no customer, got %+v", cust)
+	}
+	if !errors.Is(err, ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+	var ie *InternalError
+	if !errors.As(err, &ie) || ie.Operation != "CreateCustomer" || ie.Version != "v0" || len(ie.Stack) == 0 {
+		t.Fatalf("unexpected internal error %+v", ie)
+	}
+}
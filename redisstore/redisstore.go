@@ -0,0 +1,61 @@
+// Package redisstore implements gomultistripe.SharedStore on Redis, so locks and
+// duplicate suppression work across every replica connected to the same Redis.
+//
+// It does not depend on a Redis client library: New takes a function sending one command,
+// which is a one-line adapter for any client. For github.com/redis/go-redis/v9:
+//
+//	store := redisstore.New(func(ctx context.Context, args ...any) (any, error) {
+//		return rdb.Do(ctx, args...).Result()
+//	})
+//	locker := &gomultistripe.StoreLocker{Store: store}
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+var _ gomultistripe.SharedStore = (*Store)(nil)
+
+// Do sends one Redis command, e.g. "EVAL", script, 1, key, arg, and returns its reply.
+type Do func(ctx context.Context, args ...any) (any, error)
+
+// Both operations run as scripts returning 1 or 0, so that every client reports them the
+// same way (go-redis, for one, turns a nil reply into an error).
+const (
+	setNXScript            = `if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then return 1 else return 0 end`
+	compareAndDeleteScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+)
+
+// Store is a gomultistripe.SharedStore on Redis.
+type Store struct {
+	do Do
+}
+
+// New returns a Store sending its commands with do.
+func New(do Do) *Store {
+	return &Store{do: do}
+}
+
+func (s *Store) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return s.eval(ctx, setNXScript, key, value, ttl.Milliseconds())
+}
+
+func (s *Store) CompareAndDelete(ctx context.Context, key, value string) (bool, error) {
+	return s.eval(ctx, compareAndDeleteScript, key, value)
+}
+
+func (s *Store) eval(ctx context.Context, script, key string, args ...any) (bool, error) {
+	reply, err := s.do(ctx, append([]any{"EVAL", script, 1, key}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redisstore: unexpected reply %T to EVAL", reply)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,58 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRedis evaluates the two scripts of Store against a map, replying like Redis does.
+func fakeRedis(data map[string]string) Do {
+	return func(ctx context.Context, args ...any) (any, error) {
+		if len(args) < 5 || args[0] != "EVAL" || args[2] != 1 {
+			return nil, errors.New("unexpected command")
+		}
+		key, value := args[3].(string), args[4].(string)
+		switch args[1] {
+		case setNXScript:
+			if _, ok := data[key]; ok {
+				return int64(0), nil
+			}
+			data[key] = value
+			return int64(1), nil
+		case compareAndDeleteScript:
+			if data[key] != value {
+				return int64(0), nil
+			}
+			delete(data, key)
+			return int64(1), nil
+		}
+		return nil, errors.New("unknown script")
+	}
+}
+
+func TestStore(t *testing.T) {
+	data := map[string]string{}
+	s := New(fakeRedis(data))
+	ctx := context.Background()
+	if ok, err := s.SetNX(ctx, "k", "a", time.Second); !ok || err != nil {
+		t.Fatalf("first SetNX: %v, %v", ok, err)
+	}
+	if ok, _ := s.SetNX(ctx, "k", "b", time.Second); ok {
+		t.Fatal("SetNX must not overwrite an existing key")
+	}
+	if ok, _ := s.CompareAndDelete(ctx, "k", "b"); ok {
+		t.Fatal("CompareAndDelete must not delete another holder's value")
+	}
+	if ok, _ := s.CompareAndDelete(ctx, "k", "a"); !ok || len(data) != 0 {
+		t.Fatal("CompareAndDelete should delete its own value")
+	}
+}
+
+func TestStore_UnexpectedReply(t *testing.T) {
+	s := New(func(ctx context.Context, args ...any) (any, error) { return "OK", nil })
+	if _, err := s.SetNX(context.Background(), "k", "a", time.Second); err == nil {
+		t.Fatal("expected an error for a non-integer reply")
+	}
+}
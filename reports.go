@@ -0,0 +1,103 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Report types of Stripe Revenue Recognition. Running them requires Revenue Recognition to
+// be enabled on the account; otherwise Stripe rejects the report run.
+const (
+	ReportRevenueDebitCreditSummary    = "revenue_recognition.debit_credit_summary.1"
+	ReportRevenueDebitCreditByCustomer = "revenue_recognition.debit_credit_by_customer.1"
+	ReportRevenueDebitCreditByInvoice  = "revenue_recognition.debit_credit_by_invoice.1"
+	ReportRevenueDebitCreditByProduct  = "revenue_recognition.debit_credit_by_product.1"
+	ReportRevenueDebitCreditByPrice    = "revenue_recognition.debit_credit_by_price.1"
+	ReportRevenueDebitCreditByLineItem = "revenue_recognition.debit_credit_by_invoice_line_item.1"
+)
+
+// ErrReportNotReady is returned when downloading a report run that has not succeeded.
+var ErrReportNotReady = errors.New("report run has not succeeded")
+
+// ReportRunRequest describes a Stripe report run.
+type ReportRunRequest struct {
+	// ReportType is the report to run, e.g. ReportRevenueDebitCreditSummary.
+	ReportType string
+	// IntervalStart and IntervalEnd bound the accounting period (end exclusive). Zero values
+	// leave the report type's default.
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+	// Currency restricts the report to one currency.
+	Currency string
+	// Columns selects the report columns. Empty uses the report type's default set.
+	Columns []string
+	// Timezone is the IANA time zone of timestamps in the output. Defaults to Etc/UTC.
+	Timezone string
+}
+
+// ReportRun is an asynchronous Stripe report run.
+type ReportRun struct {
+	ID         string
+	ReportType string
+	// Status is "pending", "succeeded" or "failed".
+	Status string
+	// Error describes the failure when Status is "failed".
+	Error string
+	// ResultFileID and ResultURL identify the CSV output once Status is "succeeded".
+	ResultFileID  string
+	ResultURL     string
+	IntervalStart time.Time
+	IntervalEnd   time.Time
+	CreatedAt     time.Time
+}
+
+// WaitForReportRun polls a report run every interval until it is no longer pending or ctx is done.
+func WaitForReportRun(ctx context.Context, h Handler, reportRunID string, interval time.Duration) (*ReportRun, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		run, err := h.GetReportRun(ctx, reportRunID)
+		if err != nil {
+			return nil, err
+		}
+		if run.Status != "pending" {
+			return run, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return run, ctx.Err()
+		}
+	}
+}
+
+// DownloadFile streams a Stripe file's contents from url to w, authenticating with apiKey.
+// Handlers use it to fetch report results. The account from ContextWithAccount, if any, is
+// sent as the Stripe-Account header.
+func DownloadFile(ctx context.Context, client *http.Client, url, apiKey string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if account, ok := AccountFromContext(ctx); ok {
+		req.Header.Set("Stripe-Account", account)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,51 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// DiffRequirements returns the requirements in after that are not in before, and those in
+// before that are no longer in after, each in the order of their list.
+func DiffRequirements(before, after []string) (added, resolved []string) {
+	for _, r := range after {
+		if !slices.Contains(before, r) {
+			added = append(added, r)
+		}
+	}
+	for _, r := range before {
+		if !slices.Contains(after, r) {
+			resolved = append(resolved, r)
+		}
+	}
+	return added, resolved
+}
+
+// SetRequirementChanges fills the requirement changes of an account.updated event, whose
+// Account is set, from the event's previous attributes (the raw data.previous_attributes).
+// Stripe only includes the requirement lists that changed, so the others are left empty.
+// Handlers use it while normalizing the event.
+func (evt *CallbackEvent) SetRequirementChanges(previousAttributes json.RawMessage) error {
+	if evt.Account == nil || len(previousAttributes) == 0 {
+		return nil
+	}
+	var previous struct {
+		Requirements *struct {
+			CurrentlyDue *[]string `json:"currently_due"`
+			PastDue      *[]string `json:"past_due"`
+		} `json:"requirements"`
+	}
+	if err := json.Unmarshal(previousAttributes, &previous); err != nil {
+		return err
+	}
+	if previous.Requirements == nil {
+		return nil
+	}
+	if due := previous.Requirements.CurrentlyDue; due != nil {
+		evt.RequirementsAdded, evt.RequirementsResolved = DiffRequirements(*due, evt.Account.RequirementsDue)
+	}
+	if pastDue := previous.Requirements.PastDue; pastDue != nil {
+		evt.PastDueAdded, evt.PastDueResolved = DiffRequirements(*pastDue, evt.Account.RequirementsPastDue)
+	}
+	return nil
+}
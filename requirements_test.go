@@ -0,0 +1,33 @@
+package gomultistripe
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetRequirementChanges(t *testing.T) {
+	evt := &CallbackEvent{Account: &Account{
+		RequirementsDue:     []string{"external_account", "individual.verification.document"},
+		RequirementsPastDue: []string{"individual.verification.document"},
+	}}
+	previous := []byte(`{"requirements":{"currently_due":["external_account","tos_acceptance.date"]}}`)
+	if err := evt.SetRequirementChanges(previous); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(evt.RequirementsAdded, []string{"individual.verification.document"}) ||
+		!slices.Equal(evt.RequirementsResolved, []string{"tos_acceptance.date"}) {
+		t.Errorf("currently due: added %v, resolved %v", evt.RequirementsAdded, evt.RequirementsResolved)
+	}
+	// past_due is absent from the previous attributes, so it did not change.
+	if evt.PastDueAdded != nil || evt.PastDueResolved != nil {
+		t.Errorf("past due: added %v, resolved %v", evt.PastDueAdded, evt.PastDueResolved)
+	}
+
+	evt = &CallbackEvent{Account: &Account{}}
+	if err := evt.SetRequirementChanges([]byte(`{"requirements":{"past_due":["external_account"]}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(evt.PastDueResolved, []string{"external_account"}) || evt.PastDueAdded != nil {
+		t.Errorf("past due: added %v, resolved %v", evt.PastDueAdded, evt.PastDueResolved)
+	}
+}
@@ -0,0 +1,72 @@
+package gomultistripe
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaReport describes how a webhook object differs from what the handler's pinned
+// SDK version expects. A report is only produced when something differs.
+type SchemaReport struct {
+	EventID   string
+	EventType CallbackEventType
+	// Version is the handler version that parsed the event.
+	Version string
+	// APIVersion is the API version the event was rendered with.
+	APIVersion string
+	// Object is the Stripe object type carried by the event, e.g. "payment_intent".
+	Object string
+	// Unknown lists top-level fields in the payload that the SDK model does not define.
+	Unknown []string
+	// Missing lists critical fields the handler maps that are absent from the payload.
+	Missing []string
+}
+
+// SchemaReporter receives schema reports from handlers with strict validation enabled.
+type SchemaReporter func(report SchemaReport)
+
+// CheckSchema compares the top-level keys of a decoded Stripe object against the JSON
+// fields of model (a stripe-go struct) and the list of critical fields.
+func CheckSchema(object map[string]interface{}, model interface{}, critical []string) (unknown, missing []string) {
+	known := jsonFields(reflect.TypeOf(model))
+	for key := range object {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	for _, field := range critical {
+		if _, ok := object[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, missing
+}
+
+// jsonFields returns the set of JSON field names declared on t, including promoted fields.
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fields := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			for name := range jsonFields(f.Type) {
+				fields[name] = true
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = true
+	}
+	return fields
+}
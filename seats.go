@@ -0,0 +1,54 @@
+package gomultistripe
+
+import "errors"
+
+// ErrSeatItemNotFound is returned by ReconcileSeats when the subscription has no item the
+// policy applies to.
+var ErrSeatItemNotFound = errors.New("no matching subscription item for seat reconciliation")
+
+// SeatPolicy controls how ReconcileSeats corrects a per-seat subscription.
+type SeatPolicy struct {
+	// Threshold is the number of seats the billed quantity may drift from the actual count
+	// before it is corrected. Zero corrects any drift.
+	Threshold int64
+	// AllowDecrease permits lowering the billed quantity. When false only increases are
+	// applied, so customers are not credited for seats freed mid-period.
+	AllowDecrease bool
+	// PriceID selects the subscription item to reconcile. Required when the subscription has
+	// more than one item.
+	PriceID string
+	// ProrationBehavior is passed to Stripe: "create_prorations" (the default),
+	// "always_invoice" or "none".
+	ProrationBehavior string
+	// DryRun reports the reconciliation without updating the subscription.
+	DryRun bool
+}
+
+// SeatReconciliation is the result of ReconcileSeats.
+type SeatReconciliation struct {
+	SubscriptionID     string
+	SubscriptionItemID string
+	// BilledQuantity is the subscription item quantity before reconciliation.
+	BilledQuantity int64
+	// ActualQuantity is the seat count passed to ReconcileSeats.
+	ActualQuantity int64
+	// Drift is ActualQuantity minus BilledQuantity.
+	Drift int64
+	// Updated is true when the quantity was changed in Stripe. It is always false on a dry run.
+	Updated bool
+	// WouldUpdate is true when the policy calls for a change, whether or not it was applied.
+	WouldUpdate bool
+}
+
+// Evaluate fills in the drift and whether the policy calls for an update.
+func (p SeatPolicy) Evaluate(r *SeatReconciliation) {
+	r.Drift = r.ActualQuantity - r.BilledQuantity
+	abs := r.Drift
+	if abs < 0 {
+		if !p.AllowDecrease {
+			return
+		}
+		abs = -abs
+	}
+	r.WouldUpdate = r.Drift != 0 && abs > p.Threshold
+}
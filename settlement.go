@@ -0,0 +1,110 @@
+package gomultistripe
+
+import "context"
+
+// SettlementLine is what one payment contributed to a payout. Amounts are in the payout's
+// currency; Refunded and Disputed are negative for funds that left the balance.
+type SettlementLine struct {
+	// PaymentIntentID is the payment, or empty for charges created without a payment
+	// intent, which ChargeID identifies alone.
+	PaymentIntentID string
+	ChargeID        string
+	Charged         int64
+	Refunded        int64
+	// Disputed is withdrawn by disputes and returned when they are won.
+	Disputed int64
+	// Fees are the Stripe fees on the payment, and Net what it added to the payout.
+	Fees int64
+	Net  int64
+	// TransactionIDs lists the balance transactions of the payment, for ledger references.
+	TransactionIDs []string
+}
+
+// SettlementReport breaks a payout down by the balance transactions it settled.
+type SettlementReport struct {
+	PayoutID string
+	Currency string
+	// Amount is the amount paid out.
+	Amount int64
+	// Lines are the payments settled, in the order their first transaction was listed.
+	Lines []*SettlementLine
+	// Charges, Refunds and Disputes total the lines' amounts by group. Fees totals the Stripe
+	// fees, both those on the payments and standalone fees such as Radar or billing fees.
+	Charges  int64
+	Refunds  int64
+	Disputes int64
+	Fees     int64
+	// Other lists the transactions not tied to a payment, e.g. transfers, top-ups,
+	// adjustments and standalone fees.
+	Other []*BalanceTransaction
+	// Net totals the settled transactions, which equals Amount for a fully reconciled
+	// payout.
+	Net int64
+}
+
+// BuildSettlementReport lists the balance transactions an automatic payout settled and
+// groups them by payment, so each payout can be posted to a ledger per payment intent. The
+// account's payouts must be automatic: Stripe does not record which transactions manual
+// payouts settle.
+func BuildSettlementReport(ctx context.Context, h Handler, payoutID string) (*SettlementReport, error) {
+	report := &SettlementReport{PayoutID: payoutID}
+	lines := make(map[string]*SettlementLine)
+	query := BalanceTransactionQuery{PayoutID: payoutID, ExpandSources: true}
+	opts := &ListOptions{Limit: MaxExportPageSize}
+	for {
+		page, err := h.ListBalanceTransactions(ctx, query, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, txn := range page.Transactions {
+			report.settle(txn, lines)
+		}
+		if !page.HasMore {
+			return report, nil
+		}
+		opts.StartingAfter = page.NextCursor
+	}
+}
+
+// settle adds one balance transaction of the payout to the report.
+func (r *SettlementReport) settle(txn *BalanceTransaction, lines map[string]*SettlementLine) {
+	if txn.Type == "payout" && txn.SourceID == r.PayoutID {
+		r.Amount = -txn.Amount
+		r.Currency = txn.Currency
+		return
+	}
+	r.Currency = txn.Currency
+	r.Net += txn.Net
+	r.Fees += txn.Fee
+	key := txn.PaymentIntentID
+	if key == "" {
+		key = txn.ChargeID
+	}
+	if key == "" {
+		if txn.ReportingCategory == "fee" {
+			r.Fees -= txn.Amount
+		}
+		r.Other = append(r.Other, txn)
+		return
+	}
+	line, ok := lines[key]
+	if !ok {
+		line = &SettlementLine{PaymentIntentID: txn.PaymentIntentID, ChargeID: txn.ChargeID}
+		lines[key] = line
+		r.Lines = append(r.Lines, line)
+	}
+	switch txn.ReportingCategory {
+	case "charge":
+		line.Charged += txn.Amount
+		r.Charges += txn.Amount
+	case "refund", "refund_failure":
+		line.Refunded += txn.Amount
+		r.Refunds += txn.Amount
+	case "dispute", "dispute_reversal":
+		line.Disputed += txn.Amount
+		r.Disputes += txn.Amount
+	}
+	line.Fees += txn.Fee
+	line.Net += txn.Net
+	line.TransactionIDs = append(line.TransactionIDs, txn.ID)
+}
@@ -0,0 +1,62 @@
+package gomultistripe
+
+import (
+	"context"
+	"testing"
+)
+
+// stubBalanceHandler returns the transactions in pages of two.
+type stubBalanceHandler struct {
+	Handler
+	txns    []*BalanceTransaction
+	queries []BalanceTransactionQuery
+}
+
+func (h *stubBalanceHandler) ListBalanceTransactions(ctx context.Context, query BalanceTransactionQuery, opts *ListOptions) (*BalanceTransactionPage, error) {
+	h.queries = append(h.queries, query)
+	start := 0
+	for i, txn := range h.txns {
+		if txn.ID == opts.StartingAfter {
+			start = i + 1
+		}
+	}
+	end := min(start+2, len(h.txns))
+	page := &BalanceTransactionPage{Transactions: h.txns[start:end], HasMore: end < len(h.txns)}
+	if page.HasMore {
+		page.NextCursor = h.txns[end-1].ID
+	}
+	return page, nil
+}
+
+func TestBuildSettlementReport(t *testing.T) {
+	stub := &stubBalanceHandler{txns: []*BalanceTransaction{
+		{ID: "txn_po", Type: "payout", ReportingCategory: "payout", SourceID: "po_1", Amount: -12079, Net: -12079, Currency: "usd"},
+		{ID: "txn_ch1", Type: "charge", ReportingCategory: "charge", ChargeID: "ch_1", PaymentIntentID: "pi_1", Amount: 10000, Fee: 320, Net: 9680, Currency: "usd"},
+		{ID: "txn_ch2", Type: "charge", ReportingCategory: "charge", ChargeID: "ch_2", PaymentIntentID: "pi_2", Amount: 5000, Fee: 175, Net: 4825, Currency: "usd"},
+		{ID: "txn_re1", Type: "refund", ReportingCategory: "refund", ChargeID: "ch_1", PaymentIntentID: "pi_1", Amount: -2000, Net: -2000, Currency: "usd"},
+		{ID: "txn_ch3", Type: "charge", ReportingCategory: "charge", ChargeID: "ch_3", Amount: 500, Fee: 45, Net: 455, Currency: "usd"},
+		{ID: "txn_fee", Type: "stripe_fee", ReportingCategory: "fee", Amount: -881, Net: -881, Currency: "usd"},
+	}}
+	r, err := BuildSettlementReport(context.Background(), stub, "po_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.queries) != 3 || stub.queries[0].PayoutID != "po_1" || !stub.queries[0].ExpandSources {
+		t.Errorf("queries %+v", stub.queries)
+	}
+	if r.Amount != 12079 || r.Net != r.Amount || r.Currency != "usd" {
+		t.Errorf("payout %d, net %d %s", r.Amount, r.Net, r.Currency)
+	}
+	if r.Charges != 15500 || r.Refunds != -2000 || r.Fees != 320+175+45+881 || len(r.Other) != 1 {
+		t.Errorf("totals %+v", r)
+	}
+	if len(r.Lines) != 3 {
+		t.Fatalf("lines %+v", r.Lines)
+	}
+	if l := r.Lines[0]; l.PaymentIntentID != "pi_1" || l.Charged != 10000 || l.Refunded != -2000 || l.Net != 7680 || len(l.TransactionIDs) != 2 {
+		t.Errorf("pi_1 %+v", l)
+	}
+	if l := r.Lines[2]; l.PaymentIntentID != "" || l.ChargeID != "ch_3" || l.Net != 455 {
+		t.Errorf("ch_3 %+v", l)
+	}
+}
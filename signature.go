@@ -0,0 +1,79 @@
+package gomultistripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSignatureTolerance is the maximum age of a signed webhook Stripe recommends accepting.
+const DefaultSignatureTolerance = 300 * time.Second
+
+var (
+	// ErrInvalidSignatureHeader is returned when the Stripe-Signature header cannot be parsed.
+	ErrInvalidSignatureHeader = errors.New("webhook has invalid Stripe-Signature header")
+	// ErrNoValidSignature is returned when no v1 signature matches the payload and secret.
+	ErrNoValidSignature = errors.New("webhook had no valid signature")
+	// ErrSignatureTooOld is returned when the signature timestamp is outside the tolerance.
+	ErrSignatureTooOld = errors.New("timestamp wasn't within tolerance")
+	// ErrInvalidWebhook is matched by errors from HandleWebhook for webhooks that are
+	// unsigned, badly signed or not an event, which fail the same way when redelivered.
+	ErrInvalidWebhook = errors.New("invalid webhook")
+	// ErrUnknownEventType is matched by errors from HandleWebhook for correctly signed events
+	// of a type the handler does not normalize.
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// VerifySignature checks a Stripe-Signature header against payload using the endpoint
+// secret, without parsing the event. It is version independent and is used to find the
+// account a webhook belongs to before handing it to a versioned handler.
+func VerifySignature(payload []byte, sigHeader string, secret string, tolerance time.Duration) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+	if tolerance > 0 && time.Since(time.Unix(ts, 0)) > tolerance {
+		return ErrSignatureTooOld
+	}
+	expected := computeSignature(payload, timestamp, secret)
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrNoValidSignature
+}
+
+func computeSignature(payload []byte, timestamp string, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// SignPayload returns a Stripe-Signature header for payload signed with secret at the given
+// time, as Stripe would send it. Use it to build webhook requests in tests.
+func SignPayload(payload []byte, secret string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(computeSignature(payload, timestamp, secret))
+}
@@ -0,0 +1,36 @@
+package gomultistripe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+	// During a secret roll Stripe signs with both secrets.
+	rolled := SignPayload(payload, "whsec_old", now) + ",v1=" + strings.SplitN(SignPayload(payload, "whsec_new", now), ",v1=", 2)[1]
+	for _, tc := range []struct {
+		name, header string
+		tolerance    time.Duration
+		err          error
+	}{
+		{name: "valid", header: SignPayload(payload, "whsec_new", now), tolerance: DefaultSignatureTolerance},
+		{name: "rolled secret", header: rolled, tolerance: DefaultSignatureTolerance},
+		{name: "other secret", header: SignPayload(payload, "whsec_other", now), tolerance: DefaultSignatureTolerance, err: ErrNoValidSignature},
+		{name: "too old", header: SignPayload(payload, "whsec_new", now.Add(-time.Hour)), tolerance: DefaultSignatureTolerance, err: ErrSignatureTooOld},
+		{name: "no tolerance", header: SignPayload(payload, "whsec_new", now.Add(-time.Hour))},
+		{name: "no timestamp", header: "v1=00", tolerance: DefaultSignatureTolerance, err: ErrInvalidSignatureHeader},
+		{name: "no signature", header: "t=1700000000,v0=00", tolerance: DefaultSignatureTolerance, err: ErrInvalidSignatureHeader},
+		{name: "malformed signature", header: "t=1700000000,v1=zz", err: ErrNoValidSignature},
+	} {
+		if err := VerifySignature(payload, tc.header, "whsec_new", tc.tolerance); !errors.Is(err, tc.err) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.err)
+		}
+	}
+	if err := VerifySignature([]byte(`{"id":"evt_2"}`), SignPayload(payload, "whsec_new", now), "whsec_new", 0); !errors.Is(err, ErrNoValidSignature) {
+		t.Errorf("changed payload: got %v", err)
+	}
+}
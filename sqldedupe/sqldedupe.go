@@ -0,0 +1,77 @@
+// Package sqldedupe implements gomultistripe.DedupeStore on a database/sql table, so that
+// webhook redeliveries are dropped across every replica sharing the database. It is kept
+// small to serve as an example for other storage.
+//
+// Create the table with Schema, or an equivalent for your database, and wrap the consumer:
+//
+//	store := sqldedupe.New(db)
+//	consume = gomultistripe.Deduplicate(store, consume)
+//
+// The default queries use PostgreSQL and SQLite syntax. For MySQL, set them to e.g.
+// "INSERT IGNORE INTO stripe_webhook_events (event_id) VALUES (?)".
+package sqldedupe
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+var _ gomultistripe.DedupeStore = (*Store)(nil)
+
+const (
+	// Schema creates the table the default queries use.
+	Schema = `CREATE TABLE IF NOT EXISTS stripe_webhook_events (
+	event_id TEXT PRIMARY KEY,
+	seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+	DefaultInsertQuery = `INSERT INTO stripe_webhook_events (event_id) VALUES ($1) ON CONFLICT (event_id) DO NOTHING`
+	DefaultDeleteQuery = `DELETE FROM stripe_webhook_events WHERE event_id = $1`
+	DefaultPruneQuery  = `DELETE FROM stripe_webhook_events WHERE seen_at < $1`
+)
+
+// Store is a gomultistripe.DedupeStore on a database/sql table.
+type Store struct {
+	DB *sql.DB
+	// InsertQuery records the event ID it is given, and affects no row when the ID is
+	// already recorded.
+	InsertQuery string
+	// DeleteQuery removes the event ID it is given.
+	DeleteQuery string
+	// PruneQuery removes the event IDs recorded before the time it is given.
+	PruneQuery string
+}
+
+// New returns a Store using the default queries on db.
+func New(db *sql.DB) *Store {
+	return &Store{DB: db, InsertQuery: DefaultInsertQuery, DeleteQuery: DefaultDeleteQuery, PruneQuery: DefaultPruneQuery}
+}
+
+func (s *Store) MarkSeen(ctx context.Context, eventID string) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, s.InsertQuery, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (s *Store) Forget(ctx context.Context, eventID string) error {
+	_, err := s.DB.ExecContext(ctx, s.DeleteQuery, eventID)
+	return err
+}
+
+// Prune removes the event IDs recorded before before, e.g. 72 hours ago, after which Stripe
+// no longer retries deliveries. Run it periodically to bound the table.
+func (s *Store) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, s.PruneQuery, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,83 @@
+package sqldedupe
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeDriver executes the default queries against a map of event IDs to the time they
+// were recorded.
+type fakeDriver struct{ rows map[string]time.Time }
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var n int64
+	switch query {
+	case DefaultInsertQuery:
+		id := args[0].Value.(string)
+		if _, ok := c.d.rows[id]; !ok {
+			c.d.rows[id], n = time.Now(), 1
+		}
+	case DefaultDeleteQuery:
+		id := args[0].Value.(string)
+		if _, ok := c.d.rows[id]; ok {
+			delete(c.d.rows, id)
+			n = 1
+		}
+	case DefaultPruneQuery:
+		before := args[0].Value.(time.Time)
+		for id, seen := range c.d.rows {
+			if seen.Before(before) {
+				delete(c.d.rows, id)
+				n++
+			}
+		}
+	default:
+		return nil, errors.New("unexpected query")
+	}
+	return driver.RowsAffected(n), nil
+}
+
+func TestStore(t *testing.T) {
+	fake := &fakeDriver{rows: map[string]time.Time{"evt_old": time.Now().Add(-96 * time.Hour)}}
+	sql.Register("sqldedupe-fake", fake)
+	db, err := sql.Open("sqldedupe-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := New(db)
+	ctx := context.Background()
+
+	if isNew, err := s.MarkSeen(ctx, "evt_1"); !isNew || err != nil {
+		t.Fatalf("first MarkSeen: %t, %v", isNew, err)
+	}
+	if isNew, _ := s.MarkSeen(ctx, "evt_1"); isNew {
+		t.Fatal("second MarkSeen reported a new event")
+	}
+	if err := s.Forget(ctx, "evt_1"); err != nil {
+		t.Fatal(err)
+	}
+	if isNew, _ := s.MarkSeen(ctx, "evt_1"); !isNew {
+		t.Fatal("forgotten event not new")
+	}
+	if n, err := s.Prune(ctx, time.Now().Add(-72*time.Hour)); n != 1 || err != nil {
+		t.Fatalf("Prune removed %d: %v", n, err)
+	}
+	if _, ok := fake.rows["evt_1"]; !ok || len(fake.rows) != 1 {
+		t.Errorf("rows left %v", fake.rows)
+	}
+}
@@ -0,0 +1,111 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SharedStore is storage shared by every replica of a deployment, such as Redis (see the
+// redisstore package). StoreLocker builds distributed locks on it, and duplicate
+// suppression records the keys it has seen in it, so both work across replicas.
+// Implementations must be safe for concurrent use.
+type SharedStore interface {
+	// SetNX stores value under key for ttl unless the key exists, and reports whether it
+	// stored it.
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// CompareAndDelete deletes key if it holds value, and reports whether it deleted it.
+	CompareAndDelete(ctx context.Context, key, value string) (bool, error)
+}
+
+// MemoryStore is a SharedStore for a single process, e.g. for tests. The zero value is
+// ready to use. Expired keys are removed when they are next written.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   string
+	expires time.Time
+}
+
+func (m *MemoryStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]memoryEntry)
+	}
+	if e, ok := m.entries[key]; ok && time.Now().Before(e.expires) {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{value: value, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (m *MemoryStore) CompareAndDelete(ctx context.Context, key, value string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || e.value != value || !time.Now().Before(e.expires) {
+		return false, nil
+	}
+	delete(m.entries, key)
+	return true, nil
+}
+
+// StoreLocker is a Locker shared by every replica that uses the same SharedStore. A lock
+// expires after TTL even if it is never released, so a crashed replica cannot block a
+// customer forever; keep the locked operations well below it.
+type StoreLocker struct {
+	Store SharedStore
+	// Prefix is prepended to lock keys. Defaults to "gomultistripe:lock:".
+	Prefix string
+	// TTL bounds how long a lock is held. Defaults to 30 seconds.
+	TTL time.Duration
+	// RetryInterval is how often a waiting Lock retries. Defaults to 50 milliseconds.
+	RetryInterval time.Duration
+}
+
+func (l *StoreLocker) Lock(ctx context.Context, key string) (func(), error) {
+	prefix, ttl, retry := l.Prefix, l.TTL, l.RetryInterval
+	if prefix == "" {
+		prefix = "gomultistripe:lock:"
+	}
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	if retry <= 0 {
+		retry = 50 * time.Millisecond
+	}
+	key = prefix + key
+	// The token makes sure a replica only releases its own lock, not one another replica
+	// acquired after this one expired.
+	token := NewIdempotencyKey()
+	for {
+		ok, err := l.Store.SetNX(ctx, key, token, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+					defer cancel()
+					if _, err := l.Store.CompareAndDelete(ctx, key, token); err != nil {
+						Logger().WarnContext(ctx, "could not release lock; it expires on its own",
+							"key", key, "ttl", ttl, "error", err)
+					}
+				})
+			}, nil
+		}
+		timer := time.NewTimer(retry)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package gomultistripe
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStoreLocker_SerializesReplicas(t *testing.T) {
+	store := &MemoryStore{}
+	// Two replicas share only the store.
+	replicas := []*StoreLocker{
+		{Store: store, RetryInterval: time.Millisecond},
+		{Store: store, RetryInterval: time.Millisecond},
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		holders int
+	)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := replicas[i%2].Lock(context.Background(), "cus_a")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			holders++
+			if holders > 1 {
+				t.Error("two replicas hold the same lock")
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			holders--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStoreLocker_LockExpires(t *testing.T) {
+	store := &MemoryStore{}
+	crashed := &StoreLocker{Store: store, TTL: 20 * time.Millisecond}
+	if _, err := crashed.Lock(context.Background(), "cus_a"); err != nil {
+		t.Fatal(err)
+	}
+	other := &StoreLocker{Store: store, RetryInterval: 5 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := other.Lock(ctx, "cus_a")
+	if err != nil {
+		t.Fatalf("lock of a crashed replica should expire: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,82 @@
+package gomultistripe
+
+// SubscriptionItem is one price on a subscription.
+type SubscriptionItem struct {
+	ID       string
+	PriceID  string
+	Quantity int64
+}
+
+// SubscriptionItemParams adds, changes or removes one subscription item, for
+// WithSubscriptionItems and Handler.UpdateSubscriptionItems.
+type SubscriptionItemParams struct {
+	// ID is the item to change or remove. Empty adds a new item.
+	ID string
+	// PriceID is the price of a new item, or moves an existing item to another price.
+	PriceID string
+	// Quantity is the item's quantity, e.g. its seats. Zero leaves an existing item's
+	// quantity unchanged and gives a new item a quantity of 1.
+	Quantity int64
+	// Delete removes the item with ID from the subscription.
+	Delete bool
+}
+
+// SubscriptionItemChange describes how one subscription item differs between the previous
+// and current items of a customer.subscription.updated event.
+type SubscriptionItemChange struct {
+	ID               string
+	PriceID          string
+	PreviousPriceID  string
+	Quantity         int64
+	PreviousQuantity int64
+	// Added and Removed are set for items that only exist after or before the update.
+	Added   bool
+	Removed bool
+}
+
+// PriceChanged reports a plan change: the item moved to a different price.
+func (c SubscriptionItemChange) PriceChanged() bool {
+	return !c.Added && !c.Removed && c.PriceID != c.PreviousPriceID
+}
+
+// QuantityChanged reports a seat change on an item present before and after the update.
+func (c SubscriptionItemChange) QuantityChanged() bool {
+	return !c.Added && !c.Removed && c.Quantity != c.PreviousQuantity
+}
+
+// ItemChanges compares Items with PreviousItems by item ID and returns the items that were
+// added, removed or changed price or quantity. It returns nil when the event did not change
+// the items.
+func (e *CallbackEvent) ItemChanges() []SubscriptionItemChange {
+	if e.PreviousItems == nil {
+		return nil
+	}
+	previous := make(map[string]SubscriptionItem, len(e.PreviousItems))
+	for _, item := range e.PreviousItems {
+		previous[item.ID] = item
+	}
+	var changes []SubscriptionItemChange
+	for _, item := range e.Items {
+		prev, ok := previous[item.ID]
+		delete(previous, item.ID)
+		if !ok {
+			changes = append(changes, SubscriptionItemChange{ID: item.ID, PriceID: item.PriceID, Quantity: item.Quantity, Added: true})
+			continue
+		}
+		if prev.PriceID != item.PriceID || prev.Quantity != item.Quantity {
+			changes = append(changes, SubscriptionItemChange{
+				ID:               item.ID,
+				PriceID:          item.PriceID,
+				PreviousPriceID:  prev.PriceID,
+				Quantity:         item.Quantity,
+				PreviousQuantity: prev.Quantity,
+			})
+		}
+	}
+	for _, item := range e.PreviousItems {
+		if _, ok := previous[item.ID]; ok {
+			changes = append(changes, SubscriptionItemChange{ID: item.ID, PreviousPriceID: item.PriceID, PreviousQuantity: item.Quantity, Removed: true})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,104 @@
+package gomultistripe
+
+import "time"
+
+// SubscriptionOptions holds the optional settings for Handler.CreateSubscription. Zero
+// values leave Stripe's defaults in place.
+type SubscriptionOptions struct {
+	// BackdateStartDate starts the subscription in the past, e.g. to carry over the original
+	// start date when migrating from another billing system. Stripe invoices the backdated
+	// period unless proration is disabled.
+	BackdateStartDate time.Time
+	// BillingCycleAnchor fixes the future date the billing cycle renews on, e.g. to keep
+	// a migrated customer's existing renewal date.
+	BillingCycleAnchor time.Time
+	// CouponID and PromotionCodeID discount the subscription's invoices.
+	CouponID        string
+	PromotionCodeID string
+	// TrialPeriodDays starts the subscription with a free trial of that many days, and
+	// TrialEnd with one ending at a fixed time instead; TrialEnd wins when both are set.
+	// Subscriptions created with a trial are trialing until it ends.
+	TrialPeriodDays int64
+	TrialEnd        time.Time
+	// DefaultPaymentMethodID pays the subscription's invoices, ahead of the customer's
+	// default payment method.
+	DefaultPaymentMethodID string
+	// Metadata is set on the subscription.
+	Metadata map[string]string
+	// Quantity is the quantity of the price passed to CreateSubscription, e.g. its seats.
+	Quantity int64
+	// Items are added to the subscription besides the price passed to CreateSubscription,
+	// for subscriptions billing several prices. Only their PriceID and Quantity are used.
+	Items []SubscriptionItemParams
+}
+
+// SubscriptionOption configures SubscriptionOptions.
+type SubscriptionOption func(*SubscriptionOptions)
+
+// WithBackdateStartDate backdates the subscription start to t.
+func WithBackdateStartDate(t time.Time) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.BackdateStartDate = t }
+}
+
+// WithBillingCycleAnchor anchors the subscription's billing cycle to t.
+func WithBillingCycleAnchor(t time.Time) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.BillingCycleAnchor = t }
+}
+
+// WithCoupon applies a coupon to the subscription.
+func WithCoupon(couponID string) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.CouponID = couponID }
+}
+
+// WithPromotionCode applies a promotion code, by its ID as returned by
+// Handler.ValidatePromotionCode, to the subscription.
+func WithPromotionCode(promotionCodeID string) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.PromotionCodeID = promotionCodeID }
+}
+
+// WithTrialPeriodDays starts the subscription with a trial of days days.
+func WithTrialPeriodDays(days int64) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.TrialPeriodDays = days }
+}
+
+// WithTrialEnd starts the subscription with a trial ending at t.
+func WithTrialEnd(t time.Time) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.TrialEnd = t }
+}
+
+// WithDefaultPaymentMethod pays the subscription's invoices with a payment method of the
+// customer.
+func WithDefaultPaymentMethod(paymentMethodID string) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.DefaultPaymentMethodID = paymentMethodID }
+}
+
+// WithSubscriptionMetadata sets a metadata key on the subscription.
+func WithSubscriptionMetadata(key, value string) SubscriptionOption {
+	return func(o *SubscriptionOptions) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// WithQuantity sets the quantity of the subscription's price, e.g. its number of seats.
+func WithQuantity(quantity int64) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.Quantity = quantity }
+}
+
+// WithSubscriptionItems adds prices to the subscription. Pass an empty priceID to
+// CreateSubscription to create a subscription of these items only.
+func WithSubscriptionItems(items ...SubscriptionItemParams) SubscriptionOption {
+	return func(o *SubscriptionOptions) { o.Items = append(o.Items, items...) }
+}
+
+// NewSubscriptionOptions applies opts in order. Handlers use it to read the options
+// passed to CreateSubscription.
+func NewSubscriptionOptions(opts ...SubscriptionOption) SubscriptionOptions {
+	var o SubscriptionOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
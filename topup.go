@@ -0,0 +1,50 @@
+package gomultistripe
+
+import "time"
+
+// TopupParams describes a top-up, which funds the platform's Stripe balance from its
+// default bank account, e.g. to cover transfers and payouts ahead of incoming payments.
+type TopupParams struct {
+	Amount   int64
+	Currency string
+	// StatementDescriptor appears on the bank statement, up to 15 characters.
+	StatementDescriptor string
+	// TransferGroup groups the top-up with the transfers it funds.
+	TransferGroup string
+	Description   string
+	Metadata      map[string]string
+}
+
+// Topup represents a Stripe top-up in a version-agnostic way.
+type Topup struct {
+	ID       string
+	Amount   int64
+	Currency string
+	// Status is "pending", "succeeded", "failed", "canceled" or "reversed".
+	Status string
+	// ExpectedAvailabilityDate is when the funds are expected in the balance, or zero.
+	ExpectedAvailabilityDate time.Time
+	BalanceTransactionID     string
+	// FailureCode and FailureMessage explain a failed top-up.
+	FailureCode         string
+	FailureMessage      string
+	StatementDescriptor string
+	TransferGroup       string
+	Description         string
+	Metadata            map[string]string
+	CreatedAt           time.Time
+}
+
+// TopupQuery filters ListTopups. Empty fields do not filter.
+type TopupQuery struct {
+	// Status is one of the Topup statuses.
+	Status string
+}
+
+// TopupPage is one page of top-ups.
+type TopupPage struct {
+	Topups  []*Topup
+	HasMore bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,94 @@
+package gomultistripe
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// TraceTarget selects where handlers attach the active trace to outgoing Stripe requests.
+type TraceTarget int
+
+const (
+	// TraceToMetadata stores the traceparent in the metadata of created and updated
+	// customers, payment intents, subscriptions and subscription items, where it is visible
+	// in the Dashboard and in webhook payloads.
+	TraceToMetadata TraceTarget = 1 << iota
+	// TraceToIdempotencyKey appends the trace ID to the random Idempotency-Keys of requests
+	// without a key from ContextWithIdempotencyKey, so Stripe's request logs can be searched
+	// by trace. Caller-supplied keys are left unchanged.
+	TraceToIdempotencyKey
+)
+
+// DefaultTraceMetadataKey is the metadata key used by TraceToMetadata when none is set.
+const DefaultTraceMetadataKey = "traceparent"
+
+// TracePropagation configures trace propagation into Stripe requests.
+type TracePropagation struct {
+	// Targets is where the trace is attached. Zero disables propagation.
+	Targets TraceTarget
+	// MetadataKey overrides DefaultTraceMetadataKey.
+	MetadataKey string
+	// Extract returns the W3C traceparent (or any trace identifier) of the span active in ctx,
+	// or "" when there is none. Defaults to TraceIDFromContext.
+	Extract func(ctx context.Context) string
+}
+
+var (
+	traceMu  sync.RWMutex
+	traceCfg TracePropagation
+)
+
+// SetTracePropagation installs the trace propagation used by all handlers. Pass the zero
+// value to disable it.
+func SetTracePropagation(cfg TracePropagation) {
+	if cfg.MetadataKey == "" {
+		cfg.MetadataKey = DefaultTraceMetadataKey
+	}
+	if cfg.Extract == nil {
+		cfg.Extract = func(ctx context.Context) string {
+			id, _ := TraceIDFromContext(ctx)
+			return id
+		}
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	traceCfg = cfg
+}
+
+// activeTrace returns the configuration and the trace active in ctx, if propagation to
+// target is enabled.
+func activeTrace(ctx context.Context, target TraceTarget) (TracePropagation, string, bool) {
+	traceMu.RLock()
+	cfg := traceCfg
+	traceMu.RUnlock()
+	if cfg.Targets&target == 0 {
+		return cfg, "", false
+	}
+	trace := cfg.Extract(ctx)
+	return cfg, trace, trace != ""
+}
+
+// TraceMetadata returns the metadata entry handlers add to requests for the trace active in
+// ctx, when TraceToMetadata is enabled.
+func TraceMetadata(ctx context.Context) (key, value string, ok bool) {
+	cfg, trace, ok := activeTrace(ctx, TraceToMetadata)
+	if !ok {
+		return "", "", false
+	}
+	return cfg.MetadataKey, trace, true
+}
+
+// traceKeySuffix returns the suffix RequestIdempotencyKey appends for the trace active in
+// ctx: the trace ID of a traceparent ("00-<trace-id>-<span-id>-<flags>"), or the whole value
+// for other identifiers.
+func traceKeySuffix(ctx context.Context) string {
+	_, trace, ok := activeTrace(ctx, TraceToIdempotencyKey)
+	if !ok {
+		return ""
+	}
+	if parts := strings.Split(trace, "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		trace = parts[1]
+	}
+	return "." + trace
+}
@@ -0,0 +1,33 @@
+package gomultistripe
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTracePropagation(t *testing.T) {
+	defer SetTracePropagation(TracePropagation{})
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := ContextWithTraceID(context.Background(), traceparent)
+
+	if _, _, ok := TraceMetadata(ctx); ok {
+		t.Fatal("propagation should be disabled by default")
+	}
+
+	SetTracePropagation(TracePropagation{Targets: TraceToMetadata | TraceToIdempotencyKey})
+	key, value, ok := TraceMetadata(ctx)
+	if !ok || key != DefaultTraceMetadataKey || value != traceparent {
+		t.Fatalf("TraceMetadata = %q, %q, %v", key, value, ok)
+	}
+	if got := RequestIdempotencyKey(ctx, "CreatePaymentIntent"); !strings.HasSuffix(got, ".4bf92f3577b34da6a3ce929d0e0e4736") {
+		t.Fatalf("RequestIdempotencyKey = %q", got)
+	}
+	// A caller's key must survive a retry in another trace.
+	if got := RequestIdempotencyKey(ContextWithIdempotencyKey(ctx, "order-42"), "CreatePaymentIntent"); got != "order-42:CreatePaymentIntent" {
+		t.Fatalf("RequestIdempotencyKey with a caller key = %q", got)
+	}
+	if got := RequestIdempotencyKey(context.Background(), "CreatePaymentIntent"); strings.Contains(got, ".") {
+		t.Fatalf("no trace is active, but key %q has a suffix", got)
+	}
+}
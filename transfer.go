@@ -0,0 +1,60 @@
+package gomultistripe
+
+import "time"
+
+// TransferParams describes a transfer of funds from the platform's balance to a connected
+// account.
+type TransferParams struct {
+	Amount               int64
+	Currency             string
+	DestinationAccountID string
+	// SourceChargeID ties the transfer to a charge, so it is paid from that charge's funds
+	// even before they are available in the balance.
+	SourceChargeID string
+	// TransferGroup groups the transfers and payment intents of one order.
+	TransferGroup string
+	Description   string
+	Metadata      map[string]string
+}
+
+// Transfer represents a Stripe transfer to a connected account in a version-agnostic way.
+type Transfer struct {
+	ID                   string
+	Amount               int64
+	AmountReversed       int64
+	Currency             string
+	DestinationAccountID string
+	// DestinationPaymentID is the payment created on the connected account by the transfer.
+	DestinationPaymentID string
+	SourceChargeID       string
+	TransferGroup        string
+	// Reversed is set once the transfer is reversed in full.
+	Reversed    bool
+	Description string
+	Metadata    map[string]string
+	CreatedAt   time.Time
+}
+
+// TransferReversal is a full or partial reversal of a transfer, which moves the funds back
+// from the connected account to the platform.
+type TransferReversal struct {
+	ID         string
+	TransferID string
+	Amount     int64
+	Currency   string
+	CreatedAt  time.Time
+}
+
+// TransferQuery filters ListTransfers. Empty fields do not filter.
+type TransferQuery struct {
+	DestinationAccountID string
+	TransferGroup        string
+}
+
+// TransferPage is one page of transfers.
+type TransferPage struct {
+	Transfers []*Transfer
+	HasMore   bool
+	// NextCursor is passed as ListOptions.StartingAfter to fetch the next page.
+	NextCursor string
+}
@@ -0,0 +1,12 @@
+package v74
+
+import (
+	"context"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// CreateCustomerSession is not available before stripe-go v76.
+func (h *HandlerV74) CreateCustomerSession(ctx context.Context, customerID string, components []gomultistripe.CustomerSessionComponent) (*gomultistripe.CustomerSession, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "CreateCustomerSession")
+}
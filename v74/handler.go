@@ -4,33 +4,124 @@ package v74
 import (
 	"context"
 	"errors"
-	"time"
+	"sync"
 
 	gomultistripe "github.com/iqhive/gomultistripe"
 	"github.com/stripe/stripe-go/v74"
-	"github.com/stripe/stripe-go/v74/customer"
-	"github.com/stripe/stripe-go/v74/paymentintent"
-	"github.com/stripe/stripe-go/v74/paymentmethod"
-	"github.com/stripe/stripe-go/v74/subscription"
+	"github.com/stripe/stripe-go/v74/client"
 )
 
 // Handler implements the Handler interface for Stripe API v74.
 type HandlerV74 struct {
-	webhookSecret string
+	webhookSecret  string
+	schemaReporter gomultistripe.SchemaReporter
+
+	clientMu  sync.Mutex
+	secretKey string
+	backends  *stripe.Backends // set by SetEndpoints
+	api       *client.API      // for apiKey
+	apiKey    string
 }
 
 func NewHandler() *HandlerV74 { return &HandlerV74{} }
 
 func (h *HandlerV74) Version() string { return "v74" }
 
+func (h *HandlerV74) APIVersion() string { return stripe.APIVersion }
+
+// SetSecretKey sets the key this handler authenticates with. Handlers hold their own
+// key, so handlers in one process can act for different Stripe accounts.
 func (h *HandlerV74) SetSecretKey(secretKey string) {
-	stripe.Key = secretKey
+	h.clientMu.Lock()
+	h.secretKey = secretKey
+	h.clientMu.Unlock()
 }
 
 func (h *HandlerV74) SetWebhookSecret(webhookSecret string) {
 	h.webhookSecret = webhookSecret
 }
 
+// SetEndpoints points this handler's SDK backends at the given base URLs. Other handlers,
+// including those of the same SDK major, keep their own endpoints.
+func (h *HandlerV74) SetEndpoints(endpoints gomultistripe.Endpoints) {
+	backends := &stripe.Backends{
+		API: stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+			URL: endpointURL(endpoints.APIURL),
+		}),
+		Uploads: stripe.GetBackendWithConfig(stripe.UploadsBackend, &stripe.BackendConfig{
+			URL: endpointURL(endpoints.FilesURL),
+		}),
+		Connect: stripe.GetBackendWithConfig(stripe.ConnectBackend, &stripe.BackendConfig{
+			URL: endpointURL(endpoints.ConnectURL),
+		}),
+	}
+	h.clientMu.Lock()
+	h.backends = backends
+	h.api = nil
+	h.clientMu.Unlock()
+}
+
+// endpointURL returns nil for an empty override so the SDK falls back to its default URL.
+func endpointURL(url string) *string {
+	if url == "" {
+		return nil
+	}
+	return stripe.String(url)
+}
+
+// key returns the secret key set with gomultistripe.ContextWithAPIKey, or else the
+// handler's, falling back to the package-level stripe.Key for callers that set it directly.
+func (h *HandlerV74) key(ctx context.Context) string {
+	if key, ok := gomultistripe.APIKeyFromContext(ctx); ok {
+		return key
+	}
+	h.clientMu.Lock()
+	defer h.clientMu.Unlock()
+	if h.secretKey != "" {
+		return h.secretKey
+	}
+	return stripe.Key
+}
+
+// client returns the SDK client for the key of a request. The client for the handler's own
+// key is built on first use and dropped when the key or the endpoints change. Clients for
+// keys set with gomultistripe.ContextWithAPIKey are built for each call, so a handler
+// serving many tenants does not keep one for every key it has seen.
+func (h *HandlerV74) client(ctx context.Context) *client.API {
+	h.clientMu.Lock()
+	defer h.clientMu.Unlock()
+	// A nil h.backends makes the client use the SDK's default backends.
+	if key, ok := gomultistripe.APIKeyFromContext(ctx); ok {
+		return client.New(key, h.backends)
+	}
+	key := h.secretKey
+	if key == "" {
+		key = stripe.Key
+	}
+	if h.api == nil || h.apiKey != key {
+		h.api, h.apiKey = client.New(key, h.backends), key
+	}
+	return h.api
+}
+
+// backend returns the handler's backend of the given type, or the SDK's default when
+// SetEndpoints was not called.
+func (h *HandlerV74) backend(backendType stripe.SupportedBackend) stripe.Backend {
+	h.clientMu.Lock()
+	backends := h.backends
+	h.clientMu.Unlock()
+	if backends == nil {
+		return stripe.GetBackend(backendType)
+	}
+	switch backendType {
+	case stripe.UploadsBackend:
+		return backends.Uploads
+	case stripe.ConnectBackend:
+		return backends.Connect
+	}
+	return backends.API
+}
+
 // CreateCustomer implements the Handler interface for v74.
 func (h *HandlerV74) CreateCustomer(ctx context.Context, params *gomultistripe.Customer) (*gomultistripe.Customer, error) {
 	stripeParams := &stripe.CustomerParams{
@@ -41,31 +132,16 @@ func (h *HandlerV74) CreateCustomer(ctx context.Context, params *gomultistripe.C
 			PostalCode: stripe.String(params.Postcode),
 		},
 	}
-	cust, err := customer.New(stripeParams)
+	for _, l := range params.PreferredLocales {
+		stripeParams.PreferredLocales = append(stripeParams.PreferredLocales, stripe.String(l))
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreateCustomer", nil)
+	cust, err := h.client(ctx).Customers.New(stripeParams)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.Customer{
-		ID:    cust.ID,
-		Name:  cust.Name,
-		Email: cust.Email,
-		Phone: cust.Phone,
-		Metadata: func() map[string]string {
-			if cust.Metadata != nil {
-				return cust.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-		Postcode: func() string {
-			if cust.Address != nil {
-				return cust.Address.PostalCode
-			} else {
-				return ""
-			}
-		}(),
-		CreatedAt: time.Unix(cust.Created, 0),
-	}, nil
+	return customerFromStripe(cust), nil
 }
 
 // UpdateCustomer implements the Handler interface for v74.
@@ -78,97 +154,79 @@ func (h *HandlerV74) UpdateCustomer(ctx context.Context, customerID string, para
 			PostalCode: stripe.String(params.Postcode),
 		},
 	}
-	cust, err := customer.Update(customerID, stripeParams)
+	for _, l := range params.PreferredLocales {
+		stripeParams.PreferredLocales = append(stripeParams.PreferredLocales, stripe.String(l))
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "UpdateCustomer", map[string]string{"customer": customerID})
+	cust, err := h.client(ctx).Customers.Update(customerID, stripeParams)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.Customer{
-		ID:    cust.ID,
-		Name:  cust.Name,
-		Email: cust.Email,
-		Phone: cust.Phone,
-		Metadata: func() map[string]string {
-			if cust.Metadata != nil {
-				return cust.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-		Postcode: func() string {
-			if cust.Address != nil {
-				return cust.Address.PostalCode
-			} else {
-				return ""
-			}
-		}(),
-		CreatedAt: time.Unix(cust.Created, 0),
-	}, nil
+	return customerFromStripe(cust), nil
 }
 
 // GetPaymentMethods implements the Handler interface for v74.
-func (h *HandlerV74) GetPaymentMethods(ctx context.Context, customerID string) ([]*gomultistripe.PaymentMethod, error) {
+func (h *HandlerV74) GetPaymentMethods(ctx context.Context, customerID string, opts ...gomultistripe.PaymentMethodListOption) ([]*gomultistripe.PaymentMethod, error) {
+	o := gomultistripe.NewPaymentMethodListOptions(opts...)
 	params := &stripe.PaymentMethodListParams{
 		Customer: stripe.String(customerID),
-		Type:     stripe.String("card"),
 	}
-	iter := paymentmethod.List(params)
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).PaymentMethods.List(params)
 	var methods []*gomultistripe.PaymentMethod
 	for iter.Next() {
 		pm := iter.PaymentMethod()
-		methods = append(methods, &gomultistripe.PaymentMethod{
-			ID:         pm.ID,
-			CustomerID: pm.Customer.ID,
-			Metadata: func() map[string]string {
-				if pm.Metadata != nil {
-					return pm.Metadata
-				} else {
-					return make(map[string]string)
-				}
-			}(),
-			Type:      string(pm.Type),
-			Last4:     pm.Card.Last4,
-			Brand:     string(pm.Card.Brand),
-			ExpMonth:  uint(pm.Card.ExpMonth),
-			ExpYear:   uint(pm.Card.ExpYear),
-			CreatedAt: time.Unix(pm.Created, 0),
-		})
+		methods = append(methods, paymentMethodFromStripe(pm))
 	}
 	if err := iter.Err(); err != nil {
 		return nil, err
 	}
+	if o.ResolveDefault {
+		custParams := &stripe.CustomerParams{}
+		h.scope(ctx, &custParams.Params)
+		cust, err := h.client(ctx).Customers.Get(customerID, custParams)
+		if err != nil {
+			return nil, err
+		}
+		if cust.InvoiceSettings != nil && cust.InvoiceSettings.DefaultPaymentMethod != nil {
+			for _, pm := range methods {
+				pm.IsDefault = pm.ID == cust.InvoiceSettings.DefaultPaymentMethod.ID
+			}
+		}
+	}
+	gomultistripe.SortPaymentMethods(methods, o.Order)
 	return methods, nil
 }
 
 // AttachPaymentMethod attaches a payment method to a customer.
+func (h *HandlerV74) RetrievePaymentMethod(ctx context.Context, paymentMethodID string) (*gomultistripe.PaymentMethod, error) {
+	params := &stripe.PaymentMethodParams{}
+	h.scope(ctx, &params.Params)
+	pm, err := h.client(ctx).PaymentMethods.Get(paymentMethodID, params)
+	if err != nil {
+		return nil, err
+	}
+	return paymentMethodFromStripe(pm), nil
+}
+
 func (h *HandlerV74) AttachPaymentMethod(ctx context.Context, customerID string, paymentMethodID string) (*gomultistripe.PaymentMethod, error) {
 	params := &stripe.PaymentMethodAttachParams{
 		Customer: stripe.String(customerID),
 	}
-	pm, err := paymentmethod.Attach(paymentMethodID, params)
+	h.idempotent(ctx, &params.Params, "AttachPaymentMethod", map[string]string{"customer": customerID, "payment_method": paymentMethodID})
+	pm, err := h.client(ctx).PaymentMethods.Attach(paymentMethodID, params)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.PaymentMethod{
-		ID:         pm.ID,
-		CustomerID: pm.Customer.ID,
-		Metadata: func() map[string]string {
-			if pm.Metadata != nil {
-				return pm.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-		Type:      string(pm.Type),
-		Last4:     pm.Card.Last4,
-		Brand:     string(pm.Card.Brand),
-		ExpMonth:  uint(pm.Card.ExpMonth),
-		ExpYear:   uint(pm.Card.ExpYear),
-		CreatedAt: time.Unix(pm.Created, 0)}, nil
+	return paymentMethodFromStripe(pm), nil
 }
 
 // DetachPaymentMethod detaches a payment method from a customer.
 func (h *HandlerV74) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
-	_, err := paymentmethod.Detach(paymentMethodID, nil)
+	params := &stripe.PaymentMethodDetachParams{}
+	h.idempotent(ctx, &params.Params, "DetachPaymentMethod", map[string]string{"payment_method": paymentMethodID})
+	_, err := h.client(ctx).PaymentMethods.Detach(paymentMethodID, params)
 	return err
 }
 
@@ -179,133 +237,153 @@ func (h *HandlerV74) CreatePaymentIntent(ctx context.Context, params *gomultistr
 		Currency:      stripe.String(params.Currency),
 		Customer:      stripe.String(params.CustomerID),
 		PaymentMethod: stripe.String(params.PaymentMethod),
-		Confirm:       stripe.Bool(true),
+		Confirm:       stripe.Bool(!params.ConfirmLater),
+	}
+	if params.CaptureMethod != "" {
+		stripeParams.CaptureMethod = stripe.String(params.CaptureMethod)
 	}
-	pi, err := paymentintent.New(stripeParams)
+	if !params.ConfirmLater {
+		stripeParams.MandateData = paymentIntentMandateData(params.MandateData)
+		if params.ReturnURL != "" {
+			stripeParams.ReturnURL = stripe.String(params.ReturnURL)
+		}
+		if params.OffSession {
+			stripeParams.OffSession = stripe.Bool(true)
+		}
+	}
+	if apm := params.AutomaticPaymentMethods; apm != nil {
+		stripeParams.AutomaticPaymentMethods = &stripe.PaymentIntentAutomaticPaymentMethodsParams{Enabled: stripe.Bool(apm.Enabled)}
+		if apm.Enabled && apm.AllowRedirects != "" {
+			stripeParams.AutomaticPaymentMethods.AllowRedirects = stripe.String(apm.AllowRedirects)
+		}
+	}
+	if params.ApplicationFeeAmount > 0 {
+		stripeParams.ApplicationFeeAmount = stripe.Int64(params.ApplicationFeeAmount)
+	}
+	if params.TransferDestination != "" {
+		stripeParams.TransferData = &stripe.PaymentIntentTransferDataParams{Destination: stripe.String(params.TransferDestination)}
+	}
+	if params.TransferGroup != "" {
+		stripeParams.TransferGroup = stripe.String(params.TransferGroup)
+	}
+	if params.RequestMulticapture {
+		return nil, gomultistripe.Unsupported(h.Version(), "multicapture")
+	}
+	if params.InstallmentsEnabled || params.InstallmentPlan != nil {
+		if err := gomultistripe.CheckInstallmentCurrency(params.Currency); err != nil {
+			return nil, err
+		}
+		if stripeParams.PaymentMethodOptions == nil {
+			stripeParams.PaymentMethodOptions = &stripe.PaymentIntentPaymentMethodOptionsParams{}
+		}
+		if stripeParams.PaymentMethodOptions.Card == nil {
+			stripeParams.PaymentMethodOptions.Card = &stripe.PaymentIntentPaymentMethodOptionsCardParams{}
+		}
+		stripeParams.PaymentMethodOptions.Card.Installments = installmentsParams(params.InstallmentPlan)
+	}
+	if params.BankTransfer != nil {
+		if params.CustomerID == "" {
+			return nil, gomultistripe.ErrBankTransferRequiresCustomer
+		}
+		stripeParams.PaymentMethod = nil
+		stripeParams.PaymentMethodTypes = []*string{stripe.String("customer_balance")}
+		stripeParams.PaymentMethodData = &stripe.PaymentIntentPaymentMethodDataParams{Type: stripe.String("customer_balance")}
+		if stripeParams.PaymentMethodOptions == nil {
+			stripeParams.PaymentMethodOptions = &stripe.PaymentIntentPaymentMethodOptionsParams{}
+		}
+		stripeParams.PaymentMethodOptions.CustomerBalance = customerBalanceParams(params.BankTransfer)
+	}
+	if params.StatementDescriptorSuffix != "" {
+		if err := gomultistripe.ValidateStatementDescriptorSuffix(params.StatementDescriptorSuffix); err != nil {
+			return nil, err
+		}
+		stripeParams.StatementDescriptorSuffix = stripe.String(params.StatementDescriptorSuffix)
+	}
+	if len(params.PaymentMethodOptions) > 0 {
+		if name, ok := params.PaymentMethodOptions.Unknown(stripe.PaymentIntentPaymentMethodOptionsParams{}); ok {
+			return nil, gomultistripe.Unsupported(h.Version(), "payment method option "+name)
+		}
+		for k, v := range params.PaymentMethodOptions.FormParams() {
+			stripeParams.AddExtra(k, v)
+		}
+	}
+	if params.Level3 != nil {
+		if err := params.Level3.ValidateLineItems(); err != nil {
+			return nil, err
+		}
+		for k, v := range params.Level3.FormParams() {
+			stripeParams.AddExtra(k, v)
+		}
+	}
+	if params.ReceiptEmail != "" {
+		stripeParams.ReceiptEmail = stripe.String(params.ReceiptEmail)
+	}
+	if params.ReceiptLocale != "" {
+		// Receipts follow the customer's preferred locales; changing those here would
+		// affect every later email to the customer, even if the intent is never created.
+		return nil, gomultistripe.Unsupported(h.Version(), "receipt locale")
+	}
+	// The coupon and promotion code lookups go last, once every local check has passed.
+	if params.CouponID != "" || params.PromotionCode != "" {
+		if err := h.discountPaymentIntent(ctx, params, stripeParams); err != nil {
+			return nil, err
+		}
+	}
+	// Level 3 line items must add up to the amount charged, after any discount.
+	if params.Level3 != nil {
+		if err := params.Level3.Validate(*stripeParams.Amount); err != nil {
+			return nil, err
+		}
+	}
+	stripeParams.AddExpand("customer")
+	stripeParams.AddExpand("latest_charge")
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreatePaymentIntent", map[string]string{"customer": params.CustomerID, "payment_method": params.PaymentMethod})
+	pi, err := h.client(ctx).PaymentIntents.New(stripeParams)
 	if err != nil {
-		return nil, err
+		return nil, installmentsError(err)
 	}
-	return &gomultistripe.PaymentIntent{
-		ID:           pi.ID,
-		Amount:       pi.Amount,
-		Currency:     string(pi.Currency),
-		Status:       string(pi.Status),
-		ClientSecret: pi.ClientSecret,
-		CustomerID:   pi.Customer.ID,
-		CreatedAt:    time.Unix(pi.Created, 0),
-		Metadata: func() map[string]string {
-			if pi.Metadata != nil {
-				return pi.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-		PaymentMethod: func() string {
-			if pi.PaymentMethod != nil {
-				return pi.PaymentMethod.ID
-			} else {
-				return ""
-			}
-		}(),
-	}, nil
+	return paymentIntentFromStripe(pi), nil
 }
 
 // RetrievePaymentIntent retrieves a PaymentIntent by ID.
 func (h *HandlerV74) RetrievePaymentIntent(ctx context.Context, paymentIntentID string) (*gomultistripe.PaymentIntent, error) {
-	pi, err := paymentintent.Get(paymentIntentID, nil)
+	params := &stripe.PaymentIntentParams{}
+	params.AddExpand("customer")
+	params.AddExpand("latest_charge")
+	h.scope(ctx, &params.Params)
+	pi, err := h.client(ctx).PaymentIntents.Get(paymentIntentID, params)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.PaymentIntent{
-		ID:           pi.ID,
-		Amount:       pi.Amount,
-		Currency:     string(pi.Currency),
-		Status:       string(pi.Status),
-		ClientSecret: pi.ClientSecret,
-		CustomerID:   pi.Customer.ID,
-		CreatedAt:    time.Unix(pi.Created, 0),
-		Metadata: func() map[string]string {
-			if pi.Metadata != nil {
-				return pi.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-		PaymentMethod: func() string {
-			if pi.PaymentMethod != nil {
-				return pi.PaymentMethod.ID
-			} else {
-				return ""
-			}
-		}(),
-	}, nil
+	return paymentIntentFromStripe(pi), nil
 }
 
 // CreateSubscription implements the Handler interface for v74.
-func (h *HandlerV74) CreateSubscription(ctx context.Context, customerID string, priceID string) (*gomultistripe.Subscription, error) {
-	params := &stripe.SubscriptionParams{
-		Customer: stripe.String(customerID),
-		Items: []*stripe.SubscriptionItemsParams{
-			{Price: stripe.String(priceID)},
-		},
+func (h *HandlerV74) CreateSubscription(ctx context.Context, customerID string, priceID string, opts ...gomultistripe.SubscriptionOption) (*gomultistripe.Subscription, error) {
+	params := &stripe.SubscriptionParams{Customer: stripe.String(customerID)}
+	if priceID != "" {
+		params.Items = []*stripe.SubscriptionItemsParams{{Price: stripe.String(priceID)}}
 	}
-	s, err := subscription.New(params)
+	applySubscriptionOptions(params, gomultistripe.NewSubscriptionOptions(opts...))
+	h.traced(ctx, params)
+	h.idempotent(ctx, &params.Params, "CreateSubscription", map[string]string{"customer": customerID, "price": priceID})
+	s, err := h.client(ctx).Subscriptions.New(params)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.Subscription{
-		ID:         s.ID,
-		CustomerID: s.Customer.ID,
-		Status:     string(s.Status),
-		PriceID: func() string {
-			if len(s.Items.Data) > 0 && s.Items.Data[0].Price != nil {
-				return s.Items.Data[0].Price.ID
-			}
-			return ""
-		}(),
-		CurrentPeriodEnd:  s.CancelAt,
-		CancelAtPeriodEnd: s.CancelAtPeriodEnd,
-		CanceledAt:        s.CanceledAt,
-		CreatedAt:         time.Unix(s.Created, 0),
-		Metadata: func() map[string]string {
-			if s.Metadata != nil {
-				return s.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-	}, nil
+	return subscriptionFromStripe(s), nil
 }
 
 // ListSubscriptions implements the Handler interface for v74.
 func (h *HandlerV74) ListSubscriptions(ctx context.Context, customerID string) ([]*gomultistripe.Subscription, error) {
 	params := &stripe.SubscriptionListParams{Customer: stripe.String(customerID)}
-	iter := subscription.List(params)
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Subscriptions.List(params)
 	var subs []*gomultistripe.Subscription
 	for iter.Next() {
 		s := iter.Subscription()
-		subs = append(subs, &gomultistripe.Subscription{
-			ID:         s.ID,
-			CustomerID: s.Customer.ID,
-			Status:     string(s.Status),
-			PriceID: func() string {
-				if len(s.Items.Data) > 0 && s.Items.Data[0].Price != nil {
-					return s.Items.Data[0].Price.ID
-				}
-				return ""
-			}(),
-			CurrentPeriodEnd:  s.CancelAt,
-			CancelAtPeriodEnd: s.CancelAtPeriodEnd,
-			CanceledAt:        s.CanceledAt,
-			CreatedAt:         time.Unix(s.Created, 0),
-			Metadata: func() map[string]string {
-				if s.Metadata != nil {
-					return s.Metadata
-				} else {
-					return make(map[string]string)
-				}
-			}(),
-		})
+		subs = append(subs, subscriptionFromStripe(s))
 	}
 	if err := iter.Err(); err != nil {
 		return nil, err
@@ -323,32 +401,13 @@ func (h *HandlerV74) UpdateSubscription(ctx context.Context, subscriptionID stri
 			Price: stripe.String(newPriceID),
 		}}
 	}
-	s, err := subscription.Update(subscriptionID, params)
+	h.traced(ctx, params)
+	h.idempotent(ctx, &params.Params, "UpdateSubscription", map[string]string{"subscription": subscriptionID})
+	s, err := h.client(ctx).Subscriptions.Update(subscriptionID, params)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.Subscription{
-		ID:         s.ID,
-		CustomerID: s.Customer.ID,
-		Status:     string(s.Status),
-		PriceID: func() string {
-			if len(s.Items.Data) > 0 && s.Items.Data[0].Price != nil {
-				return s.Items.Data[0].Price.ID
-			}
-			return ""
-		}(),
-		CurrentPeriodEnd:  s.CancelAt,
-		CancelAtPeriodEnd: s.CancelAtPeriodEnd,
-		CanceledAt:        s.CanceledAt,
-		CreatedAt:         time.Unix(s.Created, 0),
-		Metadata: func() map[string]string {
-			if s.Metadata != nil {
-				return s.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-	}, nil
+	return subscriptionFromStripe(s), nil
 }
 
 // CancelSubscription implements the Handler interface for v74.
@@ -357,37 +416,34 @@ func (h *HandlerV74) CancelSubscription(ctx context.Context, subscriptionID stri
 		InvoiceNow: stripe.Bool(!atPeriodEnd),
 		Prorate:    stripe.Bool(!atPeriodEnd),
 	}
-	s, err := subscription.Cancel(subscriptionID, params)
+	h.idempotent(ctx, &params.Params, "CancelSubscription", map[string]string{"subscription": subscriptionID})
+	s, err := h.client(ctx).Subscriptions.Cancel(subscriptionID, params)
 	if err != nil {
 		return nil, err
 	}
-	return &gomultistripe.Subscription{
-		ID:         s.ID,
-		CustomerID: s.Customer.ID,
-		Status:     string(s.Status),
-		PriceID: func() string {
-			if len(s.Items.Data) > 0 && s.Items.Data[0].Price != nil {
-				return s.Items.Data[0].Price.ID
-			}
-			return ""
-		}(),
-		CurrentPeriodEnd:  s.CancelAt,
-		CancelAtPeriodEnd: s.CancelAtPeriodEnd,
-		CanceledAt:        s.CanceledAt,
-		CreatedAt:         time.Unix(s.Created, 0),
-		Metadata: func() map[string]string {
-			if s.Metadata != nil {
-				return s.Metadata
-			} else {
-				return make(map[string]string)
-			}
-		}(),
-	}, nil
+	return subscriptionFromStripe(s), nil
 }
 
 // ErrInvalidParams is returned when params are not of the expected type.
 var ErrInvalidParams = errors.New("invalid params type for this handler version")
 
+// CancelSubscriptionAt implements the Handler interface for v74.
+func (h *HandlerV74) CancelSubscriptionAt(ctx context.Context, subscriptionID string, cancelAt int64) (*gomultistripe.Subscription, error) {
+	params := &stripe.SubscriptionParams{}
+	if cancelAt > 0 {
+		params.CancelAt = stripe.Int64(cancelAt)
+	} else {
+		params.AddExtra("cancel_at", "")
+	}
+	h.traced(ctx, params)
+	h.idempotent(ctx, &params.Params, "CancelSubscriptionAt", map[string]string{"subscription": subscriptionID})
+	s, err := h.client(ctx).Subscriptions.Update(subscriptionID, params)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionFromStripe(s), nil
+}
+
 func init() {
 	gomultistripe.RegisterHandler(NewHandler())
 }
@@ -6,12 +6,11 @@ import (
 	"testing"
 
 	"github.com/iqhive/gomultistripe"
-	"github.com/stripe/stripe-go/v74"
 )
 
 func TestHandlerV74_CreateCustomer(t *testing.T) {
-	stripe.Key = os.Getenv("STRIPE_API_KEY")
-	if stripe.Key == "" {
+	key := os.Getenv("STRIPE_API_KEY")
+	if key == "" {
 		t.Skip("STRIPE_API_KEY not set")
 	}
 
@@ -19,6 +18,7 @@ func TestHandlerV74_CreateCustomer(t *testing.T) {
 	if h == nil {
 		t.Fatal("Handler for v74 not registered")
 	}
+	h.SetSecretKey(key)
 
 	params := &gomultistripe.Customer{
 		Name:  "Test User",
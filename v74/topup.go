@@ -0,0 +1,91 @@
+package v74
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v74"
+)
+
+func (h *HandlerV74) CreateTopup(ctx context.Context, params gomultistripe.TopupParams) (*gomultistripe.Topup, error) {
+	stripeParams := &stripe.TopupParams{
+		Amount:   stripe.Int64(params.Amount),
+		Currency: stripe.String(params.Currency),
+	}
+	if params.StatementDescriptor != "" {
+		stripeParams.StatementDescriptor = stripe.String(params.StatementDescriptor)
+	}
+	if params.TransferGroup != "" {
+		stripeParams.TransferGroup = stripe.String(params.TransferGroup)
+	}
+	if params.Description != "" {
+		stripeParams.Description = stripe.String(params.Description)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreateTopup", map[string]string{"transfer_group": params.TransferGroup})
+	t, err := h.client(ctx).Topups.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return topupFromStripe(t), nil
+}
+
+func (h *HandlerV74) ListTopups(ctx context.Context, query gomultistripe.TopupQuery, opts *gomultistripe.ListOptions) (*gomultistripe.TopupPage, error) {
+	params := &stripe.TopupListParams{}
+	if query.Status != "" {
+		params.Status = stripe.String(query.Status)
+	}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Topups.List(params)
+	page := &gomultistripe.TopupPage{}
+	for iter.Next() {
+		page.Topups = append(page.Topups, topupFromStripe(iter.Topup()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Topups) > 0 {
+		page.NextCursor = page.Topups[len(page.Topups)-1].ID
+	}
+	return page, nil
+}
+
+func topupFromStripe(t *stripe.Topup) *gomultistripe.Topup {
+	out := &gomultistripe.Topup{
+		ID:                  t.ID,
+		Amount:              t.Amount,
+		Currency:            string(t.Currency),
+		Status:              string(t.Status),
+		FailureCode:         t.FailureCode,
+		FailureMessage:      t.FailureMessage,
+		StatementDescriptor: t.StatementDescriptor,
+		TransferGroup:       t.TransferGroup,
+		Description:         t.Description,
+		Metadata:            t.Metadata,
+		CreatedAt:           time.Unix(t.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if t.ExpectedAvailabilityDate > 0 {
+		out.ExpectedAvailabilityDate = time.Unix(t.ExpectedAvailabilityDate, 0)
+	}
+	if t.BalanceTransaction != nil {
+		out.BalanceTransactionID = t.BalanceTransaction.ID
+	}
+	return out
+}
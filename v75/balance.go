@@ -0,0 +1,218 @@
+package v75
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v75"
+)
+
+func (h *HandlerV75) RetrieveBalance(ctx context.Context) (*gomultistripe.Balance, error) {
+	params := &stripe.BalanceParams{}
+	h.scope(ctx, &params.Params)
+	b, err := h.client(ctx).Balance.Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &gomultistripe.Balance{
+		Available:        amountsByCurrency(b.Available),
+		Pending:          amountsByCurrency(b.Pending),
+		InstantAvailable: amountsByCurrency(b.InstantAvailable),
+		ConnectReserved:  amountsByCurrency(b.ConnectReserved),
+	}, nil
+}
+
+func amountsByCurrency(amounts []*stripe.Amount) map[string]int64 {
+	out := make(map[string]int64, len(amounts))
+	for _, a := range amounts {
+		out[string(a.Currency)] += a.Amount
+	}
+	return out
+}
+
+func (h *HandlerV75) ListPayouts(ctx context.Context, query gomultistripe.PayoutQuery, opts *gomultistripe.ListOptions) (*gomultistripe.PayoutPage, error) {
+	params := &stripe.PayoutListParams{}
+	if query.Status != "" {
+		params.Status = stripe.String(query.Status)
+	}
+	if query.DestinationID != "" {
+		params.Destination = stripe.String(query.DestinationID)
+	}
+	if !query.ArrivalFrom.IsZero() || !query.ArrivalTo.IsZero() {
+		params.ArrivalDateRange = &stripe.RangeQueryParams{}
+		if !query.ArrivalFrom.IsZero() {
+			params.ArrivalDateRange.GreaterThanOrEqual = query.ArrivalFrom.Unix()
+		}
+		if !query.ArrivalTo.IsZero() {
+			params.ArrivalDateRange.LesserThanOrEqual = query.ArrivalTo.Unix()
+		}
+	}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Payouts.List(params)
+	page := &gomultistripe.PayoutPage{}
+	for iter.Next() {
+		page.Payouts = append(page.Payouts, payoutFromStripe(iter.Payout()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Payouts) > 0 {
+		page.NextCursor = page.Payouts[len(page.Payouts)-1].ID
+	}
+	return page, nil
+}
+
+func payoutFromStripe(p *stripe.Payout) *gomultistripe.Payout {
+	out := &gomultistripe.Payout{
+		ID:                  p.ID,
+		Amount:              p.Amount,
+		Currency:            string(p.Currency),
+		Status:              string(p.Status),
+		ArrivalDate:         time.Unix(p.ArrivalDate, 0),
+		Automatic:           p.Automatic,
+		Method:              string(p.Method),
+		Type:                string(p.Type),
+		FailureCode:         string(p.FailureCode),
+		FailureMessage:      p.FailureMessage,
+		Description:         p.Description,
+		StatementDescriptor: p.StatementDescriptor,
+		Metadata:            p.Metadata,
+		CreatedAt:           time.Unix(p.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if p.Destination != nil {
+		out.DestinationID = p.Destination.ID
+	}
+	if p.BalanceTransaction != nil {
+		out.BalanceTransactionID = p.BalanceTransaction.ID
+	}
+	return out
+}
+
+func (h *HandlerV75) ListBalanceTransactions(ctx context.Context, query gomultistripe.BalanceTransactionQuery, opts *gomultistripe.ListOptions) (*gomultistripe.BalanceTransactionPage, error) {
+	params := &stripe.BalanceTransactionListParams{}
+	if query.PayoutID != "" {
+		params.Payout = stripe.String(query.PayoutID)
+	}
+	if query.Type != "" {
+		params.Type = stripe.String(query.Type)
+	}
+	if query.Currency != "" {
+		params.Currency = stripe.String(query.Currency)
+	}
+	if query.SourceID != "" {
+		params.Source = stripe.String(query.SourceID)
+	}
+	if !query.CreatedFrom.IsZero() || !query.CreatedTo.IsZero() {
+		params.CreatedRange = &stripe.RangeQueryParams{}
+		if !query.CreatedFrom.IsZero() {
+			params.CreatedRange.GreaterThanOrEqual = query.CreatedFrom.Unix()
+		}
+		if !query.CreatedTo.IsZero() {
+			params.CreatedRange.LesserThan = query.CreatedTo.Unix()
+		}
+	}
+	if query.ExpandSources {
+		params.AddExpand("data.source")
+	}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).BalanceTransactions.List(params)
+	page := &gomultistripe.BalanceTransactionPage{}
+	for iter.Next() {
+		page.Transactions = append(page.Transactions, balanceTransactionFromStripe(iter.BalanceTransaction()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if query.ReportingCurrency != "" {
+		if err := gomultistripe.ConvertToReportingCurrency(ctx, page.Transactions, query.ReportingCurrency, query.ReportingRates); err != nil {
+			return nil, err
+		}
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Transactions) > 0 {
+		page.NextCursor = page.Transactions[len(page.Transactions)-1].ID
+	}
+	return page, nil
+}
+
+func balanceTransactionFromStripe(bt *stripe.BalanceTransaction) *gomultistripe.BalanceTransaction {
+	out := &gomultistripe.BalanceTransaction{
+		ID:                bt.ID,
+		Amount:            bt.Amount,
+		Fee:               bt.Fee,
+		Net:               bt.Net,
+		Currency:          string(bt.Currency),
+		ExchangeRate:      bt.ExchangeRate,
+		Type:              string(bt.Type),
+		ReportingCategory: string(bt.ReportingCategory),
+		Status:            string(bt.Status),
+		AvailableOn:       time.Unix(bt.AvailableOn, 0),
+		Description:       bt.Description,
+		CreatedAt:         time.Unix(bt.Created, 0),
+	}
+	if bt.ExchangeRate == 0 {
+		out.PresentmentAmount, out.PresentmentCurrency = out.Amount, out.Currency
+	}
+	if src := bt.Source; src != nil {
+		out.SourceID = src.ID
+		var presentment int64
+		var presentmentCurrency stripe.Currency
+		switch {
+		case src.Charge != nil:
+			out.ChargeID = src.Charge.ID
+			presentment, presentmentCurrency = src.Charge.AmountCaptured, src.Charge.Currency
+			if presentment == 0 {
+				presentment = src.Charge.Amount
+			}
+			if src.Charge.PaymentIntent != nil {
+				out.PaymentIntentID = src.Charge.PaymentIntent.ID
+			}
+		case src.Refund != nil:
+			presentment, presentmentCurrency = src.Refund.Amount, src.Refund.Currency
+			if src.Refund.Charge != nil {
+				out.ChargeID = src.Refund.Charge.ID
+			}
+			if src.Refund.PaymentIntent != nil {
+				out.PaymentIntentID = src.Refund.PaymentIntent.ID
+			}
+		case src.Dispute != nil:
+			presentment, presentmentCurrency = src.Dispute.Amount, src.Dispute.Currency
+			if src.Dispute.Charge != nil {
+				out.ChargeID = src.Dispute.Charge.ID
+			}
+			if src.Dispute.PaymentIntent != nil {
+				out.PaymentIntentID = src.Dispute.PaymentIntent.ID
+			}
+		}
+		if bt.ExchangeRate != 0 && presentmentCurrency != "" {
+			if out.Amount < 0 {
+				presentment = -presentment
+			}
+			out.PresentmentAmount, out.PresentmentCurrency = presentment, string(presentmentCurrency)
+		}
+	}
+	return out
+}
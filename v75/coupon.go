@@ -0,0 +1,139 @@
+package v75
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v75"
+)
+
+func (h *HandlerV75) CreateCoupon(ctx context.Context, params gomultistripe.CouponParams) (*gomultistripe.Coupon, error) {
+	stripeParams := &stripe.CouponParams{}
+	if params.ID != "" {
+		stripeParams.ID = stripe.String(params.ID)
+	}
+	if params.Name != "" {
+		stripeParams.Name = stripe.String(params.Name)
+	}
+	if params.PercentOff != 0 {
+		stripeParams.PercentOff = stripe.Float64(params.PercentOff)
+	}
+	if params.AmountOff != 0 {
+		stripeParams.AmountOff = stripe.Int64(params.AmountOff)
+		stripeParams.Currency = stripe.String(params.Currency)
+	}
+	if params.Duration != "" {
+		stripeParams.Duration = stripe.String(string(params.Duration))
+	}
+	if params.DurationInMonths != 0 {
+		stripeParams.DurationInMonths = stripe.Int64(params.DurationInMonths)
+	}
+	if params.MaxRedemptions != 0 {
+		stripeParams.MaxRedemptions = stripe.Int64(params.MaxRedemptions)
+	}
+	if !params.RedeemBy.IsZero() {
+		stripeParams.RedeemBy = stripe.Int64(params.RedeemBy.Unix())
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreateCoupon", nil)
+	c, err := h.client(ctx).Coupons.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return couponFromStripe(c), nil
+}
+
+func (h *HandlerV75) GetCoupon(ctx context.Context, couponID string) (*gomultistripe.Coupon, error) {
+	params := &stripe.CouponParams{}
+	h.scope(ctx, &params.Params)
+	c, err := h.client(ctx).Coupons.Get(couponID, params)
+	if err != nil {
+		return nil, err
+	}
+	return couponFromStripe(c), nil
+}
+
+func (h *HandlerV75) ValidatePromotionCode(ctx context.Context, code string, customerID string) (*gomultistripe.PromotionCode, error) {
+	params := &stripe.PromotionCodeListParams{Code: stripe.String(code), Active: stripe.Bool(true)}
+	params.Limit = stripe.Int64(1)
+	params.Single = true
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).PromotionCodes.List(params)
+	if !iter.Next() {
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s does not exist or is inactive", gomultistripe.ErrPromotionCodeInvalid, code)
+	}
+	p := iter.PromotionCode()
+	out := &gomultistripe.PromotionCode{
+		ID:             p.ID,
+		Code:           p.Code,
+		Active:         p.Active,
+		ExpiresAt:      p.ExpiresAt,
+		MaxRedemptions: p.MaxRedemptions,
+		TimesRedeemed:  p.TimesRedeemed,
+	}
+	if p.Coupon != nil {
+		out.Coupon = couponFromStripe(p.Coupon)
+	}
+	if p.Customer != nil {
+		out.CustomerID = p.Customer.ID
+	}
+	if p.Restrictions != nil {
+		out.FirstTimeTransaction = p.Restrictions.FirstTimeTransaction
+		out.MinimumAmount = p.Restrictions.MinimumAmount
+		out.MinimumAmountCurrency = string(p.Restrictions.MinimumAmountCurrency)
+	}
+	if err := out.Check(customerID, time.Now()); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// discountPaymentIntent applies the coupon or promotion code of a payment intent being
+// created to its amount.
+func (h *HandlerV75) discountPaymentIntent(ctx context.Context, params *gomultistripe.PaymentIntent, stripeParams *stripe.PaymentIntentParams) error {
+	var c *gomultistripe.Coupon
+	var promo *gomultistripe.PromotionCode
+	var err error
+	if params.PromotionCode != "" {
+		promo, err = h.ValidatePromotionCode(ctx, params.PromotionCode, params.CustomerID)
+	} else {
+		c, err = h.GetCoupon(ctx, params.CouponID)
+	}
+	if err != nil {
+		return err
+	}
+	amount, md, err := gomultistripe.DiscountPaymentIntent(params, c, promo)
+	if err != nil {
+		return err
+	}
+	stripeParams.Amount = stripe.Int64(amount)
+	for k, v := range md {
+		stripeParams.AddMetadata(k, v)
+	}
+	return nil
+}
+
+func couponFromStripe(c *stripe.Coupon) *gomultistripe.Coupon {
+	return &gomultistripe.Coupon{
+		ID:               c.ID,
+		Name:             c.Name,
+		PercentOff:       c.PercentOff,
+		AmountOff:        c.AmountOff,
+		Currency:         string(c.Currency),
+		Duration:         gomultistripe.CouponDuration(c.Duration),
+		DurationInMonths: c.DurationInMonths,
+		MaxRedemptions:   c.MaxRedemptions,
+		TimesRedeemed:    c.TimesRedeemed,
+		RedeemBy:         c.RedeemBy,
+		Valid:            c.Valid,
+		Metadata:         metadata(c.Metadata),
+		CreatedAt:        time.Unix(c.Created, 0),
+	}
+}
@@ -0,0 +1,61 @@
+package v75
+
+import (
+	"context"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v75"
+)
+
+// scope binds a request to ctx, so it is cancelled with it, and to the connected account
+// set with gomultistripe.ContextWithAccount. Every Stripe request a handler method sends
+// goes through scope, directly or via idempotent.
+func (h *HandlerV75) scope(ctx context.Context, p *stripe.Params) {
+	p.Context = ctx
+	if account, ok := gomultistripe.AccountFromContext(ctx); ok {
+		p.SetStripeAccount(account)
+	}
+}
+
+// scopeList is scope for list requests.
+func (h *HandlerV75) scopeList(ctx context.Context, p *stripe.ListParams) {
+	p.Context = ctx
+	if account, ok := gomultistripe.AccountFromContext(ctx); ok {
+		p.SetStripeAccount(account)
+	}
+}
+
+// traced adds the trace active in ctx to the metadata of a request, when
+// gomultistripe.TraceToMetadata is enabled. Only call it for objects that accept metadata.
+func (h *HandlerV75) traced(ctx context.Context, p interface{ AddMetadata(key, value string) }) {
+	if key, value, ok := gomultistripe.TraceMetadata(ctx); ok {
+		p.AddMetadata(key, value)
+	}
+}
+
+// idempotent assigns an idempotency key to a mutating request and records it in the
+// audit sink together with the entity IDs involved. stripe-go reuses the key when it
+// retries the request, so a duplicate on Stripe's side can be traced to one operation.
+// The key is derived from gomultistripe.ContextWithIdempotencyKey when ctx carries one.
+func (h *HandlerV75) idempotent(ctx context.Context, p *stripe.Params, operation string, entityIDs map[string]string) {
+	h.scope(ctx, p)
+	key := gomultistripe.RequestIdempotencyKey(ctx, operation)
+	p.SetIdempotencyKey(key)
+	account, _ := gomultistripe.AccountFromContext(ctx)
+	traceID, _ := gomultistripe.TraceIDFromContext(ctx)
+	gomultistripe.RecordIdempotencyKey(ctx, gomultistripe.AuditRecord{
+		Operation:      operation,
+		IdempotencyKey: key,
+		EntityIDs:      entityIDs,
+		Version:        h.Version(),
+		Account:        account,
+		TraceID:        traceID,
+	})
+	gomultistripe.Logger().DebugContext(ctx, "sending stripe request",
+		gomultistripe.LogKeyVersion, h.Version(),
+		gomultistripe.LogKeyOperation, operation,
+		gomultistripe.LogKeyAccount, account,
+		gomultistripe.LogKeyTraceID, traceID,
+		"idempotency_key", key,
+	)
+}
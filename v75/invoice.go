@@ -0,0 +1,209 @@
+package v75
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v75"
+)
+
+// invoicePaymentError returns the last payment error of a failed invoice's payment intent,
+// which carries the code, decline code and charge of the failed attempt. Webhook payloads
+// only hold the intent's ID, so unless it was expanded it is retrieved, on the event's
+// connected account. It returns nil when the invoice has no payment intent.
+func (h *HandlerV75) invoicePaymentError(event *stripe.Event, inv *stripe.Invoice) (*stripe.Error, error) {
+	pi := inv.PaymentIntent
+	if pi == nil || pi.ID == "" {
+		return nil, nil
+	}
+	if pi.Status != "" {
+		return pi.LastPaymentError, nil
+	}
+	ctx := gomultistripe.ContextWithAccount(context.Background(), event.Account)
+	params := &stripe.PaymentIntentParams{}
+	h.scope(ctx, &params.Params)
+	pi, err := h.client(ctx).PaymentIntents.Get(pi.ID, params)
+	if err != nil {
+		return nil, err
+	}
+	return pi.LastPaymentError, nil
+}
+
+func (h *HandlerV75) ListInvoices(ctx context.Context, customerID string, status gomultistripe.InvoiceStatus, dateRange gomultistripe.DateRange, opts *gomultistripe.ListOptions) (*gomultistripe.InvoicePage, error) {
+	params := &stripe.InvoiceListParams{}
+	if customerID != "" {
+		params.Customer = stripe.String(customerID)
+	}
+	params.Single = true
+	if status != "" {
+		params.Status = stripe.String(string(status))
+	}
+	if !dateRange.From.IsZero() || !dateRange.To.IsZero() {
+		params.CreatedRange = &stripe.RangeQueryParams{}
+		if !dateRange.From.IsZero() {
+			params.CreatedRange.GreaterThanOrEqual = dateRange.From.Unix()
+		}
+		if !dateRange.To.IsZero() {
+			params.CreatedRange.LesserThan = dateRange.To.Unix()
+		}
+	}
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Invoices.List(params)
+	page := &gomultistripe.InvoicePage{}
+	for iter.Next() {
+		page.Invoices = append(page.Invoices, invoiceFromStripe(iter.Invoice()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Invoices) > 0 {
+		page.NextCursor = page.Invoices[len(page.Invoices)-1].ID
+	}
+	return page, nil
+}
+
+func (h *HandlerV75) RetrieveInvoice(ctx context.Context, invoiceID string) (*gomultistripe.Invoice, error) {
+	params := &stripe.InvoiceParams{}
+	h.scope(ctx, &params.Params)
+	inv, err := h.client(ctx).Invoices.Get(invoiceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return h.completeInvoice(ctx, inv)
+}
+
+func (h *HandlerV75) PayInvoice(ctx context.Context, invoiceID string, paymentMethodID string) (*gomultistripe.Invoice, error) {
+	params := &stripe.InvoicePayParams{}
+	if paymentMethodID != "" {
+		params.PaymentMethod = stripe.String(paymentMethodID)
+	}
+	h.idempotent(ctx, &params.Params, "PayInvoice", map[string]string{"invoice": invoiceID})
+	inv, err := h.client(ctx).Invoices.Pay(invoiceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return h.completeInvoice(ctx, inv)
+}
+
+func (h *HandlerV75) VoidInvoice(ctx context.Context, invoiceID string) (*gomultistripe.Invoice, error) {
+	params := &stripe.InvoiceVoidInvoiceParams{}
+	h.idempotent(ctx, &params.Params, "VoidInvoice", map[string]string{"invoice": invoiceID})
+	inv, err := h.client(ctx).Invoices.VoidInvoice(invoiceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return h.completeInvoice(ctx, inv)
+}
+
+func (h *HandlerV75) FinalizeInvoice(ctx context.Context, invoiceID string) (*gomultistripe.Invoice, error) {
+	params := &stripe.InvoiceFinalizeInvoiceParams{}
+	h.idempotent(ctx, &params.Params, "FinalizeInvoice", map[string]string{"invoice": invoiceID})
+	inv, err := h.client(ctx).Invoices.FinalizeInvoice(invoiceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return h.completeInvoice(ctx, inv)
+}
+
+// completeInvoice normalizes an invoice returned by the API, retrieving the lines Stripe
+// left out of the response.
+func (h *HandlerV75) completeInvoice(ctx context.Context, inv *stripe.Invoice) (*gomultistripe.Invoice, error) {
+	out := invoiceFromStripe(inv)
+	if out.LinesHasMore {
+		more, err := h.remainingInvoiceLines(ctx, inv.ID, out.Lines)
+		if err != nil {
+			return nil, err
+		}
+		out.Lines = append(out.Lines, more...)
+		out.LinesHasMore = false
+	}
+	return out, nil
+}
+
+func invoiceFromStripe(inv *stripe.Invoice) *gomultistripe.Invoice {
+	out := &gomultistripe.Invoice{
+		ID:               inv.ID,
+		Number:           inv.Number,
+		CustomerName:     inv.CustomerName,
+		CustomerEmail:    inv.CustomerEmail,
+		Status:           gomultistripe.InvoiceStatus(inv.Status),
+		Currency:         string(inv.Currency),
+		Total:            inv.Total,
+		AmountDue:        inv.AmountDue,
+		AmountPaid:       inv.AmountPaid,
+		AmountRemaining:  inv.AmountRemaining,
+		CreatedAt:        time.Unix(inv.Created, 0),
+		DueDate:          inv.DueDate,
+		PeriodStart:      inv.PeriodStart,
+		PeriodEnd:        inv.PeriodEnd,
+		HostedInvoiceURL: inv.HostedInvoiceURL,
+		InvoicePDF:       inv.InvoicePDF,
+		Lines:            invoiceLines(inv.Lines),
+		LinesHasMore:     inv.Lines != nil && inv.Lines.HasMore,
+		Metadata:         inv.Metadata,
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if inv.Customer != nil {
+		out.CustomerID = inv.Customer.ID
+	}
+	if inv.Subscription != nil {
+		out.SubscriptionID = inv.Subscription.ID
+	}
+	if inv.StatusTransitions != nil {
+		out.PaidAt = inv.StatusTransitions.PaidAt
+	}
+	return out
+}
+
+// invoiceLines normalizes the lines included in an invoice. It returns nil when there are none.
+func invoiceLines(lines *stripe.InvoiceLineItemList) []gomultistripe.InvoiceLine {
+	if lines == nil {
+		return nil
+	}
+	var out []gomultistripe.InvoiceLine
+	for _, line := range lines.Data {
+		gmline := gomultistripe.InvoiceLine{
+			ID:          line.ID,
+			Amount:      line.Amount,
+			Currency:    string(line.Currency),
+			Description: line.Description,
+		}
+		if line.Subscription != nil {
+			gmline.SubscriptionID = line.Subscription.ID
+		}
+		out = append(out, gmline)
+	}
+	return out
+}
+
+// remainingInvoiceLines retrieves the lines of an invoice that follow those included in a
+// truncated payload or response.
+func (h *HandlerV75) remainingInvoiceLines(ctx context.Context, invoiceID string, included []gomultistripe.InvoiceLine) ([]gomultistripe.InvoiceLine, error) {
+	params := &stripe.InvoiceListLinesParams{Invoice: stripe.String(invoiceID)}
+	params.Limit = stripe.Int64(gomultistripe.MaxExportPageSize)
+	if n := len(included); n > 0 {
+		params.StartingAfter = stripe.String(included[n-1].ID)
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Invoices.ListLines(params)
+	var lines []*stripe.InvoiceLineItem
+	for iter.Next() {
+		lines = append(lines, iter.InvoiceLineItem())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return invoiceLines(&stripe.InvoiceLineItemList{Data: lines}), nil
+}
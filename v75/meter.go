@@ -0,0 +1,22 @@
+package v75
+
+import (
+	"context"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// CreateMeter is not available before stripe-go v76.
+func (h *HandlerV75) CreateMeter(ctx context.Context, params gomultistripe.MeterParams) (*gomultistripe.Meter, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "CreateMeter")
+}
+
+// ReportMeterEvent is not available before stripe-go v76.
+func (h *HandlerV75) ReportMeterEvent(ctx context.Context, evt gomultistripe.MeterEvent) error {
+	return gomultistripe.Unsupported(h.Version(), "ReportMeterEvent")
+}
+
+// ReportMeterEvents is not available before stripe-go v76.
+func (h *HandlerV75) ReportMeterEvents(ctx context.Context, events []gomultistripe.MeterEvent, opts ...gomultistripe.MeterBatchOption) error {
+	return gomultistripe.Unsupported(h.Version(), "ReportMeterEvents")
+}
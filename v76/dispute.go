@@ -0,0 +1,115 @@
+package v76
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v76"
+)
+
+func (h *HandlerV76) ListDisputes(ctx context.Context, query gomultistripe.DisputeQuery, opts *gomultistripe.ListOptions) (*gomultistripe.DisputePage, error) {
+	params := &stripe.DisputeListParams{}
+	if query.ChargeID != "" {
+		params.Charge = stripe.String(query.ChargeID)
+	}
+	if query.PaymentIntentID != "" {
+		params.PaymentIntent = stripe.String(query.PaymentIntentID)
+	}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Disputes.List(params)
+	page := &gomultistripe.DisputePage{}
+	for iter.Next() {
+		page.Disputes = append(page.Disputes, disputeFromStripe(iter.Dispute()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Disputes) > 0 {
+		page.NextCursor = page.Disputes[len(page.Disputes)-1].ID
+	}
+	return page, nil
+}
+
+func (h *HandlerV76) RetrieveDispute(ctx context.Context, disputeID string) (*gomultistripe.Dispute, error) {
+	params := &stripe.DisputeParams{}
+	h.scope(ctx, &params.Params)
+	d, err := h.client(ctx).Disputes.Get(disputeID, params)
+	if err != nil {
+		return nil, err
+	}
+	return disputeFromStripe(d), nil
+}
+
+func (h *HandlerV76) UpdateDispute(ctx context.Context, disputeID string, params gomultistripe.DisputeUpdate) (*gomultistripe.Dispute, error) {
+	stripeParams := &stripe.DisputeParams{}
+	if params.Evidence != nil {
+		for k, v := range params.Evidence.FormParams() {
+			stripeParams.AddExtra(k, v)
+		}
+	}
+	if params.Submit {
+		stripeParams.Submit = stripe.Bool(true)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.scope(ctx, &stripeParams.Params)
+	d, err := h.client(ctx).Disputes.Update(disputeID, stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return disputeFromStripe(d), nil
+}
+
+func (h *HandlerV76) CloseDispute(ctx context.Context, disputeID string) (*gomultistripe.Dispute, error) {
+	params := &stripe.DisputeParams{}
+	h.idempotent(ctx, &params.Params, "CloseDispute", map[string]string{"dispute": disputeID})
+	d, err := h.client(ctx).Disputes.Close(disputeID, params)
+	if err != nil {
+		return nil, err
+	}
+	return disputeFromStripe(d), nil
+}
+
+func disputeFromStripe(d *stripe.Dispute) *gomultistripe.Dispute {
+	out := &gomultistripe.Dispute{
+		ID:                 d.ID,
+		Amount:             d.Amount,
+		Currency:           string(d.Currency),
+		Reason:             string(d.Reason),
+		NetworkReasonCode:  d.NetworkReasonCode,
+		Status:             string(d.Status),
+		IsChargeRefundable: d.IsChargeRefundable,
+		Metadata:           d.Metadata,
+		CreatedAt:          time.Unix(d.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if d.Charge != nil {
+		out.ChargeID = d.Charge.ID
+	}
+	if d.PaymentIntent != nil {
+		out.PaymentIntentID = d.PaymentIntent.ID
+	}
+	if ed := d.EvidenceDetails; ed != nil {
+		if ed.DueBy > 0 {
+			out.EvidenceDueBy = time.Unix(ed.DueBy, 0)
+		}
+		out.HasEvidence = ed.HasEvidence
+		out.EvidencePastDue = ed.PastDue
+		out.EvidenceSubmissionCount = ed.SubmissionCount
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package v76
+
+import (
+	"errors"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v76"
+)
+
+func (h *HandlerV76) AsAPIError(err error) (*gomultistripe.APIError, bool) {
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return nil, false
+	}
+	out := &gomultistripe.APIError{
+		Type:        string(stripeErr.Type),
+		Code:        string(stripeErr.Code),
+		DeclineCode: string(stripeErr.DeclineCode),
+		Message:     stripeErr.Msg,
+		Param:       stripeErr.Param,
+		ChargeID:    stripeErr.ChargeID,
+		HTTPStatus:  stripeErr.HTTPStatusCode,
+		RequestID:   stripeErr.RequestID,
+	}
+	if stripeErr.PaymentIntent != nil {
+		out.PaymentIntentID = stripeErr.PaymentIntent.ID
+	}
+	return out, true
+}
@@ -0,0 +1,277 @@
+package v76
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// paymentIntentFromStripe normalizes a PaymentIntent. Card check results are only
+// available when latest_charge was expanded.
+func paymentIntentFromStripe(pi *stripe.PaymentIntent) *gomultistripe.PaymentIntent {
+	out := &gomultistripe.PaymentIntent{
+		ID:                 pi.ID,
+		Amount:             pi.Amount,
+		Currency:           string(pi.Currency),
+		Status:             string(pi.Status),
+		ClientSecret:       pi.ClientSecret,
+		CreatedAt:          time.Unix(pi.Created, 0),
+		Metadata:           pi.Metadata,
+		CaptureMethod:      string(pi.CaptureMethod),
+		CancellationReason: string(pi.CancellationReason),
+		AmountCapturable:   pi.AmountCapturable,
+		AmountReceived:     pi.AmountReceived,
+
+		ReceiptEmail: pi.ReceiptEmail,
+
+		StatementDescriptorSuffix: pi.StatementDescriptorSuffix,
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if pi.Customer != nil {
+		out.CustomerID = pi.Customer.ID
+		if len(pi.Customer.PreferredLocales) > 0 {
+			out.ReceiptLocale = pi.Customer.PreferredLocales[0]
+		}
+	}
+	if pi.PaymentMethod != nil {
+		out.PaymentMethod = pi.PaymentMethod.ID
+	}
+	out.ApplicationFeeAmount, out.TransferGroup = pi.ApplicationFeeAmount, pi.TransferGroup
+	if pi.AutomaticPaymentMethods != nil {
+		out.AutomaticPaymentMethods = &gomultistripe.AutomaticPaymentMethods{
+			Enabled:        pi.AutomaticPaymentMethods.Enabled,
+			AllowRedirects: string(pi.AutomaticPaymentMethods.AllowRedirects),
+		}
+	}
+	if pi.TransferData != nil && pi.TransferData.Destination != nil {
+		out.TransferDestination = pi.TransferData.Destination.ID
+	}
+	if pi.PaymentMethodOptions != nil && pi.PaymentMethodOptions.Card != nil && pi.PaymentMethodOptions.Card.Installments != nil {
+		installments := pi.PaymentMethodOptions.Card.Installments
+		out.InstallmentsEnabled = installments.Enabled
+		for _, plan := range installments.AvailablePlans {
+			out.AvailableInstallmentPlans = append(out.AvailableInstallmentPlans, installmentPlanFromStripe(plan))
+		}
+		if installments.Plan != nil {
+			plan := installmentPlanFromStripe(installments.Plan)
+			out.InstallmentPlan = &plan
+		}
+	}
+	if pi.NextAction != nil && pi.NextAction.RedirectToURL != nil {
+		out.RedirectURL = pi.NextAction.RedirectToURL.URL
+	}
+	if pi.NextAction != nil && pi.NextAction.DisplayBankTransferInstructions != nil {
+		out.FundingInstructions = fundingInstructionsFromStripe(pi.NextAction.DisplayBankTransferInstructions)
+	}
+	if pi.LatestCharge != nil {
+		out.LatestChargeID = pi.LatestCharge.ID
+		applyCardChecks(out, pi.LatestCharge)
+	}
+	return out
+}
+
+func installmentPlanFromStripe(plan *stripe.PaymentIntentPaymentMethodOptionsCardInstallmentsPlan) gomultistripe.InstallmentPlan {
+	return gomultistripe.InstallmentPlan{Type: string(plan.Type), Count: plan.Count, Interval: string(plan.Interval)}
+}
+
+// installmentsParams enables installments and, when plan is set, pays in that plan.
+func installmentsParams(plan *gomultistripe.InstallmentPlan) *stripe.PaymentIntentPaymentMethodOptionsCardInstallmentsParams {
+	params := &stripe.PaymentIntentPaymentMethodOptionsCardInstallmentsParams{Enabled: stripe.Bool(true)}
+	if plan == nil {
+		return params
+	}
+	params.Plan = &stripe.PaymentIntentPaymentMethodOptionsCardInstallmentsPlanParams{Type: stripe.String(plan.Type)}
+	if plan.Count > 0 {
+		params.Plan.Count = stripe.Int64(plan.Count)
+	}
+	if plan.Interval != "" {
+		params.Plan.Interval = stripe.String(plan.Interval)
+	}
+	return params
+}
+
+// installmentsError marks Stripe's rejection of the installment options, e.g. by an
+// account that does not offer installments, as gomultistripe.ErrInstallmentsUnavailable.
+func installmentsError(err error) error {
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && strings.HasPrefix(stripeErr.Param, "payment_method_options[card][installments]") {
+		return fmt.Errorf("%w: %w", gomultistripe.ErrInstallmentsUnavailable, err)
+	}
+	return err
+}
+
+// customerBalanceParams pays from the customer's cash balance, funded by bank transfer.
+func customerBalanceParams(bt *gomultistripe.BankTransfer) *stripe.PaymentIntentPaymentMethodOptionsCustomerBalanceParams {
+	transfer := &stripe.PaymentIntentPaymentMethodOptionsCustomerBalanceBankTransferParams{Type: stripe.String(bt.Type)}
+	if bt.Country != "" {
+		transfer.EUBankTransfer = &stripe.PaymentIntentPaymentMethodOptionsCustomerBalanceBankTransferEUBankTransferParams{Country: stripe.String(bt.Country)}
+	}
+	return &stripe.PaymentIntentPaymentMethodOptionsCustomerBalanceParams{
+		FundingType:  stripe.String("bank_transfer"),
+		BankTransfer: transfer,
+	}
+}
+
+// fundingInstructionsFromStripe normalizes the bank transfer instructions of a payment
+// intent's next action.
+func fundingInstructionsFromStripe(in *stripe.PaymentIntentNextActionDisplayBankTransferInstructions) *gomultistripe.FundingInstructions {
+	out := &gomultistripe.FundingInstructions{
+		Type:                  string(in.Type),
+		AmountRemaining:       in.AmountRemaining,
+		Currency:              string(in.Currency),
+		Reference:             in.Reference,
+		HostedInstructionsURL: in.HostedInstructionsURL,
+	}
+	for _, a := range in.FinancialAddresses {
+		addr := gomultistripe.FinancialAddress{Type: string(a.Type)}
+		switch {
+		case a.IBAN != nil:
+			addr.AccountHolderName, addr.IBAN, addr.BIC, addr.Country = a.IBAN.AccountHolderName, a.IBAN.IBAN, a.IBAN.BIC, a.IBAN.Country
+		case a.SortCode != nil:
+			addr.AccountHolderName, addr.AccountNumber, addr.SortCode = a.SortCode.AccountHolderName, a.SortCode.AccountNumber, a.SortCode.SortCode
+		case a.ABA != nil:
+			addr.BankName, addr.AccountNumber, addr.RoutingNumber = a.ABA.BankName, a.ABA.AccountNumber, a.ABA.RoutingNumber
+		case a.Swift != nil:
+			addr.BankName, addr.AccountNumber, addr.SwiftCode = a.Swift.BankName, a.Swift.AccountNumber, a.Swift.SwiftCode
+		case a.Spei != nil:
+			addr.BankName, addr.BankCode, addr.CLABE = a.Spei.BankName, a.Spei.BankCode, a.Spei.Clabe
+		case a.Zengin != nil:
+			addr.AccountHolderName, addr.BankName = a.Zengin.AccountHolderName, a.Zengin.BankName
+			addr.AccountNumber, addr.AccountType = a.Zengin.AccountNumber, a.Zengin.AccountType
+			addr.BankCode, addr.BranchCode, addr.BranchName = a.Zengin.BankCode, a.Zengin.BranchCode, a.Zengin.BranchName
+		}
+		out.Addresses = append(out.Addresses, addr)
+	}
+	return out
+}
+
+// paymentIntentMandateData converts the customer's acceptance of a mandate, or returns nil
+// when there is none.
+func paymentIntentMandateData(m *gomultistripe.MandateData) *stripe.PaymentIntentMandateDataParams {
+	if m == nil {
+		return nil
+	}
+	acceptance := &stripe.PaymentIntentMandateDataCustomerAcceptanceParams{}
+	if m.Online() {
+		acceptance.Type = stripe.String(string(stripe.MandateCustomerAcceptanceTypeOnline))
+		acceptance.Online = &stripe.PaymentIntentMandateDataCustomerAcceptanceOnlineParams{
+			IPAddress: stripe.String(m.IPAddress),
+			UserAgent: stripe.String(m.UserAgent),
+		}
+	} else {
+		acceptance.Type = stripe.String(string(stripe.MandateCustomerAcceptanceTypeOffline))
+		acceptance.Offline = &stripe.PaymentIntentMandateDataCustomerAcceptanceOfflineParams{}
+	}
+	return &stripe.PaymentIntentMandateDataParams{CustomerAcceptance: acceptance}
+}
+
+// applyCardChecks copies the authorization expiry and AVS/CVC results of a card
+// charge. Network transaction IDs are only exposed from stripe-go v81.
+func applyCardChecks(out *gomultistripe.PaymentIntent, ch *stripe.Charge) {
+	if ch.PaymentMethodDetails == nil || ch.PaymentMethodDetails.Card == nil {
+		return
+	}
+	card := ch.PaymentMethodDetails.Card
+	if card.CaptureBefore > 0 {
+		out.AuthorizationExpiresAt = time.Unix(card.CaptureBefore, 0)
+	}
+	if card.Checks != nil {
+		out.CVCCheck = string(card.Checks.CVCCheck)
+		out.AddressLine1Check = string(card.Checks.AddressLine1Check)
+		out.AddressPostalCodeCheck = string(card.Checks.AddressPostalCodeCheck)
+	}
+}
+
+func (h *HandlerV76) ListUncapturedPaymentIntents(ctx context.Context, customerID string) ([]*gomultistripe.PaymentIntent, error) {
+	params := &stripe.PaymentIntentListParams{
+		// Stripe cannot filter by status, so the window keeps the listing to intents that
+		// may still be capturable.
+		CreatedRange: &stripe.RangeQueryParams{
+			GreaterThanOrEqual: time.Now().Add(-gomultistripe.UncapturedAuthorizationWindow).Unix(),
+		},
+	}
+	if customerID != "" {
+		params.Customer = stripe.String(customerID)
+	}
+	params.AddExpand("data.latest_charge")
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).PaymentIntents.List(params)
+	var intents []*gomultistripe.PaymentIntent
+	for iter.Next() {
+		pi := iter.PaymentIntent()
+		if pi.Status != stripe.PaymentIntentStatusRequiresCapture {
+			continue
+		}
+		intents = append(intents, paymentIntentFromStripe(pi))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return intents, nil
+}
+
+func (h *HandlerV76) CapturePaymentIntent(ctx context.Context, paymentIntentID string, amount int64, final bool) (*gomultistripe.PaymentIntent, error) {
+	params := &stripe.PaymentIntentCaptureParams{
+		FinalCapture: stripe.Bool(final),
+	}
+	if amount > 0 {
+		params.AmountToCapture = stripe.Int64(amount)
+	}
+	params.AddExpand("latest_charge")
+	h.idempotent(ctx, &params.Params, "CapturePaymentIntent", map[string]string{"payment_intent": paymentIntentID})
+	pi, err := h.client(ctx).PaymentIntents.Capture(paymentIntentID, params)
+	if err != nil {
+		return nil, err
+	}
+	return paymentIntentFromStripe(pi), nil
+}
+
+func (h *HandlerV76) CancelPaymentIntent(ctx context.Context, paymentIntentID string, reason string) (*gomultistripe.PaymentIntent, error) {
+	params := &stripe.PaymentIntentCancelParams{}
+	if reason != "" {
+		params.CancellationReason = stripe.String(reason)
+	}
+	params.AddExpand("latest_charge")
+	h.idempotent(ctx, &params.Params, "CancelPaymentIntent", map[string]string{"payment_intent": paymentIntentID})
+	pi, err := h.client(ctx).PaymentIntents.Cancel(paymentIntentID, params)
+	if err != nil {
+		return nil, err
+	}
+	return paymentIntentFromStripe(pi), nil
+}
+
+func (h *HandlerV76) ConfirmPaymentIntent(ctx context.Context, paymentIntentID string, paymentMethodID string, opts ...gomultistripe.ConfirmOption) (*gomultistripe.PaymentIntent, error) {
+	o := gomultistripe.NewConfirmOptions(opts...)
+	params := &stripe.PaymentIntentConfirmParams{
+		MandateData: paymentIntentMandateData(o.MandateData),
+	}
+	if paymentMethodID != "" {
+		params.PaymentMethod = stripe.String(paymentMethodID)
+	}
+	if o.ReturnURL != "" {
+		params.ReturnURL = stripe.String(o.ReturnURL)
+	}
+	if o.OffSession {
+		params.OffSession = stripe.Bool(true)
+	}
+	if o.InstallmentPlan != nil {
+		params.PaymentMethodOptions = &stripe.PaymentIntentPaymentMethodOptionsParams{
+			Card: &stripe.PaymentIntentPaymentMethodOptionsCardParams{Installments: installmentsParams(o.InstallmentPlan)},
+		}
+	}
+	params.AddExpand("customer")
+	params.AddExpand("latest_charge")
+	h.idempotent(ctx, &params.Params, "ConfirmPaymentIntent", map[string]string{"payment_intent": paymentIntentID, "payment_method": paymentMethodID})
+	pi, err := h.client(ctx).PaymentIntents.Confirm(paymentIntentID, params)
+	if err != nil {
+		return nil, installmentsError(err)
+	}
+	return paymentIntentFromStripe(pi), nil
+}
@@ -0,0 +1,113 @@
+package v78
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func (h *HandlerV78) CreateAccount(ctx context.Context, params gomultistripe.AccountParams) (*gomultistripe.Account, error) {
+	stripeParams := &stripe.AccountParams{}
+	if params.Type != "" {
+		stripeParams.Type = stripe.String(string(params.Type))
+	}
+	if params.Country != "" {
+		stripeParams.Country = stripe.String(params.Country)
+	}
+	if params.Email != "" {
+		stripeParams.Email = stripe.String(params.Email)
+	}
+	if params.BusinessType != "" {
+		stripeParams.BusinessType = stripe.String(params.BusinessType)
+	}
+	// Capabilities are set by name, since each SDK version models a different set of them.
+	for _, c := range params.Capabilities {
+		stripeParams.AddExtra("capabilities["+c+"][requested]", "true")
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreateAccount", map[string]string{"email": params.Email})
+	acct, err := h.client(ctx).Accounts.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return accountFromStripe(acct), nil
+}
+
+func (h *HandlerV78) RetrieveAccount(ctx context.Context, accountID string) (*gomultistripe.Account, error) {
+	params := &stripe.AccountParams{}
+	h.scope(ctx, &params.Params)
+	accounts := h.client(ctx).Accounts
+	if accountID != "" {
+		acct, err := accounts.GetByID(accountID, params)
+		if err != nil {
+			return nil, err
+		}
+		return accountFromStripe(acct), nil
+	}
+	// Accounts.Get takes no parameters, so it could not be bound to ctx.
+	acct := &stripe.Account{}
+	if err := accounts.B.Call(http.MethodGet, "/v1/account", accounts.Key, params, acct); err != nil {
+		return nil, err
+	}
+	return accountFromStripe(acct), nil
+}
+
+func (h *HandlerV78) CreateAccountLink(ctx context.Context, params gomultistripe.AccountLinkParams) (*gomultistripe.AccountLink, error) {
+	linkType := params.Type
+	if linkType == "" {
+		linkType = gomultistripe.AccountLinkOnboarding
+	}
+	stripeParams := &stripe.AccountLinkParams{
+		Account:    stripe.String(params.AccountID),
+		Type:       stripe.String(string(linkType)),
+		RefreshURL: stripe.String(params.RefreshURL),
+		ReturnURL:  stripe.String(params.ReturnURL),
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreateAccountLink", map[string]string{"account": params.AccountID})
+	link, err := h.client(ctx).AccountLinks.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return &gomultistripe.AccountLink{URL: link.URL, ExpiresAt: time.Unix(link.ExpiresAt, 0)}, nil
+}
+
+func accountFromStripe(acct *stripe.Account) *gomultistripe.Account {
+	out := &gomultistripe.Account{
+		ID:               acct.ID,
+		Type:             gomultistripe.AccountType(acct.Type),
+		Country:          acct.Country,
+		Email:            acct.Email,
+		BusinessType:     string(acct.BusinessType),
+		DefaultCurrency:  string(acct.DefaultCurrency),
+		ChargesEnabled:   acct.ChargesEnabled,
+		PayoutsEnabled:   acct.PayoutsEnabled,
+		DetailsSubmitted: acct.DetailsSubmitted,
+		Capabilities:     make(map[string]string),
+		Metadata:         acct.Metadata,
+		CreatedAt:        time.Unix(acct.Created, 0),
+	}
+	if acct.Capabilities != nil {
+		// Read every capability the SDK models, whatever their names in this version.
+		var statuses map[string]string
+		if b, err := json.Marshal(acct.Capabilities); err == nil && json.Unmarshal(b, &statuses) == nil {
+			for name, status := range statuses {
+				if status != "" {
+					out.Capabilities[name] = status
+				}
+			}
+		}
+	}
+	if acct.Requirements != nil {
+		out.RequirementsDue = acct.Requirements.CurrentlyDue
+		out.RequirementsPastDue = acct.Requirements.PastDue
+		out.RequirementsDisabledReason = string(acct.Requirements.DisabledReason)
+	}
+	return out
+}
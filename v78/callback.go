@@ -1,8 +1,10 @@
 package v78
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
@@ -12,12 +14,62 @@ import (
 )
 
 func (h *HandlerV78) HandleWebhook(payload []byte, sigHeader string) (*gomultistripe.CallbackEvent, error) {
-	secret := os.Getenv("STRIPE_WEBHOOK_SECRET")
-	event, err := webhook.ConstructEvent(payload, sigHeader, secret)
+	secret := h.webhookSecret
+	if secret == "" {
+		secret = os.Getenv("STRIPE_WEBHOOK_SECRET")
+	}
+	return h.handleEvent("HandleWebhook", payload, func() error {
+		return webhook.ValidatePayload(payload, sigHeader, secret)
+	})
+}
+
+// HandleEvent normalizes an event delivered by a transport that authenticates it, so it
+// has no signature to verify.
+func (h *HandlerV78) HandleEvent(payload []byte) (*gomultistripe.CallbackEvent, error) {
+	return h.handleEvent("HandleEvent", payload, nil)
+}
+
+// handleEvent normalizes the event in payload. verify, when set, checks the payload
+// before it is decoded.
+func (h *HandlerV78) handleEvent(operation string, payload []byte, verify func() error) (*gomultistripe.CallbackEvent, error) {
+	// Log attributes cost allocations on every event, so they are only attached when
+	// records can be written at all.
+	log := gomultistripe.Logger()
+	logging := log.Enabled(context.Background(), slog.LevelWarn)
+	if logging {
+		log = log.With(
+			gomultistripe.LogKeyVersion, h.Version(),
+			gomultistripe.LogKeyOperation, operation,
+		)
+	}
+	event, err := h.constructEvent(payload, verify)
+	if err != nil {
+		log.Warn("rejected event", "error", err)
+		return nil, err
+	}
+	if logging {
+		log = log.With(
+			gomultistripe.LogKeyEventID, event.ID,
+			gomultistripe.LogKeyEventType, string(event.Type),
+			gomultistripe.LogKeyLivemode, event.Livemode,
+		)
+		if event.Request != nil && event.Request.ID != "" {
+			log = log.With(gomultistripe.LogKeyRequestID, event.Request.ID)
+		}
+	}
+	log.Debug("received event")
+	h.validateSchema(event)
+
+	evt, err := h.callbackEvent(event, log)
 	if err != nil {
 		return nil, err
 	}
+	evt.AccountID, evt.APIVersion, evt.Payload = event.Account, event.APIVersion, payload
+	return evt, nil
+}
 
+// callbackEvent normalizes the object of a decoded event by its type.
+func (h *HandlerV78) callbackEvent(event *webhookEvent, log *slog.Logger) (*gomultistripe.CallbackEvent, error) {
 	switch event.Type {
 	case stripe.EventTypeSetupIntentSucceeded:
 		var intent stripe.SetupIntent
@@ -36,7 +88,9 @@ func (h *HandlerV78) HandleWebhook(payload []byte, sigHeader string) (*gomultist
 		}
 		cbEvent := gomultistripe.CallbackEvent{
 			Type:            gomultistripe.EventSetupIntentSucceeded,
-			Metadata:        make(map[string]string),
+			EventID:         event.ID,
+			EventCreatedAt:  time.Unix(event.Created, 0),
+			Metadata:        metadata(intent.Metadata),
 			SetupIntentID:   intent.ID,
 			PaymentMethodID: pmID,
 			CardBrand:       brand,
@@ -44,10 +98,7 @@ func (h *HandlerV78) HandleWebhook(payload []byte, sigHeader string) (*gomultist
 			CardExpYear:     expYear,
 			CardLast4:       last4,
 		}
-		for k, v := range intent.Metadata {
-			cbEvent.Metadata[k] = v
-		}
-		return &cbEvent, nil
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
 	case stripe.EventTypePaymentIntentCanceled,
 		stripe.EventTypePaymentIntentPaymentFailed,
 		stripe.EventTypePaymentIntentSucceeded,
@@ -64,35 +115,24 @@ func (h *HandlerV78) HandleWebhook(payload []byte, sigHeader string) (*gomultist
 		}
 		evt := gomultistripe.CallbackEvent{
 			Type:            gomultistripe.CallbackEventType(event.Type),
-			Metadata:        make(map[string]string),
+			EventID:         event.ID,
+			EventCreatedAt:  time.Unix(event.Created, 0),
+			Metadata:        metadata(intent.Metadata),
 			PreAllocated:    preAllocated,
 			ValidateOnly:    validateOnly,
 			PaymentIntentID: intent.ID,
 			Amount:          intent.Amount,
+			Currency:        string(intent.Currency),
 			Status:          string(intent.Status),
 			PaymentMethodID: pmID,
 		}
-		for k, v := range intent.Metadata {
-			evt.Metadata[k] = v
-		}
 		if event.Type == stripe.EventType(gomultistripe.EventPaymentIntentAmountCapturableUpdated) {
 			evt.AmountCapturable = intent.AmountCapturable
 		}
 		if event.Type == stripe.EventType(gomultistripe.EventPaymentIntentPaymentFailed) {
-			if intent.LastPaymentError != nil {
-				evt.LastPaymentErrorCode = string(intent.LastPaymentError.Code)
-				evt.LastPaymentErrorMsg = ""
-				if intent.LastPaymentError.Err != nil {
-					evt.LastPaymentErrorMsg = intent.LastPaymentError.Err.Error()
-				}
-				evt.LastPaymentErrorDeclineCode = string(intent.LastPaymentError.DeclineCode)
-				if intent.LastPaymentError.PaymentMethod != nil {
-					evt.LastPaymentErrorPaymentMethodID = intent.LastPaymentError.PaymentMethod.ID
-				}
-				evt.LastPaymentErrorChargeID = intent.LastPaymentError.ChargeID
-			}
+			setLastPaymentError(&evt, intent.LastPaymentError)
 		}
-		return &evt, nil
+		return gomultistripe.NewCallbackEvent(evt), nil
 	case stripe.EventTypeCustomerSubscriptionCreated,
 		stripe.EventTypeCustomerSubscriptionUpdated,
 		stripe.EventTypeCustomerSubscriptionDeleted,
@@ -105,81 +145,360 @@ func (h *HandlerV78) HandleWebhook(payload []byte, sigHeader string) (*gomultist
 		}
 		cbEvent := gomultistripe.CallbackEvent{
 			Type:              gomultistripe.CallbackEventType(event.Type),
-			Metadata:          make(map[string]string),
+			EventID:           event.ID,
+			EventCreatedAt:    time.Unix(event.Created, 0),
+			Metadata:          metadata(sub.Metadata),
 			SubscriptionID:    sub.ID,
 			CustomerID:        sub.Customer.ID,
 			Status:            string(sub.Status),
-			CurrentPeriodEnd:  sub.CancelAt,
+			CurrentPeriodEnd:  currentPeriodEnd(&sub),
+			CancelAt:          sub.CancelAt,
 			CancelAtPeriodEnd: sub.CancelAtPeriodEnd,
 			CanceledAt:        sub.CanceledAt,
 			CreatedAt:         time.Unix(sub.Created, 0),
 		}
-		for k, v := range sub.Metadata {
-			cbEvent.Metadata[k] = v
+		cbEvent.TrialEnd = sub.TrialEnd
+		cbEvent.Items = subscriptionItems(sub.Items)
+		if len(cbEvent.Items) > 0 {
+			cbEvent.PriceID = cbEvent.Items[0].PriceID
 		}
-		return &cbEvent, nil
+		if string(event.Type) == string(gomultistripe.EventCustomerSubscriptionTrialWillEnd) {
+			cbEvent.DaysRemaining = gomultistripe.DaysUntil(time.Unix(event.Created, 0), time.Unix(sub.TrialEnd, 0))
+		}
+		if string(event.Type) == string(gomultistripe.EventCustomerSubscriptionUpdated) {
+			previous, err := previousSubscriptionItems(event.Data.PreviousAttributes)
+			if err != nil {
+				return nil, err
+			}
+			cbEvent.PreviousItems = previous
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
 	case stripe.EventTypeInvoicePaymentSucceeded,
 		stripe.EventTypeInvoicePaymentFailed,
 		stripe.EventTypeInvoiceCreated,
 		stripe.EventTypeInvoiceUpcoming:
+		// The lines are streamed out of the payload rather than unmarshaled with the
+		// invoice, as invoices with thousands of lines make multi-megabyte events.
+		object, lines, linesHaveMore, err := gomultistripe.SplitInvoiceLines(event.Data.Raw)
+		if err != nil {
+			return nil, err
+		}
 		var inv stripe.Invoice
-		if err := json.Unmarshal(event.Data.Raw, &inv); err != nil {
+		if err := json.Unmarshal(object, &inv); err != nil {
 			return nil, err
 		}
 
 		cbEvent := gomultistripe.CallbackEvent{
-			Type:       gomultistripe.CallbackEventType(event.Type),
-			Metadata:   make(map[string]string),
-			InvoiceID:  inv.ID,
-			CustomerID: inv.Customer.ID,
-			Amount:     inv.AmountDue,
-			Status:     string(inv.Status),
-			CreatedAt:  time.Unix(inv.Created, 0),
-		}
-		for k, v := range inv.Metadata {
-			cbEvent.Metadata[k] = v
-		}
-		if inv.Lines != nil {
-			for _, line := range inv.Lines.Data {
-				gmline := gomultistripe.InvoiceLine{
-					ID:          line.ID,
-					Amount:      line.Amount,
-					Currency:    string(line.Currency),
-					Description: line.Description,
-				}
-				if line.Subscription != nil {
-					gmline.SubscriptionID = line.Subscription.ID
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       metadata(inv.Metadata),
+			InvoiceID:      inv.ID,
+			Amount:         inv.AmountDue,
+			Currency:       string(inv.Currency),
+			Status:         string(inv.Status),
+			CreatedAt:      time.Unix(inv.Created, 0),
+		}
+		if inv.Customer != nil {
+			cbEvent.CustomerID = inv.Customer.ID
+		}
+		if inv.Subscription != nil {
+			cbEvent.SubscriptionID = inv.Subscription.ID
+		}
+		cbEvent.AttemptCount = inv.AttemptCount
+		cbEvent.NextPaymentAttempt = inv.NextPaymentAttempt
+		if string(event.Type) == string(gomultistripe.EventInvoicePaymentFailed) {
+			// The decline is looked up on a best-effort basis: the event is still delivered
+			// without it, as the rest is enough for most dunning decisions.
+			paymentErr, err := h.invoicePaymentError(&event.Event, &inv)
+			if err != nil {
+				log.Warn("could not look up invoice payment error", "invoice", inv.ID, "error", err)
+			}
+			setLastPaymentError(&cbEvent, paymentErr)
+		}
+		cbEvent.InvoiceLines = lines
+		if linesHaveMore {
+			if !gomultistripe.FetchAllInvoiceLines() {
+				cbEvent.InvoiceLinesHasMore = true
+			} else {
+				more, err := h.remainingInvoiceLines(gomultistripe.ContextWithAccount(context.Background(), event.Account), inv.ID, lines)
+				if err != nil {
+					log.Warn("could not fetch remaining invoice lines", "invoice", inv.ID, "error", err)
+					return nil, err
 				}
-				cbEvent.InvoiceLines = append(cbEvent.InvoiceLines, gmline)
+				cbEvent.InvoiceLines = append(cbEvent.InvoiceLines, more...)
 			}
 		}
-		return &cbEvent, nil
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
 	case stripe.EventTypeRefundCreated,
 		stripe.EventTypeRefundUpdated,
-		stripe.EventType(gomultistripe.EventRefundFailed),
-		stripe.EventTypeChargeRefunded:
+		stripe.EventType(gomultistripe.EventRefundFailed):
 		var refund stripe.Refund
 		if err := json.Unmarshal(event.Data.Raw, &refund); err != nil {
 			return nil, err
 		}
 
 		cbEvent := gomultistripe.CallbackEvent{
-			Type:         gomultistripe.CallbackEventType(event.Type),
-			Metadata:     make(map[string]string),
-			RefundID:     refund.ID,
-			RefundAmount: refund.Amount,
-			RefundReason: string(refund.Reason),
-			RefundStatus: string(refund.Status),
-			ChargeID:     refund.Charge.ID,
-			Currency:     string(refund.Currency),
-			CreatedAt:    time.Unix(refund.Created, 0),
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       metadata(refund.Metadata),
+			RefundID:       refund.ID,
+			RefundAmount:   refund.Amount,
+			RefundReason:   string(refund.Reason),
+			RefundStatus:   string(refund.Status),
+			Currency:       string(refund.Currency),
+			CreatedAt:      time.Unix(refund.Created, 0),
 		}
-
-		for k, v := range refund.Metadata {
-			cbEvent.Metadata[k] = v
+		if refund.Charge != nil {
+			cbEvent.ChargeID = refund.Charge.ID
+		}
+		if refund.PaymentIntent != nil {
+			cbEvent.PaymentIntentID = refund.PaymentIntent.ID
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeChargeRefunded:
+		// The object is the refunded charge, not a refund.
+		var ch stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &ch); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       metadata(ch.Metadata),
+			RefundAmount:   ch.AmountRefunded,
+			ChargeID:       ch.ID,
+			Currency:       string(ch.Currency),
+			CreatedAt:      time.Unix(ch.Created, 0),
+		}
+		if ch.PaymentIntent != nil {
+			cbEvent.PaymentIntentID = ch.PaymentIntent.ID
+		}
+		// Charges only include their refunds, newest first, before the 2022-11-15 API
+		// version.
+		if ch.Refunds != nil && len(ch.Refunds.Data) > 0 {
+			latest := ch.Refunds.Data[0]
+			cbEvent.RefundID = latest.ID
+			cbEvent.RefundReason = string(latest.Reason)
+			cbEvent.RefundStatus = string(latest.Status)
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeCashBalanceFundsAvailable:
+		var balance stripe.CashBalance
+		if err := json.Unmarshal(event.Data.Raw, &balance); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       metadata(nil),
+			CustomerID:     balance.Customer,
+			CashBalance:    balance.Available,
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeCustomerCashBalanceTransactionCreated:
+		var txn stripe.CustomerCashBalanceTransaction
+		if err := json.Unmarshal(event.Data.Raw, &txn); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:                       gomultistripe.CallbackEventType(event.Type),
+			EventID:                    event.ID,
+			EventCreatedAt:             time.Unix(event.Created, 0),
+			Metadata:                   metadata(nil),
+			CashBalanceTransactionID:   txn.ID,
+			CashBalanceTransactionType: string(txn.Type),
+			CashBalanceNetAmount:       txn.NetAmount,
+			CashBalanceEndingBalance:   txn.EndingBalance,
+			Currency:                   string(txn.Currency),
+			CreatedAt:                  time.Unix(txn.Created, 0),
+		}
+		if txn.Customer != nil {
+			cbEvent.CustomerID = txn.Customer.ID
+		}
+		if txn.AppliedToPayment != nil && txn.AppliedToPayment.PaymentIntent != nil {
+			cbEvent.PaymentIntentID = txn.AppliedToPayment.PaymentIntent.ID
 		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypePaymentMethodAttached,
+		stripe.EventTypePaymentMethodDetached,
+		stripe.EventTypePaymentMethodAutomaticallyUpdated:
+		var pm stripe.PaymentMethod
+		if err := json.Unmarshal(event.Data.Raw, &pm); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:              gomultistripe.CallbackEventType(event.Type),
+			EventID:           event.ID,
+			EventCreatedAt:    time.Unix(event.Created, 0),
+			Metadata:          metadata(pm.Metadata),
+			PaymentMethodID:   pm.ID,
+			PaymentMethodType: string(pm.Type),
+			CreatedAt:         time.Unix(pm.Created, 0),
+		}
+		if pm.Customer != nil {
+			cbEvent.CustomerID = pm.Customer.ID
+		} else if string(event.Type) == string(gomultistripe.EventPaymentMethodDetached) {
+			// A detached payment method no longer references the customer; the event's
+			// previous attributes do.
+			customerID, err := previousCustomer(event.Data.PreviousAttributes)
+			if err != nil {
+				return nil, err
+			}
+			cbEvent.CustomerID = customerID
+		}
+		if pm.Card != nil {
+			cbEvent.CardBrand = string(pm.Card.Brand)
+			cbEvent.CardExpMonth = uint(pm.Card.ExpMonth)
+			cbEvent.CardExpYear = uint(pm.Card.ExpYear)
+			cbEvent.CardLast4 = pm.Card.Last4
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeChargeSucceeded,
+		stripe.EventTypeChargeFailed:
+		var ch stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &ch); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:            gomultistripe.CallbackEventType(event.Type),
+			EventID:         event.ID,
+			EventCreatedAt:  time.Unix(event.Created, 0),
+			Metadata:        metadata(ch.Metadata),
+			ChargeID:        ch.ID,
+			Amount:          ch.Amount,
+			Currency:        string(ch.Currency),
+			Status:          string(ch.Status),
+			PaymentMethodID: ch.PaymentMethod,
+			CreatedAt:       time.Unix(ch.Created, 0),
+		}
+		if ch.Customer != nil {
+			cbEvent.CustomerID = ch.Customer.ID
+		}
+		if ch.PaymentIntent != nil {
+			cbEvent.PaymentIntentID = ch.PaymentIntent.ID
+		}
+		if ch.PaymentMethodDetails != nil && ch.PaymentMethodDetails.Card != nil {
+			card := ch.PaymentMethodDetails.Card
+			cbEvent.CardBrand = string(card.Brand)
+			cbEvent.CardExpMonth = uint(card.ExpMonth)
+			cbEvent.CardExpYear = uint(card.ExpYear)
+			cbEvent.CardLast4 = card.Last4
+		}
+		if ch.FailureCode != "" {
+			cbEvent.LastPaymentErrorCode = ch.FailureCode
+			cbEvent.LastPaymentErrorMsg = ch.FailureMessage
+			cbEvent.LastPaymentErrorPaymentMethodID = ch.PaymentMethod
+			cbEvent.LastPaymentErrorChargeID = ch.ID
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeAccountUpdated:
+		var acct stripe.Account
+		if err := json.Unmarshal(event.Data.Raw, &acct); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       metadata(acct.Metadata),
+			Account:        accountFromStripe(&acct),
+			CreatedAt:      time.Unix(acct.Created, 0),
+		}
+		if err := cbEvent.SetRequirementChanges(event.Data.PreviousAttributes); err != nil {
+			return nil, err
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeChargeDisputeCreated,
+		stripe.EventTypeChargeDisputeUpdated,
+		stripe.EventTypeChargeDisputeClosed:
+		var d stripe.Dispute
+		if err := json.Unmarshal(event.Data.Raw, &d); err != nil {
+			return nil, err
+		}
+		dispute := disputeFromStripe(&d)
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:            gomultistripe.CallbackEventType(event.Type),
+			EventID:         event.ID,
+			EventCreatedAt:  time.Unix(event.Created, 0),
+			Metadata:        dispute.Metadata,
+			Dispute:         dispute,
+			ChargeID:        dispute.ChargeID,
+			PaymentIntentID: dispute.PaymentIntentID,
+			CreatedAt:       dispute.CreatedAt,
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeTopupSucceeded,
+		stripe.EventTypeTopupFailed:
+		var t stripe.Topup
+		if err := json.Unmarshal(event.Data.Raw, &t); err != nil {
+			return nil, err
+		}
+		topup := topupFromStripe(&t)
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:           gomultistripe.CallbackEventType(event.Type),
+			EventID:        event.ID,
+			EventCreatedAt: time.Unix(event.Created, 0),
+			Metadata:       topup.Metadata,
+			Topup:          topup,
+			CreatedAt:      topup.CreatedAt,
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	case stripe.EventTypeCustomerSourceExpiring:
+		// The object is the expiring card, sent early in the month it expires.
+		var card stripe.Card
+		if err := json.Unmarshal(event.Data.Raw, &card); err != nil {
+			return nil, err
+		}
+		cbEvent := gomultistripe.CallbackEvent{
+			Type:              gomultistripe.CallbackEventType(event.Type),
+			EventID:           event.ID,
+			EventCreatedAt:    time.Unix(event.Created, 0),
+			Metadata:          metadata(card.Metadata),
+			PaymentMethodID:   card.ID,
+			PaymentMethodType: "card",
+			CardBrand:         string(card.Brand),
+			CardExpMonth:      uint(card.ExpMonth),
+			CardExpYear:       uint(card.ExpYear),
+			CardLast4:         card.Last4,
+		}
+		if card.Customer != nil {
+			cbEvent.CustomerID = card.Customer.ID
+		}
+		return gomultistripe.NewCallbackEvent(cbEvent), nil
+	}
+	return nil, fmt.Errorf("%w: %s", gomultistripe.ErrUnknownEventType, event.Type)
+}
 
-		return &cbEvent, nil
+// previousCustomer returns the customer in the previous attributes of an event, or ""
+// when they have none.
+func previousCustomer(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", nil
+	}
+	var previous struct {
+		Customer string `json:"customer"`
+	}
+	if err := json.Unmarshal(raw, &previous); err != nil {
+		return "", err
+	}
+	return previous.Customer, nil
+}
+
+// setLastPaymentError copies the last payment error of a payment intent into the Payment
+// error fields of evt. A nil error leaves them empty.
+func setLastPaymentError(evt *gomultistripe.CallbackEvent, e *stripe.Error) {
+	if e == nil {
+		return
+	}
+	evt.LastPaymentErrorCode = string(e.Code)
+	evt.LastPaymentErrorMsg = e.Msg
+	evt.LastPaymentErrorDeclineCode = string(e.DeclineCode)
+	if e.PaymentMethod != nil {
+		evt.LastPaymentErrorPaymentMethodID = e.PaymentMethod.ID
 	}
-	return nil, fmt.Errorf("unknown event type: %s", event.Type)
+	evt.LastPaymentErrorChargeID = e.ChargeID
 }
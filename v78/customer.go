@@ -0,0 +1,67 @@
+package v78
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func customerFromStripe(cust *stripe.Customer) *gomultistripe.Customer {
+	out := &gomultistripe.Customer{
+		ID:               cust.ID,
+		Name:             cust.Name,
+		Email:            cust.Email,
+		Phone:            cust.Phone,
+		Metadata:         cust.Metadata,
+		CreatedAt:        time.Unix(cust.Created, 0),
+		PreferredLocales: cust.PreferredLocales,
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if cust.Address != nil {
+		out.Postcode = cust.Address.PostalCode
+	}
+	return out
+}
+
+func (h *HandlerV78) FindCustomerByEmail(ctx context.Context, email string) (*gomultistripe.Customer, error) {
+	params := &stripe.CustomerListParams{Email: stripe.String(email)}
+	params.Single = true
+	params.Limit = stripe.Int64(1)
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Customers.List(params)
+	if iter.Next() {
+		return customerFromStripe(iter.Customer()), nil
+	}
+	return nil, iter.Err()
+}
+
+func (h *HandlerV78) ListCustomers(ctx context.Context, opts *gomultistripe.ListOptions) (*gomultistripe.CustomerPage, error) {
+	params := &stripe.CustomerListParams{}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Customers.List(params)
+	page := &gomultistripe.CustomerPage{}
+	for iter.Next() {
+		page.Customers = append(page.Customers, customerFromStripe(iter.Customer()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Customers) > 0 {
+		page.NextCursor = page.Customers[len(page.Customers)-1].ID
+	}
+	return page, nil
+}
@@ -0,0 +1,56 @@
+package v78
+
+import (
+	"encoding/json"
+	"fmt"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+// webhookEvent is a stripe.Event whose data is kept as raw JSON. stripe-go decodes
+// data.object a second time into a generic map, which HandleWebhook never reads: it
+// decodes the object once, into its typed struct. previous_attributes is likewise only
+// decoded by the events that use it.
+type webhookEvent struct {
+	stripe.Event
+	Data struct {
+		Raw                json.RawMessage `json:"object"`
+		PreviousAttributes json.RawMessage `json:"previous_attributes"`
+	} `json:"data"`
+}
+
+// constructEvent is webhook.ConstructEvent decoding into a webhookEvent. It runs verify,
+// which checks the signature of webhooks, and rejects events of an API version the SDK
+// cannot decode, like the SDK does.
+func (h *HandlerV78) constructEvent(payload []byte, verify func() error) (*webhookEvent, error) {
+	if verify != nil {
+		if err := verify(); err != nil {
+			return nil, fmt.Errorf("%w: %w", gomultistripe.ErrInvalidWebhook, err)
+		}
+	}
+	event := &webhookEvent{}
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse webhook body json: %w", gomultistripe.ErrInvalidWebhook, err)
+	}
+	if !compatibleAPIVersion(event.APIVersion) {
+		return nil, fmt.Errorf("received event with API version %s, but handler %s expects API version %s",
+			event.APIVersion, h.Version(), stripe.APIVersion)
+	}
+	return event, nil
+}
+
+// compatibleAPIVersion reports whether events of apiVersion decode with this SDK: only
+// those of the exact version it is pinned to.
+func compatibleAPIVersion(apiVersion string) bool {
+	return apiVersion == stripe.APIVersion
+}
+
+// metadata returns m, which was decoded for this event alone and is handed over to the
+// CallbackEvent without copying, or an empty map.
+func metadata(m map[string]string) map[string]string {
+	if m == nil {
+		return make(map[string]string)
+	}
+	return m
+}
@@ -0,0 +1,37 @@
+package v78
+
+import (
+	"context"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+)
+
+// CreateEventDestination is not available before stripe-go v80.
+func (h *HandlerV78) CreateEventDestination(ctx context.Context, params gomultistripe.EventDestinationParams) (*gomultistripe.EventDestination, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "CreateEventDestination")
+}
+
+// GetEventDestination is not available before stripe-go v80.
+func (h *HandlerV78) GetEventDestination(ctx context.Context, destinationID string) (*gomultistripe.EventDestination, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "GetEventDestination")
+}
+
+// ListEventDestinations is not available before stripe-go v80.
+func (h *HandlerV78) ListEventDestinations(ctx context.Context) ([]*gomultistripe.EventDestination, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "ListEventDestinations")
+}
+
+// UpdateEventDestination is not available before stripe-go v80.
+func (h *HandlerV78) UpdateEventDestination(ctx context.Context, destinationID string, update gomultistripe.EventDestinationUpdate) (*gomultistripe.EventDestination, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "UpdateEventDestination")
+}
+
+// SetEventDestinationEnabled is not available before stripe-go v80.
+func (h *HandlerV78) SetEventDestinationEnabled(ctx context.Context, destinationID string, enabled bool) (*gomultistripe.EventDestination, error) {
+	return nil, gomultistripe.Unsupported(h.Version(), "SetEventDestinationEnabled")
+}
+
+// DeleteEventDestination is not available before stripe-go v80.
+func (h *HandlerV78) DeleteEventDestination(ctx context.Context, destinationID string) error {
+	return gomultistripe.Unsupported(h.Version(), "DeleteEventDestination")
+}
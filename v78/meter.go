@@ -0,0 +1,107 @@
+package v78
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func (h *HandlerV78) CreateMeter(ctx context.Context, params gomultistripe.MeterParams) (*gomultistripe.Meter, error) {
+	aggregation := params.Aggregation
+	if aggregation == "" {
+		aggregation = gomultistripe.MeterSum
+	}
+	customerKey := params.CustomerKey
+	if customerKey == "" {
+		customerKey = gomultistripe.DefaultMeterCustomerKey
+	}
+	valueKey := params.ValueKey
+	if valueKey == "" {
+		valueKey = gomultistripe.DefaultMeterValueKey
+	}
+	stripeParams := &stripe.BillingMeterParams{
+		DisplayName: stripe.String(params.DisplayName),
+		EventName:   stripe.String(params.EventName),
+		DefaultAggregation: &stripe.BillingMeterDefaultAggregationParams{
+			Formula: stripe.String(string(aggregation)),
+		},
+		CustomerMapping: &stripe.BillingMeterCustomerMappingParams{
+			Type:            stripe.String("by_id"),
+			EventPayloadKey: stripe.String(customerKey),
+		},
+	}
+	if aggregation != gomultistripe.MeterCount {
+		stripeParams.ValueSettings = &stripe.BillingMeterValueSettingsParams{
+			EventPayloadKey: stripe.String(valueKey),
+		}
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreateMeter", map[string]string{"event_name": params.EventName})
+	m, err := h.client(ctx).BillingMeters.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	out := &gomultistripe.Meter{
+		ID:          m.ID,
+		DisplayName: m.DisplayName,
+		EventName:   m.EventName,
+		Status:      string(m.Status),
+		CreatedAt:   time.Unix(m.Created, 0),
+	}
+	if m.DefaultAggregation != nil {
+		out.Aggregation = gomultistripe.MeterAggregation(m.DefaultAggregation.Formula)
+	}
+	if m.CustomerMapping != nil {
+		out.CustomerKey = m.CustomerMapping.EventPayloadKey
+	}
+	if m.ValueSettings != nil {
+		out.ValueKey = m.ValueSettings.EventPayloadKey
+	}
+	return out, nil
+}
+
+// ReportMeterEvent sends the event to the v1 meter events endpoint: stripe-go v79 and
+// earlier cannot send v2 requests.
+func (h *HandlerV78) ReportMeterEvent(ctx context.Context, evt gomultistripe.MeterEvent) error {
+	params := &stripe.BillingMeterEventParams{
+		EventName: stripe.String(evt.EventName),
+		Payload:   gomultistripe.MeterEventPayload(evt),
+	}
+	if evt.Identifier != "" {
+		params.Identifier = stripe.String(evt.Identifier)
+	}
+	if !evt.Timestamp.IsZero() {
+		params.Timestamp = stripe.Int64(evt.Timestamp.Unix())
+	}
+	h.idempotent(ctx, &params.Params, "ReportMeterEvent", map[string]string{"customer": evt.CustomerID, "event_name": evt.EventName})
+	_, err := h.client(ctx).BillingMeterEvents.New(params)
+	return err
+}
+
+// ReportMeterEvents reports the events one per request, as the v1 API has no batch
+// endpoint; the batch size option is ignored.
+func (h *HandlerV78) ReportMeterEvents(ctx context.Context, events []gomultistripe.MeterEvent, opts ...gomultistripe.MeterBatchOption) error {
+	o := gomultistripe.NewMeterBatchOptions(opts...)
+	o.BatchSize = 1
+	return gomultistripe.SendMeterEventBatches(ctx, events, o, func(ctx context.Context, batch []gomultistripe.MeterEvent) error {
+		// The event's identifier keys retries of the request, so Stripe replays the response
+		// of an attempt that did reach it.
+		return h.ReportMeterEvent(gomultistripe.ContextWithIdempotencyKey(ctx, batch[0].Identifier), batch[0])
+	}, retryable)
+}
+
+// retryable reports whether a failed request may succeed when retried: it failed on the
+// network, was rate limited or hit a server error.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return true
+	}
+	return stripeErr.HTTPStatusCode == http.StatusTooManyRequests || stripeErr.HTTPStatusCode >= http.StatusInternalServerError
+}
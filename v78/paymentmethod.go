@@ -0,0 +1,46 @@
+package v78
+
+import (
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+// paymentMethodFromStripe normalizes a payment method of any type. Card details are
+// only set for cards (including wallet-backed cards); Link and PayPal expose the
+// account email instead.
+func paymentMethodFromStripe(pm *stripe.PaymentMethod) *gomultistripe.PaymentMethod {
+	out := &gomultistripe.PaymentMethod{
+		ID:        pm.ID,
+		Type:      string(pm.Type),
+		Metadata:  pm.Metadata,
+		CreatedAt: time.Unix(pm.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if pm.Customer != nil {
+		out.CustomerID = pm.Customer.ID
+	}
+	if pm.Card != nil {
+		out.Last4 = pm.Card.Last4
+		out.Brand = string(pm.Card.Brand)
+		out.ExpMonth = uint(pm.Card.ExpMonth)
+		out.ExpYear = uint(pm.Card.ExpYear)
+		out.Fingerprint = pm.Card.Fingerprint
+		out.Funding = string(pm.Card.Funding)
+		out.Country = pm.Card.Country
+		out.Issuer = pm.Card.Issuer
+		if pm.Card.Wallet != nil {
+			out.Wallet = string(pm.Card.Wallet.Type)
+		}
+	}
+	if pm.Link != nil {
+		out.Email = pm.Link.Email
+	}
+	if pm.Paypal != nil {
+		out.Email = pm.Paypal.PayerEmail
+	}
+	return out
+}
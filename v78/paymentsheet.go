@@ -0,0 +1,53 @@
+package v78
+
+import (
+	"context"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func (h *HandlerV78) PreparePaymentSheet(ctx context.Context, customerID string, amount int64, currency string) (*gomultistripe.PaymentSheet, error) {
+	if customerID == "" {
+		custParams := &stripe.CustomerParams{}
+		h.traced(ctx, custParams)
+		h.idempotent(ctx, &custParams.Params, "CreateCustomer", nil)
+		cust, err := h.client(ctx).Customers.New(custParams)
+		if err != nil {
+			return nil, err
+		}
+		customerID = cust.ID
+	}
+
+	keyParams := &stripe.EphemeralKeyParams{
+		Customer:      stripe.String(customerID),
+		StripeVersion: stripe.String(stripe.APIVersion),
+	}
+	h.scope(ctx, &keyParams.Params)
+	key, err := h.client(ctx).EphemeralKeys.New(keyParams)
+	if err != nil {
+		return nil, err
+	}
+
+	piParams := &stripe.PaymentIntentParams{
+		Amount:   stripe.Int64(amount),
+		Currency: stripe.String(currency),
+		Customer: stripe.String(customerID),
+		AutomaticPaymentMethods: &stripe.PaymentIntentAutomaticPaymentMethodsParams{
+			Enabled: stripe.Bool(true),
+		},
+	}
+	h.traced(ctx, piParams)
+	h.idempotent(ctx, &piParams.Params, "PreparePaymentSheet", map[string]string{"customer": customerID})
+	pi, err := h.client(ctx).PaymentIntents.New(piParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gomultistripe.PaymentSheet{
+		CustomerID:                customerID,
+		EphemeralKeySecret:        key.Secret,
+		PaymentIntentID:           pi.ID,
+		PaymentIntentClientSecret: pi.ClientSecret,
+	}, nil
+}
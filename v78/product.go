@@ -0,0 +1,131 @@
+package v78
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func (h *HandlerV78) CreateProduct(ctx context.Context, params gomultistripe.ProductParams) (*gomultistripe.Product, error) {
+	stripeParams := &stripe.ProductParams{Name: stripe.String(params.Name)}
+	if params.Description != "" {
+		stripeParams.Description = stripe.String(params.Description)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreateProduct", nil)
+	p, err := h.client(ctx).Products.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return &gomultistripe.Product{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Active:      p.Active,
+		Metadata:    metadata(p.Metadata),
+		CreatedAt:   time.Unix(p.Created, 0),
+	}, nil
+}
+
+func (h *HandlerV78) CreatePrice(ctx context.Context, params gomultistripe.PriceParams) (*gomultistripe.Price, error) {
+	stripeParams := &stripe.PriceParams{
+		Product:    stripe.String(params.ProductID),
+		Currency:   stripe.String(params.Currency),
+		UnitAmount: stripe.Int64(params.UnitAmount),
+	}
+	if params.Interval != "" {
+		stripeParams.Recurring = &stripe.PriceRecurringParams{
+			Interval:      stripe.String(string(params.Interval)),
+			IntervalCount: stripe.Int64(max(params.IntervalCount, 1)),
+		}
+	}
+	if params.LookupKey != "" {
+		stripeParams.LookupKey = stripe.String(params.LookupKey)
+		if params.TransferLookupKey {
+			stripeParams.TransferLookupKey = stripe.Bool(true)
+		}
+	}
+	if params.Nickname != "" {
+		stripeParams.Nickname = stripe.String(params.Nickname)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreatePrice", map[string]string{"product": params.ProductID})
+	p, err := h.client(ctx).Prices.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return priceFromStripe(p), nil
+}
+
+func (h *HandlerV78) GetPrice(ctx context.Context, priceID string) (*gomultistripe.Price, error) {
+	params := &stripe.PriceParams{}
+	h.scope(ctx, &params.Params)
+	p, err := h.client(ctx).Prices.Get(priceID, params)
+	if err != nil {
+		return nil, err
+	}
+	return priceFromStripe(p), nil
+}
+
+func (h *HandlerV78) ListPrices(ctx context.Context, query gomultistripe.PriceQuery, opts *gomultistripe.ListOptions) (*gomultistripe.PricePage, error) {
+	params := &stripe.PriceListParams{}
+	params.Single = true
+	if query.ProductID != "" {
+		params.Product = stripe.String(query.ProductID)
+	}
+	for _, key := range query.LookupKeys {
+		params.LookupKeys = append(params.LookupKeys, stripe.String(key))
+	}
+	if !query.IncludeInactive {
+		params.Active = stripe.Bool(true)
+	}
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Prices.List(params)
+	page := &gomultistripe.PricePage{}
+	for iter.Next() {
+		page.Prices = append(page.Prices, priceFromStripe(iter.Price()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Prices) > 0 {
+		page.NextCursor = page.Prices[len(page.Prices)-1].ID
+	}
+	return page, nil
+}
+
+func priceFromStripe(p *stripe.Price) *gomultistripe.Price {
+	out := &gomultistripe.Price{
+		ID:         p.ID,
+		Currency:   string(p.Currency),
+		UnitAmount: p.UnitAmount,
+		LookupKey:  p.LookupKey,
+		Nickname:   p.Nickname,
+		Active:     p.Active,
+		Metadata:   metadata(p.Metadata),
+		CreatedAt:  time.Unix(p.Created, 0),
+	}
+	if p.Product != nil {
+		out.ProductID = p.Product.ID
+	}
+	if p.Recurring != nil {
+		out.Interval = gomultistripe.PriceInterval(p.Recurring.Interval)
+		out.IntervalCount = p.Recurring.IntervalCount
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package v78
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func reportRunFromStripe(run *stripe.ReportingReportRun) *gomultistripe.ReportRun {
+	out := &gomultistripe.ReportRun{
+		ID:         run.ID,
+		ReportType: run.ReportType,
+		Status:     string(run.Status),
+		Error:      run.Error,
+		CreatedAt:  time.Unix(run.Created, 0),
+	}
+	if run.Parameters != nil {
+		out.IntervalStart = time.Unix(run.Parameters.IntervalStart, 0)
+		out.IntervalEnd = time.Unix(run.Parameters.IntervalEnd, 0)
+	}
+	if run.Result != nil {
+		out.ResultFileID = run.Result.ID
+		out.ResultURL = run.Result.URL
+	}
+	return out
+}
+
+func (h *HandlerV78) CreateReportRun(ctx context.Context, req gomultistripe.ReportRunRequest) (*gomultistripe.ReportRun, error) {
+	params := &stripe.ReportingReportRunParams{
+		ReportType: stripe.String(req.ReportType),
+		Parameters: &stripe.ReportingReportRunParametersParams{},
+	}
+	if !req.IntervalStart.IsZero() {
+		params.Parameters.IntervalStart = stripe.Int64(req.IntervalStart.Unix())
+	}
+	if !req.IntervalEnd.IsZero() {
+		params.Parameters.IntervalEnd = stripe.Int64(req.IntervalEnd.Unix())
+	}
+	if req.Currency != "" {
+		params.Parameters.Currency = stripe.String(req.Currency)
+	}
+	if req.Timezone != "" {
+		params.Parameters.Timezone = stripe.String(req.Timezone)
+	}
+	params.Parameters.Columns = stripe.StringSlice(req.Columns)
+	h.idempotent(ctx, &params.Params, "CreateReportRun", map[string]string{"report_type": req.ReportType})
+	run, err := h.client(ctx).ReportingReportRuns.New(params)
+	if err != nil {
+		return nil, err
+	}
+	return reportRunFromStripe(run), nil
+}
+
+func (h *HandlerV78) GetReportRun(ctx context.Context, reportRunID string) (*gomultistripe.ReportRun, error) {
+	params := &stripe.ReportingReportRunParams{}
+	h.scope(ctx, &params.Params)
+	run, err := h.client(ctx).ReportingReportRuns.Get(reportRunID, params)
+	if err != nil {
+		return nil, err
+	}
+	return reportRunFromStripe(run), nil
+}
+
+// DownloadReportRun streams the CSV result of a succeeded report run to w. The download goes
+// through the files backend, so Endpoints.FilesURL overrides apply.
+func (h *HandlerV78) DownloadReportRun(ctx context.Context, reportRunID string, w io.Writer) error {
+	params := &stripe.ReportingReportRunParams{}
+	h.scope(ctx, &params.Params)
+	run, err := h.client(ctx).ReportingReportRuns.Get(reportRunID, params)
+	if err != nil {
+		return err
+	}
+	if run.Status != stripe.ReportingReportRunStatusSucceeded || run.Result == nil {
+		return fmt.Errorf("%w: %s is %s", gomultistripe.ErrReportNotReady, reportRunID, run.Status)
+	}
+	url, client := run.Result.URL, (*http.Client)(nil)
+	if b, ok := h.backend(stripe.UploadsBackend).(*stripe.BackendImplementation); ok {
+		url = b.URL + strings.TrimPrefix(url, stripe.UploadsURL)
+		client = b.HTTPClient
+	}
+	return gomultistripe.DownloadFile(ctx, client, url, h.key(ctx), w)
+}
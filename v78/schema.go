@@ -0,0 +1,64 @@
+package v78
+
+import (
+	"encoding/json"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+// schemaModels maps webhook object types to the SDK model used to parse them and the
+// fields HandleWebhook relies on.
+var schemaModels = map[string]struct {
+	model    interface{}
+	critical []string
+}{
+	"setup_intent":   {stripe.SetupIntent{}, []string{"id", "payment_method", "metadata"}},
+	"payment_intent": {stripe.PaymentIntent{}, []string{"id", "amount", "status", "payment_method", "metadata"}},
+	"subscription":   {stripe.Subscription{}, []string{"id", "customer", "status", "cancel_at", "cancel_at_period_end", "canceled_at", "created", "metadata"}},
+	"invoice":        {stripe.Invoice{}, []string{"id", "customer", "amount_due", "status", "created", "lines", "metadata"}},
+	"refund":         {stripe.Refund{}, []string{"id", "amount", "charge", "currency", "status", "created", "metadata"}},
+	"charge":         {stripe.Charge{}, []string{"id", "amount", "currency", "status", "created", "metadata"}},
+	"account":        {stripe.Account{}, []string{"id", "charges_enabled", "payouts_enabled", "requirements", "metadata"}},
+	"dispute":        {stripe.Dispute{}, []string{"id", "amount", "charge", "currency", "reason", "status", "evidence_details", "created", "metadata"}},
+	"topup":          {stripe.Topup{}, []string{"id", "amount", "currency", "status", "balance_transaction", "created", "metadata"}},
+	"card":           {stripe.Card{}, []string{"id", "brand", "exp_month", "exp_year", "last4", "customer"}},
+	"payment_method": {stripe.PaymentMethod{}, []string{"id", "type", "customer", "created", "metadata"}},
+	"cash_balance":   {stripe.CashBalance{}, []string{"customer", "available"}},
+
+	"customer_cash_balance_transaction": {stripe.CustomerCashBalanceTransaction{}, []string{"id", "customer", "type", "net_amount", "ending_balance", "currency", "created"}},
+}
+
+func (h *HandlerV78) SetSchemaReporter(reporter gomultistripe.SchemaReporter) {
+	h.schemaReporter = reporter
+}
+
+// validateSchema reports unknown and missing fields of the event object when strict
+// validation is enabled.
+func (h *HandlerV78) validateSchema(event *webhookEvent) {
+	if h.schemaReporter == nil || len(event.Data.Raw) == 0 {
+		return
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(event.Data.Raw, &object); err != nil {
+		return
+	}
+	objectType, _ := object["object"].(string)
+	m, ok := schemaModels[objectType]
+	if !ok {
+		return
+	}
+	unknown, missing := gomultistripe.CheckSchema(object, m.model, m.critical)
+	if len(unknown) == 0 && len(missing) == 0 {
+		return
+	}
+	h.schemaReporter(gomultistripe.SchemaReport{
+		EventID:    event.ID,
+		EventType:  gomultistripe.CallbackEventType(event.Type),
+		Version:    h.Version(),
+		APIVersion: event.APIVersion,
+		Object:     objectType,
+		Unknown:    unknown,
+		Missing:    missing,
+	})
+}
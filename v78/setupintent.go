@@ -0,0 +1,113 @@
+package v78
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+func setupIntentFromStripe(si *stripe.SetupIntent) *gomultistripe.SetupIntent {
+	out := &gomultistripe.SetupIntent{
+		ID:           si.ID,
+		Status:       string(si.Status),
+		ClientSecret: si.ClientSecret,
+		Usage:        string(si.Usage),
+		Metadata:     si.Metadata,
+		CreatedAt:    time.Unix(si.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if si.Customer != nil {
+		out.CustomerID = si.Customer.ID
+	}
+	if si.PaymentMethod != nil {
+		out.PaymentMethod = si.PaymentMethod.ID
+	}
+	if si.NextAction != nil && si.NextAction.RedirectToURL != nil {
+		out.RedirectURL = si.NextAction.RedirectToURL.URL
+	}
+	return out
+}
+
+// setupIntentMandateData converts the customer's acceptance of a mandate, or returns nil
+// when there is none.
+func setupIntentMandateData(m *gomultistripe.MandateData) *stripe.SetupIntentMandateDataParams {
+	if m == nil {
+		return nil
+	}
+	acceptance := &stripe.SetupIntentMandateDataCustomerAcceptanceParams{}
+	if m.Online() {
+		acceptance.Type = stripe.MandateCustomerAcceptanceTypeOnline
+		acceptance.Online = &stripe.SetupIntentMandateDataCustomerAcceptanceOnlineParams{
+			IPAddress: stripe.String(m.IPAddress),
+			UserAgent: stripe.String(m.UserAgent),
+		}
+	} else {
+		acceptance.Type = stripe.MandateCustomerAcceptanceTypeOffline
+		acceptance.Offline = &stripe.SetupIntentMandateDataCustomerAcceptanceOfflineParams{}
+	}
+	return &stripe.SetupIntentMandateDataParams{CustomerAcceptance: acceptance}
+}
+
+func (h *HandlerV78) CreateSetupIntent(ctx context.Context, params *gomultistripe.SetupIntent) (*gomultistripe.SetupIntent, error) {
+	stripeParams := &stripe.SetupIntentParams{}
+	if params.CustomerID != "" {
+		stripeParams.Customer = stripe.String(params.CustomerID)
+	}
+	if params.PaymentMethod != "" {
+		stripeParams.PaymentMethod = stripe.String(params.PaymentMethod)
+	}
+	if params.Usage != "" {
+		stripeParams.Usage = stripe.String(params.Usage)
+	}
+	if params.Confirm {
+		stripeParams.Confirm = stripe.Bool(true)
+		stripeParams.MandateData = setupIntentMandateData(params.MandateData)
+		if params.ReturnURL != "" {
+			stripeParams.ReturnURL = stripe.String(params.ReturnURL)
+		}
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreateSetupIntent", map[string]string{"customer": params.CustomerID, "payment_method": params.PaymentMethod})
+	si, err := h.client(ctx).SetupIntents.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return setupIntentFromStripe(si), nil
+}
+
+func (h *HandlerV78) RetrieveSetupIntent(ctx context.Context, setupIntentID string) (*gomultistripe.SetupIntent, error) {
+	params := &stripe.SetupIntentParams{}
+	h.scope(ctx, &params.Params)
+	si, err := h.client(ctx).SetupIntents.Get(setupIntentID, params)
+	if err != nil {
+		return nil, err
+	}
+	return setupIntentFromStripe(si), nil
+}
+
+// ConfirmSetupIntent ignores ConfirmOptions.OffSession, which only applies to payments.
+func (h *HandlerV78) ConfirmSetupIntent(ctx context.Context, setupIntentID string, paymentMethodID string, opts ...gomultistripe.ConfirmOption) (*gomultistripe.SetupIntent, error) {
+	o := gomultistripe.NewConfirmOptions(opts...)
+	params := &stripe.SetupIntentConfirmParams{
+		MandateData: setupIntentMandateData(o.MandateData),
+	}
+	if paymentMethodID != "" {
+		params.PaymentMethod = stripe.String(paymentMethodID)
+	}
+	if o.ReturnURL != "" {
+		params.ReturnURL = stripe.String(o.ReturnURL)
+	}
+	h.idempotent(ctx, &params.Params, "ConfirmSetupIntent", map[string]string{"setup_intent": setupIntentID, "payment_method": paymentMethodID})
+	si, err := h.client(ctx).SetupIntents.Confirm(setupIntentID, params)
+	if err != nil {
+		return nil, err
+	}
+	return setupIntentFromStripe(si), nil
+}
@@ -0,0 +1,194 @@
+package v78
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v78"
+)
+
+// subscriptionFromStripe normalizes a Subscription.
+func subscriptionFromStripe(s *stripe.Subscription) *gomultistripe.Subscription {
+	out := &gomultistripe.Subscription{
+		ID:                 s.ID,
+		Status:             string(s.Status),
+		CurrentPeriodEnd:   currentPeriodEnd(s),
+		CancelAt:           s.CancelAt,
+		CancelAtPeriodEnd:  s.CancelAtPeriodEnd,
+		CanceledAt:         s.CanceledAt,
+		StartDate:          s.StartDate,
+		BillingCycleAnchor: s.BillingCycleAnchor,
+		TrialStart:         s.TrialStart,
+		TrialEnd:           s.TrialEnd,
+		CreatedAt:          time.Unix(s.Created, 0),
+		Metadata:           s.Metadata,
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if s.Customer != nil {
+		out.CustomerID = s.Customer.ID
+	}
+	if s.Items != nil && len(s.Items.Data) > 0 && s.Items.Data[0].Price != nil {
+		out.PriceID = s.Items.Data[0].Price.ID
+	}
+	out.Items = subscriptionItems(s.Items)
+	return out
+}
+
+// applySubscriptionOptions copies the optional creation settings onto params.
+func applySubscriptionOptions(params *stripe.SubscriptionParams, o gomultistripe.SubscriptionOptions) {
+	if !o.BackdateStartDate.IsZero() {
+		params.BackdateStartDate = stripe.Int64(o.BackdateStartDate.Unix())
+	}
+	if !o.BillingCycleAnchor.IsZero() {
+		params.BillingCycleAnchor = stripe.Int64(o.BillingCycleAnchor.Unix())
+	}
+	if o.CouponID != "" {
+		params.Discounts = append(params.Discounts, &stripe.SubscriptionDiscountParams{Coupon: stripe.String(o.CouponID)})
+	}
+	if o.PromotionCodeID != "" {
+		params.Discounts = append(params.Discounts, &stripe.SubscriptionDiscountParams{PromotionCode: stripe.String(o.PromotionCodeID)})
+	}
+	if !o.TrialEnd.IsZero() {
+		params.TrialEnd = stripe.Int64(o.TrialEnd.Unix())
+	} else if o.TrialPeriodDays > 0 {
+		params.TrialPeriodDays = stripe.Int64(o.TrialPeriodDays)
+	}
+	if o.DefaultPaymentMethodID != "" {
+		params.DefaultPaymentMethod = stripe.String(o.DefaultPaymentMethodID)
+	}
+	if o.Quantity > 0 && len(params.Items) > 0 {
+		params.Items[0].Quantity = stripe.Int64(o.Quantity)
+	}
+	params.Items = append(params.Items, subscriptionItemsParams(o.Items)...)
+	for k, v := range o.Metadata {
+		params.AddMetadata(k, v)
+	}
+}
+
+// subscriptionItemsParams converts item changes to request parameters.
+func subscriptionItemsParams(items []gomultistripe.SubscriptionItemParams) []*stripe.SubscriptionItemsParams {
+	out := make([]*stripe.SubscriptionItemsParams, 0, len(items))
+	for _, item := range items {
+		p := &stripe.SubscriptionItemsParams{}
+		if item.ID != "" {
+			p.ID = stripe.String(item.ID)
+		}
+		if item.Delete {
+			p.Deleted = stripe.Bool(true)
+			out = append(out, p)
+			continue
+		}
+		if item.PriceID != "" {
+			p.Price = stripe.String(item.PriceID)
+		}
+		if item.Quantity > 0 {
+			p.Quantity = stripe.Int64(item.Quantity)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// currentPeriodEnd returns the end of the subscription's current period.
+func currentPeriodEnd(s *stripe.Subscription) int64 {
+	return s.CurrentPeriodEnd
+}
+
+// subscriptionItems normalizes a subscription's items.
+func subscriptionItems(list *stripe.SubscriptionItemList) []gomultistripe.SubscriptionItem {
+	if list == nil {
+		return nil
+	}
+	items := make([]gomultistripe.SubscriptionItem, 0, len(list.Data))
+	for _, item := range list.Data {
+		out := gomultistripe.SubscriptionItem{ID: item.ID, Quantity: item.Quantity}
+		if item.Price != nil {
+			out.PriceID = item.Price.ID
+		}
+		items = append(items, out)
+	}
+	return items
+}
+
+// previousSubscriptionItems decodes the items from an event's previous_attributes, which
+// Stripe includes only when the update changed them.
+func previousSubscriptionItems(previous json.RawMessage) ([]gomultistripe.SubscriptionItem, error) {
+	if len(previous) == 0 {
+		return nil, nil
+	}
+	var attrs struct {
+		Items *stripe.SubscriptionItemList `json:"items"`
+	}
+	if err := json.Unmarshal(previous, &attrs); err != nil {
+		return nil, fmt.Errorf("failed to decode previous subscription items: %w", err)
+	}
+	if attrs.Items == nil {
+		return nil, nil
+	}
+	return subscriptionItems(attrs.Items), nil
+}
+
+// UpdateSubscriptionItems applies the item changes in one update. prorationBehavior is
+// "create_prorations", "always_invoice" or "none"; empty leaves Stripe's default.
+func (h *HandlerV78) UpdateSubscriptionItems(ctx context.Context, subscriptionID string, items []gomultistripe.SubscriptionItemParams, prorationBehavior string) (*gomultistripe.Subscription, error) {
+	params := &stripe.SubscriptionParams{Items: subscriptionItemsParams(items)}
+	if prorationBehavior != "" {
+		params.ProrationBehavior = stripe.String(prorationBehavior)
+	}
+	h.traced(ctx, params)
+	h.idempotent(ctx, &params.Params, "UpdateSubscriptionItems", map[string]string{"subscription": subscriptionID})
+	s, err := h.client(ctx).Subscriptions.Update(subscriptionID, params)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionFromStripe(s), nil
+}
+
+// ReconcileSeats updates the quantity of the policy's seat item to actualSeatCount when
+// policy.Evaluate reports the drift should be corrected and the policy is not a dry run.
+func (h *HandlerV78) ReconcileSeats(ctx context.Context, subscriptionID string, actualSeatCount int64, policy gomultistripe.SeatPolicy) (*gomultistripe.SeatReconciliation, error) {
+	getParams := &stripe.SubscriptionParams{}
+	h.scope(ctx, &getParams.Params)
+	s, err := h.client(ctx).Subscriptions.Get(subscriptionID, getParams)
+	if err != nil {
+		return nil, err
+	}
+	var item *stripe.SubscriptionItem
+	if s.Items != nil {
+		for _, it := range s.Items.Data {
+			if (policy.PriceID == "" && len(s.Items.Data) == 1) || (it.Price != nil && it.Price.ID == policy.PriceID) {
+				item = it
+				break
+			}
+		}
+	}
+	if item == nil {
+		return nil, gomultistripe.ErrSeatItemNotFound
+	}
+	out := &gomultistripe.SeatReconciliation{
+		SubscriptionID:     subscriptionID,
+		SubscriptionItemID: item.ID,
+		BilledQuantity:     item.Quantity,
+		ActualQuantity:     actualSeatCount,
+	}
+	policy.Evaluate(out)
+	if !out.WouldUpdate || policy.DryRun {
+		return out, nil
+	}
+	params := &stripe.SubscriptionItemParams{Quantity: stripe.Int64(actualSeatCount)}
+	if policy.ProrationBehavior != "" {
+		params.ProrationBehavior = stripe.String(policy.ProrationBehavior)
+	}
+	h.traced(ctx, params)
+	h.idempotent(ctx, &params.Params, "ReconcileSeats", map[string]string{"subscription": subscriptionID, "subscription_item": item.ID})
+	if _, err := h.client(ctx).SubscriptionItems.Update(item.ID, params); err != nil {
+		return nil, err
+	}
+	out.Updated = true
+	return out, nil
+}
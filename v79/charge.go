@@ -0,0 +1,70 @@
+package stripe
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v79"
+)
+
+func (h *HandlerV79) RetrieveCharge(ctx context.Context, chargeID string) (*gomultistripe.Charge, error) {
+	params := &stripe.ChargeParams{}
+	h.scope(ctx, &params.Params)
+	ch, err := h.client(ctx).Charges.Get(chargeID, params)
+	if err != nil {
+		return nil, err
+	}
+	return chargeFromStripe(ch), nil
+}
+
+func (h *HandlerV79) ListCharges(ctx context.Context, customerID string) ([]*gomultistripe.Charge, error) {
+	params := &stripe.ChargeListParams{Customer: stripe.String(customerID)}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Charges.List(params)
+	var charges []*gomultistripe.Charge
+	for iter.Next() {
+		charges = append(charges, chargeFromStripe(iter.Charge()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
+
+func chargeFromStripe(ch *stripe.Charge) *gomultistripe.Charge {
+	out := &gomultistripe.Charge{
+		ID:              ch.ID,
+		PaymentMethodID: ch.PaymentMethod,
+		Amount:          ch.Amount,
+		AmountCaptured:  ch.AmountCaptured,
+		AmountRefunded:  ch.AmountRefunded,
+		Currency:        string(ch.Currency),
+		Status:          string(ch.Status),
+		Paid:            ch.Paid,
+		Captured:        ch.Captured,
+		Refunded:        ch.Refunded,
+		FailureCode:     ch.FailureCode,
+		FailureMessage:  ch.FailureMessage,
+		ReceiptURL:      ch.ReceiptURL,
+		Description:     ch.Description,
+		Metadata:        ch.Metadata,
+		CreatedAt:       time.Unix(ch.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if ch.Customer != nil {
+		out.CustomerID = ch.Customer.ID
+	}
+	if ch.PaymentIntent != nil {
+		out.PaymentIntentID = ch.PaymentIntent.ID
+	}
+	if ch.Invoice != nil {
+		out.InvoiceID = ch.Invoice.ID
+	}
+	if ch.BalanceTransaction != nil {
+		out.BalanceTransactionID = ch.BalanceTransaction.ID
+	}
+	return out
+}
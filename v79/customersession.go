@@ -0,0 +1,51 @@
+package stripe
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v79"
+)
+
+func (h *HandlerV79) CreateCustomerSession(ctx context.Context, customerID string, components []gomultistripe.CustomerSessionComponent) (*gomultistripe.CustomerSession, error) {
+	params := &stripe.CustomerSessionParams{
+		Customer:   stripe.String(customerID),
+		Components: &stripe.CustomerSessionComponentsParams{},
+	}
+	for _, c := range components {
+		switch c {
+		case gomultistripe.CustomerSessionPaymentElement:
+			// Without the features, the Payment Element ignores the customer's saved payment
+			// methods, which Stripe does not redisplay by default.
+			params.Components.PaymentElement = &stripe.CustomerSessionComponentsPaymentElementParams{
+				Enabled: stripe.Bool(true),
+				Features: &stripe.CustomerSessionComponentsPaymentElementFeaturesParams{
+					PaymentMethodRedisplay: stripe.String("enabled"),
+					PaymentMethodSave:      stripe.String("enabled"),
+				},
+			}
+		case gomultistripe.CustomerSessionPricingTable:
+			params.Components.PricingTable = &stripe.CustomerSessionComponentsPricingTableParams{
+				Enabled: stripe.Bool(true),
+			}
+		case gomultistripe.CustomerSessionBuyButton:
+			params.Components.BuyButton = &stripe.CustomerSessionComponentsBuyButtonParams{
+				Enabled: stripe.Bool(true),
+			}
+		default:
+			return nil, gomultistripe.Unsupported(h.Version(), "customer session component "+string(c))
+		}
+	}
+	h.idempotent(ctx, &params.Params, "CreateCustomerSession", map[string]string{"customer": customerID})
+	cs, err := h.client(ctx).CustomerSessions.New(params)
+	if err != nil {
+		return nil, err
+	}
+	return &gomultistripe.CustomerSession{
+		CustomerID:   customerID,
+		ClientSecret: cs.ClientSecret,
+		ExpiresAt:    time.Unix(cs.ExpiresAt, 0),
+		CreatedAt:    time.Unix(cs.Created, 0),
+	}, nil
+}
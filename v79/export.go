@@ -0,0 +1,145 @@
+package stripe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v79"
+)
+
+func (h *HandlerV79) ListForExport(ctx context.Context, q gomultistripe.ExportQuery) (*gomultistripe.ExportPage, error) {
+	limit := q.Limit
+	if limit <= 0 || limit > gomultistripe.MaxExportPageSize {
+		limit = gomultistripe.MaxExportPageSize
+	}
+	list := stripe.ListParams{Single: true, Limit: stripe.Int64(limit)}
+	if q.StartingAfter != "" {
+		list.StartingAfter = stripe.String(q.StartingAfter)
+	}
+	h.scopeList(ctx, &list)
+	created := &stripe.RangeQueryParams{}
+	if !q.CreatedFrom.IsZero() {
+		created.GreaterThanOrEqual = q.CreatedFrom.Unix()
+	}
+	if !q.CreatedTo.IsZero() {
+		created.LesserThan = q.CreatedTo.Unix()
+	}
+
+	page := &gomultistripe.ExportPage{}
+	switch q.Object {
+	case gomultistripe.ExportCharges:
+		iter := h.client(ctx).Charges.List(&stripe.ChargeListParams{ListParams: list, CreatedRange: created})
+		for iter.Next() {
+			page.Records = append(page.Records, chargeExportRecord(iter.Charge()))
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		page.HasMore = iter.Meta().HasMore
+	case gomultistripe.ExportPaymentIntents:
+		iter := h.client(ctx).PaymentIntents.List(&stripe.PaymentIntentListParams{ListParams: list, CreatedRange: created})
+		for iter.Next() {
+			page.Records = append(page.Records, paymentIntentExportRecord(iter.PaymentIntent()))
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		page.HasMore = iter.Meta().HasMore
+	case gomultistripe.ExportInvoices:
+		iter := h.client(ctx).Invoices.List(&stripe.InvoiceListParams{ListParams: list, CreatedRange: created})
+		for iter.Next() {
+			page.Records = append(page.Records, invoiceExportRecord(iter.Invoice()))
+		}
+		if err := iter.Err(); err != nil {
+			return nil, err
+		}
+		page.HasMore = iter.Meta().HasMore
+	default:
+		return nil, fmt.Errorf("unknown export object %q", q.Object)
+	}
+	if page.HasMore && len(page.Records) > 0 {
+		page.NextCursor = page.Records[len(page.Records)-1].ID
+	}
+	return page, nil
+}
+
+func chargeExportRecord(c *stripe.Charge) gomultistripe.ExportRecord {
+	r := gomultistripe.ExportRecord{
+		Object:         gomultistripe.ExportCharges,
+		ID:             c.ID,
+		Created:        time.Unix(c.Created, 0),
+		Livemode:       c.Livemode,
+		Currency:       string(c.Currency),
+		Amount:         c.Amount,
+		AmountReceived: c.AmountCaptured,
+		AmountRefunded: c.AmountRefunded,
+		Status:         string(c.Status),
+		Description:    c.Description,
+		Metadata:       c.Metadata,
+	}
+	if c.Customer != nil {
+		r.CustomerID = c.Customer.ID
+	}
+	if c.PaymentIntent != nil {
+		r.PaymentIntentID = c.PaymentIntent.ID
+	}
+	if c.BalanceTransaction != nil {
+		r.BalanceTransactionID = c.BalanceTransaction.ID
+	}
+	if c.Invoice != nil {
+		r.InvoiceID = c.Invoice.ID
+	}
+	return r
+}
+
+func paymentIntentExportRecord(pi *stripe.PaymentIntent) gomultistripe.ExportRecord {
+	r := gomultistripe.ExportRecord{
+		Object:         gomultistripe.ExportPaymentIntents,
+		ID:             pi.ID,
+		Created:        time.Unix(pi.Created, 0),
+		Livemode:       pi.Livemode,
+		Currency:       string(pi.Currency),
+		Amount:         pi.Amount,
+		AmountReceived: pi.AmountReceived,
+		Status:         string(pi.Status),
+		Description:    pi.Description,
+		Metadata:       pi.Metadata,
+	}
+	if pi.Customer != nil {
+		r.CustomerID = pi.Customer.ID
+	}
+	if pi.LatestCharge != nil {
+		r.ChargeID = pi.LatestCharge.ID
+	}
+	if pi.Invoice != nil {
+		r.InvoiceID = pi.Invoice.ID
+	}
+	return r
+}
+
+func invoiceExportRecord(inv *stripe.Invoice) gomultistripe.ExportRecord {
+	r := gomultistripe.ExportRecord{
+		Object:         gomultistripe.ExportInvoices,
+		ID:             inv.ID,
+		Created:        time.Unix(inv.Created, 0),
+		Livemode:       inv.Livemode,
+		Currency:       string(inv.Currency),
+		Amount:         inv.Total,
+		AmountReceived: inv.AmountPaid,
+		Status:         string(inv.Status),
+		Description:    inv.Description,
+		Metadata:       inv.Metadata,
+	}
+	if inv.Customer != nil {
+		r.CustomerID = inv.Customer.ID
+	}
+	if inv.Charge != nil {
+		r.ChargeID = inv.Charge.ID
+	}
+	if inv.PaymentIntent != nil {
+		r.PaymentIntentID = inv.PaymentIntent.ID
+	}
+	return r
+}
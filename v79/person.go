@@ -0,0 +1,210 @@
+package stripe
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v79"
+)
+
+func (h *HandlerV79) CreatePerson(ctx context.Context, accountID string, params gomultistripe.PersonParams) (*gomultistripe.Person, error) {
+	stripeParams := personParams(params)
+	stripeParams.Account = stripe.String(accountID)
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreatePerson", map[string]string{"account": accountID})
+	p, err := h.client(ctx).Persons.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return personFromStripe(p), nil
+}
+
+func (h *HandlerV79) UpdatePerson(ctx context.Context, accountID, personID string, params gomultistripe.PersonParams) (*gomultistripe.Person, error) {
+	stripeParams := personParams(params)
+	stripeParams.Account = stripe.String(accountID)
+	h.idempotent(ctx, &stripeParams.Params, "UpdatePerson", map[string]string{"account": accountID, "person": personID})
+	p, err := h.client(ctx).Persons.Update(personID, stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return personFromStripe(p), nil
+}
+
+func (h *HandlerV79) DeletePerson(ctx context.Context, accountID, personID string) error {
+	params := &stripe.PersonParams{Account: stripe.String(accountID)}
+	h.idempotent(ctx, &params.Params, "DeletePerson", map[string]string{"account": accountID, "person": personID})
+	_, err := h.client(ctx).Persons.Del(personID, params)
+	return err
+}
+
+func (h *HandlerV79) AddExternalBankAccount(ctx context.Context, accountID string, params gomultistripe.ExternalBankAccountParams) (*gomultistripe.ExternalBankAccount, error) {
+	stripeParams := &stripe.BankAccountParams{Account: stripe.String(accountID)}
+	if params.Token != "" {
+		stripeParams.Token = stripe.String(params.Token)
+	} else {
+		stripeParams.Country = stripe.String(params.Country)
+		stripeParams.Currency = stripe.String(params.Currency)
+		stripeParams.AccountNumber = stripe.String(params.AccountNumber)
+		if params.RoutingNumber != "" {
+			stripeParams.RoutingNumber = stripe.String(params.RoutingNumber)
+		}
+		if params.AccountHolderName != "" {
+			stripeParams.AccountHolderName = stripe.String(params.AccountHolderName)
+		}
+		if params.AccountHolderType != "" {
+			stripeParams.AccountHolderType = stripe.String(params.AccountHolderType)
+		}
+	}
+	if params.DefaultForCurrency {
+		stripeParams.DefaultForCurrency = stripe.Bool(true)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.idempotent(ctx, &stripeParams.Params, "AddExternalBankAccount", map[string]string{"account": accountID})
+	ba, err := h.client(ctx).BankAccounts.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return externalBankAccountFromStripe(ba), nil
+}
+
+func (h *HandlerV79) DeleteExternalAccount(ctx context.Context, accountID, externalAccountID string) error {
+	params := &stripe.BankAccountParams{Account: stripe.String(accountID)}
+	h.idempotent(ctx, &params.Params, "DeleteExternalAccount", map[string]string{"account": accountID, "external_account": externalAccountID})
+	_, err := h.client(ctx).BankAccounts.Del(externalAccountID, params)
+	return err
+}
+
+func personParams(params gomultistripe.PersonParams) *stripe.PersonParams {
+	p := &stripe.PersonParams{}
+	if params.FirstName != "" {
+		p.FirstName = stripe.String(params.FirstName)
+	}
+	if params.LastName != "" {
+		p.LastName = stripe.String(params.LastName)
+	}
+	if params.Email != "" {
+		p.Email = stripe.String(params.Email)
+	}
+	if params.Phone != "" {
+		p.Phone = stripe.String(params.Phone)
+	}
+	if !params.DOB.IsZero() {
+		p.DOB = &stripe.PersonDOBParams{
+			Day:   stripe.Int64(int64(params.DOB.Day())),
+			Month: stripe.Int64(int64(params.DOB.Month())),
+			Year:  stripe.Int64(int64(params.DOB.Year())),
+		}
+	}
+	if a := params.Address; a != nil {
+		p.Address = &stripe.AddressParams{
+			Line1:      stripe.String(a.Line1),
+			City:       stripe.String(a.City),
+			PostalCode: stripe.String(a.PostalCode),
+			Country:    stripe.String(a.Country),
+		}
+		if a.Line2 != "" {
+			p.Address.Line2 = stripe.String(a.Line2)
+		}
+		if a.State != "" {
+			p.Address.State = stripe.String(a.State)
+		}
+	}
+	if params.IDNumber != "" {
+		p.IDNumber = stripe.String(params.IDNumber)
+	}
+	if params.SSNLast4 != "" {
+		p.SSNLast4 = stripe.String(params.SSNLast4)
+	}
+	if r := params.Relationship; r != nil {
+		p.Relationship = &stripe.PersonRelationshipParams{
+			Representative: stripe.Bool(r.Representative),
+			Owner:          stripe.Bool(r.Owner),
+			Director:       stripe.Bool(r.Director),
+			Executive:      stripe.Bool(r.Executive),
+		}
+		if r.Title != "" {
+			p.Relationship.Title = stripe.String(r.Title)
+		}
+		if r.PercentOwnership > 0 {
+			p.Relationship.PercentOwnership = stripe.Float64(r.PercentOwnership)
+		}
+	}
+	for k, v := range params.Metadata {
+		p.AddMetadata(k, v)
+	}
+	return p
+}
+
+func personFromStripe(p *stripe.Person) *gomultistripe.Person {
+	out := &gomultistripe.Person{
+		ID:               p.ID,
+		AccountID:        p.Account,
+		FirstName:        p.FirstName,
+		LastName:         p.LastName,
+		Email:            p.Email,
+		Phone:            p.Phone,
+		IDNumberProvided: p.IDNumberProvided,
+		SSNLast4Provided: p.SSNLast4Provided,
+		Metadata:         p.Metadata,
+		CreatedAt:        time.Unix(p.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if dob := p.DOB; dob != nil && dob.Year > 0 {
+		out.DOB = time.Date(int(dob.Year), time.Month(dob.Month), int(dob.Day), 0, 0, 0, 0, time.UTC)
+	}
+	if a := p.Address; a != nil {
+		out.Address = &gomultistripe.Address{
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			State:      a.State,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	if r := p.Relationship; r != nil {
+		out.Relationship = gomultistripe.PersonRelationship{
+			Representative:   r.Representative,
+			Owner:            r.Owner,
+			Director:         r.Director,
+			Executive:        r.Executive,
+			Title:            r.Title,
+			PercentOwnership: r.PercentOwnership,
+		}
+	}
+	if p.Requirements != nil {
+		out.RequirementsDue = p.Requirements.CurrentlyDue
+	}
+	if p.Verification != nil {
+		out.VerificationStatus = string(p.Verification.Status)
+	}
+	return out
+}
+
+func externalBankAccountFromStripe(ba *stripe.BankAccount) *gomultistripe.ExternalBankAccount {
+	out := &gomultistripe.ExternalBankAccount{
+		ID:                 ba.ID,
+		Country:            ba.Country,
+		Currency:           string(ba.Currency),
+		BankName:           ba.BankName,
+		Last4:              ba.Last4,
+		RoutingNumber:      ba.RoutingNumber,
+		AccountHolderName:  ba.AccountHolderName,
+		AccountHolderType:  string(ba.AccountHolderType),
+		Status:             string(ba.Status),
+		DefaultForCurrency: ba.DefaultForCurrency,
+		Metadata:           ba.Metadata,
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if ba.Account != nil {
+		out.AccountID = ba.Account.ID
+	}
+	return out
+}
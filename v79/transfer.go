@@ -0,0 +1,118 @@
+package stripe
+
+import (
+	"context"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v79"
+)
+
+func (h *HandlerV79) CreateTransfer(ctx context.Context, params gomultistripe.TransferParams) (*gomultistripe.Transfer, error) {
+	stripeParams := &stripe.TransferParams{
+		Amount:      stripe.Int64(params.Amount),
+		Currency:    stripe.String(params.Currency),
+		Destination: stripe.String(params.DestinationAccountID),
+	}
+	if params.SourceChargeID != "" {
+		stripeParams.SourceTransaction = stripe.String(params.SourceChargeID)
+	}
+	if params.TransferGroup != "" {
+		stripeParams.TransferGroup = stripe.String(params.TransferGroup)
+	}
+	if params.Description != "" {
+		stripeParams.Description = stripe.String(params.Description)
+	}
+	for k, v := range params.Metadata {
+		stripeParams.AddMetadata(k, v)
+	}
+	h.traced(ctx, stripeParams)
+	h.idempotent(ctx, &stripeParams.Params, "CreateTransfer", map[string]string{"destination": params.DestinationAccountID, "source_transaction": params.SourceChargeID})
+	tr, err := h.client(ctx).Transfers.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	return transferFromStripe(tr), nil
+}
+
+func (h *HandlerV79) ReverseTransfer(ctx context.Context, transferID string, amount int64, refundApplicationFee bool) (*gomultistripe.TransferReversal, error) {
+	params := &stripe.TransferReversalParams{ID: stripe.String(transferID)}
+	if amount > 0 {
+		params.Amount = stripe.Int64(amount)
+	}
+	if refundApplicationFee {
+		params.RefundApplicationFee = stripe.Bool(true)
+	}
+	h.idempotent(ctx, &params.Params, "ReverseTransfer", map[string]string{"transfer": transferID})
+	rev, err := h.client(ctx).TransferReversals.New(params)
+	if err != nil {
+		return nil, err
+	}
+	return &gomultistripe.TransferReversal{
+		ID:         rev.ID,
+		TransferID: transferID,
+		Amount:     rev.Amount,
+		Currency:   string(rev.Currency),
+		CreatedAt:  time.Unix(rev.Created, 0),
+	}, nil
+}
+
+func (h *HandlerV79) ListTransfers(ctx context.Context, query gomultistripe.TransferQuery, opts *gomultistripe.ListOptions) (*gomultistripe.TransferPage, error) {
+	params := &stripe.TransferListParams{}
+	if query.DestinationAccountID != "" {
+		params.Destination = stripe.String(query.DestinationAccountID)
+	}
+	if query.TransferGroup != "" {
+		params.TransferGroup = stripe.String(query.TransferGroup)
+	}
+	params.Single = true
+	if opts != nil {
+		if opts.Limit > 0 {
+			params.Limit = stripe.Int64(min(opts.Limit, gomultistripe.MaxExportPageSize))
+		}
+		if opts.StartingAfter != "" {
+			params.StartingAfter = stripe.String(opts.StartingAfter)
+		}
+	}
+	h.scopeList(ctx, &params.ListParams)
+	iter := h.client(ctx).Transfers.List(params)
+	page := &gomultistripe.TransferPage{}
+	for iter.Next() {
+		page.Transfers = append(page.Transfers, transferFromStripe(iter.Transfer()))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	page.HasMore = iter.Meta().HasMore
+	if page.HasMore && len(page.Transfers) > 0 {
+		page.NextCursor = page.Transfers[len(page.Transfers)-1].ID
+	}
+	return page, nil
+}
+
+func transferFromStripe(tr *stripe.Transfer) *gomultistripe.Transfer {
+	out := &gomultistripe.Transfer{
+		ID:             tr.ID,
+		Amount:         tr.Amount,
+		AmountReversed: tr.AmountReversed,
+		Currency:       string(tr.Currency),
+		TransferGroup:  tr.TransferGroup,
+		Reversed:       tr.Reversed,
+		Description:    tr.Description,
+		Metadata:       tr.Metadata,
+		CreatedAt:      time.Unix(tr.Created, 0),
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	if tr.Destination != nil {
+		out.DestinationAccountID = tr.Destination.ID
+	}
+	if tr.DestinationPayment != nil {
+		out.DestinationPaymentID = tr.DestinationPayment.ID
+	}
+	if tr.SourceTransaction != nil {
+		out.SourceChargeID = tr.SourceTransaction.ID
+	}
+	return out
+}
@@ -0,0 +1,241 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v80"
+)
+
+func (h *HandlerV80) CreateMeter(ctx context.Context, params gomultistripe.MeterParams) (*gomultistripe.Meter, error) {
+	aggregation := params.Aggregation
+	if aggregation == "" {
+		aggregation = gomultistripe.MeterSum
+	}
+	customerKey := params.CustomerKey
+	if customerKey == "" {
+		customerKey = gomultistripe.DefaultMeterCustomerKey
+	}
+	valueKey := params.ValueKey
+	if valueKey == "" {
+		valueKey = gomultistripe.DefaultMeterValueKey
+	}
+	stripeParams := &stripe.BillingMeterParams{
+		DisplayName: stripe.String(params.DisplayName),
+		EventName:   stripe.String(params.EventName),
+		DefaultAggregation: &stripe.BillingMeterDefaultAggregationParams{
+			Formula: stripe.String(string(aggregation)),
+		},
+		CustomerMapping: &stripe.BillingMeterCustomerMappingParams{
+			Type:            stripe.String("by_id"),
+			EventPayloadKey: stripe.String(customerKey),
+		},
+	}
+	if aggregation != gomultistripe.MeterCount {
+		stripeParams.ValueSettings = &stripe.BillingMeterValueSettingsParams{
+			EventPayloadKey: stripe.String(valueKey),
+		}
+	}
+	h.idempotent(ctx, &stripeParams.Params, "CreateMeter", map[string]string{"event_name": params.EventName})
+	m, err := h.client(ctx).BillingMeters.New(stripeParams)
+	if err != nil {
+		return nil, err
+	}
+	out := &gomultistripe.Meter{
+		ID:          m.ID,
+		DisplayName: m.DisplayName,
+		EventName:   m.EventName,
+		Status:      string(m.Status),
+		CreatedAt:   time.Unix(m.Created, 0),
+	}
+	if m.DefaultAggregation != nil {
+		out.Aggregation = gomultistripe.MeterAggregation(m.DefaultAggregation.Formula)
+	}
+	if m.CustomerMapping != nil {
+		out.CustomerKey = m.CustomerMapping.EventPayloadKey
+	}
+	if m.ValueSettings != nil {
+		out.ValueKey = m.ValueSettings.EventPayloadKey
+	}
+	return out, nil
+}
+
+// meterEventV2 is a meter event in the request body of the v2 meter event endpoints.
+type meterEventV2 struct {
+	EventName  string            `json:"event_name"`
+	Payload    map[string]string `json:"payload"`
+	Identifier string            `json:"identifier,omitempty"`
+	Timestamp  string            `json:"timestamp,omitempty"`
+}
+
+func newMeterEventV2(evt gomultistripe.MeterEvent) meterEventV2 {
+	body := meterEventV2{
+		EventName:  evt.EventName,
+		Payload:    gomultistripe.MeterEventPayload(evt),
+		Identifier: evt.Identifier,
+	}
+	if !evt.Timestamp.IsZero() {
+		body.Timestamp = evt.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	return body
+}
+
+// v2Params returns the parameters of a mutating v2 request. v2 endpoints take the connected
+// account in the Stripe-Context header rather than Stripe-Account.
+func (h *HandlerV80) v2Params(ctx context.Context, operation string, entityIDs map[string]string) *stripe.RawParams {
+	params := &stripe.RawParams{}
+	h.idempotent(ctx, &params.Params, operation, entityIDs)
+	if params.StripeAccount != nil {
+		params.StripeContext = *params.StripeAccount
+		params.StripeAccount = nil
+	}
+	return params
+}
+
+// v2ReadParams returns the parameters of a v2 read request, which carry the connected
+// account like v2Params but no idempotency key.
+func (h *HandlerV80) v2ReadParams(ctx context.Context) *stripe.RawParams {
+	params := &stripe.RawParams{}
+	h.scope(ctx, &params.Params)
+	if params.StripeAccount != nil {
+		params.StripeContext = *params.StripeAccount
+		params.StripeAccount = nil
+	}
+	return params
+}
+
+// ReportMeterEvent sends the event to the v2 meter events endpoint, which stripe-go v80
+// and later reach through raw requests. Unlike its v1 counterpart, it processes events
+// asynchronously, so invalid events are reported by v1.billing.meter.error_report_triggered
+// events rather than by the response.
+func (h *HandlerV80) ReportMeterEvent(ctx context.Context, evt gomultistripe.MeterEvent) error {
+	content, err := json.Marshal(newMeterEventV2(evt))
+	if err != nil {
+		return err
+	}
+	params := h.v2Params(ctx, "ReportMeterEvent", map[string]string{"customer": evt.CustomerID, "event_name": evt.EventName})
+	_, err = h.rawRequest(stripe.APIBackend, h.key(ctx), http.MethodPost, "/v2/billing/meter_events", string(content), params)
+	return err
+}
+
+// rawRequest sends a request through a raw request backend, so it can reach v2 endpoints.
+// The backends of stripe-go v80 to v82.0 panic when a raw request fails before Stripe
+// responds, e.g. on a DNS or connection error; the panic is returned as an error instead.
+func (h *HandlerV80) rawRequest(backendType stripe.SupportedBackend, key, method, path, content string, params *stripe.RawParams) (resp *stripe.APIResponse, err error) {
+	var b stripe.RawRequestBackend
+	if backendType == stripe.MeterEventsBackend {
+		h.meterMu.Lock()
+		b = h.meterEvents
+		h.meterMu.Unlock()
+	}
+	if b == nil {
+		var ok bool
+		if b, ok = h.backend(backendType).(stripe.RawRequestBackend); !ok {
+			return nil, fmt.Errorf("%s backend does not support raw requests", backendType)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s %s failed before Stripe responded: %v", method, path, r)
+		}
+	}()
+	return b.RawRequest(method, path, key, content, params)
+}
+
+// ReportMeterEvents sends the events in batches to the v2 meter event stream, which
+// authenticates with the token of a short-lived meter event session rather than the
+// secret key.
+func (h *HandlerV80) ReportMeterEvents(ctx context.Context, events []gomultistripe.MeterEvent, opts ...gomultistripe.MeterBatchOption) error {
+	return gomultistripe.SendMeterEventBatches(ctx, events, gomultistripe.NewMeterBatchOptions(opts...), h.sendMeterEventStream, retryable)
+}
+
+func (h *HandlerV80) sendMeterEventStream(ctx context.Context, batch []gomultistripe.MeterEvent) error {
+	body := struct {
+		Events []meterEventV2 `json:"events"`
+	}{Events: make([]meterEventV2, len(batch))}
+	for i, evt := range batch {
+		body.Events[i] = newMeterEventV2(evt)
+	}
+	content, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	// The batch's first identifier keys retries of the request, so Stripe replays the
+	// response of an attempt that did reach it.
+	streamCtx := gomultistripe.ContextWithIdempotencyKey(ctx, batch[0].Identifier)
+	for refreshed := false; ; refreshed = true {
+		token, err := h.meterEventSessionToken(ctx, refreshed)
+		if err != nil {
+			return err
+		}
+		params := h.v2Params(streamCtx, "ReportMeterEvents", map[string]string{"event_name": batch[0].EventName, "events": strconv.Itoa(len(batch))})
+		// The session token identifies the account, so the request carries no Stripe-Context.
+		params.StripeContext = ""
+		_, err = h.rawRequest(stripe.MeterEventsBackend, token, http.MethodPost, "/v2/billing/meter_event_stream", string(content), params)
+		var stripeErr *stripe.Error
+		if !refreshed && errors.As(err, &stripeErr) && stripeErr.HTTPStatusCode == http.StatusUnauthorized {
+			continue // the session expired early
+		}
+		return err
+	}
+}
+
+// meterEventSession is a cached meter event stream session.
+type meterEventSession struct {
+	Token     string    `json:"authentication_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// meterEventSessionToken returns the token of a meter event session for the connected
+// account in ctx. It creates a session when there is none cached, the cached one expires
+// within a minute, or refresh is set.
+func (h *HandlerV80) meterEventSessionToken(ctx context.Context, refresh bool) (string, error) {
+	account, _ := gomultistripe.AccountFromContext(ctx)
+	// A session belongs to the key that created it, so per-call keys do not share one.
+	session := h.key(ctx) + "/" + account
+	h.meterMu.Lock()
+	defer h.meterMu.Unlock()
+	if s, ok := h.meterSessions[session]; ok && !refresh && time.Until(s.ExpiresAt) > time.Minute {
+		return s.Token, nil
+	}
+	params := h.v2Params(ctx, "CreateMeterEventSession", nil)
+	resp, err := h.rawRequest(stripe.APIBackend, h.key(ctx), http.MethodPost, "/v2/billing/meter_event_session", "", params)
+	if err != nil {
+		return "", err
+	}
+	var s meterEventSession
+	if err := json.Unmarshal(resp.RawJSON, &s); err != nil {
+		return "", err
+	}
+	if h.meterSessions == nil {
+		h.meterSessions = make(map[string]meterEventSession)
+	}
+	// Sessions last 15 minutes; dropping the expired ones keeps only the keys and accounts
+	// that reported usage recently.
+	for k, cached := range h.meterSessions {
+		if time.Now().After(cached.ExpiresAt) {
+			delete(h.meterSessions, k)
+		}
+	}
+	h.meterSessions[session] = s
+	return s.Token, nil
+}
+
+// retryable reports whether a failed request may succeed when retried: it failed on the
+// network, was rate limited or hit a server error.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var stripeErr *stripe.Error
+	if !errors.As(err, &stripeErr) {
+		return true
+	}
+	return stripeErr.HTTPStatusCode == http.StatusTooManyRequests || stripeErr.HTTPStatusCode >= http.StatusInternalServerError
+}
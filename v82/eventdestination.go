@@ -0,0 +1,198 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	gomultistripe "github.com/iqhive/gomultistripe"
+	"github.com/stripe/stripe-go/v82"
+)
+
+const eventDestinationsPath = "/v2/core/event_destinations"
+
+// eventDestinationIncludes are the fields v2 event destinations omit unless requested.
+var eventDestinationIncludes = []string{"webhook_endpoint.url"}
+
+type webhookEndpointV2 struct {
+	URL           string `json:"url,omitempty"`
+	SigningSecret string `json:"signing_secret,omitempty"`
+}
+
+type amazonEventBridgeV2 struct {
+	AWSAccountID         string `json:"aws_account_id,omitempty"`
+	AWSRegion            string `json:"aws_region,omitempty"`
+	AWSEventSourceARN    string `json:"aws_event_source_arn,omitempty"`
+	AWSEventSourceStatus string `json:"aws_event_source_status,omitempty"`
+}
+
+// eventDestinationV2 is an event destination in the request and response bodies of the v2
+// event destination endpoints.
+type eventDestinationV2 struct {
+	ID                 string               `json:"id,omitempty"`
+	Name               string               `json:"name,omitempty"`
+	Description        string               `json:"description,omitempty"`
+	Type               string               `json:"type,omitempty"`
+	Status             string               `json:"status,omitempty"`
+	EnabledEvents      []string             `json:"enabled_events,omitempty"`
+	EventPayload       string               `json:"event_payload,omitempty"`
+	SnapshotAPIVersion string               `json:"snapshot_api_version,omitempty"`
+	EventsFrom         []string             `json:"events_from,omitempty"`
+	WebhookEndpoint    *webhookEndpointV2   `json:"webhook_endpoint,omitempty"`
+	AmazonEventBridge  *amazonEventBridgeV2 `json:"amazon_eventbridge,omitempty"`
+	Livemode           bool                 `json:"livemode,omitempty"`
+	Metadata           map[string]string    `json:"metadata,omitempty"`
+	Created            *time.Time           `json:"created,omitempty"`
+	Include            []string             `json:"include,omitempty"`
+}
+
+func (d *eventDestinationV2) toDestination() *gomultistripe.EventDestination {
+	out := &gomultistripe.EventDestination{
+		ID:                 d.ID,
+		Name:               d.Name,
+		Description:        d.Description,
+		Type:               gomultistripe.EventDestinationType(d.Type),
+		Status:             d.Status,
+		EnabledEvents:      d.EnabledEvents,
+		Payload:            gomultistripe.EventPayload(d.EventPayload),
+		SnapshotAPIVersion: d.SnapshotAPIVersion,
+		EventsFrom:         d.EventsFrom,
+		Livemode:           d.Livemode,
+		Metadata:           d.Metadata,
+	}
+	if d.WebhookEndpoint != nil {
+		out.WebhookURL = d.WebhookEndpoint.URL
+		out.WebhookSecret = d.WebhookEndpoint.SigningSecret
+	}
+	if d.AmazonEventBridge != nil {
+		out.AWSAccountID = d.AmazonEventBridge.AWSAccountID
+		out.AWSEventSourceARN = d.AmazonEventBridge.AWSEventSourceARN
+		out.AWSEventSourceStatus = d.AmazonEventBridge.AWSEventSourceStatus
+	}
+	if d.Created != nil {
+		out.CreatedAt = *d.Created
+	}
+	return out
+}
+
+// eventDestinationRequest sends a request to the v2 event destination endpoints and
+// decodes the destination in the response. A nil body sends no content.
+func (h *HandlerV82) eventDestinationRequest(ctx context.Context, method, path string, body *eventDestinationV2, params *stripe.RawParams) (*gomultistripe.EventDestination, error) {
+	var content []byte
+	if body != nil {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := h.rawRequest(stripe.APIBackend, h.key(ctx), method, path, string(content), params)
+	if err != nil {
+		return nil, err
+	}
+	var d eventDestinationV2
+	if err := json.Unmarshal(resp.RawJSON, &d); err != nil {
+		return nil, err
+	}
+	return d.toDestination(), nil
+}
+
+func eventDestinationPath(destinationID string) string {
+	return eventDestinationsPath + "/" + url.PathEscape(destinationID)
+}
+
+func (h *HandlerV82) CreateEventDestination(ctx context.Context, params gomultistripe.EventDestinationParams) (*gomultistripe.EventDestination, error) {
+	payload := params.Payload
+	if payload == "" {
+		payload = gomultistripe.EventPayloadThin
+	}
+	body := &eventDestinationV2{
+		Name:          params.Name,
+		Description:   params.Description,
+		Type:          string(params.Type),
+		EnabledEvents: params.EnabledEvents,
+		EventPayload:  string(payload),
+		Metadata:      params.Metadata,
+	}
+	if payload == gomultistripe.EventPayloadSnapshot {
+		body.SnapshotAPIVersion = params.SnapshotAPIVersion
+		if body.SnapshotAPIVersion == "" {
+			body.SnapshotAPIVersion = h.APIVersion()
+		}
+	}
+	if params.IncludeConnectedAccounts {
+		body.EventsFrom = []string{"self", "other_accounts"}
+	}
+	switch params.Type {
+	case gomultistripe.EventDestinationWebhook:
+		body.WebhookEndpoint = &webhookEndpointV2{URL: params.WebhookURL}
+		// The signing secret is only returned on request, and only useful at creation.
+		body.Include = append([]string{"webhook_endpoint.signing_secret"}, eventDestinationIncludes...)
+	case gomultistripe.EventDestinationEventBridge:
+		body.AmazonEventBridge = &amazonEventBridgeV2{AWSAccountID: params.AWSAccountID, AWSRegion: params.AWSRegion}
+	}
+	rawParams := h.v2Params(ctx, "CreateEventDestination", map[string]string{"name": params.Name, "type": string(params.Type)})
+	return h.eventDestinationRequest(ctx, http.MethodPost, eventDestinationsPath, body, rawParams)
+}
+
+func (h *HandlerV82) GetEventDestination(ctx context.Context, destinationID string) (*gomultistripe.EventDestination, error) {
+	query := url.Values{"include": eventDestinationIncludes}
+	return h.eventDestinationRequest(ctx, http.MethodGet, eventDestinationPath(destinationID)+"?"+query.Encode(), nil, h.v2ReadParams(ctx))
+}
+
+// ListEventDestinations follows next_page_url, the cursor of v2 list responses, until the
+// last page.
+func (h *HandlerV82) ListEventDestinations(ctx context.Context) ([]*gomultistripe.EventDestination, error) {
+	query := url.Values{"limit": {"100"}, "include": eventDestinationIncludes}
+	path := eventDestinationsPath + "?" + query.Encode()
+	var out []*gomultistripe.EventDestination
+	for path != "" {
+		resp, err := h.rawRequest(stripe.APIBackend, h.key(ctx), http.MethodGet, path, "", h.v2ReadParams(ctx))
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Data        []eventDestinationV2 `json:"data"`
+			NextPageURL string               `json:"next_page_url"`
+		}
+		if err := json.Unmarshal(resp.RawJSON, &page); err != nil {
+			return nil, err
+		}
+		for i := range page.Data {
+			out = append(out, page.Data[i].toDestination())
+		}
+		path = page.NextPageURL
+	}
+	return out, nil
+}
+
+func (h *HandlerV82) UpdateEventDestination(ctx context.Context, destinationID string, update gomultistripe.EventDestinationUpdate) (*gomultistripe.EventDestination, error) {
+	body := &eventDestinationV2{
+		Name:          update.Name,
+		Description:   update.Description,
+		EnabledEvents: update.EnabledEvents,
+		Metadata:      update.Metadata,
+		Include:       eventDestinationIncludes,
+	}
+	if update.WebhookURL != "" {
+		body.WebhookEndpoint = &webhookEndpointV2{URL: update.WebhookURL}
+	}
+	params := h.v2Params(ctx, "UpdateEventDestination", map[string]string{"event_destination": destinationID})
+	return h.eventDestinationRequest(ctx, http.MethodPost, eventDestinationPath(destinationID), body, params)
+}
+
+func (h *HandlerV82) SetEventDestinationEnabled(ctx context.Context, destinationID string, enabled bool) (*gomultistripe.EventDestination, error) {
+	action := "/disable"
+	if enabled {
+		action = "/enable"
+	}
+	params := h.v2Params(ctx, "SetEventDestinationEnabled", map[string]string{"event_destination": destinationID})
+	return h.eventDestinationRequest(ctx, http.MethodPost, eventDestinationPath(destinationID)+action, nil, params)
+}
+
+func (h *HandlerV82) DeleteEventDestination(ctx context.Context, destinationID string) error {
+	params := h.v2Params(ctx, "DeleteEventDestination", map[string]string{"event_destination": destinationID})
+	_, err := h.rawRequest(stripe.APIBackend, h.key(ctx), http.MethodDelete, eventDestinationPath(destinationID), "", params)
+	return err
+}
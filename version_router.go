@@ -0,0 +1,118 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoHandlers is returned when a router has no handlers to dispatch to.
+var ErrNoHandlers = errors.New("no handlers available")
+
+// VersionRouter dispatches webhooks to the handler whose pinned API version best matches
+// the api_version the event was rendered with. This lets one endpoint serve accounts
+// (or webhook endpoints) pinned to different Stripe API versions.
+type VersionRouter struct {
+	handlers []Handler
+}
+
+// NewVersionRouter creates a router over the given handlers. With no arguments it uses
+// every registered handler.
+func NewVersionRouter(handlers ...Handler) *VersionRouter {
+	if len(handlers) == 0 {
+		handlers = Handlers()
+	}
+	return &VersionRouter{handlers: handlers}
+}
+
+// HandleWebhook inspects the event's api_version and hands the payload to the best
+// matching handler, which verifies the signature and normalizes the event.
+func (r *VersionRouter) HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, error) {
+	h, err := r.handlerForEvent(payload)
+	if err != nil {
+		return nil, err
+	}
+	return h.HandleWebhook(payload, sigHeader)
+}
+
+// HandleEvent hands an event without a signature to the best matching handler, like
+// HandleWebhook. It makes a VersionRouter usable with IngestEvent.
+func (r *VersionRouter) HandleEvent(payload []byte) (*CallbackEvent, error) {
+	h, err := r.handlerForEvent(payload)
+	if err != nil {
+		return nil, err
+	}
+	return h.HandleEvent(payload)
+}
+
+// handlerForEvent returns the handler for the api_version of the event in payload.
+func (r *VersionRouter) handlerForEvent(payload []byte) (Handler, error) {
+	var envelope struct {
+		APIVersion string `json:"api_version"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to read event api_version: %w", err)
+	}
+	h := r.HandlerFor(envelope.APIVersion)
+	if h == nil {
+		return nil, ErrNoHandlers
+	}
+	return h, nil
+}
+
+// HandlerFor returns the handler best suited to parse events rendered with apiVersion:
+// an exact match, else the newest handler on the same release train (e.g. "acacia")
+// that is not newer than the event, else the newest handler not newer than the event,
+// else the oldest handler.
+func (r *VersionRouter) HandlerFor(apiVersion string) Handler {
+	if len(r.handlers) == 0 {
+		return nil
+	}
+	for _, h := range r.handlers {
+		if h.APIVersion() == apiVersion {
+			return h
+		}
+	}
+	date, train := splitAPIVersion(apiVersion)
+	if train != "" {
+		if h := newestNotAfter(r.handlers, date, train); h != nil {
+			return h
+		}
+	}
+	if h := newestNotAfter(r.handlers, date, ""); h != nil {
+		return h
+	}
+	oldest := r.handlers[0]
+	oldestDate, _ := splitAPIVersion(oldest.APIVersion())
+	for _, h := range r.handlers[1:] {
+		if d, _ := splitAPIVersion(h.APIVersion()); d < oldestDate {
+			oldest, oldestDate = h, d
+		}
+	}
+	return oldest
+}
+
+// newestNotAfter returns the handler with the latest API version date that is not after
+// date, optionally restricted to a release train.
+func newestNotAfter(handlers []Handler, date, train string) Handler {
+	var best Handler
+	bestDate := ""
+	for _, h := range handlers {
+		d, t := splitAPIVersion(h.APIVersion())
+		if train != "" && t != train {
+			continue
+		}
+		if d <= date && d > bestDate {
+			best, bestDate = h, d
+		}
+	}
+	return best
+}
+
+// splitAPIVersion splits "2024-09-30.acacia" into its date and release train.
+// Dates are ISO formatted, so they compare correctly as strings.
+func splitAPIVersion(apiVersion string) (date, train string) {
+	date, train, _ = strings.Cut(apiVersion, ".")
+	return date, train
+}
@@ -0,0 +1,108 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxWebhookBodySize is the largest webhook body WebhookHTTPHandler reads unless
+// configured otherwise. Invoice events carrying thousands of lines run to several MB, so
+// it leaves room for them. Stripe redelivers a rejected body until the event expires, and
+// it fails the same way each time, so a lower limit must stay above the largest event the
+// endpoint receives.
+const DefaultMaxWebhookBodySize = 16 << 20
+
+// WebhookReceiver verifies and normalizes a webhook. Every Handler is one, as is a
+// VersionRouter.
+type WebhookReceiver interface {
+	HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, error)
+}
+
+// WebhookHTTPOptions configures WebhookHTTPHandler.
+type WebhookHTTPOptions struct {
+	// MaxBodySize caps the body read from a request, in bytes. Larger requests are
+	// rejected with 413 Request Entity Too Large.
+	MaxBodySize int64
+}
+
+// WebhookHTTPOption configures WebhookHTTPOptions.
+type WebhookHTTPOption func(*WebhookHTTPOptions)
+
+// WithMaxWebhookBodySize sets the largest body accepted, in bytes.
+func WithMaxWebhookBodySize(n int64) WebhookHTTPOption {
+	return func(o *WebhookHTTPOptions) { o.MaxBodySize = n }
+}
+
+// WebhookHTTPHandler serves a Stripe webhook endpoint: it reads the body of POST requests,
+// verifies it against the Stripe-Signature header with h and passes the event to dispatch,
+// which owns it from then on. Dispatcher.Dispatch is a suitable dispatch function:
+//
+//	http.Handle("/stripe/webhook", gomultistripe.WebhookHTTPHandler(handler, dispatcher.Dispatch))
+//
+// Responses follow Stripe's delivery semantics. Unsigned, badly signed or unreadable
+// events are answered with 400 Bad Request, and events of types h does not normalize with
+// 200 OK, so that Stripe does not retry them. Other errors from h, such as a failed API
+// call fetching the rest of an invoice's lines, and dispatch errors are answered with 500
+// Internal Server Error, so that Stripe delivers the event again.
+func WebhookHTTPHandler(h WebhookReceiver, dispatch EventConsumer, opts ...WebhookHTTPOption) http.Handler {
+	o := WebhookHTTPOptions{MaxBodySize: DefaultMaxWebhookBodySize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, o.MaxBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		sigHeader := r.Header.Get("Stripe-Signature")
+		if sigHeader == "" {
+			http.Error(w, "missing Stripe-Signature header", http.StatusBadRequest)
+			return
+		}
+		evt, err := h.HandleWebhook(payload, sigHeader)
+		if errors.Is(err, ErrUnknownEventType) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err != nil {
+			// The handler logs why the event was rejected.
+			if invalidWebhook(err) {
+				http.Error(w, "invalid webhook", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		// dispatch may release the event, so it is read beforehand.
+		eventID, eventType := evt.EventID, evt.Type
+		if err := dispatch(r.Context(), evt); err != nil {
+			Logger().Warn("webhook dispatch failed", LogKeyEventID, eventID, LogKeyEventType, string(eventType), "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// invalidWebhook reports whether err rejects the webhook itself, so that a redelivery
+// would fail the same way: its signature does not verify or it does not parse.
+func invalidWebhook(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.Is(err, ErrInvalidWebhook) ||
+		errors.Is(err, ErrInvalidSignatureHeader) || errors.Is(err, ErrNoValidSignature) || errors.Is(err, ErrSignatureTooOld) ||
+		errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
@@ -0,0 +1,79 @@
+package gomultistripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// typedWebhookHandler verifies signatures and only normalizes invoice events.
+type typedWebhookHandler struct{ stubWebhookHandler }
+
+func (h *typedWebhookHandler) HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, error) {
+	if _, err := h.stubWebhookHandler.HandleWebhook(payload, sigHeader); err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(payload), "lines_unavailable") {
+		return nil, errors.New("fetching invoice lines: connection reset")
+	}
+	if !strings.Contains(string(payload), "invoice.") {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, payload)
+	}
+	return &CallbackEvent{EventID: "evt_1", Type: EventInvoicePaymentSucceeded}, nil
+}
+
+func TestWebhookHTTPHandler(t *testing.T) {
+	h := &typedWebhookHandler{stubWebhookHandler{secret: "whsec_http"}}
+	var dispatched []*CallbackEvent
+	failing := false
+	srv := httptest.NewServer(WebhookHTTPHandler(h, func(ctx context.Context, evt *CallbackEvent) error {
+		if failing {
+			return errors.New("queue full")
+		}
+		dispatched = append(dispatched, evt)
+		return nil
+	}, WithMaxWebhookBodySize(64)))
+	defer srv.Close()
+
+	post := func(method, payload, sig string) int {
+		req, _ := http.NewRequest(method, srv.URL, strings.NewReader(payload))
+		if sig != "" {
+			req.Header.Set("Stripe-Signature", sig)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	signed := func(payload string) string { return SignPayload([]byte(payload), "whsec_http", time.Now()) }
+
+	for _, tc := range []struct {
+		name, method, payload, sig string
+		failing                    bool
+		want                       int
+	}{
+		{name: "dispatched", method: http.MethodPost, payload: `{"type":"invoice.paid"}`, sig: signed(`{"type":"invoice.paid"}`), want: http.StatusOK},
+		{name: "unknown type", method: http.MethodPost, payload: `{"type":"coupon.created"}`, sig: signed(`{"type":"coupon.created"}`), want: http.StatusOK},
+		{name: "bad signature", method: http.MethodPost, payload: `{"type":"invoice.paid"}`, sig: signed(`{}`), want: http.StatusBadRequest},
+		{name: "unsigned", method: http.MethodPost, payload: `{"type":"invoice.paid"}`, want: http.StatusBadRequest},
+		{name: "handler failed", method: http.MethodPost, payload: `{"lines_unavailable":1}`, sig: signed(`{"lines_unavailable":1}`), want: http.StatusInternalServerError},
+		{name: "too large", method: http.MethodPost, payload: strings.Repeat("x", 65), sig: signed(strings.Repeat("x", 65)), want: http.StatusRequestEntityTooLarge},
+		{name: "dispatch failed", method: http.MethodPost, payload: `{"type":"invoice.paid"}`, sig: signed(`{"type":"invoice.paid"}`), failing: true, want: http.StatusInternalServerError},
+		{name: "GET", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+	} {
+		failing = tc.failing
+		if got := post(tc.method, tc.payload, tc.sig); got != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, got, tc.want)
+		}
+	}
+	if len(dispatched) != 1 || dispatched[0].EventID != "evt_1" {
+		t.Errorf("dispatched %+v", dispatched)
+	}
+}
@@ -0,0 +1,187 @@
+package gomultistripe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+)
+
+var (
+	// ErrUnknownWebhookAccount is returned when a webhook cannot be matched to a configured account.
+	ErrUnknownWebhookAccount = errors.New("webhook does not match any configured account")
+	// ErrWebhookAccountMismatch is returned when a webhook routed to one account names
+	// another configured account's AccountID in its payload.
+	ErrWebhookAccountMismatch = errors.New("webhook belongs to a different account")
+)
+
+// WebhookAccount is one tenant's webhook configuration.
+type WebhookAccount struct {
+	// Name identifies the account, and is matched against the URL path segment when routing by path.
+	Name string
+	// Secret is the signing secret (whsec_...) of the account's webhook endpoint.
+	Secret string
+	// SecretKey, when set, is given to the handler with SetSecretKey, so the requests it
+	// sends while parsing the account's events authenticate as that account.
+	SecretKey string
+	// AccountID is the account's Stripe ID (acct_...), for tenants that are connected
+	// accounts of the platform sending the events. Events naming it in their "account"
+	// field are routed to this account, and are rejected if routed to another.
+	AccountID string
+	// Handler parses the account's events. Accounts must not share a handler instance,
+	// as the router sets the handler's webhook secret; create one per account with the
+	// version package's NewHandler.
+	Handler Handler
+}
+
+// WebhookRouter receives webhooks for many accounts on shared infrastructure. Each event
+// is matched to its account either by the URL path segment or by trying each account's
+// endpoint secret against the Stripe-Signature header.
+type WebhookRouter struct {
+	accounts    []WebhookAccount
+	byName      map[string]int
+	byAccountID map[string]int
+	metadataKey string
+}
+
+// NewWebhookRouter creates a router for the given accounts and configures each
+// account's handler with its webhook secret. It returns an error if two accounts share a
+// handler, which would leave both with the secret of the last.
+func NewWebhookRouter(accounts ...WebhookAccount) (*WebhookRouter, error) {
+	r := &WebhookRouter{byName: make(map[string]int, len(accounts)), byAccountID: make(map[string]int)}
+	handlers := make(map[Handler]string, len(accounts))
+	for _, acct := range accounts {
+		if acct.Handler == nil {
+			return nil, fmt.Errorf("webhook account %q has no handler", acct.Name)
+		}
+		if reflect.TypeOf(acct.Handler).Comparable() {
+			if other, dup := handlers[acct.Handler]; dup {
+				return nil, fmt.Errorf("webhook accounts %q and %q share a handler", other, acct.Name)
+			}
+			handlers[acct.Handler] = acct.Name
+		}
+		if _, dup := r.byName[acct.Name]; dup {
+			return nil, fmt.Errorf("duplicate webhook account %q", acct.Name)
+		}
+		if acct.AccountID != "" {
+			if _, dup := r.byAccountID[acct.AccountID]; dup {
+				return nil, fmt.Errorf("duplicate Stripe account %q", acct.AccountID)
+			}
+			r.byAccountID[acct.AccountID] = len(r.accounts)
+		}
+		acct.Handler.SetWebhookSecret(acct.Secret)
+		if acct.SecretKey != "" {
+			acct.Handler.SetSecretKey(acct.SecretKey)
+		}
+		r.byName[acct.Name] = len(r.accounts)
+		r.accounts = append(r.accounts, acct)
+	}
+	return r, nil
+}
+
+// SetMetadataKey routes events whose object carries the metadata key to the account whose
+// Name or AccountID is its value, e.g. a tenant ID stamped on every customer. Call it
+// before the router receives webhooks.
+func (r *WebhookRouter) SetMetadataKey(key string) {
+	r.metadataKey = key
+}
+
+// HandleWebhook finds the account the payload belongs to and parses the event with that
+// account's handler. It returns the matched account name. The account is taken from the
+// payload's connected account or metadata (see SetMetadataKey) when they name one, and
+// otherwise found by trying each account's secret against the signature. Either way the
+// event must be signed with the matched account's secret.
+func (r *WebhookRouter) HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, string, error) {
+	hints := r.payloadHints(payload)
+	for _, hint := range [...]string{hints.Account, hints.Metadata} {
+		if i, ok := r.lookup(hint); ok {
+			evt, err := r.handle(r.accounts[i], hints, payload, sigHeader)
+			return evt, r.accounts[i].Name, err
+		}
+	}
+	for _, acct := range r.accounts {
+		if VerifySignature(payload, sigHeader, acct.Secret, DefaultSignatureTolerance) == nil {
+			evt, err := r.handle(acct, hints, payload, sigHeader)
+			return evt, acct.Name, err
+		}
+	}
+	return nil, "", ErrUnknownWebhookAccount
+}
+
+// HandleWebhookHint parses the event with the account whose Name or AccountID is hint,
+// e.g. a tenant header set by the ingress. An empty hint routes like HandleWebhook.
+func (r *WebhookRouter) HandleWebhookHint(hint string, payload []byte, sigHeader string) (*CallbackEvent, string, error) {
+	if hint == "" {
+		return r.HandleWebhook(payload, sigHeader)
+	}
+	i, ok := r.lookup(hint)
+	if !ok {
+		return nil, "", ErrUnknownWebhookAccount
+	}
+	evt, err := r.handle(r.accounts[i], r.payloadHints(payload), payload, sigHeader)
+	return evt, r.accounts[i].Name, err
+}
+
+// HandleWebhookForAccount parses the event with the named account's handler.
+func (r *WebhookRouter) HandleWebhookForAccount(name string, payload []byte, sigHeader string) (*CallbackEvent, error) {
+	i, ok := r.byName[name]
+	if !ok {
+		return nil, ErrUnknownWebhookAccount
+	}
+	return r.handle(r.accounts[i], r.payloadHints(payload), payload, sigHeader)
+}
+
+// HandleWebhookPath routes by the last segment of the request path, e.g.
+// "/stripe/webhooks/acme" is handled by the "acme" account.
+func (r *WebhookRouter) HandleWebhookPath(urlPath string, payload []byte, sigHeader string) (*CallbackEvent, string, error) {
+	name := path.Base(urlPath)
+	evt, err := r.HandleWebhookForAccount(name, payload, sigHeader)
+	return evt, name, err
+}
+
+// lookup returns the index of the account whose Name or AccountID is hint.
+func (r *WebhookRouter) lookup(hint string) (int, bool) {
+	if hint == "" {
+		return 0, false
+	}
+	if i, ok := r.byName[hint]; ok {
+		return i, true
+	}
+	i, ok := r.byAccountID[hint]
+	return i, ok
+}
+
+// webhookHints are the fields of a payload that name the account it belongs to.
+type webhookHints struct {
+	Account  string // the connected account of a Connect event
+	Metadata string // the value of the router's metadata key on the event's object
+}
+
+func (r *WebhookRouter) payloadHints(payload []byte) webhookHints {
+	var event struct {
+		Account string `json:"account"`
+		Data    struct {
+			Object struct {
+				Metadata map[string]string `json:"metadata"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(payload, &event) != nil {
+		return webhookHints{}
+	}
+	hints := webhookHints{Account: event.Account}
+	if r.metadataKey != "" {
+		hints.Metadata = event.Data.Object.Metadata[r.metadataKey]
+	}
+	return hints
+}
+
+// handle rejects payloads of another configured account before parsing the event with
+// acct's handler, which checks that acct's secret signed it.
+func (r *WebhookRouter) handle(acct WebhookAccount, hints webhookHints, payload []byte, sigHeader string) (*CallbackEvent, error) {
+	if i, ok := r.byAccountID[hints.Account]; ok && r.accounts[i].Name != acct.Name {
+		return nil, fmt.Errorf("%w: event for %q routed to %q", ErrWebhookAccountMismatch, r.accounts[i].Name, acct.Name)
+	}
+	return acct.Handler.HandleWebhook(payload, sigHeader)
+}
@@ -0,0 +1,87 @@
+package gomultistripe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubWebhookHandler verifies signatures like the version handlers, and parses nothing.
+type stubWebhookHandler struct {
+	Handler
+	secret, key string
+}
+
+func (h *stubWebhookHandler) SetWebhookSecret(secret string) { h.secret = secret }
+
+func (h *stubWebhookHandler) SetSecretKey(key string) { h.key = key }
+
+func (h *stubWebhookHandler) HandleWebhook(payload []byte, sigHeader string) (*CallbackEvent, error) {
+	if err := VerifySignature(payload, sigHeader, h.secret, DefaultSignatureTolerance); err != nil {
+		return nil, err
+	}
+	return &CallbackEvent{}, nil
+}
+
+func TestWebhookRouter_RoutesByHint(t *testing.T) {
+	acme, globex := &stubWebhookHandler{}, &stubWebhookHandler{}
+	r, err := NewWebhookRouter(
+		WebhookAccount{Name: "acme", Secret: "whsec_acme", SecretKey: "sk_acme", AccountID: "acct_acme", Handler: acme},
+		WebhookAccount{Name: "globex", Secret: "whsec_globex", AccountID: "acct_globex", Handler: globex},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetMetadataKey("tenant")
+	if acme.key != "sk_acme" || globex.key != "" {
+		t.Errorf("secret keys %q and %q", acme.key, globex.key)
+	}
+
+	sign := func(payload, secret string) ([]byte, string) {
+		return []byte(payload), SignPayload([]byte(payload), secret, time.Now())
+	}
+	for _, tc := range []struct {
+		name, hint, payload, secret string
+		want                        string
+		err                         error
+	}{
+		{name: "signature", payload: `{"id":"evt_1"}`, secret: "whsec_globex", want: "globex"},
+		{name: "connected account", payload: `{"account":"acct_acme"}`, secret: "whsec_acme", want: "acme"},
+		{name: "metadata", payload: `{"data":{"object":{"metadata":{"tenant":"globex"}}}}`, secret: "whsec_globex", want: "globex"},
+		{name: "header by name", hint: "acme", payload: `{}`, secret: "whsec_acme", want: "acme"},
+		{name: "header by account", hint: "acct_globex", payload: `{}`, secret: "whsec_globex", want: "globex"},
+		{name: "unknown hint", hint: "initech", payload: `{}`, secret: "whsec_acme", err: ErrUnknownWebhookAccount},
+		{name: "unsigned", payload: `{}`, secret: "whsec_other", err: ErrUnknownWebhookAccount},
+		{name: "account routed elsewhere", hint: "globex", payload: `{"account":"acct_acme"}`, secret: "whsec_acme", err: ErrWebhookAccountMismatch},
+	} {
+		payload, sig := sign(tc.payload, tc.secret)
+		_, name, err := r.HandleWebhookHint(tc.hint, payload, sig)
+		if !errors.Is(err, tc.err) || (tc.err == nil && name != tc.want) {
+			t.Errorf("%s: routed to %q with %v, want %q with %v", tc.name, name, err, tc.want, tc.err)
+		}
+	}
+
+	// Metadata naming one tenant on an event signed by another must not be accepted.
+	payload, sig := sign(`{"data":{"object":{"metadata":{"tenant":"acme"}}}}`, "whsec_globex")
+	if _, _, err := r.HandleWebhook(payload, sig); !errors.Is(err, ErrNoValidSignature) {
+		t.Errorf("misrouted by metadata: %v", err)
+	}
+	payload, sig = sign(`{"account":"acct_globex"}`, "whsec_acme")
+	if _, err := r.HandleWebhookForAccount("acme", payload, sig); !errors.Is(err, ErrWebhookAccountMismatch) {
+		t.Errorf("misrouted by path: %v", err)
+	}
+}
+
+func TestNewWebhookRouter_RejectsSharedHandler(t *testing.T) {
+	h := &stubWebhookHandler{}
+	_, err := NewWebhookRouter(
+		WebhookAccount{Name: "acme", Secret: "whsec_acme", Handler: h},
+		WebhookAccount{Name: "globex", Secret: "whsec_globex", Handler: h},
+	)
+	if err == nil {
+		t.Fatal("accounts sharing a handler were accepted")
+	}
+	if h.secret != "whsec_acme" {
+		t.Errorf("handler secret %q", h.secret)
+	}
+}